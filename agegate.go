@@ -0,0 +1,76 @@
+package nano64
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrIDAge is wrapped by the errors of AgePolicy.Check.
+var ErrIDAge = errors.New("ID age outside policy")
+
+// defaultAgeGateMaxAhead is the clock skew AgeGate tolerates for IDs from the future.
+const defaultAgeGateMaxAhead = time.Minute
+
+// AgeViolation describes an ID rejected or flagged by an AgePolicy.
+type AgeViolation struct {
+	ID Nano64
+	// Age is the clock minus the ID's timestamp; negative for IDs from the future.
+	Age time.Duration
+}
+
+// AgePolicy admits requests by the age of the IDs they reference, using the
+// embedded timestamp as the authority: a webhook replayed days later or a
+// forged ID from the future is turned away before any lookup. Create one with
+// AgeGate and adjust the fields as needed; the nano64http package applies it
+// to HTTP handlers.
+type AgePolicy struct {
+	// MaxAge is the oldest accepted ID. Zero disables the bound.
+	MaxAge time.Duration
+
+	// MaxAhead is how far in the future an ID may be, to allow for clock skew
+	// between hosts. Zero rejects every ID from the future.
+	MaxAhead time.Duration
+
+	// Clock defaults to DefaultClock.
+	Clock Clock
+}
+
+// AgeGate returns a policy accepting IDs up to maxAge old and at most a minute
+// ahead of the clock.
+func AgeGate(maxAge time.Duration) AgePolicy {
+	return AgePolicy{MaxAge: maxAge, MaxAhead: defaultAgeGateMaxAhead}
+}
+
+// age returns the age of id.
+func (p AgePolicy) age(id Nano64) time.Duration {
+	clock := p.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	return time.Duration(clock()-id.GetTimestamp()) * time.Millisecond
+}
+
+// violates reports whether age is outside the policy.
+func (p AgePolicy) violates(age time.Duration) bool {
+	return (p.MaxAge > 0 && age > p.MaxAge) || -age > p.MaxAhead
+}
+
+// Violation reports whether id is outside the policy and, if so, describes it.
+func (p AgePolicy) Violation(id Nano64) (AgeViolation, bool) {
+	age := p.age(id)
+	return AgeViolation{ID: id, Age: age}, p.violates(age)
+}
+
+// Check returns an error wrapping ErrIDAge if id is older than MaxAge or further
+// ahead than MaxAhead.
+func (p AgePolicy) Check(id Nano64) error {
+	age := p.age(id)
+	if !p.violates(age) {
+		return nil
+	}
+	if age < 0 {
+		return fmt.Errorf("%w: %s is %v in the future", ErrIDAge, id.ToHex(), -age)
+	}
+	return fmt.Errorf("%w: %s is %v old, more than %v", ErrIDAge, id.ToHex(), age, p.MaxAge)
+}
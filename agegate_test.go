@@ -0,0 +1,37 @@
+package nano64
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAgePolicy(t *testing.T) {
+	const now = int64(1_700_000_000_000)
+	p := AgeGate(24 * time.Hour)
+	p.Clock = func() int64 { return now }
+
+	tests := []struct {
+		name    string
+		ts      int64
+		wantErr bool
+	}{
+		{"fresh", now - 1000, false},
+		{"a day old", now - 24*3600*1000, false},
+		{"replayed", now - 48*3600*1000, true},
+		{"slight skew", now + 30*1000, false},
+		{"from the future", now + 3600*1000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := mustGenerate(t, tt.ts, 1)
+			err := p.Check(id)
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrIDAge)) {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if v, violates := p.Violation(id); violates != tt.wantErr || v.Age != time.Duration(now-tt.ts)*time.Millisecond {
+				t.Errorf("Violation() = %+v, %v", v, violates)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package nano64
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultAgeHistogramScale gives buckets about 9% wide.
+	defaultAgeHistogramScale = 3
+
+	// maxAgeHistogramScale bounds the resolution and so the bucket count.
+	maxAgeHistogramScale = 8
+)
+
+// AgeHistogramOptions configures an AgeHistogram.
+type AgeHistogramOptions struct {
+	// Scale sets the bucket resolution as in OpenTelemetry exponential
+	// histograms: bucket boundaries are powers of 2^(2^-Scale). 1 to 8; zero
+	// means 3.
+	Scale int
+
+	// Clock defaults to DefaultClock.
+	Clock Clock
+}
+
+// AgeHistogram measures end-to-end pipeline lag from the IDs themselves: record
+// each ID as it is processed and the histogram tracks the distribution of
+// now - id.Time(). It uses the base-2 exponential bucketing of OpenTelemetry, so
+// a Snapshot maps one-to-one onto an OTel ExponentialHistogram data point with
+// values in milliseconds. IDs from the future count as age zero.
+// An AgeHistogram is safe for concurrent use.
+type AgeHistogram struct {
+	scale int
+	clock Clock
+
+	mu       sync.Mutex
+	offset   int32
+	counts   []uint64
+	zero     uint64
+	count    uint64
+	sum      float64
+	min, max float64
+}
+
+// AgeHistogramSnapshot is the state of an AgeHistogram. Its fields match the
+// OpenTelemetry ExponentialHistogramDataPoint, with values in milliseconds: the
+// bucket at index Offset+i counts ages in (base^(Offset+i), base^(Offset+i+1)]
+// where base = 2^(2^-Scale).
+type AgeHistogramSnapshot struct {
+	Scale        int
+	ZeroCount    uint64
+	Offset       int32
+	BucketCounts []uint64
+	Count        uint64
+	Sum          float64
+	Min, Max     float64
+}
+
+// NewAgeHistogram creates an AgeHistogram.
+func NewAgeHistogram(opts AgeHistogramOptions) (*AgeHistogram, error) {
+	scale := opts.Scale
+	if scale == 0 {
+		scale = defaultAgeHistogramScale
+	}
+	if scale < 1 || scale > maxAgeHistogramScale {
+		return nil, fmt.Errorf("histogram scale must be 1-%d, got %d", maxAgeHistogramScale, scale)
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	return &AgeHistogram{scale: scale, clock: clock}, nil
+}
+
+// Observe records the age of id and returns it.
+func (h *AgeHistogram) Observe(id Nano64) time.Duration {
+	age := max(h.clock()-id.GetTimestamp(), 0)
+	h.record(float64(age))
+	return time.Duration(age) * time.Millisecond
+}
+
+// record adds an age in milliseconds.
+func (h *AgeHistogram) record(ms float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 || ms < h.min {
+		h.min = ms
+	}
+	if h.count == 0 || ms > h.max {
+		h.max = ms
+	}
+	h.count++
+	h.sum += ms
+	if ms == 0 {
+		h.zero++
+		return
+	}
+
+	index := exponentialBucket(ms, h.scale)
+	switch {
+	case len(h.counts) == 0:
+		h.offset = index
+		h.counts = []uint64{0}
+	case index < h.offset:
+		grown := make([]uint64, int(h.offset-index)+len(h.counts))
+		copy(grown[h.offset-index:], h.counts)
+		h.counts, h.offset = grown, index
+	case int(index-h.offset) >= len(h.counts):
+		h.counts = append(h.counts, make([]uint64, int(index-h.offset)-len(h.counts)+1)...)
+	}
+	h.counts[index-h.offset]++
+}
+
+// exponentialBucket returns the index of the bucket (base^i, base^(i+1)]
+// holding v > 0.
+func exponentialBucket(v float64, scale int) int32 {
+	return int32(math.Ceil(math.Log2(v)*math.Exp2(float64(scale)))) - 1
+}
+
+// Snapshot returns the current state.
+func (h *AgeHistogram) Snapshot() AgeHistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.snapshotLocked()
+}
+
+// snapshotLocked returns the current state; h.mu must be held.
+func (h *AgeHistogram) snapshotLocked() AgeHistogramSnapshot {
+	return AgeHistogramSnapshot{
+		Scale:        h.scale,
+		ZeroCount:    h.zero,
+		Offset:       h.offset,
+		BucketCounts: append([]uint64(nil), h.counts...),
+		Count:        h.count,
+		Sum:          h.sum,
+		Min:          h.min,
+		Max:          h.max,
+	}
+}
+
+// Reset clears the histogram, e.g. after exporting a snapshot with delta
+// temporality.
+func (h *AgeHistogram) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.resetLocked()
+}
+
+// resetLocked clears the histogram; h.mu must be held.
+func (h *AgeHistogram) resetLocked() {
+	h.offset, h.counts, h.zero, h.count, h.sum, h.min, h.max = 0, nil, 0, 0, 0, 0, 0
+}
+
+// Quantile estimates the q-quantile (0 <= q <= 1) of the ages as the upper
+// bound of the bucket holding it, capped at the largest age seen; it is off by
+// at most one bucket width. It returns 0 for an empty snapshot.
+func (s AgeHistogramSnapshot) Quantile(q float64) time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(min(max(q, 0), 1) * float64(s.Count)))
+	seen := s.ZeroCount
+	if rank <= seen {
+		return 0
+	}
+	for i, n := range s.BucketCounts {
+		if seen += n; seen >= rank {
+			upper := math.Exp2(float64(int(s.Offset)+i+1) / math.Exp2(float64(s.Scale)))
+			return time.Duration(min(upper, s.Max) * float64(time.Millisecond))
+		}
+	}
+	return time.Duration(s.Max * float64(time.Millisecond))
+}
@@ -0,0 +1,71 @@
+package nano64
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAgeHistogram(t *testing.T) {
+	now := int64(10_000_000)
+	h, err := NewAgeHistogram(AgeHistogramOptions{Clock: func() int64 { return now }})
+	if err != nil {
+		t.Fatalf("NewAgeHistogram() error = %v", err)
+	}
+
+	// 90 IDs 100ms old, 9 IDs 1s old, one 10s old, one from the future.
+	for i := range 90 {
+		h.Observe(mustGenerate(t, now-100, uint32(i)))
+	}
+	for i := range 9 {
+		h.Observe(mustGenerate(t, now-1000, uint32(i)))
+	}
+	if age := h.Observe(mustGenerate(t, now-10_000, 0)); age != 10*time.Second {
+		t.Errorf("Observe() = %v, want 10s", age)
+	}
+	h.Observe(mustGenerate(t, now+50, 0))
+
+	s := h.Snapshot()
+	if s.Count != 101 || s.ZeroCount != 1 || s.Min != 0 || s.Max != 10_000 || s.Sum != 90*100+9*1000+10_000 {
+		t.Errorf("unexpected snapshot totals: %+v", s)
+	}
+	var total uint64
+	for _, n := range s.BucketCounts {
+		total += n
+	}
+	if total+s.ZeroCount != s.Count {
+		t.Errorf("buckets hold %d + %d, want %d", total, s.ZeroCount, s.Count)
+	}
+
+	// Each value falls in the bucket whose bounds contain it.
+	base := math.Exp2(math.Exp2(-float64(s.Scale)))
+	for _, v := range []float64{1, 2, 100, 1000, 1001, 10_000} {
+		i := exponentialBucket(v, s.Scale)
+		if lower, upper := math.Pow(base, float64(i)), math.Pow(base, float64(i+1)); v <= lower || v > upper*(1+1e-9) {
+			t.Errorf("value %v in bucket %d = (%v, %v]", v, i, lower, upper)
+		}
+	}
+
+	tests := []struct {
+		q        float64
+		min, max time.Duration
+	}{
+		{0, 0, 0},
+		{0.5, 100 * time.Millisecond, 110 * time.Millisecond},
+		{0.95, time.Second, 1100 * time.Millisecond},
+		{1, 10 * time.Second, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := s.Quantile(tt.q); got < tt.min || got > tt.max {
+			t.Errorf("Quantile(%v) = %v, want %v-%v", tt.q, got, tt.min, tt.max)
+		}
+	}
+
+	h.Reset()
+	if s := h.Snapshot(); s.Count != 0 || len(s.BucketCounts) != 0 || s.Quantile(0.5) != 0 {
+		t.Errorf("snapshot after Reset() = %+v", s)
+	}
+	if _, err := NewAgeHistogram(AgeHistogramOptions{Scale: 9}); err == nil {
+		t.Error("NewAgeHistogram() accepted scale 9")
+	}
+}
@@ -0,0 +1,180 @@
+package nano64
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"time"
+)
+
+// minExportKeyLength is the minimum accepted anonymization key length.
+const minExportKeyLength = 16
+
+// exportDomain separates the pseudonym derivation from the noise derivation,
+// which share the policy key.
+const exportDomain = "nano64-export"
+
+// exportRounds is the number of Feistel rounds permuting the random field; each
+// takes 8 bytes of the round keys derived with HMAC-SHA256.
+const exportRounds = 4
+
+// Record is an ID-keyed row of a dataset passed to Export.
+type Record struct {
+	ID Nano64
+
+	// Data is the rest of the row as a single line, e.g. JSON. IDs it refers to
+	// should be rewritten with ExportPolicy.Anonymize so joins survive the export.
+	Data []byte
+}
+
+// ExportPolicy configures Export.
+type ExportPolicy struct {
+	// Key keys the pseudonyms and the timestamp noise; at least 16 bytes.
+	// Required. Exports made with the same key can be joined with each other;
+	// use a new key per recipient to keep their shares unlinkable.
+	Key []byte
+
+	// Epsilon is the privacy budget of the timestamp noise (see TimeNoise).
+	// Zero disables the noise and keeps exact creation times.
+	Epsilon float64
+
+	// Sensitivity is passed to TimeNoise; zero means one hour.
+	Sensitivity time.Duration
+
+	// Snapshot configures the snapshot file written to dst.
+	Snapshot SnapshotOptions
+
+	// Rows, if set, receives each record as the hex anonymized ID, a tab and
+	// Data, one per line in the order of the snapshot.
+	Rows io.Writer
+}
+
+// validate checks the policy.
+func (p ExportPolicy) validate() error {
+	if len(p.Key) < minExportKeyLength {
+		return fmt.Errorf("export key must be at least %d bytes, got %d", minExportKeyLength, len(p.Key))
+	}
+	if p.Epsilon < 0 {
+		return fmt.Errorf("export epsilon must not be negative, got %v", p.Epsilon)
+	}
+	return nil
+}
+
+// Anonymize returns the pseudonym of id under the policy: the random field is
+// mapped through a permutation keyed by the policy key and the timestamp, and
+// the timestamp gets the policy's noise, clamped to the valid range. The same ID
+// always maps to the same pseudonym, so references between records stay
+// consistent, and without noise distinct IDs map to distinct pseudonyms.
+func (p ExportPolicy) Anonymize(id Nano64) (Nano64, error) {
+	if err := p.validate(); err != nil {
+		return Nil, err
+	}
+	return p.anonymize(id), nil
+}
+
+// anonymize is Anonymize for a validated policy.
+func (p ExportPolicy) anonymize(id Nano64) Nano64 {
+	timestamp := id.GetTimestamp()
+	if p.Epsilon > 0 {
+		offset, _ := TimeNoise{Key: p.Key, Sensitivity: p.Sensitivity}.Offset(id, p.Epsilon)
+		timestamp = min(max(timestamp+offset.Milliseconds(), 0), maxTimestamp)
+	}
+
+	random := p.permuteRandom(id.GetTimestamp(), id.value&randomMask)
+	return Nano64{value: uint64(timestamp)<<timestampShift | random}
+}
+
+// permuteRandom maps the random field of an ID stamped timestamp through a
+// balanced Feistel network whose round keys are derived from the policy key and
+// the timestamp. Being a bijection on the field, it never maps two IDs of one
+// millisecond to the same pseudonym, which a truncated hash would.
+func (p ExportPolicy) permuteRandom(timestamp int64, random uint64) uint64 {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(timestamp))
+	mac := hmac.New(sha256.New, p.Key)
+	mac.Write([]byte(exportDomain))
+	mac.Write(buf[:])
+	keys := mac.Sum(nil)
+
+	const half = RandomBits / 2
+	l, r := random>>half, random&(1<<half-1)
+	for i := range exportRounds {
+		k := binary.BigEndian.Uint64(keys[i*8:])
+		l, r = r, l^((r^k)*0x9E3779B97F4A7C15)>>(64-half)
+	}
+	return l<<half | r
+}
+
+// Export writes a privacy-preserving share of the records in src: every ID is
+// replaced with its pseudonym under policy, and the pseudonyms are written to dst
+// in the snapshot file format, readable with OpenSnapshot. With policy.Rows set,
+// the record data is written alongside, keyed by pseudonym.
+//
+// Noise reorders IDs, so Export holds the records in memory to sort them before
+// writing. It returns the number of records exported.
+func Export(ctx context.Context, src iter.Seq[Record], policy ExportPolicy, dst io.Writer) (int, error) {
+	if err := policy.validate(); err != nil {
+		return 0, err
+	}
+
+	var records []Record
+	for r := range src {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if bytes.ContainsAny(r.Data, "\r\n") {
+			return 0, fmt.Errorf("data of record %s spans multiple lines", r.ID.ToHex())
+		}
+		records = append(records, Record{ID: policy.anonymize(r.ID), Data: r.Data})
+	}
+	slices.SortStableFunc(records, func(a, b Record) int { return Compare(a.ID, b.ID) })
+	for i := 1; i < len(records); i++ {
+		// Noise can move IDs of different milliseconds onto one pseudonym.
+		if records[i].ID == records[i-1].ID {
+			return 0, fmt.Errorf("two records map to pseudonym %s; lower the noise or export again with another key", records[i].ID.ToHex())
+		}
+	}
+
+	sw, err := NewSnapshotWriter(dst, policy.Snapshot)
+	if err != nil {
+		return 0, err
+	}
+	var rows *bufio.Writer
+	if policy.Rows != nil {
+		rows = bufio.NewWriter(policy.Rows)
+	}
+	for i, r := range records {
+		if i%DefaultSnapshotBlockSize == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+		if err := sw.Write(r.ID); err != nil {
+			return 0, err
+		}
+		if rows != nil {
+			rows.WriteString(r.ID.ToHex())
+			rows.WriteByte('\t')
+			rows.Write(r.Data)
+			if err := rows.WriteByte('\n'); err != nil {
+				return 0, fmt.Errorf("failed to write export rows: %w", err)
+			}
+		}
+	}
+	if err := sw.Close(); err != nil {
+		return 0, err
+	}
+	if rows != nil {
+		if err := rows.Flush(); err != nil {
+			return 0, fmt.Errorf("failed to write export rows: %w", err)
+		}
+	}
+	return len(records), nil
+}
@@ -0,0 +1,146 @@
+package nano64
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExport(t *testing.T) {
+	ids := snapshotIDs(t, 500)
+	records := func(yield func(Record) bool) {
+		for i, id := range ids {
+			if !yield(Record{ID: id, Data: []byte(fmt.Sprintf(`{"n":%d}`, i))}) {
+				return
+			}
+		}
+	}
+	policy := ExportPolicy{Key: []byte("0123456789abcdef"), Epsilon: 1, Sensitivity: time.Minute}
+
+	var snapshot, rows bytes.Buffer
+	policy.Rows = &rows
+	n, err := Export(context.Background(), records, policy, &snapshot)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if n != len(ids) {
+		t.Errorf("Export() = %d, want %d", n, len(ids))
+	}
+
+	sr, err := OpenSnapshot(bytes.NewReader(snapshot.Bytes()), int64(snapshot.Len()))
+	if err != nil {
+		t.Fatalf("OpenSnapshot() error = %v", err)
+	}
+	var exported []Nano64
+	for id, err := range sr.All() {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		exported = append(exported, id)
+	}
+	if !slices.IsSortedFunc(exported, Compare) || len(exported) != len(ids) {
+		t.Fatalf("snapshot holds %d IDs, sorted %v", len(exported), slices.IsSortedFunc(exported, Compare))
+	}
+
+	want := make(map[Nano64]string)
+	for i, id := range ids {
+		pseudonym, err := policy.Anonymize(id)
+		if err != nil {
+			t.Fatalf("Anonymize() error = %v", err)
+		}
+		if pseudonym == id {
+			t.Errorf("Anonymize(%s) returned the ID unchanged", id.ToHex())
+		}
+		want[pseudonym] = fmt.Sprintf(`{"n":%d}`, i)
+	}
+	lines := strings.Split(strings.TrimSuffix(rows.String(), "\n"), "\n")
+	if len(lines) != len(ids) {
+		t.Fatalf("got %d rows, want %d", len(lines), len(ids))
+	}
+	for i, line := range lines {
+		hex, data, _ := strings.Cut(line, "\t")
+		if hex != exported[i].ToHex() {
+			t.Errorf("row %d is keyed %s, snapshot has %s", i, hex, exported[i].ToHex())
+		}
+		if want[exported[i]] != data {
+			t.Errorf("row %d data = %s, want %s", i, data, want[exported[i]])
+		}
+	}
+
+	other := policy
+	other.Key = []byte("fedcba9876543210")
+	a, _ := policy.Anonymize(ids[0])
+	b, _ := other.Anonymize(ids[0])
+	if a == b {
+		t.Error("different keys gave the same pseudonym")
+	}
+	exact := policy
+	exact.Epsilon = 0
+	if c, _ := exact.Anonymize(ids[0]); c.GetTimestamp() != ids[0].GetTimestamp() {
+		t.Error("epsilon 0 changed the timestamp")
+	}
+}
+
+func TestExportOneMillisecond(t *testing.T) {
+	// Far more IDs than a 20-bit hash could keep apart: with 4096 IDs it would
+	// collide with probability about 1 - e^-8.
+	const n = 1 << 12
+	records := func(yield func(Record) bool) {
+		for i := range n {
+			if !yield(Record{ID: mustGenerate(t, 1_700_000_000_000, uint32(i*97))}) {
+				return
+			}
+		}
+	}
+	policy := ExportPolicy{Key: []byte("0123456789abcdef")}
+	var snapshot bytes.Buffer
+	if _, err := Export(context.Background(), records, policy, &snapshot); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	sr, err := OpenSnapshot(bytes.NewReader(snapshot.Bytes()), int64(snapshot.Len()))
+	if err != nil {
+		t.Fatalf("OpenSnapshot() error = %v", err)
+	}
+	seen := make(map[Nano64]bool)
+	for id, err := range sr.All() {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		if seen[id] {
+			t.Fatalf("pseudonym %s exported twice", id.ToHex())
+		}
+		seen[id] = true
+	}
+	if len(seen) != n {
+		t.Errorf("exported %d pseudonyms, want %d", len(seen), n)
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	one := func(r Record) func(func(Record) bool) {
+		return func(yield func(Record) bool) { yield(r) }
+	}
+	id := mustGenerate(t, 1_700_000_000_000, 1)
+	tests := []struct {
+		name   string
+		src    func(func(Record) bool)
+		policy ExportPolicy
+	}{
+		{"short key", one(Record{ID: id}), ExportPolicy{Key: []byte("short")}},
+		{"negative epsilon", one(Record{ID: id}), ExportPolicy{Key: key, Epsilon: -1}},
+		{"multi-line data", one(Record{ID: id, Data: []byte("a\nb")}), ExportPolicy{Key: key}},
+		{"duplicate pseudonym", func(yield func(Record) bool) { _ = yield(Record{ID: id}) && yield(Record{ID: id}) }, ExportPolicy{Key: key}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Export(context.Background(), tt.src, tt.policy, &bytes.Buffer{}); err == nil {
+				t.Error("Export() error = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package nano64
+
+import "sync/atomic"
+
+// AtomicNano64 is a Nano64 that can be read and updated atomically, e.g. the
+// "highest ID processed" watermark shared by concurrent consumers.
+// The zero value holds Nil. An AtomicNano64 must not be copied after first use.
+type AtomicNano64 struct {
+	v atomic.Uint64
+}
+
+// Load returns the stored ID.
+func (a *AtomicNano64) Load() Nano64 {
+	return Nano64{value: a.v.Load()}
+}
+
+// Store stores id.
+func (a *AtomicNano64) Store(id Nano64) {
+	a.v.Store(id.value)
+}
+
+// Swap stores id and returns the previous ID.
+func (a *AtomicNano64) Swap(id Nano64) Nano64 {
+	return Nano64{value: a.v.Swap(id.value)}
+}
+
+// CompareAndSwap stores new if the current ID is old and reports whether it did.
+func (a *AtomicNano64) CompareAndSwap(old, new Nano64) bool {
+	return a.v.CompareAndSwap(old.value, new.value)
+}
+
+// Max stores id if it is greater than the current ID and reports whether it did.
+// Concurrent calls leave the greatest ID stored, regardless of their order.
+func (a *AtomicNano64) Max(id Nano64) bool {
+	for {
+		current := a.v.Load()
+		if id.value <= current {
+			return false
+		}
+		if a.v.CompareAndSwap(current, id.value) {
+			return true
+		}
+	}
+}
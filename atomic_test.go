@@ -0,0 +1,50 @@
+package nano64
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAtomicNano64(t *testing.T) {
+	var a AtomicNano64
+	if !a.Load().IsNil() {
+		t.Fatalf("zero value Load() = %v, want Nil", a.Load())
+	}
+
+	a.Store(New(5))
+	if got := a.Swap(New(7)); got != New(5) {
+		t.Errorf("Swap() = %v, want 5", got)
+	}
+	if a.CompareAndSwap(New(5), New(9)) {
+		t.Error("CompareAndSwap() succeeded with stale old value")
+	}
+	if !a.CompareAndSwap(New(7), New(9)) || a.Load() != New(9) {
+		t.Errorf("CompareAndSwap() failed, Load() = %v", a.Load())
+	}
+
+	if a.Max(New(3)) {
+		t.Error("Max() stored a smaller ID")
+	}
+	if !a.Max(New(11)) || a.Load() != New(11) {
+		t.Errorf("Max() did not store a larger ID, Load() = %v", a.Load())
+	}
+}
+
+func TestAtomicNano64MaxConcurrent(t *testing.T) {
+	var a AtomicNano64
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				a.Max(New(uint64(i*8 + g)))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if got := a.Load(); got != New(7999) {
+		t.Errorf("Load() after concurrent Max = %v, want 7999", got.Uint64Value())
+	}
+}
@@ -0,0 +1,194 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// defaultMaxAuditIssues is the number of issues AuditTable records when AuditOptions.MaxIssues is zero.
+const defaultMaxAuditIssues = 100
+
+// Querier is the subset of *sql.DB, *sql.Tx and *sql.Conn used by the database helpers.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// AuditIssueKind classifies a problem found by AuditTable.
+type AuditIssueKind int
+
+const (
+	// AuditNil marks a row holding the Nil ID.
+	AuditNil AuditIssueKind = iota
+	// AuditOutOfRange marks an ID whose timestamp lies outside the configured bounds.
+	AuditOutOfRange
+	// AuditDuplicate marks an ID that was already seen earlier in the scan.
+	AuditDuplicate
+	// AuditNonMonotonic marks an ID that sorts below the ID of the preceding row.
+	AuditNonMonotonic
+)
+
+// String returns a short name for the issue kind.
+func (k AuditIssueKind) String() string {
+	switch k {
+	case AuditNil:
+		return "nil"
+	case AuditOutOfRange:
+		return "out-of-range"
+	case AuditDuplicate:
+		return "duplicate"
+	case AuditNonMonotonic:
+		return "non-monotonic"
+	default:
+		return fmt.Sprintf("AuditIssueKind(%d)", int(k))
+	}
+}
+
+// AuditOptions configures AuditTable.
+type AuditOptions struct {
+	// Args are passed to the query.
+	Args []any
+
+	// MinTime and MaxTime bound plausible ID timestamps. A zero value disables that bound.
+	MinTime time.Time
+	MaxTime time.Time
+
+	// Signed interprets integer columns as SignedNano64 values instead of raw unsigned values.
+	Signed bool
+
+	// MaxIssues caps how many issues are kept in the report; counters keep running past it.
+	// Zero means 100, a negative value keeps every issue.
+	MaxIssues int
+
+	// Collisions, if set, records every duplicate with both occurrences; an
+	// error from it aborts the audit. Source names the audited data in the records.
+	Collisions CollisionSink
+	Source     string
+}
+
+// AuditIssue describes a single problem found by AuditTable.
+type AuditIssue struct {
+	Kind AuditIssueKind
+	// Row is the zero-based position of the row in the result set.
+	Row int64
+	ID  Nano64
+	// Previous is the ID of the preceding row, set for AuditNonMonotonic issues.
+	Previous Nano64
+}
+
+// AuditReport summarizes the result of AuditTable.
+type AuditReport struct {
+	Rows         int64
+	Nil          int64
+	OutOfRange   int64
+	Duplicates   int64
+	NonMonotonic int64
+
+	// Issues holds the first AuditOptions.MaxIssues problems in scan order.
+	Issues []AuditIssue
+}
+
+// OK reports whether the audit found no problems.
+func (r AuditReport) OK() bool {
+	return r.Nil == 0 && r.OutOfRange == 0 && r.Duplicates == 0 && r.NonMonotonic == 0
+}
+
+// AuditTable streams the rows returned by query and checks the ID held in the first column.
+// It reports Nil values, timestamps outside [opts.MinTime, opts.MaxTime], duplicates and
+// IDs that sort below the previous row. Further columns are ignored, so ordering the query by
+// a created_at column turns the non-monotonic check into a comparison of insertion order
+// against ID order.
+//
+// Duplicate detection keeps every seen ID in memory; audit very large tables in ID ranges.
+func AuditTable(ctx context.Context, db Querier, query string, opts AuditOptions) (AuditReport, error) {
+	maxIssues := opts.MaxIssues
+	if maxIssues == 0 {
+		maxIssues = defaultMaxAuditIssues
+	}
+
+	rows, err := db.QueryContext(ctx, query, opts.Args...)
+	if err != nil {
+		return AuditReport{}, fmt.Errorf("audit query failed: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return AuditReport{}, fmt.Errorf("failed to read audit columns: %w", err)
+	}
+	if len(columns) == 0 {
+		return AuditReport{}, fmt.Errorf("audit query returned no columns")
+	}
+
+	var raw any
+	dest := make([]any, len(columns))
+	dest[0] = &raw
+	for i := 1; i < len(dest); i++ {
+		dest[i] = new(any)
+	}
+
+	var report AuditReport
+	record := func(issue AuditIssue) {
+		if maxIssues < 0 || len(report.Issues) < maxIssues {
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	seen := make(map[uint64]int64) // ID to the row it was first seen in
+	var previous Nano64
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return report, fmt.Errorf("failed to scan row %d: %w", report.Rows, err)
+		}
+
+		var id Nano64
+		if v, ok := raw.(int64); ok && opts.Signed {
+			id = SignedNano64.ToId(v)
+		} else if err := id.Scan(raw); err != nil {
+			return report, fmt.Errorf("failed to decode ID in row %d: %w", report.Rows, err)
+		}
+
+		row := report.Rows
+		report.Rows++
+
+		if id.IsNil() {
+			report.Nil++
+			record(AuditIssue{Kind: AuditNil, Row: row, ID: id})
+			continue
+		}
+
+		ts := id.GetTimestamp()
+		if (!opts.MinTime.IsZero() && ts < opts.MinTime.UnixMilli()) ||
+			(!opts.MaxTime.IsZero() && ts > opts.MaxTime.UnixMilli()) {
+			report.OutOfRange++
+			record(AuditIssue{Kind: AuditOutOfRange, Row: row, ID: id})
+		}
+
+		if first, ok := seen[id.value]; ok {
+			report.Duplicates++
+			record(AuditIssue{Kind: AuditDuplicate, Row: row, ID: id})
+			if opts.Collisions != nil {
+				c := Collision{Source: opts.Source, First: newCollisionSide(id, first), Second: newCollisionSide(id, row), DetectedAt: time.Now()}
+				if err := opts.Collisions.RecordCollision(ctx, c); err != nil {
+					return report, fmt.Errorf("failed to record collision in row %d: %w", row, err)
+				}
+			}
+		} else {
+			seen[id.value] = row
+		}
+
+		if row > 0 && id.value < previous.value {
+			report.NonMonotonic++
+			record(AuditIssue{Kind: AuditNonMonotonic, Row: row, ID: id, Previous: previous})
+		}
+		previous = id
+	}
+	if err := rows.Err(); err != nil {
+		return report, fmt.Errorf("audit iteration failed: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,87 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestAuditTable(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE events (id INTEGER NOT NULL, created_at INTEGER NOT NULL)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	ids := []Nano64{
+		New(1000 << RandomBits),
+		New(2000 << RandomBits),
+		Nil,
+		New(1500 << RandomBits),    // inserted after a higher ID
+		New(2000 << RandomBits),    // duplicate
+		New(9000000 << RandomBits), // beyond MaxTime
+	}
+	for i, id := range ids {
+		if _, err := db.Exec("INSERT INTO events (id, created_at) VALUES (?, ?)", SignedNano64.FromId(id), i); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+
+	report, err := AuditTable(context.Background(), db, "SELECT id, created_at FROM events ORDER BY created_at", AuditOptions{
+		MaxTime: time.UnixMilli(5000000),
+		Signed:  true,
+	})
+	if err != nil {
+		t.Fatalf("AuditTable() error = %v", err)
+	}
+
+	if report.Rows != int64(len(ids)) {
+		t.Errorf("Rows = %d, want %d", report.Rows, len(ids))
+	}
+	if report.Nil != 1 || report.Duplicates != 1 || report.NonMonotonic != 1 || report.OutOfRange != 1 {
+		t.Errorf("unexpected counters: %+v", report)
+	}
+	if report.OK() {
+		t.Error("OK() = true, want false")
+	}
+	if len(report.Issues) != 4 {
+		t.Fatalf("got %d issues, want 4", len(report.Issues))
+	}
+	if issue := report.Issues[1]; issue.Kind != AuditNonMonotonic || issue.Row != 3 || !issue.Previous.Equals(ids[1]) {
+		t.Errorf("unexpected non-monotonic issue: %+v", issue)
+	}
+}
+
+func TestAuditTable_Clean(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE events (id BLOB NOT NULL)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		if _, err := db.Exec("INSERT INTO events (id) VALUES (?)", New(uint64(i)<<RandomBits)); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+
+	report, err := AuditTable(context.Background(), db, "SELECT id FROM events ORDER BY rowid", AuditOptions{})
+	if err != nil {
+		t.Fatalf("AuditTable() error = %v", err)
+	}
+	if !report.OK() || report.Rows != 3 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
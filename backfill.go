@@ -0,0 +1,140 @@
+package nano64
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// BackfillTracker records the progress of a backfill over an ID-keyed table as
+// the set of completed ID ranges, so an interrupted run resumes with what is
+// left instead of starting over. Unlike a BackupManifest it accepts ranges
+// completed in any order, e.g. by parallel workers. Progress is measured in
+// timestamp span: IDs are time-ordered, so finishing half of the target range
+// means finishing half of its time.
+//
+// A BackfillTracker is not safe for concurrent use.
+type BackfillTracker struct {
+	// Target is the range being backfilled.
+	Target IDRange `json:"target"`
+	// Completed is the part of Target already backfilled.
+	Completed RangeSet `json:"completed"`
+
+	clock        Clock
+	startedAt    int64   // epoch ms this process started tracking
+	startCovered float64 // covered IDs at startedAt
+}
+
+// NewBackfillTracker starts tracking a backfill of target. clock defaults to
+// DefaultClock and times the ETA.
+func NewBackfillTracker(target IDRange, clock Clock) (*BackfillTracker, error) {
+	if target.Empty() {
+		return nil, fmt.Errorf("backfill target %s is empty", target)
+	}
+	t := &BackfillTracker{Target: target}
+	t.start(clock)
+	return t, nil
+}
+
+// LoadBackfillTracker reads a tracker written by Save to resume its backfill.
+// The ETA only counts progress made after loading.
+func LoadBackfillTracker(r io.Reader, clock Clock) (*BackfillTracker, error) {
+	var t BackfillTracker
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, fmt.Errorf("failed to read backfill tracker: %w", err)
+	}
+	if t.Target.Empty() {
+		return nil, fmt.Errorf("backfill target %s is empty", t.Target)
+	}
+	for completed := range t.Completed.Ranges() {
+		if !t.Target.Contains(completed.First) || !t.Target.Contains(completed.Last) {
+			return nil, fmt.Errorf("backfill progress %s is outside the target %s", completed, t.Target)
+		}
+	}
+	t.start(clock)
+	return &t, nil
+}
+
+// start resets the ETA baseline to the current progress.
+func (t *BackfillTracker) start(clock Clock) {
+	if clock == nil {
+		clock = DefaultClock
+	}
+	t.clock = clock
+	t.startedAt = clock()
+	t.startCovered = t.covered()
+}
+
+// Save writes the tracker as JSON.
+func (t *BackfillTracker) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(t); err != nil {
+		return fmt.Errorf("failed to write backfill tracker: %w", err)
+	}
+	return nil
+}
+
+// Complete records that every ID of r has been backfilled. r must lie within
+// the target.
+func (t *BackfillTracker) Complete(r IDRange) error {
+	if r.Empty() || !t.Target.Contains(r.First) || !t.Target.Contains(r.Last) {
+		return fmt.Errorf("range %s is outside the backfill target %s", r, t.Target)
+	}
+	t.Completed.Add(r)
+	return nil
+}
+
+// Pending returns the parts of the target not yet backfilled, in order.
+func (t *BackfillTracker) Pending() []IDRange {
+	return t.Completed.Missing(t.Target)
+}
+
+// Next returns the first pending range, where a resumed run continues; false
+// once the backfill is done.
+func (t *BackfillTracker) Next() (IDRange, bool) {
+	pending := t.Pending()
+	if len(pending) == 0 {
+		return IDRange{}, false
+	}
+	return pending[0], true
+}
+
+// Done reports whether the whole target has been backfilled.
+func (t *BackfillTracker) Done() bool {
+	return len(t.Pending()) == 0
+}
+
+// covered returns the number of target IDs completed.
+func (t *BackfillTracker) covered() float64 {
+	var n float64
+	for r := range t.Completed.Ranges() {
+		n += float64(r.Last.value-r.First.value) + 1
+	}
+	return n
+}
+
+// Progress returns the completed fraction of the target's timestamp span, from
+// 0 to 1.
+func (t *BackfillTracker) Progress() float64 {
+	total := float64(t.Target.Last.value-t.Target.First.value) + 1
+	return min(t.covered()/total, 1)
+}
+
+// ETA estimates the time left from the rate of progress since the tracker was
+// created or loaded. It returns false until some progress has been made.
+func (t *BackfillTracker) ETA() (time.Duration, bool) {
+	if t.Done() {
+		return 0, true
+	}
+	covered := t.covered()
+	elapsed := t.clock() - t.startedAt
+	if covered <= t.startCovered || elapsed <= 0 {
+		return 0, false
+	}
+	remaining := float64(t.Target.Last.value-t.Target.First.value) + 1 - covered
+	ms := remaining * float64(elapsed) / (covered - t.startCovered)
+	return time.Duration(min(ms*float64(time.Millisecond), math.MaxInt64)), true
+}
@@ -0,0 +1,73 @@
+package nano64
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBackfillTracker(t *testing.T) {
+	const start = 1_700_000_000_000
+	target := RangeForTime(time.UnixMilli(start), time.UnixMilli(start+100))
+	clock := NewSimClock(start)
+	tr, err := NewBackfillTracker(target, clock.Now)
+	if err != nil {
+		t.Fatalf("NewBackfillTracker() error = %v", err)
+	}
+	if _, ok := tr.ETA(); ok {
+		t.Error("ETA() is known before any progress")
+	}
+
+	// Two workers finish the first and the last quarter out of order.
+	ms := func(from, to int64) IDRange {
+		return RangeForTime(time.UnixMilli(start+from), time.UnixMilli(start+to))
+	}
+	if err := tr.Complete(ms(75, 100)); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := tr.Complete(ms(0, 25)); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := tr.Complete(ms(90, 101)); err == nil {
+		t.Error("Complete() accepted a range beyond the target")
+	}
+	if p := tr.Progress(); math.Abs(p-0.5) > 1e-9 {
+		t.Errorf("Progress() = %v, want 0.5", p)
+	}
+	if next, ok := tr.Next(); !ok || next != ms(25, 75) {
+		t.Errorf("Next() = %s, %v, want %s", next, ok, ms(25, 75))
+	}
+
+	clock.Advance(10 * time.Minute)
+	if eta, ok := tr.ETA(); !ok || eta.Round(time.Second) != 10*time.Minute {
+		t.Errorf("ETA() = %v, %v, want 10m", eta, ok)
+	}
+
+	// A resumed run picks up the pending range and times only its own progress.
+	var buf bytes.Buffer
+	if err := tr.Save(&buf); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	resumed, err := LoadBackfillTracker(&buf, clock.Now)
+	if err != nil {
+		t.Fatalf("LoadBackfillTracker() error = %v", err)
+	}
+	if _, ok := resumed.ETA(); ok {
+		t.Error("ETA() of a resumed tracker counts earlier progress")
+	}
+	next, _ := resumed.Next()
+	if err := resumed.Complete(next); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if !resumed.Done() || resumed.Progress() != 1 {
+		t.Errorf("Done() = %v, Progress() = %v after completing the rest", resumed.Done(), resumed.Progress())
+	}
+	if _, ok := resumed.Next(); ok {
+		t.Error("Next() returned a range after the backfill is done")
+	}
+
+	if _, err := LoadBackfillTracker(bytes.NewBufferString(`{"target":{"first":"0000000000000-00001","last":"0000000000000-00000"}}`), nil); err == nil {
+		t.Error("LoadBackfillTracker() accepted an empty target")
+	}
+}
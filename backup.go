@@ -0,0 +1,207 @@
+package nano64
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// maxBackupRanges bounds the number of ranges ChangedRangesSince returns.
+const maxBackupRanges = 1024
+
+// ChangedRangesSince returns the ranges of IDs created after lastBackup up to and
+// including the millisecond of now, in order. Ranges are aligned to UTC hours so
+// repeated runs produce the same boundaries; after a long gap each range spans
+// several hours, keeping the result to at most 1024 ranges. Returns nil if nothing
+// can have been created since lastBackup.
+func ChangedRangesSince(lastBackup Nano64, now time.Time) []IDRange {
+	nowMs := now.UnixMilli()
+	if lastBackup.value == ^uint64(0) || nowMs < 0 {
+		return nil
+	}
+	first := lastBackup.value + 1
+	last := uint64(min(nowMs, maxTimestamp))<<timestampShift | randomMask
+	if first > last {
+		return nil
+	}
+	return splitHourly(first, last)
+}
+
+// splitHourly splits [first, last] at UTC hour boundaries, widening the step to
+// several hours as needed to return at most maxBackupRanges ranges.
+func splitHourly(first, last uint64) []IDRange {
+	hour := uint64(time.Hour.Milliseconds())
+	startMs, endMs := first>>timestampShift, last>>timestampShift
+	hours := endMs/hour - startMs/hour + 1
+	chunk := hour * ((hours + maxBackupRanges - 1) / maxBackupRanges)
+
+	var ranges []IDRange
+	for first <= last {
+		boundary := (first>>timestampShift/chunk + 1) * chunk
+		end := last
+		if boundary <= endMs {
+			end = boundary<<timestampShift - 1
+		}
+		ranges = append(ranges, IDRange{First: Nano64{value: first}, Last: Nano64{value: end}})
+		first = end + 1
+		if end == last {
+			break
+		}
+	}
+	return ranges
+}
+
+// BackupRange is one range of a BackupManifest and the progress made copying it.
+type BackupRange struct {
+	Range IDRange `json:"range"`
+
+	// Copied is the highest ID of the range known to be backed up, or Nil if none.
+	// Ranges are copied in ID order, so everything before it is backed up too.
+	Copied Nano64 `json:"copied"`
+
+	// Done is set once the whole range is backed up.
+	Done bool `json:"done"`
+}
+
+// BackupManifest records an incremental backup of an ID-keyed table as a list of
+// ranges, so an interrupted run can resume where it stopped instead of starting
+// over. Workers copy each pending range in ID order and report progress with
+// Record and Finish; the manifest is persisted with Save between steps.
+//
+// A BackupManifest is not safe for concurrent use.
+type BackupManifest struct {
+	// Since is the lastBackup the manifest was planned from.
+	Since Nano64 `json:"since"`
+	// Until is the last ID covered by the manifest.
+	Until Nano64 `json:"until"`
+	// Ranges are the ranges to copy, in order.
+	Ranges []BackupRange `json:"ranges"`
+}
+
+// NewBackupManifest plans a backup of the IDs created after lastBackup up to now.
+func NewBackupManifest(lastBackup Nano64, now time.Time) *BackupManifest {
+	m := &BackupManifest{Since: lastBackup, Until: lastBackup}
+	for _, r := range ChangedRangesSince(lastBackup, now) {
+		m.Ranges = append(m.Ranges, BackupRange{Range: r})
+		m.Until = r.Last
+	}
+	return m
+}
+
+// LoadBackupManifest reads a manifest written by Save.
+func LoadBackupManifest(r io.Reader) (*BackupManifest, error) {
+	var m BackupManifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to read backup manifest: %w", err)
+	}
+
+	prev := m.Since
+	for i, br := range m.Ranges {
+		if br.Range.First.value != prev.value+1 || br.Range.Empty() {
+			return nil, fmt.Errorf("backup manifest range %d %s is not contiguous", i, br.Range)
+		}
+		if !br.Copied.IsNil() && !br.Range.Contains(br.Copied) {
+			return nil, fmt.Errorf("backup manifest range %d progress %s is outside the range", i, br.Copied.ToHex())
+		}
+		prev = br.Range.Last
+	}
+	if prev != m.Until {
+		return nil, fmt.Errorf("backup manifest ranges end at %s, want %s", prev.ToHex(), m.Until.ToHex())
+	}
+	return &m, nil
+}
+
+// Save writes the manifest as JSON.
+func (m *BackupManifest) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	return nil
+}
+
+// find returns the index of the range containing id, or -1.
+func (m *BackupManifest) find(id Nano64) int {
+	i := sort.Search(len(m.Ranges), func(i int) bool { return m.Ranges[i].Range.Last.value >= id.value })
+	if i < len(m.Ranges) && m.Ranges[i].Range.Contains(id) {
+		return i
+	}
+	return -1
+}
+
+// Pending returns what is left to copy: the remainder of every unfinished range,
+// in order.
+func (m *BackupManifest) Pending() []IDRange {
+	var pending []IDRange
+	for _, br := range m.Ranges {
+		if br.Done {
+			continue
+		}
+		r := br.Range
+		if !br.Copied.IsNil() {
+			r.First.value = br.Copied.value + 1
+		}
+		pending = append(pending, r)
+	}
+	return pending
+}
+
+// Record notes that every ID of its range up to and including through has been
+// backed up. Progress never moves backwards, and recording the last ID of a range
+// finishes it.
+func (m *BackupManifest) Record(through Nano64) error {
+	i := m.find(through)
+	if i < 0 {
+		return fmt.Errorf("ID %s is outside the backup manifest", through.ToHex())
+	}
+	br := &m.Ranges[i]
+	if br.Copied.IsNil() || through.value > br.Copied.value {
+		br.Copied = through
+	}
+	if through == br.Range.Last {
+		br.Done = true
+	}
+	return nil
+}
+
+// Finish marks the range starting at first as fully backed up, typically after its
+// last page came back short.
+func (m *BackupManifest) Finish(first Nano64) error {
+	i := m.find(first)
+	if i < 0 || m.Ranges[i].Range.First != first {
+		return fmt.Errorf("no backup manifest range starts at %s", first.ToHex())
+	}
+	m.Ranges[i].Done = true
+	return nil
+}
+
+// Done reports whether every range has been backed up.
+func (m *BackupManifest) Done() bool {
+	for _, br := range m.Ranges {
+		if !br.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// Watermark returns the highest ID such that it and every ID before it are backed
+// up. Passing it as lastBackup to the next run loses nothing, even if this run did
+// not complete.
+func (m *BackupManifest) Watermark() Nano64 {
+	watermark := m.Since
+	for _, br := range m.Ranges {
+		if br.Done {
+			watermark = br.Range.Last
+			continue
+		}
+		if !br.Copied.IsNil() {
+			watermark = br.Copied
+		}
+		break
+	}
+	return watermark
+}
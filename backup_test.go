@@ -0,0 +1,97 @@
+package nano64
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestChangedRangesSince(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	last := mustGenerate(t, base.UnixMilli(), 42)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{"same hour", base.Add(10 * time.Minute), 1},
+		{"next hour", base.Add(time.Hour), 2},
+		{"three hours", base.Add(150 * time.Minute), 4},
+		{"before last backup", base.Add(-time.Minute), 0},
+		{"long gap", base.Add(5 * 365 * 24 * time.Hour), maxBackupRanges},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := ChangedRangesSince(last, tt.now)
+			if len(ranges) > tt.want || (tt.want < maxBackupRanges && len(ranges) != tt.want) {
+				t.Fatalf("got %d ranges, want %d", len(ranges), tt.want)
+			}
+			if len(ranges) == 0 {
+				return
+			}
+			if ranges[0].First.value != last.value+1 {
+				t.Errorf("first range starts at %s, want just after %s", ranges[0].First.ToHex(), last.ToHex())
+			}
+			end := ranges[len(ranges)-1].Last
+			if end.GetTimestamp() != tt.now.UnixMilli() || end.GetRandom() != randomMask {
+				t.Errorf("last range ends at %s, want end of now", end.ToHex())
+			}
+			for i := 1; i < len(ranges); i++ {
+				if ranges[i].First.value != ranges[i-1].Last.value+1 {
+					t.Fatalf("ranges %d and %d are not contiguous", i-1, i)
+				}
+				if ranges[i].First.GetRandom() != 0 || ranges[i].First.GetTimestamp()%time.Hour.Milliseconds() != 0 {
+					t.Errorf("range %d does not start on an hour: %s", i, ranges[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBackupManifestResume(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	last := mustGenerate(t, base.UnixMilli(), 0)
+	m := NewBackupManifest(last, base.Add(80*time.Minute))
+	if len(m.Ranges) != 2 {
+		t.Fatalf("got %d ranges, want 2", len(m.Ranges))
+	}
+
+	copied := mustGenerate(t, base.Add(10*time.Minute).UnixMilli(), 7)
+	if err := m.Record(copied); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if got := m.Watermark(); got != copied {
+		t.Errorf("Watermark() = %s, want %s", got.ToHex(), copied.ToHex())
+	}
+	if err := m.Record(last); err == nil {
+		t.Error("Record() expected error for ID outside the manifest")
+	}
+
+	var buf bytes.Buffer
+	if err := m.Save(&buf); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	resumed, err := LoadBackupManifest(&buf)
+	if err != nil {
+		t.Fatalf("LoadBackupManifest() error = %v", err)
+	}
+
+	pending := resumed.Pending()
+	if len(pending) != 2 || pending[0].First.value != copied.value+1 {
+		t.Fatalf("Pending() = %v, want remainder after %s", pending, copied.ToHex())
+	}
+
+	if err := resumed.Finish(m.Ranges[0].Range.First); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if err := resumed.Record(m.Until); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if !resumed.Done() || resumed.Watermark() != m.Until {
+		t.Errorf("Done() = %v, Watermark() = %s, want done through %s", resumed.Done(), resumed.Watermark().ToHex(), m.Until.ToHex())
+	}
+	if next := ChangedRangesSince(resumed.Watermark(), base.Add(80*time.Minute)); next != nil {
+		t.Errorf("ChangedRangesSince() after completed backup = %v, want nil", next)
+	}
+}
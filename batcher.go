@@ -0,0 +1,152 @@
+package nano64
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// BatcherOptions configures a Batcher.
+type BatcherOptions[T any] struct {
+	// Resolution is the bucket width; buckets are aligned as by DownsampleKey.
+	// Required.
+	Resolution time.Duration
+
+	// Delay is how long after a bucket's end it is flushed, to let late items
+	// arrive. Zero flushes as soon as the bucket has ended.
+	Delay time.Duration
+
+	// Flush writes one bucket's items, e.g. as a file or a multi-row insert.
+	// bucket is the bucket's DownsampleKey. Required.
+	Flush func(ctx context.Context, bucket Nano64, items []T) error
+
+	// Clock decides when buckets are due; DefaultClock if nil.
+	Clock Clock
+}
+
+// Batcher groups items by the time bucket of their ID and flushes each bucket
+// once the wall clock passes its end plus a delay, for writers producing one
+// file or insert per time bucket. Items arriving for a bucket already flushed
+// form a new batch of that bucket.
+// A Batcher is safe for concurrent use.
+type Batcher[T any] struct {
+	resolution time.Duration
+	delay      int64
+	flush      func(ctx context.Context, bucket Nano64, items []T) error
+	clock      Clock
+
+	// flushMu serializes flushes so a bucket's batches are written in order.
+	flushMu sync.Mutex
+	mu      sync.Mutex
+	buckets map[Nano64][]T
+}
+
+// NewBatcher creates a Batcher.
+func NewBatcher[T any](opts BatcherOptions[T]) (*Batcher[T], error) {
+	if opts.Resolution < time.Millisecond {
+		return nil, fmt.Errorf("batch resolution must be at least 1ms, got %v", opts.Resolution)
+	}
+	if opts.Delay < 0 {
+		return nil, fmt.Errorf("batch delay must not be negative, got %v", opts.Delay)
+	}
+	if opts.Flush == nil {
+		return nil, fmt.Errorf("batch Flush function is required")
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	return &Batcher[T]{
+		resolution: opts.Resolution,
+		delay:      opts.Delay.Milliseconds(),
+		flush:      opts.Flush,
+		clock:      clock,
+		buckets:    make(map[Nano64][]T),
+	}, nil
+}
+
+// Add queues item in the bucket of id.
+func (b *Batcher[T]) Add(id Nano64, item T) {
+	bucket := DownsampleKey(id, b.resolution)
+	b.mu.Lock()
+	b.buckets[bucket] = append(b.buckets[bucket], item)
+	b.mu.Unlock()
+}
+
+// Pending returns the number of queued items.
+func (b *Batcher[T]) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for _, items := range b.buckets {
+		n += len(items)
+	}
+	return n
+}
+
+// FlushDue flushes the buckets whose deadline has passed, oldest first.
+func (b *Batcher[T]) FlushDue(ctx context.Context) error {
+	cutoff := b.clock() - b.resolution.Milliseconds() - b.delay
+	return b.flushWhere(ctx, func(bucket Nano64) bool { return bucket.GetTimestamp() <= cutoff })
+}
+
+// Flush flushes every bucket regardless of its deadline, oldest first.
+func (b *Batcher[T]) Flush(ctx context.Context) error {
+	return b.flushWhere(ctx, func(Nano64) bool { return true })
+}
+
+// flushWhere flushes the buckets selected by due. A bucket whose flush fails is
+// put back, ahead of items added meanwhile, and the error returned.
+func (b *Batcher[T]) flushWhere(ctx context.Context, due func(Nano64) bool) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	var buckets []Nano64
+	for bucket := range b.buckets {
+		if due(bucket) {
+			buckets = append(buckets, bucket)
+		}
+	}
+	b.mu.Unlock()
+	slices.SortFunc(buckets, Compare)
+
+	for _, bucket := range buckets {
+		b.mu.Lock()
+		items := b.buckets[bucket]
+		delete(b.buckets, bucket)
+		b.mu.Unlock()
+
+		if err := b.flush(ctx, bucket, items); err != nil {
+			b.mu.Lock()
+			b.buckets[bucket] = append(items, b.buckets[bucket]...)
+			b.mu.Unlock()
+			return fmt.Errorf("failed to flush bucket %s: %w", bucket.ToDate().Format(time.RFC3339Nano), err)
+		}
+	}
+	return nil
+}
+
+// Run flushes due buckets every interval until ctx is done, then flushes all
+// remaining items with a fresh context so nothing queued is lost at shutdown.
+// It returns the first flush error.
+func (b *Batcher[T]) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("flush interval must be positive, got %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := b.FlushDue(ctx); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return b.Flush(context.WithoutCancel(ctx))
+		}
+	}
+}
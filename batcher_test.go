@@ -0,0 +1,78 @@
+package nano64
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatcher(t *testing.T) {
+	var now atomic.Int64
+	type batch struct {
+		bucket int64
+		items  []string
+	}
+	var flushed []batch
+	var fail bool
+	b, err := NewBatcher(BatcherOptions[string]{
+		Resolution: time.Second,
+		Delay:      500 * time.Millisecond,
+		Clock:      func() int64 { return now.Load() },
+		Flush: func(ctx context.Context, bucket Nano64, items []string) error {
+			if fail {
+				return errors.New("disk full")
+			}
+			flushed = append(flushed, batch{bucket.GetTimestamp(), items})
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewBatcher() error = %v", err)
+	}
+
+	b.Add(mustGenerate(t, 2100, 1), "c")
+	b.Add(mustGenerate(t, 1000, 1), "a")
+	b.Add(mustGenerate(t, 1999, 2), "b")
+	b.Add(mustGenerate(t, 3000, 3), "d")
+
+	now.Store(2499)
+	if err := b.FlushDue(context.Background()); err != nil || len(flushed) != 0 {
+		t.Fatalf("FlushDue() before the deadline flushed %v, %v", flushed, err)
+	}
+
+	now.Store(3500)
+	fail = true
+	if err := b.FlushDue(context.Background()); err == nil || b.Pending() != 4 {
+		t.Fatalf("failed FlushDue() = %v with %d pending, want an error and 4", err, b.Pending())
+	}
+	fail = false
+	if err := b.FlushDue(context.Background()); err != nil {
+		t.Fatalf("FlushDue() error = %v", err)
+	}
+	if len(flushed) != 2 || flushed[0].bucket != 1000 || len(flushed[0].items) != 2 || flushed[1].bucket != 2000 {
+		t.Fatalf("FlushDue() flushed %v, want buckets 1000 (2 items) and 2000", flushed)
+	}
+
+	// A late item forms a new batch of its bucket.
+	b.Add(mustGenerate(t, 1500, 4), "late")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Run(ctx, time.Hour); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(flushed) != 4 || flushed[2].bucket != 1000 || flushed[2].items[0] != "late" || flushed[3].bucket != 3000 || b.Pending() != 0 {
+		t.Errorf("final flush gave %v with %d pending", flushed, b.Pending())
+	}
+
+	for _, opts := range []BatcherOptions[string]{
+		{Resolution: 0, Flush: func(context.Context, Nano64, []string) error { return nil }},
+		{Resolution: time.Second},
+		{Resolution: time.Second, Delay: -time.Second, Flush: func(context.Context, Nano64, []string) error { return nil }},
+	} {
+		if _, err := NewBatcher(opts); err == nil {
+			t.Errorf("NewBatcher(%+v) succeeded", opts)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package nano64
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestBiTemporal(t *testing.T) {
+	fact := mustGenerate(t, 1_000_000, 7)
+	original := BiTemporal{Valid: fact, System: mustGenerate(t, 2_000_000, 1)}
+	correction := BiTemporal{Valid: fact, System: mustGenerate(t, 3_000_000, 1)}
+	later := BiTemporal{Valid: mustGenerate(t, 1_500_000, 0), System: mustGenerate(t, 1_600_000, 0)}
+
+	pairs := []BiTemporal{later, correction, original}
+	slices.SortFunc(pairs, CompareBiTemporal)
+	if want := []BiTemporal{original, correction, later}; !slices.Equal(pairs, want) {
+		t.Errorf("sorted = %v, want %v", pairs, want)
+	}
+
+	// Byte order agrees with CompareBiTemporal.
+	for i := 1; i < len(pairs); i++ {
+		if bytes.Compare(pairs[i-1].ToBytes(), pairs[i].ToBytes()) >= 0 {
+			t.Errorf("bytes of %v do not sort before %v", pairs[i-1], pairs[i])
+		}
+	}
+
+	if !original.VisibleAt(1_000_000, 2_500_000) || correction.VisibleAt(1_000_000, 2_500_000) {
+		t.Error("VisibleAt() does not separate the original from its later correction")
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded BiTemporal
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error = %v", data, err)
+	}
+	if decoded != original {
+		t.Errorf("JSON round trip = %v, want %v", decoded, original)
+	}
+}
@@ -0,0 +1,335 @@
+package nano64
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultBucketMaxOpen is the number of bucket files kept open unless configured.
+	defaultBucketMaxOpen = 4
+
+	// bucketFileExt is the extension of bucket files.
+	bucketFileExt = ".ids"
+
+	// BucketManifestName is the file BucketWriter.Close writes the manifest to.
+	BucketManifestName = "manifest.json"
+)
+
+// BucketWriterOptions configures a BucketWriter.
+type BucketWriterOptions struct {
+	// MaxFileSize rotates a bucket to a new file once its file would grow past
+	// this many bytes. Zero disables size-based rotation.
+	MaxFileSize int64
+
+	// MaxOpen is the number of bucket files kept open; writing to another bucket
+	// closes the file of the oldest one. Zero means 4.
+	MaxOpen int
+}
+
+// BucketFile describes a file written by a BucketWriter.
+type BucketFile struct {
+	// Name is the file name within the directory.
+	Name string `json:"name"`
+	// Bucket is the DownsampleKey of the bucket the file belongs to.
+	Bucket  Nano64 `json:"bucket"`
+	First   Nano64 `json:"first"`
+	Last    Nano64 `json:"last"`
+	Records int64  `json:"records"`
+	Bytes   int64  `json:"bytes"`
+	SHA256  string `json:"sha256"`
+}
+
+// bucketFile is an open file of a BucketWriter.
+type bucketFile struct {
+	info BucketFile
+	file *os.File
+	w    *bufio.Writer
+	hash hash.Hash
+}
+
+// BucketWriter lands ID-keyed records on disk in one file per time bucket, named
+// by the bucket start in UTC, e.g. "2024-06-01T12.ids" for hourly buckets, so
+// raw data is laid out uniformly before upload. Each record is written as a
+// line. A bucket moves on to a new part such as "2024-06-01T12.1.ids" when its
+// file reaches MaxFileSize, when records arrive after its file was closed, and
+// when the name is already taken, so existing files are never appended to.
+//
+// Close writes a manifest of every file with its ID span, record count and
+// SHA-256. A BucketWriter is safe for concurrent use.
+type BucketWriter struct {
+	dir         string
+	resolution  time.Duration
+	nameLayout  string
+	maxFileSize int64
+	maxOpen     int
+
+	mu     sync.Mutex
+	open   map[Nano64]*bucketFile
+	parts  map[Nano64]int
+	closed []BucketFile
+	done   bool
+}
+
+// NewBucketWriter creates a BucketWriter writing into dir, which must exist.
+// resolution must be at least a millisecond.
+func NewBucketWriter(dir string, resolution time.Duration, opts BucketWriterOptions) (*BucketWriter, error) {
+	if resolution < time.Millisecond {
+		return nil, fmt.Errorf("bucket resolution must be at least 1ms, got %v", resolution)
+	}
+	if opts.MaxFileSize < 0 || opts.MaxOpen < 0 {
+		return nil, fmt.Errorf("bucket writer limits must not be negative")
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("bucket directory: %w", err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("bucket directory %s is not a directory", dir)
+	}
+	maxOpen := opts.MaxOpen
+	if maxOpen == 0 {
+		maxOpen = defaultBucketMaxOpen
+	}
+	return &BucketWriter{
+		dir:         dir,
+		resolution:  resolution,
+		nameLayout:  bucketNameLayout(resolution),
+		maxFileSize: opts.MaxFileSize,
+		maxOpen:     maxOpen,
+		open:        make(map[Nano64]*bucketFile),
+		parts:       make(map[Nano64]int),
+	}, nil
+}
+
+// bucketNameLayout returns the time layout naming buckets of resolution, as
+// coarse as the resolution allows. Colons are avoided for portability.
+func bucketNameLayout(resolution time.Duration) string {
+	switch {
+	case resolution%(24*time.Hour) == 0:
+		return "2006-01-02"
+	case resolution%time.Hour == 0:
+		return "2006-01-02T15"
+	case resolution%time.Minute == 0:
+		return "2006-01-02T15-04"
+	case resolution%time.Second == 0:
+		return "2006-01-02T15-04-05"
+	default:
+		return "2006-01-02T15-04-05.000"
+	}
+}
+
+// Write appends record as a line to the file of id's bucket.
+func (w *BucketWriter) Write(id Nano64, record []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done {
+		return fmt.Errorf("bucket writer is closed")
+	}
+
+	bucket := DownsampleKey(id, w.resolution)
+	f := w.open[bucket]
+	size := int64(len(record)) + 1
+	if f != nil && w.maxFileSize > 0 && f.info.Bytes > 0 && f.info.Bytes+size > w.maxFileSize {
+		if err := w.closeFile(bucket); err != nil {
+			return err
+		}
+		f = nil
+	}
+	if f == nil {
+		var err error
+		if f, err = w.openFile(bucket); err != nil {
+			return err
+		}
+	}
+
+	if _, err := f.w.Write(record); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.info.Name, err)
+	}
+	if err := f.w.WriteByte('\n'); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.info.Name, err)
+	}
+	f.hash.Write(record)
+	f.hash.Write([]byte{'\n'})
+	if f.info.Records == 0 || Compare(id, f.info.First) < 0 {
+		f.info.First = id
+	}
+	if f.info.Records == 0 || Compare(id, f.info.Last) > 0 {
+		f.info.Last = id
+	}
+	f.info.Records++
+	f.info.Bytes += size
+	return nil
+}
+
+// openFile creates the next free part of bucket, first closing the oldest open
+// bucket if MaxOpen files are open; w.mu must be held.
+func (w *BucketWriter) openFile(bucket Nano64) (*bucketFile, error) {
+	if len(w.open) >= w.maxOpen {
+		oldest := Nil
+		first := true
+		for b := range w.open {
+			if first || Compare(b, oldest) < 0 {
+				oldest, first = b, false
+			}
+		}
+		if err := w.closeFile(oldest); err != nil {
+			return nil, err
+		}
+	}
+
+	base := bucket.ToDate().UTC().Format(w.nameLayout)
+	for {
+		part := w.parts[bucket]
+		w.parts[bucket] = part + 1
+		name := base + bucketFileExt
+		if part > 0 {
+			name = base + "." + strconv.Itoa(part) + bucketFileExt
+		}
+		file, err := os.OpenFile(filepath.Join(w.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bucket file: %w", err)
+		}
+		f := &bucketFile{info: BucketFile{Name: name, Bucket: bucket}, file: file, w: bufio.NewWriter(file), hash: sha256.New()}
+		w.open[bucket] = f
+		return f, nil
+	}
+}
+
+// closeFile flushes and closes the open file of bucket; w.mu must be held.
+func (w *BucketWriter) closeFile(bucket Nano64) error {
+	f := w.open[bucket]
+	delete(w.open, bucket)
+	err := f.w.Flush()
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to close %s: %w", f.info.Name, err)
+	}
+	f.info.SHA256 = hex.EncodeToString(f.hash.Sum(nil))
+	w.closed = append(w.closed, f.info)
+	return nil
+}
+
+// CloseBucketsBefore closes the files of buckets starting before t, e.g. once a
+// Batcher-style deadline has passed, so they can be uploaded.
+func (w *BucketWriter) CloseBucketsBefore(t time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for bucket := range w.open {
+		if bucket.GetTimestamp() < t.UnixMilli() {
+			if err := w.closeFile(bucket); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Files returns the files closed so far, in bucket order.
+func (w *BucketWriter) Files() []BucketFile {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.sortedFiles()
+}
+
+// sortedFiles returns a sorted copy of w.closed; w.mu must be held.
+func (w *BucketWriter) sortedFiles() []BucketFile {
+	files := slices.Clone(w.closed)
+	slices.SortStableFunc(files, func(a, b BucketFile) int { return Compare(a.Bucket, b.Bucket) })
+	return files
+}
+
+// Close closes every open file and writes the manifest of all files to
+// manifest.json in the directory. Entries already in the manifest, such as those
+// of an earlier writer into the same directory, are kept. The manifest is
+// replaced by renaming a temporary file, so readers never see it partially
+// written; writers closing at the same moment must still be serialized.
+func (w *BucketWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done {
+		return nil
+	}
+	w.done = true
+	for bucket := range w.open {
+		if err := w.closeFile(bucket); err != nil {
+			return err
+		}
+	}
+
+	path := filepath.Join(w.dir, BucketManifestName)
+	files, err := readBucketManifest(path)
+	if err != nil {
+		return err
+	}
+	files = slices.DeleteFunc(files, func(f BucketFile) bool {
+		return slices.ContainsFunc(w.closed, func(c BucketFile) bool { return c.Name == f.Name })
+	})
+	files = append(files, w.closed...)
+	slices.SortStableFunc(files, func(a, b BucketFile) int { return Compare(a.Bucket, b.Bucket) })
+
+	data, err := json.MarshalIndent(files, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bucket manifest: %w", err)
+	}
+	if err := writeFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write bucket manifest: %w", err)
+	}
+	return nil
+}
+
+// readBucketManifest returns the entries of the manifest at path, or none if it
+// does not exist.
+func readBucketManifest(path string) ([]BucketFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bucket manifest: %w", err)
+	}
+	var files []BucketFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("failed to decode bucket manifest %s: %w", path, err)
+	}
+	return files, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if syncErr := tmp.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o644)
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
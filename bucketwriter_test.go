@@ -0,0 +1,193 @@
+package nano64
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBucketWriter(t *testing.T) {
+	dir := t.TempDir()
+	hour := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC).UnixMilli()
+	at := func(h int64, random uint32) Nano64 {
+		return mustGenerate(t, hour+h*time.Hour.Milliseconds()+int64(random), random)
+	}
+	// An existing file is never appended to.
+	if err := os.WriteFile(filepath.Join(dir, "2024-06-01T14.ids"), []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewBucketWriter(dir, time.Hour, BucketWriterOptions{MaxFileSize: 12, MaxOpen: 2})
+	if err != nil {
+		t.Fatalf("NewBucketWriter() error = %v", err)
+	}
+	writes := []struct {
+		id     Nano64
+		record string
+	}{
+		{at(0, 1), "aaaaa"},
+		{at(0, 2), "bbbbb"},
+		{at(0, 3), "ccccc"}, // rotates hour 12 by size
+		{at(1, 1), "ddddd"},
+		{at(2, 1), "eeeee"}, // closes hour 12 as the oldest open bucket
+		{at(0, 4), "fffff"}, // late record for hour 12, closes hour 13
+	}
+	for _, write := range writes {
+		if err := w.Write(write.id, []byte(write.record)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.Write(at(0, 5), []byte("x")); err == nil {
+		t.Error("Write() after Close() succeeded")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, BucketManifestName))
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	var files []BucketFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		t.Fatalf("failed to decode manifest: %v", err)
+	}
+
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+		content, err := os.ReadFile(filepath.Join(dir, f.Name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", f.Name, err)
+		}
+		sum := sha256.Sum256(content)
+		if f.SHA256 != hex.EncodeToString(sum[:]) || f.Bytes != int64(len(content)) || f.Bytes != 6*f.Records {
+			t.Errorf("manifest entry %+v does not match its %d-byte file", f, len(content))
+		}
+		if DownsampleKey(f.First, time.Hour) != f.Bucket || DownsampleKey(f.Last, time.Hour) != f.Bucket {
+			t.Errorf("file %s spans %s-%s outside its bucket", f.Name, f.First.ToHex(), f.Last.ToHex())
+		}
+	}
+	want := []string{"2024-06-01T12.ids", "2024-06-01T12.1.ids", "2024-06-01T12.2.ids", "2024-06-01T13.ids", "2024-06-01T14.1.ids"}
+	if !slices.Equal(names, want) {
+		t.Errorf("files = %v, want %v", names, want)
+	}
+	if files[0].Records != 2 || files[1].First != at(0, 3) || files[2].Last != at(0, 4) {
+		t.Errorf("unexpected hour 12 files: %+v", files[:3])
+	}
+	if old, _ := os.ReadFile(filepath.Join(dir, "2024-06-01T14.ids")); string(old) != "old\n" {
+		t.Errorf("existing file was modified: %q", old)
+	}
+}
+
+func TestBucketWriterKeepsManifest(t *testing.T) {
+	dir := t.TempDir()
+	hour := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC).UnixMilli()
+	for run, record := range []string{"first", "second"} {
+		w, err := NewBucketWriter(dir, time.Hour, BucketWriterOptions{})
+		if err != nil {
+			t.Fatalf("NewBucketWriter() error = %v", err)
+		}
+		id := mustGenerate(t, hour+int64(run)*time.Hour.Milliseconds(), 1)
+		if err := w.Write(id, []byte(record)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+	// A later run into an hour an earlier run already wrote takes the next part.
+	w, err := NewBucketWriter(dir, time.Hour, BucketWriterOptions{})
+	if err != nil {
+		t.Fatalf("NewBucketWriter() error = %v", err)
+	}
+	if err := w.Write(mustGenerate(t, hour, 2), []byte("third")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, BucketManifestName))
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	var files []BucketFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		t.Fatalf("failed to decode manifest: %v", err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	want := []string{"2024-06-01T12.ids", "2024-06-01T12.1.ids", "2024-06-01T13.ids"}
+	if !slices.Equal(names, want) {
+		t.Errorf("manifest lists %v, want %v", names, want)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(want)+1 {
+		t.Errorf("directory holds %d entries, want the files and the manifest only", len(entries))
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, BucketManifestName), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if w, err = NewBucketWriter(dir, time.Hour, BucketWriterOptions{}); err != nil {
+		t.Fatalf("NewBucketWriter() error = %v", err)
+	}
+	if err := w.Close(); err == nil {
+		t.Error("Close() over a corrupt manifest succeeded")
+	}
+}
+
+func TestBucketWriterNamesInUTC(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = la
+
+	dir := t.TempDir()
+	w, err := NewBucketWriter(dir, 24*time.Hour, BucketWriterOptions{})
+	if err != nil {
+		t.Fatalf("NewBucketWriter() error = %v", err)
+	}
+	noon := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC).UnixMilli()
+	if err := w.Write(mustGenerate(t, noon, 1), []byte("a")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2024-06-01.ids")); err != nil {
+		t.Errorf("record of 2024-06-01T12Z not in 2024-06-01.ids: %v", err)
+	}
+}
+
+func TestBucketNameLayout(t *testing.T) {
+	ts := time.Date(2024, 6, 1, 12, 34, 56, 789e6, time.UTC)
+	tests := []struct {
+		resolution time.Duration
+		want       string
+	}{
+		{24 * time.Hour, "2024-06-01"},
+		{time.Hour, "2024-06-01T12"},
+		{15 * time.Minute, "2024-06-01T12-34"},
+		{time.Second, "2024-06-01T12-34-56"},
+		{100 * time.Millisecond, "2024-06-01T12-34-56.789"},
+	}
+	for _, tt := range tests {
+		if got := ts.Format(bucketNameLayout(tt.resolution)); got != tt.want {
+			t.Errorf("bucketNameLayout(%v) formats %q, want %q", tt.resolution, got, tt.want)
+		}
+	}
+}
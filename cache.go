@@ -0,0 +1,68 @@
+package nano64
+
+import (
+	"strings"
+	"time"
+)
+
+// AppendCacheKey appends the cache key for id under prefix to dst, as CacheKey
+// formats it, without allocating when dst has room.
+func AppendCacheKey(dst []byte, prefix string, id Nano64) []byte {
+	if prefix != "" {
+		dst = append(dst, prefix...)
+		dst = append(dst, ':')
+	}
+	return appendHex(dst, id)
+}
+
+// CacheKey returns the cache key for an ID-addressed entity, e.g. "user:0199C6A1F5E-3B2D4".
+// The key is built in a single allocation, so it is cheap enough for hot lookup paths.
+func CacheKey(prefix string, id Nano64) string {
+	var hex [hexLength]byte
+	var b strings.Builder
+	b.Grow(len(prefix) + 1 + hexLength)
+	if prefix != "" {
+		b.WriteString(prefix)
+		b.WriteByte(':')
+	}
+	b.Write(appendHex(hex[:0], id))
+	return b.String()
+}
+
+// TTLPolicy derives cache TTLs from entity age: recently created entities tend to
+// change often and get short TTLs, long-lived ones settle and can be cached longer.
+type TTLPolicy struct {
+	// Min and Max bound the suggested TTL.
+	Min, Max time.Duration
+
+	// Factor scales the entity's age into a TTL. Zero means 0.1 (10% of the age),
+	// the heuristic HTTP caches use for freshness of unversioned resources.
+	Factor float64
+
+	// Clock supplies the current time. Nil means DefaultClock.
+	Clock Clock
+}
+
+// SuggestedTTL returns the TTL to use when caching the entity identified by id:
+// its age times policy.Factor, clamped to [policy.Min, policy.Max]. IDs from the
+// future get policy.Min. A zero Max leaves the TTL unbounded above.
+func SuggestedTTL(id Nano64, policy TTLPolicy) time.Duration {
+	clock := policy.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	factor := policy.Factor
+	if factor == 0 {
+		factor = 0.1
+	}
+
+	age := time.Duration(clock()-id.GetTimestamp()) * time.Millisecond
+	ttl := time.Duration(float64(max(age, 0)) * factor)
+	if ttl < policy.Min {
+		ttl = policy.Min
+	}
+	if policy.Max > 0 && ttl > policy.Max {
+		ttl = policy.Max
+	}
+	return ttl
+}
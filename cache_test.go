@@ -0,0 +1,77 @@
+package nano64
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheKey(t *testing.T) {
+	id := New(0x0123456789ABCDEF)
+
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{"user", "user:0123456789A-BCDEF"},
+		{"", "0123456789A-BCDEF"},
+	}
+
+	for _, tt := range tests {
+		if got := CacheKey(tt.prefix, id); got != tt.want {
+			t.Errorf("CacheKey(%q) = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+
+	for _, v := range []uint64{0, 1, ^uint64(0), 0xFEDCBA9876543210} {
+		if got, want := string(appendHex(nil, New(v))), New(v).ToHex(); got != want {
+			t.Errorf("appendHex(%#x) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestCacheKeyAllocations(t *testing.T) {
+	id := New(0x0123456789ABCDEF)
+	buf := make([]byte, 0, 64)
+
+	if allocs := testing.AllocsPerRun(100, func() { buf = AppendCacheKey(buf[:0], "user", id) }); allocs != 0 {
+		t.Errorf("AppendCacheKey() allocs = %v, want 0", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { _ = CacheKey("user", id) }); allocs != 1 {
+		t.Errorf("CacheKey() allocs = %v, want 1", allocs)
+	}
+}
+
+func TestSuggestedTTL(t *testing.T) {
+	const now = 1_700_000_000_000
+	policy := TTLPolicy{
+		Min:   time.Minute,
+		Max:   time.Hour,
+		Clock: func() int64 { return now },
+	}
+	created := func(ago time.Duration) Nano64 {
+		id, err := Generate(now-ago.Milliseconds(), nil)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		return id
+	}
+
+	tests := []struct {
+		name string
+		id   Nano64
+		want time.Duration
+	}{
+		{"fresh", created(time.Second), time.Minute},
+		{"scaled", created(100 * time.Minute), 10 * time.Minute},
+		{"capped", created(48 * time.Hour), time.Hour},
+		{"future", created(-time.Hour), time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestedTTL(tt.id, policy); got != tt.want {
+				t.Errorf("SuggestedTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
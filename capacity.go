@@ -0,0 +1,120 @@
+package nano64
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// millisecondsPerDay is the horizon PlanCapacity evaluates collision risk over.
+const millisecondsPerDay = 24 * 60 * 60 * 1000
+
+// PlanMode is the generation strategy recommended by PlanCapacity.
+type PlanMode int
+
+const (
+	// PlanRandom uses Generate with fresh random bits for every ID.
+	PlanRandom PlanMode = iota
+	// PlanMonotonic uses GenerateMonotonic on a single node; IDs never collide.
+	PlanMonotonic
+	// PlanWorkerBits gives every node a distinct number in the low WorkerBits of the
+	// random field (see EmbedShard) above which it counts monotonically, so nodes
+	// never collide with each other or themselves.
+	PlanWorkerBits
+	// PlanNano128 switches to 128-bit IDs, whose 84 random bits make collisions
+	// negligible at any rate.
+	PlanNano128
+)
+
+// String returns a short name for the mode.
+func (m PlanMode) String() string {
+	switch m {
+	case PlanRandom:
+		return "random"
+	case PlanMonotonic:
+		return "monotonic"
+	case PlanWorkerBits:
+		return "worker-bits"
+	case PlanNano128:
+		return "nano128"
+	default:
+		return fmt.Sprintf("PlanMode(%d)", int(m))
+	}
+}
+
+// Plan is the recommendation of PlanCapacity.
+type Plan struct {
+	Mode PlanMode
+
+	// WorkerBits is the number of random bits reserved for node numbers in
+	// PlanWorkerBits mode, zero otherwise.
+	WorkerBits int
+
+	// CollisionProbability estimates the chance of at least one collision per day
+	// of sustained traffic at the target rate in the recommended mode.
+	CollisionProbability float64
+
+	// Reason explains the decision in one sentence.
+	Reason string
+}
+
+// randomCollisionProbability returns the chance of at least one collision per day
+// when perMs IDs per millisecond draw from a space of 2^spaceBits values.
+func randomCollisionProbability(perMs float64, spaceBits int) float64 {
+	pairsPerMs := perMs * perMs / 2
+	expected := pairsPerMs / math.Ldexp(1, spaceBits) * millisecondsPerDay
+	return -math.Expm1(-expected)
+}
+
+// PlanCapacity recommends a generation strategy for nodes writers producing
+// targetRate IDs per second in total, such that the chance of any ID collision
+// per day stays at or below maxCollisionProb. The decision tree is:
+//
+//  1. Pure random IDs, if their birthday bound meets the target. This is the
+//     simplest mode and needs no coordination.
+//  2. Monotonic generation, if there is a single node: its IDs never collide as
+//     long as it stays below 2^20 IDs per millisecond.
+//  3. Worker bits, if node numbers fit the 8 bits EmbedShard supports and every
+//     node stays below its share of the per-millisecond space. This needs a
+//     unique node number per writer.
+//  4. Nano128 otherwise.
+//
+// The estimate assumes traffic spread evenly over time; size targetRate for the
+// peak, not the average.
+func PlanCapacity(nodes int, targetRate int, maxCollisionProb float64) Plan {
+	nodes = max(nodes, 1)
+	perMs := float64(max(targetRate, 0)) / 1000
+
+	if p := randomCollisionProbability(perMs, RandomBits); p <= maxCollisionProb {
+		return Plan{
+			Mode:                 PlanRandom,
+			CollisionProbability: p,
+			Reason:               fmt.Sprintf("%.3g IDs/ms leave a %.3g daily collision chance in the 20-bit random field", perMs, p),
+		}
+	}
+
+	if nodes == 1 && perMs < 1<<RandomBits {
+		return Plan{
+			Mode:   PlanMonotonic,
+			Reason: "a single writer counting monotonically never repeats an ID",
+		}
+	}
+
+	workerBits := max(bits.Len(uint(nodes-1)), 1)
+	perNode := perMs / float64(nodes)
+	if workerBits <= maxShardBits && perNode < math.Ldexp(1, RandomBits-workerBits) {
+		return Plan{
+			Mode:       PlanWorkerBits,
+			WorkerBits: workerBits,
+			Reason: fmt.Sprintf("%d worker bits give each of %d nodes a private space of %d IDs/ms for its %.3g IDs/ms",
+				workerBits, nodes, 1<<(RandomBits-workerBits), perNode),
+		}
+	}
+
+	p := randomCollisionProbability(perMs, Random128Bits)
+	return Plan{
+		Mode:                 PlanNano128,
+		CollisionProbability: p,
+		Reason:               fmt.Sprintf("%d nodes at %.3g IDs/ms exceed what 64-bit IDs can partition; 84 random bits keep the daily collision chance at %.3g", nodes, perMs, p),
+	}
+}
@@ -0,0 +1,42 @@
+package nano64
+
+import "testing"
+
+func TestPlanCapacity(t *testing.T) {
+	tests := []struct {
+		name       string
+		nodes      int
+		rate       int
+		maxProb    float64
+		wantMode   PlanMode
+		workerBits int
+	}{
+		{"trickle", 3, 1, 0.01, PlanRandom, 0},
+		{"single writer", 1, 10_000, 1e-6, PlanMonotonic, 0},
+		{"zero nodes count as one", 0, 10_000, 1e-6, PlanMonotonic, 0},
+		{"small fleet", 16, 100_000, 1e-6, PlanWorkerBits, 4},
+		{"two nodes", 2, 100_000, 1e-6, PlanWorkerBits, 1},
+		{"too many nodes", 1000, 1_000_000, 1e-9, PlanNano128, 0},
+		{"nodes too busy", 2, 2_000_000_000, 1e-9, PlanNano128, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PlanCapacity(tt.nodes, tt.rate, tt.maxProb)
+			if plan.Mode != tt.wantMode || plan.WorkerBits != tt.workerBits {
+				t.Errorf("PlanCapacity() = %s with %d worker bits, want %s with %d (%s)",
+					plan.Mode, plan.WorkerBits, tt.wantMode, tt.workerBits, plan.Reason)
+			}
+			if plan.CollisionProbability > tt.maxProb && plan.Mode != PlanNano128 {
+				t.Errorf("CollisionProbability = %g exceeds the target %g", plan.CollisionProbability, tt.maxProb)
+			}
+			if plan.Reason == "" {
+				t.Error("Reason is empty")
+			}
+		})
+	}
+
+	// One ID per millisecond already collides about 41 times a day in 20 bits.
+	if p := randomCollisionProbability(1, RandomBits); p < 0.99 {
+		t.Errorf("randomCollisionProbability(1/ms) = %g, want near 1", p)
+	}
+}
@@ -0,0 +1,85 @@
+package nano64
+
+import (
+	"fmt"
+	"time"
+)
+
+// CausalityViolationKind classifies a problem found by CausalityCheck.
+type CausalityViolationKind int
+
+const (
+	// CausalityBeforeCause marks an event that sorts before its cause by more than the tolerance.
+	CausalityBeforeCause CausalityViolationKind = iota
+	// CausalityCorrelationMismatch marks an event whose correlation ID differs from its cause's.
+	CausalityCorrelationMismatch
+	// CausalityMissingCause marks an event whose cause is not among the checked events.
+	CausalityMissingCause
+)
+
+// String returns a short name for the violation kind.
+func (k CausalityViolationKind) String() string {
+	switch k {
+	case CausalityBeforeCause:
+		return "before-cause"
+	case CausalityCorrelationMismatch:
+		return "correlation-mismatch"
+	case CausalityMissingCause:
+		return "missing-cause"
+	default:
+		return fmt.Sprintf("CausalityViolationKind(%d)", int(k))
+	}
+}
+
+// CausalityViolation is a single problem found by CausalityCheck.
+type CausalityViolation struct {
+	Kind  CausalityViolationKind
+	Event Envelope
+	// Cause is the event's cause; it is the zero Envelope for CausalityMissingCause.
+	Cause Envelope
+	// Skew is how far the event's timestamp lies before its cause's, for CausalityBeforeCause.
+	Skew time.Duration
+}
+
+// CausalityCheck audits events collected from several services and reports every
+// event that sorts before its cause, disagrees with its cause about the
+// correlation ID, or refers to a cause that is not in events.
+//
+// Clocks of different services drift apart, so an effect minted on another host
+// may legitimately carry a slightly earlier timestamp than its cause. Such an event
+// is only reported once the gap exceeds tolerance; with a zero tolerance any effect
+// whose ID is not above its cause's is reported. Violations follow the order of events.
+func CausalityCheck(events []Envelope, tolerance time.Duration) []CausalityViolation {
+	byID := make(map[Nano64]Envelope, len(events))
+	for _, e := range events {
+		byID[e.ID] = e
+	}
+
+	var violations []CausalityViolation
+	for _, e := range events {
+		if e.IsRoot() {
+			continue
+		}
+		cause, ok := byID[e.CausationID.ID]
+		if !ok {
+			violations = append(violations, CausalityViolation{Kind: CausalityMissingCause, Event: e})
+			continue
+		}
+
+		if Compare(e.ID, cause.ID) <= 0 {
+			skew := time.Duration(cause.ID.GetTimestamp()-e.ID.GetTimestamp()) * time.Millisecond
+			if tolerance == 0 || skew > tolerance {
+				violations = append(violations, CausalityViolation{
+					Kind:  CausalityBeforeCause,
+					Event: e,
+					Cause: cause,
+					Skew:  skew,
+				})
+			}
+		}
+		if e.CorrelationID != cause.CorrelationID {
+			violations = append(violations, CausalityViolation{Kind: CausalityCorrelationMismatch, Event: e, Cause: cause})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,53 @@
+package nano64
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCausalityCheck(t *testing.T) {
+	envelope := func(ts int64, random uint32, cause *Envelope) Envelope {
+		e := Envelope{ID: mustGenerate(t, ts, random)}
+		e.CorrelationID = e.ID
+		if cause != nil {
+			e.CorrelationID = cause.CorrelationID
+			e.CausationID = NullNano64{ID: cause.ID, Valid: true}
+		}
+		return e
+	}
+
+	root := envelope(10_000, 5, nil)
+	ordered := envelope(10_001, 0, &root)
+	sameMs := envelope(10_000, 1, &root) // lower ID within the same millisecond
+	skewed := envelope(9_990, 0, &root)  // 10ms before its cause
+	orphan := envelope(10_002, 0, &Envelope{ID: mustGenerate(t, 9_000, 0)})
+	foreign := envelope(10_003, 0, &ordered)
+	foreign.CorrelationID = orphan.ID
+	events := []Envelope{root, ordered, sameMs, skewed, orphan, foreign}
+
+	tests := []struct {
+		tolerance time.Duration
+		want      []CausalityViolationKind
+	}{
+		{0, []CausalityViolationKind{CausalityBeforeCause, CausalityBeforeCause, CausalityMissingCause, CausalityCorrelationMismatch}},
+		{5 * time.Millisecond, []CausalityViolationKind{CausalityBeforeCause, CausalityMissingCause, CausalityCorrelationMismatch}},
+		{time.Second, []CausalityViolationKind{CausalityMissingCause, CausalityCorrelationMismatch}},
+	}
+	for _, tt := range tests {
+		got := CausalityCheck(events, tt.tolerance)
+		if len(got) != len(tt.want) {
+			t.Errorf("CausalityCheck(%v) = %d violations %v, want %v", tt.tolerance, len(got), got, tt.want)
+			continue
+		}
+		for i, v := range got {
+			if v.Kind != tt.want[i] {
+				t.Errorf("CausalityCheck(%v)[%d] = %s, want %s", tt.tolerance, i, v.Kind, tt.want[i])
+			}
+		}
+	}
+
+	violations := CausalityCheck(events, 5*time.Millisecond)
+	if v := violations[0]; v.Event != skewed || v.Cause != root || v.Skew != 10*time.Millisecond {
+		t.Errorf("violation = %+v, want skewed event 10ms before root", v)
+	}
+}
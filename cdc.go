@@ -0,0 +1,159 @@
+package nano64
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CDCEncoding selects how DecodeCDC interprets a change-data-capture column value.
+type CDCEncoding int
+
+const (
+	// CDCAuto infers the encoding from the value: integers are read as SignedNano64
+	// BIGINTs, `\x`-prefixed strings as wal2json bytea, 12-char padded strings as
+	// Debezium base64 bytea, dashed or lettered 16-char strings as hex and digit-only
+	// strings as decimal BIGINTs. Pick an explicit encoding when hex values may
+	// consist of digits only.
+	CDCAuto CDCEncoding = iota
+	// CDCSigned reads integers (or decimal strings) in SignedNano64 form, as stored in BIGINT columns.
+	CDCSigned
+	// CDCUnsigned reads integers (or decimal strings) as raw unsigned values.
+	CDCUnsigned
+	// CDCBase64 reads standard base64 of the 8 big-endian bytes (Debezium's default bytea handling).
+	CDCBase64
+	// CDCHex reads hex of the 8 big-endian bytes, with or without a `\x` or `0x` prefix or dash.
+	CDCHex
+)
+
+// DecodeCDC decodes a Nano64 from a column value of a Debezium or wal2json payload.
+// It accepts the values produced by encoding/json (string, json.Number, float64),
+// raw []byte or json.RawMessage fragments and Go integers.
+//
+// Decode payloads with json.Decoder.UseNumber: float64 cannot represent every
+// 64-bit ID and is rejected unless it is exactly integral.
+func DecodeCDC(value any, enc CDCEncoding) (Nano64, error) {
+	switch v := value.(type) {
+	case nil:
+		return Nil, fmt.Errorf("cannot decode NULL CDC value into Nano64")
+	case json.RawMessage:
+		var decoded any
+		dec := json.NewDecoder(strings.NewReader(string(v)))
+		dec.UseNumber()
+		if err := dec.Decode(&decoded); err != nil {
+			return Nil, fmt.Errorf("invalid CDC JSON value: %w", err)
+		}
+		return DecodeCDC(decoded, enc)
+	case []byte:
+		if len(v) == 8 && enc != CDCSigned && enc != CDCUnsigned {
+			return FromBytes(v)
+		}
+		return decodeCDCString(string(v), enc)
+	case string:
+		return decodeCDCString(v, enc)
+	case json.Number:
+		return decodeCDCString(v.String(), cdcIntegerEncoding(enc))
+	case int64:
+		return decodeCDCInteger(uint64(v), enc)
+	case int:
+		return decodeCDCInteger(uint64(v), enc)
+	case uint64:
+		return decodeCDCInteger(v, enc)
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return Nil, fmt.Errorf("CDC number %v is not an exact integer; decode with UseNumber", v)
+		}
+		return decodeCDCInteger(uint64(int64(v)), enc)
+	default:
+		return Nil, fmt.Errorf("cannot decode CDC value of type %T into Nano64", value)
+	}
+}
+
+// DecodeCDCNull is like DecodeCDC but maps NULL to an invalid NullNano64.
+func DecodeCDCNull(value any, enc CDCEncoding) (NullNano64, error) {
+	if value == nil {
+		return NullNano64{}, nil
+	}
+	if raw, ok := value.(json.RawMessage); ok && strings.TrimSpace(string(raw)) == "null" {
+		return NullNano64{}, nil
+	}
+	id, err := DecodeCDC(value, enc)
+	if err != nil {
+		return NullNano64{}, err
+	}
+	return NullNano64{ID: id, Valid: true}, nil
+}
+
+// cdcIntegerEncoding maps CDCAuto to CDCSigned for values known to be numeric.
+func cdcIntegerEncoding(enc CDCEncoding) CDCEncoding {
+	if enc == CDCAuto {
+		return CDCSigned
+	}
+	return enc
+}
+
+// decodeCDCInteger interprets the bits of an integer column value.
+func decodeCDCInteger(bits uint64, enc CDCEncoding) (Nano64, error) {
+	switch cdcIntegerEncoding(enc) {
+	case CDCSigned:
+		return SignedNano64.ToId(int64(bits)), nil
+	case CDCUnsigned:
+		return FromUint64(bits), nil
+	default:
+		return Nil, fmt.Errorf("integer CDC value cannot be decoded as bytea")
+	}
+}
+
+// decodeCDCString decodes a textual column value.
+func decodeCDCString(s string, enc CDCEncoding) (Nano64, error) {
+	if enc == CDCAuto {
+		enc = detectCDCEncoding(s)
+	}
+
+	switch enc {
+	case CDCSigned:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return Nil, fmt.Errorf("invalid signed CDC value: %w", err)
+		}
+		return SignedNano64.ToId(v), nil
+	case CDCUnsigned:
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return Nil, fmt.Errorf("invalid unsigned CDC value: %w", err)
+		}
+		return FromUint64(v), nil
+	case CDCBase64:
+		bytes, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return Nil, fmt.Errorf("invalid base64 CDC value: %w", err)
+		}
+		return FromBytes(bytes)
+	case CDCHex:
+		if strings.HasPrefix(s, `\x`) {
+			s = s[2:]
+		}
+		return FromHex(s)
+	default:
+		return Nil, fmt.Errorf("unknown CDC encoding %d", int(enc))
+	}
+}
+
+// detectCDCEncoding guesses the encoding of a textual value for CDCAuto.
+func detectCDCEncoding(s string) CDCEncoding {
+	switch {
+	case strings.HasPrefix(s, `\x`), strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		return CDCHex
+	case len(s) == 12 && strings.HasSuffix(s, "="):
+		return CDCBase64
+	case len(s) == 17 && s[11] == '-':
+		return CDCHex
+	case len(s) == 16 && strings.ContainsAny(s, "abcdefABCDEF"):
+		return CDCHex
+	default:
+		return CDCSigned
+	}
+}
@@ -0,0 +1,82 @@
+package nano64
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func TestDecodeCDC(t *testing.T) {
+	id := New(0x0123456789ABCDEF)
+	signed := SignedNano64.FromId(id)
+
+	tests := []struct {
+		name  string
+		value any
+		enc   CDCEncoding
+	}{
+		{"debezium int64", json.Number(strconv.FormatInt(signed, 10)), CDCAuto},
+		{"go int64", signed, CDCAuto},
+		{"unsigned number", json.Number(strconv.FormatUint(id.Uint64Value(), 10)), CDCUnsigned},
+		{"debezium base64", base64.StdEncoding.EncodeToString(id.ToBytes()), CDCAuto},
+		{"wal2json bytea", `\x0123456789abcdef`, CDCAuto},
+		{"dashed hex", id.ToHex(), CDCAuto},
+		{"raw message", json.RawMessage(`"ASNFZ4mrze8="`), CDCAuto},
+		{"raw bytes", id.ToBytes(), CDCAuto},
+		{"explicit hex", "0123456789000000", CDCHex},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCDC(tt.value, tt.enc)
+			if err != nil {
+				t.Fatalf("DecodeCDC() error = %v", err)
+			}
+			want := id
+			if tt.name == "explicit hex" {
+				want = New(0x0123456789000000)
+			}
+			if !got.Equals(want) {
+				t.Errorf("DecodeCDC() = %s, want %s", got.ToHex(), want.ToHex())
+			}
+		})
+	}
+}
+
+func TestDecodeCDC_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		enc   CDCEncoding
+	}{
+		{"nil", nil, CDCAuto},
+		{"lossy float", float64(1 << 60), CDCAuto},
+		{"fractional float", 1.5, CDCAuto},
+		{"bad base64", "!!!!!!!!!!!=", CDCBase64},
+		{"integer as hex", int64(1), CDCHex},
+		{"unsupported type", true, CDCAuto},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCDC(tt.value, tt.enc); err == nil {
+				t.Error("DecodeCDC() expected error")
+			}
+		})
+	}
+}
+
+func TestDecodeCDCNull(t *testing.T) {
+	for _, value := range []any{nil, json.RawMessage("null")} {
+		got, err := DecodeCDCNull(value, CDCAuto)
+		if err != nil || got.Valid {
+			t.Errorf("DecodeCDCNull(%v) = %+v, %v; want invalid", value, got, err)
+		}
+	}
+
+	got, err := DecodeCDCNull(int64(5), CDCUnsigned)
+	if err != nil || !got.Valid || got.ID.Uint64Value() != 5 {
+		t.Errorf("DecodeCDCNull(5) = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,162 @@
+package nano64
+
+import (
+	"fmt"
+	"time"
+)
+
+// TwitterSnowflakeEpoch is the epoch (Unix ms) of Twitter's Snowflake IDs, the
+// default of Classifier.SnowflakeEpoch.
+const TwitterSnowflakeEpoch int64 = 1288834974657
+
+// Snowflake layout: 41-bit timestamp, 10-bit worker, 12-bit sequence.
+const (
+	snowflakeSequenceBits = 12
+	snowflakeTimeShift    = 22
+
+	// snowflakeLowSequence is the sequence below which a value that reads as
+	// both a Nano64 and a Snowflake counts as a Snowflake. Generators rarely
+	// issue more than a few IDs per millisecond per worker, while a random
+	// field lands this low with probability 16/4096.
+	snowflakeLowSequence = 16
+
+	// snowflakeMinOffset is the earliest plausible Snowflake timestamp after its
+	// epoch; otherwise every small integer would read as a Snowflake.
+	snowflakeMinOffset = 24 * time.Hour
+)
+
+// Kind is the apparent format of a 64-bit ID value.
+type Kind int
+
+const (
+	// KindUnknown is returned for the value 0, which no format produces.
+	KindUnknown Kind = iota
+	// KindNano64 is a Nano64 with a plausible timestamp.
+	KindNano64
+	// KindSnowflake is a Snowflake ID (41-bit timestamp since the Snowflake
+	// epoch, worker and sequence) with a plausible timestamp.
+	KindSnowflake
+	// KindUnixSeconds is a raw Unix timestamp in seconds.
+	KindUnixSeconds
+	// KindUnixMillis is a raw Unix timestamp in milliseconds.
+	KindUnixMillis
+	// KindRandom is a value with no plausible timestamp in any known format.
+	KindRandom
+)
+
+// String returns a short name for the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindUnknown:
+		return "unknown"
+	case KindNano64:
+		return "nano64"
+	case KindSnowflake:
+		return "snowflake"
+	case KindUnixSeconds:
+		return "unix-seconds"
+	case KindUnixMillis:
+		return "unix-millis"
+	case KindRandom:
+		return "random"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// DefaultClassifier accepts timestamps from 2010 onwards and at most a day
+// ahead of DefaultClock, and reads Snowflakes with Twitter's epoch.
+var DefaultClassifier = Classifier{
+	NotBefore:      time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+	MaxAhead:       24 * time.Hour,
+	SnowflakeEpoch: TwitterSnowflakeEpoch,
+}
+
+// Classifier heuristically identifies the format of 64-bit ID values, for
+// migrating columns that hold several generations of IDs. A value is decoded as
+// each format in turn and takes the first whose timestamp is plausible: raw Unix
+// seconds, raw Unix milliseconds, then Nano64 or Snowflake.
+//
+// The result for a single value is a guess. About 3% of random values decode to
+// a plausible Nano64 timestamp, and Nano64s and Snowflakes of the same era
+// overlap, so they are told apart by the Snowflake sequence field. Classify a
+// sample of a column and act on the majority.
+type Classifier struct {
+	// NotBefore is the earliest plausible timestamp. Zero disables the bound,
+	// which makes almost every value plausible; set it.
+	NotBefore time.Time
+
+	// MaxAhead is how far ahead of the clock a timestamp may be.
+	MaxAhead time.Duration
+
+	// SnowflakeEpoch is the Unix ms the Snowflake timestamp counts from.
+	SnowflakeEpoch int64
+
+	// Clock defaults to DefaultClock.
+	Clock Clock
+}
+
+// Classify returns the apparent format of value using DefaultClassifier.
+func Classify(value uint64) Kind {
+	return DefaultClassifier.Classify(value)
+}
+
+// Classify returns the apparent format of value.
+func (c Classifier) Classify(value uint64) Kind {
+	if value == 0 {
+		return KindUnknown
+	}
+	clock := c.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	notBefore := int64(0)
+	if !c.NotBefore.IsZero() {
+		notBefore = c.NotBefore.UnixMilli()
+	}
+	notAfter := clock() + c.MaxAhead.Milliseconds()
+	plausible := func(ms int64) bool { return ms >= notBefore && ms <= notAfter }
+
+	// Raw timestamps are far smaller than IDs of the same era.
+	if value <= uint64(notAfter)/1000 && plausible(int64(value)*1000) {
+		return KindUnixSeconds
+	}
+	if value <= uint64(notAfter) && plausible(int64(value)) {
+		return KindUnixMillis
+	}
+
+	nano := plausible(int64(value >> RandomBits))
+	offset := int64(value >> snowflakeTimeShift)
+	snowflake := value>>63 == 0 && offset >= snowflakeMinOffset.Milliseconds() && plausible(offset+c.SnowflakeEpoch)
+	switch {
+	case nano && snowflake:
+		if value&(1<<snowflakeSequenceBits-1) < snowflakeLowSequence {
+			return KindSnowflake
+		}
+		return KindNano64
+	case nano:
+		return KindNano64
+	case snowflake:
+		return KindSnowflake
+	default:
+		return KindRandom
+	}
+}
+
+// Timestamp classifies value and returns the Unix ms it embeds, or false if it
+// has none (KindUnknown and KindRandom).
+func (c Classifier) Timestamp(value uint64) (int64, Kind, bool) {
+	kind := c.Classify(value)
+	switch kind {
+	case KindNano64:
+		return int64(value >> timestampShift), kind, true
+	case KindSnowflake:
+		return int64(value>>snowflakeTimeShift) + c.SnowflakeEpoch, kind, true
+	case KindUnixSeconds:
+		return int64(value) * 1000, kind, true
+	case KindUnixMillis:
+		return int64(value), kind, true
+	default:
+		return 0, kind, false
+	}
+}
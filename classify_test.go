@@ -0,0 +1,36 @@
+package nano64
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	now := int64(1_700_000_000_000) // 2023-11-14
+	c := DefaultClassifier
+	c.Clock = func() int64 { return now }
+
+	snowflake := func(ms int64, worker, seq uint64) uint64 {
+		return uint64(ms-TwitterSnowflakeEpoch)<<snowflakeTimeShift | worker<<snowflakeSequenceBits | seq
+	}
+
+	tests := []struct {
+		name  string
+		value uint64
+		want  Kind
+	}{
+		{"zero", 0, KindUnknown},
+		{"nano64", mustGenerate(t, now-1000, 0xABCDE).Uint64Value(), KindNano64},
+		{"snowflake", snowflake(now-5000, 3, 0), KindSnowflake},
+		{"snowflake busy sequence", snowflake(1_500_000_000_000, 1023, 4000), KindSnowflake},
+		{"unix seconds", uint64(now / 1000), KindUnixSeconds},
+		{"unix millis", uint64(now), KindUnixMillis},
+		{"future unix seconds", uint64(now/1000) + 7*24*3600, KindRandom},
+		{"all ones", ^uint64(0), KindRandom},
+		{"small counter", 42, KindRandom},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Classify(tt.value); got != tt.want {
+				t.Errorf("Classify(%#x) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+)
+
+// grepMatch is the --json output of one found ID.
+type grepMatch struct {
+	File   string    `json:"file,omitempty"`
+	Line   int       `json:"line"`
+	Column int       `json:"column"`
+	Text   string    `json:"text"`
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+}
+
+// errReader remembers the first read error, which ExtractIDs does not report.
+type errReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF && r.err == nil {
+		r.err = err
+	}
+	return n, err
+}
+
+// runGrep implements `nano64 grep`.
+func runGrep(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("grep", "[--since 1h] [--until 10m] [--json] [files...]", stderr)
+	since := fs.String("since", "", "only IDs created at or after this time (duration ago or RFC 3339)")
+	until := fs.String("until", "", "only IDs created before this time (duration ago or RFC 3339)")
+	asJSON := fs.Bool("json", false, "print one JSON object per ID")
+	utc := fs.Bool("utc", true, "print times in UTC rather than the local zone")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	from, err := parseTimeFlag(*since)
+	if err != nil {
+		return err
+	}
+	to, err := parseTimeFlag(*until)
+	if err != nil {
+		return err
+	}
+	loc := time.Local
+	if *utc {
+		loc = time.UTC
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	showName := len(files) > 1
+	enc := json.NewEncoder(stdout)
+
+	found := false
+	for _, name := range files {
+		err := grepFile(name, stdin, func(match nano64.FoundID) error {
+			if (!from.IsZero() && match.Time.Before(from)) || (!to.IsZero() && !match.Time.Before(to)) {
+				return nil
+			}
+			found = true
+
+			if *asJSON {
+				out := grepMatch{Line: match.Line, Column: match.Column, Text: match.Text, ID: match.ID.ToHex(), Time: match.Time.In(loc)}
+				if showName {
+					out.File = name
+				}
+				return enc.Encode(out)
+			}
+
+			prefix := ""
+			if showName {
+				prefix = name + ":"
+			}
+			_, err := fmt.Fprintf(stdout, "%s%d:%d: %s %s (%s)\n", prefix, match.Line, match.Column,
+				match.ID.ToHex(), match.ID.FormatTime(time.RFC3339Nano, loc), match.ID.HumanAgeAt(now()))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return errSilent
+	}
+	return nil
+}
+
+// grepFile calls fn for every ID in the named file, or in stdin for "-".
+func grepFile(name string, stdin io.Reader, fn func(nano64.FoundID) error) error {
+	r := &errReader{r: stdin}
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r.r = f
+	}
+
+	for match := range nano64.ExtractIDs(r) {
+		if err := fn(match); err != nil {
+			return err
+		}
+	}
+	if r.err != nil {
+		return fmt.Errorf("%s: %w", name, r.err)
+	}
+	return nil
+}
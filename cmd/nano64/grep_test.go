@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+)
+
+func fixedNow(t *testing.T, at time.Time) {
+	t.Helper()
+	old := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = old })
+}
+
+func mustID(t *testing.T, at time.Time, random uint32) nano64.Nano64 {
+	t.Helper()
+	id, err := nano64.Generate(at.UnixMilli(), func(int) (uint32, error) { return random, nil })
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	return id
+}
+
+func TestGrep(t *testing.T) {
+	current := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fixedNow(t, current)
+
+	old := mustID(t, current.Add(-3*time.Hour), 1)
+	recent := mustID(t, current.Add(-10*time.Minute), 2)
+	input := "boot " + old.ToHex() + "\nrequest " + recent.ToHex() + " done\n"
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"grep", "--since", "1h"}, strings.NewReader(input), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	want := "2:9: " + recent.ToHex() + " 2024-05-01T11:50:00Z (10m ago)\n"
+	if stdout.String() != want {
+		t.Errorf("output = %q, want %q", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"grep", "--since", "1m"}, strings.NewReader(input), &stdout, &stderr); code != 1 {
+		t.Errorf("exit code %d without matches, want 1", code)
+	}
+}
+
+func TestGrepJSONFiles(t *testing.T) {
+	current := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fixedNow(t, current)
+
+	dir := t.TempDir()
+	ids := []nano64.Nano64{mustID(t, current.Add(-time.Hour), 1), mustID(t, current.Add(-time.Minute), 2)}
+	var files []string
+	for i, id := range ids {
+		name := filepath.Join(dir, []string{"a.log", "b.log"}[i])
+		if err := os.WriteFile(name, []byte("id="+id.ToHex()+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, name)
+	}
+
+	var stdout, stderr strings.Builder
+	args := append([]string{"grep", "--json", "--until", "30m"}, files...)
+	if code := run(args, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+
+	var match grepMatch
+	if err := json.Unmarshal([]byte(stdout.String()), &match); err != nil {
+		t.Fatalf("invalid JSON output %q: %v", stdout.String(), err)
+	}
+	if match.File != files[0] || match.ID != ids[0].ToHex() || match.Column != 4 || !match.Time.Equal(ids[0].ToDate()) {
+		t.Errorf("match = %+v", match)
+	}
+
+	if code := run([]string{"grep", filepath.Join(dir, "missing.log")}, nil, &stdout, &stderr); code != 1 {
+		t.Errorf("exit code %d for missing file, want 1", code)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	var stdout, stderr strings.Builder
+	if code := run([]string{"frobnicate"}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("exit code %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "grep") {
+		t.Errorf("usage does not list commands: %s", stderr.String())
+	}
+}
@@ -0,0 +1,115 @@
+// Command nano64 works with Nano64 IDs from the shell.
+//
+// Usage:
+//
+//	nano64 <command> [flags] [args]
+//
+// Run `nano64 help` for the list of commands and `nano64 <command> -h` for the
+// flags of one.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// errSilent reports failure through the exit status only, like grep finding no match.
+var errSilent = errors.New("silent failure")
+
+// now is the clock used for relative times; tests replace it.
+var now = time.Now
+
+// command is a subcommand of the CLI.
+type command struct {
+	summary string
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+var commands = map[string]command{
+	"grep":   {"find IDs in text and print their creation times", runGrep},
+	"sql":    {"print SQL for ranges, timestamps and columns of IDs", runSQL},
+	"verify": {"check files of IDs for format, order, duplicates and plausibility", runVerify},
+	"watch":  {"stream freshly generated IDs at a target rate", runWatch},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "nano64: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	err := cmd.run(args[1:], stdin, stdout, stderr)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errSilent):
+		return 1
+	default:
+		fmt.Fprintf(stderr, "nano64 %s: %v\n", args[0], err)
+		return 1
+	}
+}
+
+// usage lists the commands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: nano64 <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", name, commands[name].summary)
+	}
+}
+
+// newFlagSet creates the flag set of a subcommand, reporting errors to stderr.
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("nano64 "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: nano64 %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseTimeFlag parses a time given as RFC 3339 or as a duration before now
+// ("1h" meaning an hour ago). The empty string yields the zero time.
+func parseTimeFlag(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: want a duration such as 1h or an RFC 3339 time", value)
+	}
+	return t, nil
+}
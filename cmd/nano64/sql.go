@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+)
+
+// runSQL implements `nano64 sql`.
+func runSQL(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("sql", "--op range|extract-ts|ddl [--dialect postgres] [--repr bytes] [flags]", stderr)
+	dialectName := fs.String("dialect", "postgres", "SQL dialect: postgres, mysql or sqlite")
+	op := fs.String("op", "", "snippet to generate: range, extract-ts or ddl")
+	reprName := fs.String("repr", "bytes", "column representation: bytes, signed or hex")
+	table := fs.String("table", "items", "table name")
+	column := fs.String("column", "id", "ID column name")
+	since := fs.String("since", "1h", "range start (duration ago or RFC 3339)")
+	until := fs.String("until", "0s", "range end, exclusive (duration ago or RFC 3339)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	dialect, err := nano64.ParseDialect(*dialectName)
+	if err != nil {
+		return err
+	}
+	repr, err := nano64.ParseRepresentation(*reprName)
+	if err != nil {
+		return err
+	}
+
+	var snippet string
+	switch *op {
+	case "range":
+		from, err := parseTimeFlag(*since)
+		if err != nil {
+			return err
+		}
+		to, err := parseTimeFlag(*until)
+		if err != nil {
+			return err
+		}
+		r := nano64.RangeForTime(from, to)
+		if r.Empty() {
+			return fmt.Errorf("empty time range %s to %s", from.Format(time.RFC3339Nano), to.Format(time.RFC3339Nano))
+		}
+		cond, err := dialect.RangeCondition(*column, r, repr)
+		if err != nil {
+			return err
+		}
+		snippet = fmt.Sprintf("SELECT * FROM %s WHERE %s;", dialect.QuoteIdent(*table), cond)
+	case "extract-ts":
+		expr, err := dialect.TimestampExpr(*column, repr)
+		if err != nil {
+			return err
+		}
+		snippet = fmt.Sprintf("SELECT %s, %s AS created_ms FROM %s;", dialect.QuoteIdent(*column), expr, dialect.QuoteIdent(*table))
+	case "ddl":
+		colType, err := dialect.ColumnType(*column, repr)
+		if err != nil {
+			return err
+		}
+		snippet = fmt.Sprintf("CREATE TABLE %s (\n\t%s %s PRIMARY KEY\n);", dialect.QuoteIdent(*table), dialect.QuoteIdent(*column), colType)
+	case "":
+		fs.Usage()
+		return fmt.Errorf("--op is required")
+	default:
+		return fmt.Errorf("unknown op %q: want range, extract-ts or ddl", *op)
+	}
+
+	_, err = fmt.Fprintln(stdout, snippet)
+	return err
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+)
+
+func TestSQL(t *testing.T) {
+	current := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fixedNow(t, current)
+
+	r := nano64.RangeForTime(current.Add(-time.Hour), current)
+	cond, _ := nano64.DialectMySQL.RangeCondition("created", r, nano64.RepresentationSigned)
+	expr, _ := nano64.DialectPostgres.TimestampExpr("id", nano64.RepresentationBytes)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{
+			[]string{"--dialect", "mysql", "--op", "range", "--repr", "int64", "--table", "events", "--column", "created"},
+			"SELECT * FROM `events` WHERE " + cond + ";\n",
+		},
+		{
+			[]string{"--op", "extract-ts"},
+			`SELECT "id", ` + expr + ` AS created_ms FROM "items";` + "\n",
+		},
+		{
+			[]string{"--dialect", "sqlite", "--op", "ddl", "--repr", "signed"},
+			"CREATE TABLE \"items\" (\n\t\"id\" INTEGER PRIMARY KEY\n);\n",
+		},
+	}
+	for _, tt := range tests {
+		var stdout, stderr strings.Builder
+		if code := run(append([]string{"sql"}, tt.args...), nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("sql %v: exit code %d, stderr: %s", tt.args, code, stderr.String())
+		}
+		if stdout.String() != tt.want {
+			t.Errorf("sql %v =\n%s\nwant\n%s", tt.args, stdout.String(), tt.want)
+		}
+	}
+
+	for _, args := range [][]string{{}, {"--op", "drop"}, {"--op", "ddl", "--dialect", "oracle"}} {
+		var stdout, stderr strings.Builder
+		if code := run(append([]string{"sql"}, args...), nil, &stdout, &stderr); code != 1 {
+			t.Errorf("sql %v: exit code %d, want 1", args, code)
+		}
+	}
+}
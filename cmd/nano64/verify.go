@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+)
+
+// verifyStats counts the problems found by `nano64 verify`.
+type verifyStats struct {
+	ids, invalid, duplicates, nonMonotonic, skewed, implausible int
+}
+
+func (s verifyStats) issues() int {
+	return s.invalid + s.duplicates + s.nonMonotonic + s.skewed + s.implausible
+}
+
+// verifier checks a stream of IDs, one per line.
+type verifier struct {
+	monotonic bool
+	maxSkew   time.Duration
+	strict    nano64.StrictParser
+	dedupe    *nano64.Deduper
+	maxIssues int
+	out       io.Writer
+
+	stats    verifyStats
+	previous nano64.Nano64
+	newest   int64
+	reported int
+}
+
+// report prints an issue unless the print limit is reached.
+func (v *verifier) report(name string, line int, format string, args ...any) {
+	v.reported++
+	if v.maxIssues >= 0 && v.reported > v.maxIssues {
+		return
+	}
+	fmt.Fprintf(v.out, "%s:%d: %s\n", name, line, fmt.Sprintf(format, args...))
+}
+
+// check verifies the IDs read from r.
+func (v *verifier) check(name string, r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := bytes.TrimSpace(sc.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if !nano64.LooksLikeID(text) {
+			v.stats.invalid++
+			v.report(name, line, "invalid ID %q", text)
+			continue
+		}
+		id, err := nano64.FromHex(string(text))
+		if err != nil {
+			v.stats.invalid++
+			v.report(name, line, "invalid ID %q: %v", text, err)
+			continue
+		}
+		v.stats.ids++
+
+		if err := v.strict.Check(id); err != nil {
+			v.stats.implausible++
+			v.report(name, line, "%s: %v", id.ToHex(), err)
+		}
+		if v.dedupe.Seen(id) {
+			v.stats.duplicates++
+			v.report(name, line, "%s: duplicate", id.ToHex())
+		}
+		if v.monotonic && v.stats.ids > 1 && nano64.Compare(id, v.previous) <= 0 {
+			v.stats.nonMonotonic++
+			v.report(name, line, "%s: not greater than previous ID %s", id.ToHex(), v.previous.ToHex())
+		}
+		ts := id.GetTimestamp()
+		if v.maxSkew > 0 && v.newest-ts > v.maxSkew.Milliseconds() {
+			v.stats.skewed++
+			v.report(name, line, "%s: %v behind the newest ID", id.ToHex(), time.Duration(v.newest-ts)*time.Millisecond)
+		}
+		v.newest = max(v.newest, ts)
+		v.previous = id
+	}
+	return sc.Err()
+}
+
+// runVerify implements `nano64 verify`.
+func runVerify(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("verify", "[--expect-monotonic] [--max-skew 5m] [flags] [files...]", stderr)
+	monotonic := fs.Bool("expect-monotonic", false, "require every ID to be greater than the one before it")
+	maxSkew := fs.Duration("max-skew", 0, "flag IDs created this much before the newest ID so far (0 disables)")
+	notBefore := fs.String("not-before", "2000-01-01T00:00:00Z", "flag IDs created before this time (duration ago or RFC 3339)")
+	maxAhead := fs.Duration("max-future", 24*time.Hour, "flag IDs created this far in the future")
+	window := fs.Duration("dedupe-window", time.Minute, "detect duplicates this close in time; raised to --max-skew")
+	maxIssues := fs.Int("max-issues", 20, "print at most this many issues (-1 for all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	from, err := parseTimeFlag(*notBefore)
+	if err != nil {
+		return err
+	}
+	v := &verifier{
+		monotonic: *monotonic,
+		maxSkew:   *maxSkew,
+		strict: nano64.StrictParser{
+			NotBefore: from,
+			MaxAhead:  *maxAhead,
+			Clock:     func() int64 { return now().UnixMilli() },
+		},
+		dedupe:    nano64.DedupeWindow(max(*window, *maxSkew)),
+		maxIssues: *maxIssues,
+		out:       stdout,
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		if err := verifyFile(v, name, stdin); err != nil {
+			return err
+		}
+	}
+
+	s := v.stats
+	fmt.Fprintf(stdout, "%d IDs: %d invalid, %d duplicate, %d non-monotonic, %d skewed, %d implausible\n",
+		s.ids, s.invalid, s.duplicates, s.nonMonotonic, s.skewed, s.implausible)
+	if s.issues() > 0 {
+		return errSilent
+	}
+	return nil
+}
+
+// verifyFile runs v over the named file, or stdin for "-".
+func verifyFile(v *verifier, name string, stdin io.Reader) error {
+	if name == "-" {
+		return v.check("<stdin>", stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := v.check(name, f); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
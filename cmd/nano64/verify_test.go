@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	current := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fixedNow(t, current)
+
+	a := mustID(t, current.Add(-time.Hour), 1)
+	b := mustID(t, current.Add(-50*time.Minute), 2)
+	late := mustID(t, current.Add(-2*time.Hour), 3)
+	future := mustID(t, current.Add(48*time.Hour), 4)
+
+	clean := strings.Join([]string{a.ToHex(), "", b.ToHex()}, "\n")
+	var stdout, stderr strings.Builder
+	if code := run([]string{"verify", "--expect-monotonic", "--max-skew", "5m"}, strings.NewReader(clean), &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d for clean input, output: %s%s", code, stdout.String(), stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "2 IDs: 0 invalid") {
+		t.Errorf("summary = %q", stdout.String())
+	}
+
+	dirty := strings.Join([]string{a.ToHex(), a.ToHex(), "not-an-id", b.ToHex(), late.ToHex(), future.ToHex()}, "\n")
+	stdout.Reset()
+	if code := run([]string{"verify", "--expect-monotonic", "--max-skew", "5m"}, strings.NewReader(dirty), &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code %d for dirty input, want 1", code)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"<stdin>:2: " + a.ToHex() + ": duplicate",
+		"<stdin>:3: invalid ID",
+		"<stdin>:5: " + late.ToHex() + ": not greater than previous ID",
+		"<stdin>:5: " + late.ToHex() + ": 1h10m0s behind the newest ID",
+		"<stdin>:6: " + future.ToHex() + ": implausible ID timestamp",
+		"5 IDs: 1 invalid, 1 duplicate, 2 non-monotonic, 1 skewed, 1 implausible",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+)
+
+// parseRate parses a rate such as "1000/s", "5/ms" or "60/m" into IDs per second.
+// A bare number is per second.
+func parseRate(s string) (float64, error) {
+	count, unit, found := strings.Cut(s, "/")
+	per := time.Second
+	if found {
+		switch unit {
+		case "ms":
+			per = time.Millisecond
+		case "s":
+			per = time.Second
+		case "m", "min":
+			per = time.Minute
+		case "h":
+			per = time.Hour
+		default:
+			return 0, fmt.Errorf("invalid rate unit %q: want ms, s, m or h", unit)
+		}
+	}
+	n, err := strconv.ParseFloat(count, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid rate %q: want a positive count such as 1000/s", s)
+	}
+	return n * float64(time.Second) / float64(per), nil
+}
+
+// appendFormatted appends id in the named output format.
+func appendFormatted(dst []byte, id nano64.Nano64, format string) ([]byte, error) {
+	switch format {
+	case "hex":
+		return append(dst, id.ToHex()...), nil
+	case "signed":
+		return strconv.AppendInt(dst, nano64.SignedNano64.FromId(id), 10), nil
+	case "uint64":
+		return strconv.AppendUint(dst, id.Uint64Value(), 10), nil
+	case "json":
+		return id.AppendJSON(dst), nil
+	default:
+		return dst, fmt.Errorf("unknown format %q: want hex, signed, uint64 or json", format)
+	}
+}
+
+// runWatch implements `nano64 watch`.
+func runWatch(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("watch", "[--rate 1000/s] [--format hex] [--count N] [--duration 1m] [--monotonic]", stderr)
+	rateFlag := fs.String("rate", "1/s", "IDs to emit per unit of time: N/ms, N/s, N/m or N/h")
+	format := fs.String("format", "hex", "output format: hex, signed, uint64 or json")
+	count := fs.Int64("count", 0, "stop after this many IDs (0 for no limit)")
+	duration := fs.Duration("duration", 0, "stop after this long (0 for no limit)")
+	monotonic := fs.Bool("monotonic", false, "generate strictly increasing IDs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rate, err := parseRate(*rateFlag)
+	if err != nil {
+		return err
+	}
+	if _, err := appendFormatted(nil, nano64.Nil, *format); err != nil {
+		return err
+	}
+	generate := nano64.GenerateDefault
+	if *monotonic {
+		generate = nano64.GenerateMonotonicDefault
+	}
+
+	// Emit in batches so high rates do not need a sleep per ID: every tick,
+	// catch up with the number of IDs due since the start.
+	w := bufio.NewWriter(stdout)
+	buf := make([]byte, 0, 32)
+	start := time.Now()
+	tick := min(max(time.Duration(float64(time.Second)/rate), time.Millisecond), 100*time.Millisecond)
+	var emitted int64
+	for {
+		elapsed := time.Since(start)
+		if *duration > 0 && elapsed >= *duration {
+			break
+		}
+		due := int64(rate * elapsed.Seconds())
+		if emitted == 0 {
+			due = max(due, 1)
+		}
+		if *count > 0 {
+			due = min(due, *count)
+		}
+		for ; emitted < due; emitted++ {
+			id, err := generate()
+			if err != nil {
+				return err
+			}
+			buf, _ = appendFormatted(buf[:0], id, *format)
+			buf = append(buf, '\n')
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if *count > 0 && emitted >= *count {
+			break
+		}
+		time.Sleep(tick)
+	}
+	return w.Flush()
+}
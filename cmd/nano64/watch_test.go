@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"1000/s", 1000},
+		{"5/ms", 5000},
+		{"60/m", 1},
+		{"250", 250},
+	}
+	for _, tt := range tests {
+		if got, err := parseRate(tt.in); err != nil || got != tt.want {
+			t.Errorf("parseRate(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "0/s", "-1/s", "10/week", "fast"} {
+		if _, err := parseRate(in); err == nil {
+			t.Errorf("parseRate(%q) expected error", in)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	var stdout, stderr strings.Builder
+	start := time.Now()
+	code := run([]string{"watch", "--rate", "200/ms", "--count", "1000", "--monotonic"}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("watch took %v for 1000 IDs at 200/ms", elapsed)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if len(lines) != 1000 {
+		t.Fatalf("got %d IDs, want 1000", len(lines))
+	}
+	var previous nano64.Nano64
+	for i, line := range lines {
+		id, err := nano64.FromHex(line)
+		if err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if i > 0 && nano64.Compare(id, previous) <= 0 {
+			t.Fatalf("line %d: %s not greater than %s", i+1, id.ToHex(), previous.ToHex())
+		}
+		previous = id
+	}
+}
+
+func TestWatchFormats(t *testing.T) {
+	for _, format := range []string{"signed", "uint64", "json"} {
+		var stdout, stderr strings.Builder
+		if code := run([]string{"watch", "--rate", "1000/s", "--count", "3", "--format", format}, nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("--format %s: exit code %d, stderr: %s", format, code, stderr.String())
+		}
+		if n := strings.Count(stdout.String(), "\n"); n != 3 {
+			t.Errorf("--format %s: got %d lines, want 3", format, n)
+		}
+	}
+
+	var stdout, stderr strings.Builder
+	if code := run([]string{"watch", "--format", "base64"}, nil, &stdout, &stderr); code != 1 {
+		t.Errorf("exit code %d for unknown format, want 1", code)
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"text/template"
+)
+
+// config describes the typed IDs to generate.
+type config struct {
+	// Package is the name of the package the files belong to.
+	Package string
+	// Suffix is appended to each entity name to form the type name.
+	Suffix string
+	// Generator is the function New<Type> calls, returning (nano64.Nano64, error).
+	Generator string
+	// Entities are the entity names, such as User and Order.
+	Entities []string
+	// Args is the command line recorded in the generated header.
+	Args string
+}
+
+// idType is one typed ID in the templates.
+type idType struct {
+	Entity, Type string
+}
+
+// types validates c and returns the IDs to generate.
+func (c config) types() ([]idType, error) {
+	if !token.IsIdentifier(c.Package) {
+		return nil, fmt.Errorf("invalid package name %q", c.Package)
+	}
+	if c.Generator == "" {
+		return nil, fmt.Errorf("empty generator")
+	}
+	if len(c.Entities) == 0 {
+		return nil, fmt.Errorf("no entity names")
+	}
+	types := make([]idType, 0, len(c.Entities))
+	seen := make(map[string]bool)
+	for _, entity := range c.Entities {
+		name := entity + c.Suffix
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return nil, fmt.Errorf("%q is not an exported Go identifier", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate type %s", name)
+		}
+		seen[name] = true
+		types = append(types, idType{Entity: entity, Type: name})
+	}
+	return types, nil
+}
+
+// generate returns the formatted sources for output, keyed by file name. The
+// SQL methods go to their own files, built under the same constraint as the
+// nano64 methods they wrap, so tiny builds of the package still compile.
+func generate(c config, output string, tests bool) (map[string][]byte, error) {
+	types, err := c.types()
+	if err != nil {
+		return nil, err
+	}
+	data := struct {
+		config
+		Types []idType
+	}{c, types}
+	templates := map[string]*template.Template{output: codeTemplate, sqlFile(output): sqlTemplate}
+	if tests {
+		templates[testFile(output)] = testTemplate
+		templates[testFile(sqlFile(output))] = sqlTestTemplate
+	}
+	files := make(map[string][]byte, len(templates))
+	for name, t := range templates {
+		if files[name], err = execute(t, data); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// execute runs t and formats the result as Go source.
+func execute(t *template.Template, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w", t.Name(), err)
+	}
+	return src, nil
+}
+
+// testFile returns the name of the test file accompanying output.
+func testFile(output string) string {
+	return strings.TrimSuffix(output, ".go") + "_test.go"
+}
+
+// sqlFile returns the name of the file holding the SQL methods for output.
+func sqlFile(output string) string {
+	return strings.TrimSuffix(output, ".go") + "_sql.go"
+}
+
+var codeTemplate = template.Must(template.New("code").Parse(`// Code generated by "nano64gen {{.Args}}"; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+)
+{{range .Types}}
+// {{.Type}} is the ID of {{.Entity}} entities. It is encoded like nano64.Nano64
+// but cannot be used where the ID of another entity is expected.
+type {{.Type}} nano64.Nano64
+
+// New{{.Type}} generates a {{.Type}}.
+func New{{.Type}}() ({{.Type}}, error) {
+	id, err := {{$.Generator}}()
+	return {{.Type}}(id), err
+}
+
+// Parse{{.Type}} parses a {{.Type}} from hex, as nano64.FromHex.
+func Parse{{.Type}}(s string) ({{.Type}}, error) {
+	id, err := nano64.FromHex(s)
+	if err != nil {
+		return {{.Type}}{}, fmt.Errorf("parse {{.Type}}: %w", err)
+	}
+	return {{.Type}}(id), nil
+}
+
+// Nano64 returns id as an untyped nano64.Nano64.
+func (id {{.Type}}) Nano64() nano64.Nano64 {
+	return nano64.Nano64(id)
+}
+
+// String returns the dashed hex encoding of id.
+func (id {{.Type}}) String() string {
+	return nano64.Nano64(id).ToHex()
+}
+
+// IsNil reports whether id is the zero value.
+func (id {{.Type}}) IsNil() bool {
+	return nano64.Nano64(id).IsNil()
+}
+
+// Time returns the creation time embedded in id.
+func (id {{.Type}}) Time() time.Time {
+	return nano64.Nano64(id).ToDate()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (id {{.Type}}) MarshalJSON() ([]byte, error) {
+	return nano64.Nano64(id).MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (id *{{.Type}}) UnmarshalJSON(data []byte) error {
+	if err := (*nano64.Nano64)(id).UnmarshalJSON(data); err != nil {
+		return fmt.Errorf("unmarshal {{.Type}}: %w", err)
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, so {{.Type}} can key JSON maps.
+func (id {{.Type}}) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *{{.Type}}) UnmarshalText(text []byte) error {
+	parsed, err := Parse{{.Type}}(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+{{end}}`))
+
+var sqlTemplate = template.Must(template.New("sql").Parse(`// Code generated by "nano64gen {{.Args}}"; DO NOT EDIT.
+
+//go:build !tinygo && !nano64_tiny
+
+package {{.Package}}
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/pisoj/go-nano64"
+)
+{{range .Types}}
+// Value implements driver.Valuer.
+func (id {{.Type}}) Value() (driver.Value, error) {
+	return nano64.Nano64(id).Value()
+}
+
+// Scan implements sql.Scanner.
+func (id *{{.Type}}) Scan(value any) error {
+	if err := (*nano64.Nano64)(id).Scan(value); err != nil {
+		return fmt.Errorf("scan {{.Type}}: %w", err)
+	}
+	return nil
+}
+{{end}}`))
+
+var testTemplate = template.Must(template.New("tests").Parse(`// Code generated by "nano64gen {{.Args}}"; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"encoding/json"
+	"testing"
+)
+{{range .Types}}
+func Test{{.Type}}(t *testing.T) {
+	id, err := New{{.Type}}()
+	if err != nil {
+		t.Fatalf("New{{.Type}}() error = %v", err)
+	}
+	if id.IsNil() {
+		t.Fatal("New{{.Type}}() returned the zero ID")
+	}
+
+	if parsed, err := Parse{{.Type}}(id.String()); err != nil || parsed != id {
+		t.Errorf("Parse{{.Type}}(%q) = %v, %v", id.String(), parsed, err)
+	}
+	if _, err := Parse{{.Type}}("not an ID"); err == nil {
+		t.Error("Parse{{.Type}}() accepted an invalid ID")
+	}
+
+	data, err := json.Marshal(map[{{.Type}}]{{.Type}}{id: id})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded map[{{.Type}}]{{.Type}}
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded) != 1 || decoded[id] != id {
+		t.Errorf("json.Unmarshal(%s) = %v, %v", data, decoded, err)
+	}
+}
+{{end}}`))
+
+var sqlTestTemplate = template.Must(template.New("sqltests").Parse(`// Code generated by "nano64gen {{.Args}}"; DO NOT EDIT.
+
+//go:build !tinygo && !nano64_tiny
+
+package {{.Package}}
+
+import "testing"
+{{range .Types}}
+func Test{{.Type}}SQL(t *testing.T) {
+	id, err := New{{.Type}}()
+	if err != nil {
+		t.Fatalf("New{{.Type}}() error = %v", err)
+	}
+	value, err := id.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	var scanned {{.Type}}
+	if err := scanned.Scan(value); err != nil || scanned != id {
+		t.Errorf("Scan(%v) = %v, %v", value, scanned, err)
+	}
+	if err := scanned.Scan("not an ID"); err == nil {
+		t.Error("Scan() accepted a string")
+	}
+}
+{{end}}`))
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestExampleCurrent checks that internal/example holds the current output.
+func TestExampleCurrent(t *testing.T) {
+	files, err := generate(config{
+		Package:   "example",
+		Suffix:    "ID",
+		Generator: "nano64.GenerateDefault",
+		Entities:  []string{"User", "Order"},
+		Args:      "User Order",
+	}, "nano64_ids.go", true)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join("internal", "example", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("internal/example/%s is stale; run go generate ./cmd/nano64gen/internal/example", name)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	valid := config{Package: "models", Suffix: "ID", Generator: "nano64.GenerateDefault", Entities: []string{"User"}}
+	tests := map[string]func(c *config){
+		"no package":     func(c *config) { c.Package = "" },
+		"no generator":   func(c *config) { c.Generator = "" },
+		"no entities":    func(c *config) { c.Entities = nil },
+		"unexported":     func(c *config) { c.Entities = []string{"user"} },
+		"not identifier": func(c *config) { c.Entities = []string{"User-Account"} },
+		"duplicate":      func(c *config) { c.Entities = []string{"User", "User"} },
+	}
+	for name, mutate := range tests {
+		c := valid
+		mutate(&c)
+		if _, err := generate(c, "ids.go", true); err == nil {
+			t.Errorf("%s: generate() error = nil", name)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "ids.go")
+	var stderr strings.Builder
+	args := []string{"-package", "models", "-output", output, "-generator", "nextID", "Invoice"}
+	if code := run(args, &stderr); code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	code, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`// Code generated by "nano64gen -package models -output ` + output,
+		"package models",
+		"type InvoiceID nano64.Nano64",
+		"id, err := nextID()",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("output does not contain %q", want)
+		}
+	}
+	for _, name := range []string{"ids_sql.go", "ids_test.go", "ids_sql_test.go"} {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(output), name)); err != nil {
+			t.Errorf("%s not written: %v", name, err)
+		}
+	}
+	sqlCode, err := os.ReadFile(filepath.Join(filepath.Dir(output), "ids_sql.go"))
+	if err != nil || !strings.Contains(string(sqlCode), "//go:build !tinygo && !nano64_tiny") {
+		t.Errorf("ids_sql.go lacks the build constraint: %v", err)
+	}
+	if strings.Contains(string(code), "database/sql") {
+		t.Error("ids.go imports database/sql")
+	}
+
+	if code := run([]string{"-package", "models", "-output", output, "invoice"}, &stderr); code != 1 {
+		t.Errorf("unexported entity: exit code %d, want 1", code)
+	}
+}
@@ -0,0 +1,6 @@
+// Package example holds typed IDs generated by nano64gen, so the generated code
+// and tests build and run with the repository and gen_test.go can check that
+// the generator output is current.
+package example
+
+//go:generate go run github.com/pisoj/go-nano64/cmd/nano64gen User Order
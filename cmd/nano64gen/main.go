@@ -0,0 +1,73 @@
+// Command nano64gen generates strongly-typed Nano64 IDs, one type per entity,
+// with constructors, parsing, JSON, text and SQL methods and their tests.
+//
+// Usage:
+//
+//	nano64gen [-package name] [-output file] [-suffix ID] [-generator func] [-tests] Entity...
+//
+// It is meant to run from go generate, which supplies the package name:
+//
+//	//go:generate go run github.com/pisoj/go-nano64/cmd/nano64gen User Order Invoice
+//
+// This writes nano64_ids.go declaring UserID, OrderID and InvoiceID,
+// nano64_ids_sql.go with their SQL methods, and the matching _test.go files
+// exercising them. The SQL files carry the //go:build !tinygo && !nano64_tiny
+// constraint of the nano64 SQL methods, so tiny builds leave them out. Each type has the underlying type
+// nano64.Nano64, so converting between typed IDs takes an explicit conversion.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run executes the command line and returns the exit status.
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("nano64gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: nano64gen [flags] Entity...")
+		fs.PrintDefaults()
+	}
+	c := config{Args: strings.Join(args, " ")}
+	fs.StringVar(&c.Package, "package", os.Getenv("GOPACKAGE"), "package name of the generated files (default $GOPACKAGE)")
+	fs.StringVar(&c.Suffix, "suffix", "ID", "suffix appended to entity names to form type names")
+	fs.StringVar(&c.Generator, "generator", "nano64.GenerateDefault", "function generating IDs, returning (nano64.Nano64, error)")
+	output := fs.String("output", "nano64_ids.go", "file to write the types to")
+	tests := fs.Bool("tests", true, "also write tests to the output file name with a _test suffix")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	c.Entities = fs.Args()
+
+	if err := write(c, *output, *tests); err != nil {
+		fmt.Fprintf(stderr, "nano64gen: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// write generates the files for c.
+func write(c config, output string, tests bool) error {
+	files, err := generate(c, output, tests)
+	if err != nil {
+		return err
+	}
+	for name, src := range files {
+		if err := os.WriteFile(name, src, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package nano64
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// InCohort reports whether the entity id belongs to the given fraction (0 to 1)
+// of the named cohort, e.g. InCohort(userID, "new-checkout", 0.05) for a 5%
+// rollout. The decision is a hash of the ID keyed by the cohort name, so it is
+// the same in every service and stable over time, raising the fraction only
+// adds entities, and different cohorts select independent sets of entities.
+// Unlike SampleByID it depends on the whole ID.
+func InCohort(id Nano64, cohort string, fraction float64) bool {
+	if fraction >= 1 {
+		return true
+	}
+	if fraction <= 0 || math.IsNaN(fraction) {
+		return false
+	}
+	h := fnv.New64a()
+	h.Write([]byte(cohort))
+	return id.Hash64(h.Sum64()) < uint64(fraction*(1<<63))<<1
+}
@@ -0,0 +1,56 @@
+package nano64
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInCohort(t *testing.T) {
+	const n = 1 << 16
+	ids := make([]Nano64, n)
+	for i := range ids {
+		ids[i] = mustGenerate(t, int64(1000+i/64), uint32(i%64))
+	}
+
+	tests := []struct {
+		cohort   string
+		fraction float64
+	}{
+		{"new-checkout", 0.05},
+		{"new-checkout", 0.5},
+		{"dark-mode", 0.05},
+	}
+	kept := make([]map[Nano64]bool, len(tests))
+	for i, tt := range tests {
+		kept[i] = make(map[Nano64]bool)
+		for _, id := range ids {
+			if InCohort(id, tt.cohort, tt.fraction) {
+				kept[i][id] = true
+			}
+		}
+		if got := float64(len(kept[i])) / n; math.Abs(got-tt.fraction) > tt.fraction*0.1 {
+			t.Errorf("InCohort(%q, %v) kept %.4f of IDs", tt.cohort, tt.fraction, got)
+		}
+	}
+
+	// Raising the fraction only adds entities.
+	for id := range kept[0] {
+		if !kept[1][id] {
+			t.Errorf("%s left the cohort when the fraction grew", id.ToHex())
+		}
+	}
+	// Different cohorts overlap as independent sets would: about 5% of 5%.
+	overlap := 0
+	for id := range kept[0] {
+		if kept[2][id] {
+			overlap++
+		}
+	}
+	if expected := 0.05 * 0.05 * n; math.Abs(float64(overlap)-expected) > expected*0.5 {
+		t.Errorf("cohorts overlap in %d IDs, want about %.0f", overlap, expected)
+	}
+
+	if InCohort(ids[0], "x", 0) || !InCohort(ids[0], "x", 1) {
+		t.Error("fractions 0 and 1 are not all-or-nothing")
+	}
+}
@@ -0,0 +1,71 @@
+package nano64
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// CollisionSide is one occurrence of a collided ID, decomposed into its fields.
+type CollisionSide struct {
+	ID        Nano64    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Random    uint32    `json:"random"`
+	// Row is the zero-based position of the occurrence in its scan.
+	Row int64 `json:"row"`
+}
+
+// newCollisionSide decomposes the occurrence of id at row.
+func newCollisionSide(id Nano64, row int64) CollisionSide {
+	return CollisionSide{ID: id, Timestamp: id.ToDate(), Random: id.GetRandom(), Row: row}
+}
+
+// Collision records a duplicate ID found by a scan: where it was first seen and
+// where it was seen again.
+type Collision struct {
+	// Source names the scan, e.g. the audited table.
+	Source     string        `json:"source"`
+	First      CollisionSide `json:"first"`
+	Second     CollisionSide `json:"second"`
+	DetectedAt time.Time     `json:"detected_at"`
+}
+
+// CollisionSink persists collisions so rare production duplicates are kept with
+// enough context to analyze. AuditOptions.Collisions takes one. Bridge to other
+// backends, such as an OpenTelemetry log exporter, with CollisionSinkFunc.
+type CollisionSink interface {
+	RecordCollision(ctx context.Context, c Collision) error
+}
+
+// CollisionSinkFunc adapts a function to CollisionSink.
+type CollisionSinkFunc func(ctx context.Context, c Collision) error
+
+// RecordCollision implements CollisionSink.
+func (f CollisionSinkFunc) RecordCollision(ctx context.Context, c Collision) error {
+	return f(ctx, c)
+}
+
+// WriterCollisionSink writes each collision to an io.Writer such as os.Stdout as
+// a line of JSON. It is safe for concurrent use.
+type WriterCollisionSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriterCollisionSink creates a sink writing to w.
+func NewWriterCollisionSink(w io.Writer) *WriterCollisionSink {
+	return &WriterCollisionSink{enc: json.NewEncoder(w)}
+}
+
+// RecordCollision implements CollisionSink.
+func (s *WriterCollisionSink) RecordCollision(ctx context.Context, c Collision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.enc.Encode(c); err != nil {
+		return fmt.Errorf("failed to write collision: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"fmt"
+)
+
+// SQLCollisionSink inserts each collision as a row of a table:
+//
+//	CREATE TABLE id_collisions (
+//		source TEXT NOT NULL, id BYTEA NOT NULL,
+//		first_row BIGINT NOT NULL, first_timestamp_ms BIGINT NOT NULL, first_random BIGINT NOT NULL,
+//		second_row BIGINT NOT NULL, second_timestamp_ms BIGINT NOT NULL, second_random BIGINT NOT NULL,
+//		detected_at_ms BIGINT NOT NULL)
+type SQLCollisionSink struct {
+	db    Execer
+	query string
+}
+
+// NewSQLCollisionSink creates a sink inserting into table. db is usually a *sql.DB.
+func NewSQLCollisionSink(db Execer, dialect Dialect, table string) (*SQLCollisionSink, error) {
+	if err := dialect.validate(); err != nil {
+		return nil, err
+	}
+	if table == "" {
+		return nil, fmt.Errorf("collision table is required")
+	}
+	placeholders := ""
+	for i := 1; i <= 9; i++ {
+		if i > 1 {
+			placeholders += ", "
+		}
+		placeholders += dialect.Placeholder(i)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (source, id, first_row, first_timestamp_ms, first_random, "+
+		"second_row, second_timestamp_ms, second_random, detected_at_ms) VALUES (%s)", dialect.QuoteIdent(table), placeholders)
+	return &SQLCollisionSink{db: db, query: query}, nil
+}
+
+// RecordCollision implements CollisionSink.
+func (s *SQLCollisionSink) RecordCollision(ctx context.Context, c Collision) error {
+	_, err := s.db.ExecContext(ctx, s.query, c.Source, c.First.ID,
+		c.First.Row, c.First.Timestamp.UnixMilli(), int64(c.First.Random),
+		c.Second.Row, c.Second.Timestamp.UnixMilli(), int64(c.Second.Random),
+		c.DetectedAt.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to insert collision: %w", err)
+	}
+	return nil
+}
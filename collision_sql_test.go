@@ -0,0 +1,79 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestCollisionSinks(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		`CREATE TABLE events (id BLOB NOT NULL, seq INTEGER NOT NULL)`,
+		`CREATE TABLE id_collisions (source TEXT, id BLOB, first_row INTEGER, first_timestamp_ms INTEGER, first_random INTEGER,
+			second_row INTEGER, second_timestamp_ms INTEGER, second_random INTEGER, detected_at_ms INTEGER)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+	dup := mustGenerate(t, 2000, 0xABCDE)
+	for i, id := range []Nano64{mustGenerate(t, 1000, 1), dup, mustGenerate(t, 3000, 2), dup} {
+		if _, err := db.Exec("INSERT INTO events (id, seq) VALUES (?, ?)", id, i); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+	const query = "SELECT id FROM events ORDER BY seq"
+
+	var buf bytes.Buffer
+	report, err := AuditTable(context.Background(), db, query, AuditOptions{Collisions: NewWriterCollisionSink(&buf), Source: "events"})
+	if err != nil || report.Duplicates != 1 {
+		t.Fatalf("AuditTable() = %+v, %v", report, err)
+	}
+	var c Collision
+	if err := json.Unmarshal(buf.Bytes(), &c); err != nil {
+		t.Fatalf("failed to decode %q: %v", buf.String(), err)
+	}
+	if c.Source != "events" || c.First.ID != dup || c.First.Row != 1 || c.Second.Row != 3 ||
+		c.Second.Timestamp.UnixMilli() != 2000 || c.Second.Random != 0xABCDE || c.DetectedAt.IsZero() {
+		t.Errorf("unexpected collision: %+v", c)
+	}
+
+	sink, err := NewSQLCollisionSink(db, DialectSQLite, "id_collisions")
+	if err != nil {
+		t.Fatalf("NewSQLCollisionSink() error = %v", err)
+	}
+	if err := sink.RecordCollision(context.Background(), c); err != nil {
+		t.Fatalf("RecordCollision() error = %v", err)
+	}
+	var source string
+	var id Nano64
+	var firstRow, secondRow, ts, random int64
+	err = db.QueryRow("SELECT source, id, first_row, second_row, second_timestamp_ms, second_random FROM id_collisions").
+		Scan(&source, &id, &firstRow, &secondRow, &ts, &random)
+	if err != nil {
+		t.Fatalf("failed to read collision: %v", err)
+	}
+	if source != "events" || id != dup || firstRow != 1 || secondRow != 3 || ts != 2000 || random != 0xABCDE {
+		t.Errorf("stored collision = %s %s %d %d %d %d", source, id.ToHex(), firstRow, secondRow, ts, random)
+	}
+
+	failing := CollisionSinkFunc(func(context.Context, Collision) error { return errors.New("sink down") })
+	if _, err := AuditTable(context.Background(), db, query, AuditOptions{Collisions: failing}); err == nil {
+		t.Error("AuditTable() ignored a failing sink")
+	}
+	if _, err := NewSQLCollisionSink(db, DialectSQLite, ""); err == nil {
+		t.Error("NewSQLCollisionSink() accepted an empty table")
+	}
+}
@@ -0,0 +1,182 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var nullNano64Type = reflect.TypeOf(NullNano64{})
+
+// Column adapts a Nano64 or NullNano64 field to a storage representation. It
+// implements driver.Valuer, writing the field in that representation, and
+// sql.Scanner, reading it back from any of them; a *Column can therefore be
+// passed both as a statement argument and as a Scan destination.
+type Column struct {
+	Repr Representation
+
+	id   *Nano64
+	null *NullNano64
+}
+
+// ColumnOf returns a Column reading and writing *id as repr.
+func ColumnOf(id *Nano64, repr Representation) *Column {
+	return &Column{Repr: repr, id: id}
+}
+
+// NullColumnOf returns a Column reading and writing *id as repr, with NULL
+// mapped to an invalid NullNano64.
+func NullColumnOf(id *NullNano64, repr Representation) *Column {
+	return &Column{Repr: repr, null: id}
+}
+
+// Value implements driver.Valuer.
+func (c *Column) Value() (driver.Value, error) {
+	var id Nano64
+	if c.null != nil {
+		if !c.null.Valid {
+			return nil, nil
+		}
+		id = c.null.ID
+	} else {
+		id = *c.id
+	}
+
+	switch c.Repr {
+	case RepresentationBytes:
+		return id.ToBytes(), nil
+	case RepresentationSigned:
+		return SignedNano64.FromId(id), nil
+	case RepresentationHex:
+		return id.ToHex(), nil
+	default:
+		return nil, fmt.Errorf("unsupported representation %v", c.Repr)
+	}
+}
+
+// Scan implements sql.Scanner. Integers are read as SignedNano64 values when Repr
+// is RepresentationSigned and as raw values otherwise; 8-byte values are read as
+// big-endian bytes and other strings or byte slices as hex.
+func (c *Column) Scan(value any) error {
+	if value == nil {
+		if c.null != nil {
+			*c.null = NullNano64{}
+		} else {
+			*c.id = Nil
+		}
+		return nil
+	}
+
+	var id Nano64
+	var err error
+	switch v := value.(type) {
+	case int64:
+		if c.Repr == RepresentationSigned {
+			id = SignedNano64.ToId(v)
+		} else {
+			id = FromUint64(uint64(v))
+		}
+	case []byte:
+		if len(v) == 8 {
+			id, err = FromBytes(v)
+		} else {
+			id, err = FromHex(string(v))
+		}
+	case string:
+		id, err = FromHex(v)
+	default:
+		err = id.Scan(value)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot scan %T into Nano64: %w", value, err)
+	}
+
+	if c.null != nil {
+		*c.null = NullNano64{ID: id, Valid: true}
+	} else {
+		*c.id = id
+	}
+	return nil
+}
+
+// tagRepresentation returns the storage representation named in a nano64 struct
+// tag: "int64" (or "signed"), "hex" or "bytes". Fields without one use bytes, the
+// form written by Nano64.Value.
+func tagRepresentation(tag reflect.StructTag) (Representation, error) {
+	repr := RepresentationBytes
+	for _, opt := range strings.Split(tag.Get(tagName), ",") {
+		switch strings.TrimSpace(opt) {
+		case "int64", "signed":
+			repr = RepresentationSigned
+		case "hex":
+			repr = RepresentationHex
+		case "bytes":
+			repr = RepresentationBytes
+		case "", "generate", "monotonic":
+		default:
+			return 0, fmt.Errorf("unknown nano64 tag option %q", opt)
+		}
+	}
+	return repr, nil
+}
+
+// Fields returns one pointer per exported field of the struct ptr points to, in
+// declaration order, for use as Scan destinations or statement arguments.
+// Nano64 and NullNano64 fields are wrapped in a Column using the representation
+// from their tag, so a single struct definition controls how each ID is stored:
+//
+//	type Order struct {
+//		ID     nano64.Nano64     `nano64:"generate"`
+//		UserID nano64.Nano64     `nano64:"int64"`
+//		Ref    nano64.NullNano64 `nano64:"hex"`
+//	}
+//
+//	fields, err := nano64.Fields(&order)
+//	err = db.QueryRowContext(ctx, "SELECT id, user_id, ref FROM orders WHERE ...").Scan(fields...)
+//
+// Embedded structs are flattened; fields tagged `nano64:"-"` are skipped.
+func Fields(ptr any) ([]any, error) {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot map %T: expected non-nil pointer to struct", ptr)
+	}
+	return appendFields(nil, rv.Elem())
+}
+
+// appendFields appends the field pointers of the struct value rv.
+func appendFields(fields []any, rv reflect.Value) ([]any, error) {
+	typ := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Tag.Get(tagName) == "-" {
+			continue
+		}
+		fv := rv.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Type != nano64Type && field.Type != nullNano64Type {
+			var err error
+			if fields, err = appendFields(fields, fv); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch field.Type {
+		case nano64Type, nullNano64Type:
+			repr, err := tagRepresentation(field.Tag)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			if field.Type == nano64Type {
+				fields = append(fields, ColumnOf(fv.Addr().Interface().(*Nano64), repr))
+			} else {
+				fields = append(fields, NullColumnOf(fv.Addr().Interface().(*NullNano64), repr))
+			}
+		default:
+			fields = append(fields, fv.Addr().Interface())
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,115 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestColumnValue(t *testing.T) {
+	id := mustGenerate(t, 1_700_000_000_000, 42)
+	tests := []struct {
+		repr Representation
+		want any
+	}{
+		{RepresentationBytes, string(id.ToBytes())},
+		{RepresentationSigned, SignedNano64.FromId(id)},
+		{RepresentationHex, id.ToHex()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repr.String(), func(t *testing.T) {
+			col := ColumnOf(&id, tt.repr)
+			v, err := col.Value()
+			if err != nil {
+				t.Fatalf("Value() error = %v", err)
+			}
+			got := v
+			if b, ok := v.([]byte); ok {
+				got = string(b)
+			}
+			if got != tt.want {
+				t.Errorf("Value() = %v, want %v", got, tt.want)
+			}
+
+			var back Nano64
+			if err := ColumnOf(&back, tt.repr).Scan(v); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if back != id {
+				t.Errorf("Scan() = %v, want %v", back, id)
+			}
+		})
+	}
+
+	var null NullNano64
+	if v, err := NullColumnOf(&null, RepresentationHex).Value(); err != nil || v != nil {
+		t.Errorf("Value() of invalid NullNano64 = %v, %v; want nil", v, err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	type Base struct {
+		ID Nano64 `nano64:"generate"`
+	}
+	type Order struct {
+		Base
+		UserID Nano64     `nano64:"int64"`
+		Ref    NullNano64 `nano64:"hex"`
+		Note   string
+		Cache  Nano64 `nano64:"-"`
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE orders (id BLOB, user_id INTEGER, ref TEXT, note TEXT)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	in := Order{
+		Base:   Base{ID: mustGenerate(t, 1000, 1)},
+		UserID: mustGenerate(t, 2000, 2),
+		Ref:    NullNano64{ID: mustGenerate(t, 3000, 3), Valid: true},
+		Note:   "first",
+	}
+	args, err := Fields(&in)
+	if err != nil {
+		t.Fatalf("Fields() error = %v", err)
+	}
+	if len(args) != 4 {
+		t.Fatalf("Fields() returned %d fields, want 4", len(args))
+	}
+	if _, err := db.Exec(`INSERT INTO orders VALUES (?, ?, ?, ?)`, args...); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+
+	var typ string
+	if err := db.QueryRow(`SELECT typeof(user_id) || ',' || ref FROM orders`).Scan(&typ); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if want := "integer," + in.Ref.ID.ToHex(); typ != want {
+		t.Errorf("stored %q, want %q", typ, want)
+	}
+
+	var out Order
+	dest, _ := Fields(&out)
+	if err := db.QueryRowContext(context.Background(), `SELECT id, user_id, ref, note FROM orders`).Scan(dest...); err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if out != in {
+		t.Errorf("scanned %+v, want %+v", out, in)
+	}
+
+	type Bad struct {
+		ID Nano64 `nano64:"base64"`
+	}
+	if _, err := Fields(&Bad{}); err == nil {
+		t.Error("Fields() expected error for unknown tag option")
+	}
+}
@@ -0,0 +1,173 @@
+package nano64
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Bounds on the number of IDs in a Composite.
+const (
+	minCompositeLen = 2
+	maxCompositeLen = 3
+)
+
+// Composite is an ordered tuple of two or three IDs, such as (tenantID, entityID),
+// used as a compound key. It replaces keys built by string concatenation with
+// an encoding that sorts correctly.
+//
+// Its byte encoding is the big-endian IDs concatenated, so byte order equals
+// CompareComposite and a BYTEA/BINARY column of composites can be range-scanned
+// by its leading ID (see CompositeRange). In JSON it is an array of hex strings;
+// as text it is the hex IDs joined by "/". The zero Composite stands for a missing
+// key: it is null in JSON and NULL in SQL.
+type Composite struct {
+	ids [maxCompositeLen]Nano64
+	n   int
+}
+
+// NewComposite creates a Composite of two or three IDs.
+func NewComposite(ids ...Nano64) (Composite, error) {
+	if len(ids) < minCompositeLen || len(ids) > maxCompositeLen {
+		return Composite{}, fmt.Errorf("composite must have %d-%d IDs, got %d", minCompositeLen, maxCompositeLen, len(ids))
+	}
+	var c Composite
+	c.n = copy(c.ids[:], ids)
+	return c, nil
+}
+
+// Len returns the number of IDs; 0 for the zero Composite.
+func (c Composite) Len() int {
+	return c.n
+}
+
+// At returns the i-th ID, counting from 0.
+func (c Composite) At(i int) Nano64 {
+	if i < 0 || i >= c.n {
+		panic(fmt.Sprintf("composite index %d out of range [0, %d)", i, c.n))
+	}
+	return c.ids[i]
+}
+
+// Leading returns the first ID, the one composites are ranged by.
+func (c Composite) Leading() Nano64 {
+	return c.ids[0]
+}
+
+// IDs returns the IDs in order.
+func (c Composite) IDs() []Nano64 {
+	return append([]Nano64(nil), c.ids[:c.n]...)
+}
+
+// CompareComposite orders composites lexicographically by their IDs; a composite
+// sorts before a longer one it is a prefix of.
+// Returns -1 if a < b, 0 if a == b, 1 if a > b.
+func CompareComposite(a, b Composite) int {
+	for i := 0; i < a.n && i < b.n; i++ {
+		if c := Compare(a.ids[i], b.ids[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case a.n < b.n:
+		return -1
+	case a.n > b.n:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CompositeRange returns the smallest and largest composites of n IDs whose
+// leading ID lies in r, for a BETWEEN query on a composite column. Pass
+// IDRange{First: id, Last: id} to select every composite led by id.
+func CompositeRange(r IDRange, n int) (Composite, Composite, error) {
+	if n < minCompositeLen || n > maxCompositeLen {
+		return Composite{}, Composite{}, fmt.Errorf("composite must have %d-%d IDs, got %d", minCompositeLen, maxCompositeLen, n)
+	}
+	if Compare(r.First, r.Last) > 0 {
+		return Composite{}, Composite{}, fmt.Errorf("range first %s is after last %s", r.First.ToHex(), r.Last.ToHex())
+	}
+	first, last := Composite{n: n}, Composite{n: n}
+	first.ids[0], last.ids[0] = r.First, r.Last
+	for i := 1; i < n; i++ {
+		last.ids[i] = Nano64{value: math.MaxUint64}
+	}
+	return first, last, nil
+}
+
+// String returns the IDs as hex joined by "/".
+func (c Composite) String() string {
+	parts := make([]string, c.n)
+	for i := range parts {
+		parts[i] = c.ids[i].ToHex()
+	}
+	return strings.Join(parts, "/")
+}
+
+// ParseComposite parses the form produced by String.
+func ParseComposite(s string) (Composite, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < minCompositeLen || len(parts) > maxCompositeLen {
+		return Composite{}, fmt.Errorf("composite must have %d-%d IDs separated by /, got %q", minCompositeLen, maxCompositeLen, s)
+	}
+	var c Composite
+	for i, part := range parts {
+		id, err := FromHex(part)
+		if err != nil {
+			return Composite{}, fmt.Errorf("invalid composite ID %d: %w", i, err)
+		}
+		c.ids[i] = id
+	}
+	c.n = len(parts)
+	return c, nil
+}
+
+// ToBytes returns the 8 big-endian bytes of each ID, concatenated.
+func (c Composite) ToBytes() []byte {
+	bytes := make([]byte, 8*c.n)
+	for i := 0; i < c.n; i++ {
+		binary.BigEndian.PutUint64(bytes[8*i:], c.ids[i].value)
+	}
+	return bytes
+}
+
+// CompositeFromBytes parses the 16- or 24-byte encoding produced by ToBytes.
+func CompositeFromBytes(bytes []byte) (Composite, error) {
+	if len(bytes)%8 != 0 || len(bytes) < 8*minCompositeLen || len(bytes) > 8*maxCompositeLen {
+		return Composite{}, fmt.Errorf("composite must be %d or %d bytes, got %d", 8*minCompositeLen, 8*maxCompositeLen, len(bytes))
+	}
+	c := Composite{n: len(bytes) / 8}
+	for i := 0; i < c.n; i++ {
+		c.ids[i] = Nano64{value: binary.BigEndian.Uint64(bytes[8*i:])}
+	}
+	return c, nil
+}
+
+// MarshalJSON implements json.Marshaler. The zero Composite encodes as null.
+func (c Composite) MarshalJSON() ([]byte, error) {
+	if c.n == 0 {
+		return []byte("null"), nil
+	}
+	return json.Marshal(c.ids[:c.n])
+}
+
+// UnmarshalJSON implements json.Unmarshaler. null decodes to the zero Composite.
+func (c *Composite) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*c = Composite{}
+		return nil
+	}
+	var ids []Nano64
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return err
+	}
+	parsed, err := NewComposite(ids...)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
@@ -0,0 +1,81 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestCompositeRange(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE docs (key BLOB PRIMARY KEY)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	tenantA, tenantB := mustGenerate(t, 1000, 1), mustGenerate(t, 1000, 2)
+	for _, tenant := range []Nano64{tenantA, tenantB} {
+		for i := range 3 {
+			key := mustComposite(t, tenant, mustGenerate(t, int64(5000+i), uint32(i)))
+			if _, err := db.Exec("INSERT INTO docs (key) VALUES (?)", key); err != nil {
+				t.Fatalf("insert failed: %v", err)
+			}
+		}
+	}
+
+	first, last, err := CompositeRange(IDRange{First: tenantB, Last: tenantB}, 2)
+	if err != nil {
+		t.Fatalf("CompositeRange() error = %v", err)
+	}
+	rows, err := db.Query("SELECT key FROM docs WHERE key BETWEEN ? AND ? ORDER BY key", first, last)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer rows.Close()
+	var n int
+	for rows.Next() {
+		var key Composite
+		if err := rows.Scan(&key); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		if key.Leading() != tenantB || key.Len() != 2 {
+			t.Errorf("range returned %s", key)
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("range returned %d rows, want 3", n)
+	}
+
+	if _, _, err := CompositeRange(IDRange{First: tenantB, Last: tenantA}, 2); err == nil {
+		t.Error("CompositeRange() accepted an inverted range")
+	}
+}
+
+func TestCompositeZeroValueSQL(t *testing.T) {
+	c := mustComposite(t, New(1), New(2))
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE links (key BLOB)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO links (key) VALUES (?)", Composite{}); err != nil {
+		t.Fatalf("insert of the zero Composite failed: %v", err)
+	}
+	var null bool
+	if err := db.QueryRow("SELECT key IS NULL FROM links").Scan(&null); err != nil || !null {
+		t.Errorf("zero Composite stored as NULL = %v, %v", null, err)
+	}
+	if err := db.QueryRow("SELECT key FROM links").Scan(&c); err != nil || c.Len() != 0 {
+		t.Errorf("Scan(NULL) = %v, %v; want the zero Composite", c, err)
+	}
+}
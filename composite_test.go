@@ -0,0 +1,88 @@
+package nano64
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCompositeOrdering(t *testing.T) {
+	a, b, c := mustGenerate(t, 1000, 1), mustGenerate(t, 1000, 2), mustGenerate(t, 2000, 0)
+	composites := []Composite{
+		mustComposite(t, a, c),
+		mustComposite(t, a, b, c),
+		mustComposite(t, b, a),
+		mustComposite(t, c, a),
+		mustComposite(t, a, b),
+	}
+	for _, x := range composites {
+		for _, y := range composites {
+			want := CompareComposite(x, y)
+			if got := bytes.Compare(x.ToBytes(), y.ToBytes()); got != want && x.Len() == y.Len() {
+				t.Errorf("byte order of %s and %s = %d, want %d", x, y, got, want)
+			}
+		}
+	}
+	if CompareComposite(mustComposite(t, a, b), mustComposite(t, a, b, c)) != -1 {
+		t.Error("a prefix should sort first")
+	}
+
+	for _, x := range composites {
+		parsed, err := ParseComposite(x.String())
+		if err != nil || CompareComposite(parsed, x) != 0 {
+			t.Errorf("ParseComposite(%q) = %s, %v", x, parsed, err)
+		}
+		fromBytes, err := CompositeFromBytes(x.ToBytes())
+		if err != nil || CompareComposite(fromBytes, x) != 0 {
+			t.Errorf("CompositeFromBytes(%s) = %s, %v", x, fromBytes, err)
+		}
+		data, _ := json.Marshal(x)
+		var decoded Composite
+		if err := json.Unmarshal(data, &decoded); err != nil || CompareComposite(decoded, x) != 0 {
+			t.Errorf("JSON round trip of %s via %s = %s, %v", x, data, decoded, err)
+		}
+	}
+
+	for _, bad := range []string{a.ToHex(), "x/y", a.ToHex() + "/" + b.ToHex() + "/" + c.ToHex() + "/" + a.ToHex()} {
+		if _, err := ParseComposite(bad); err == nil {
+			t.Errorf("ParseComposite(%q) succeeded", bad)
+		}
+	}
+	if _, err := NewComposite(a); err == nil {
+		t.Error("NewComposite() accepted one ID")
+	}
+	var decoded Composite
+	if err := json.Unmarshal([]byte(`["`+a.ToHex()+`"]`), &decoded); err == nil {
+		t.Error("UnmarshalJSON() accepted one ID")
+	}
+}
+
+func mustComposite(t *testing.T, ids ...Nano64) Composite {
+	t.Helper()
+	c, err := NewComposite(ids...)
+	if err != nil {
+		t.Fatalf("NewComposite() error = %v", err)
+	}
+	return c
+}
+
+func TestCompositeZeroValue(t *testing.T) {
+	data, err := json.Marshal(Composite{})
+	if err != nil || string(data) != "null" {
+		t.Fatalf("json.Marshal(Composite{}) = %s, %v; want null", data, err)
+	}
+	c, _ := NewComposite(New(1), New(2))
+	if err := json.Unmarshal(data, &c); err != nil || c.Len() != 0 {
+		t.Errorf("json.Unmarshal(null) = %v, %v; want the zero Composite", c, err)
+	}
+
+	var holder struct {
+		Key Composite `json:"key"`
+	}
+	if err := json.Unmarshal([]byte(`{"key":null}`), &holder); err != nil || holder.Key.Len() != 0 {
+		t.Errorf("json.Unmarshal() of a null field = %v, %v", holder.Key, err)
+	}
+	if err := json.Unmarshal([]byte(`{"key":[]}`), &holder); err == nil {
+		t.Error("json.Unmarshal() of an empty array succeeded, want error")
+	}
+}
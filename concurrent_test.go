@@ -0,0 +1,93 @@
+package nano64
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerateConcurrent(t *testing.T) {
+	ids, err := GenerateConcurrent(context.Background(), 200_000, 8)
+	if err != nil {
+		t.Fatalf("GenerateConcurrent() error = %v", err)
+	}
+	if len(ids) != 200_000 {
+		t.Fatalf("got %d IDs, want 200000", len(ids))
+	}
+	for i := 1; i < len(ids); i++ {
+		if Compare(ids[i-1], ids[i]) >= 0 {
+			t.Fatalf("IDs %d and %d are not strictly increasing", i-1, i)
+		}
+	}
+
+	if ids, err := GenerateConcurrent(context.Background(), 0, 4); err != nil || len(ids) != 0 {
+		t.Errorf("GenerateConcurrent(0) = %d IDs, %v", len(ids), err)
+	}
+}
+
+func TestGenerateConcurrentReplacesCollisions(t *testing.T) {
+	defer SetEntropySource(testEntropy)
+	// Only a handful of random values exist, so most draws collide and must be
+	// regenerated until the requested count of unique IDs is reached.
+	SetEntropySource(func(p []byte) error {
+		if _, err := rand.Read(p); err != nil {
+			return err
+		}
+		for i := range p {
+			p[i] &= 0x01
+		}
+		return nil
+	})
+	ids, err := GenerateConcurrent(context.Background(), 6, 2)
+	if err != nil {
+		t.Fatalf("GenerateConcurrent() error = %v", err)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i-1] == ids[i] {
+			t.Fatalf("duplicate ID %v", ids[i])
+		}
+	}
+}
+
+func TestGenerateConcurrentFrozenClock(t *testing.T) {
+	defer SetEntropySource(testEntropy)
+	defer func(clock Clock, timeout time.Duration) {
+		concurrentClock, concurrentStallTimeout = clock, timeout
+	}(concurrentClock, concurrentStallTimeout)
+	concurrentClock = func() int64 { return 1_700_000_000_000 }
+	concurrentStallTimeout = 20 * time.Millisecond
+	// Two random values in a frozen millisecond cannot make three unique IDs.
+	SetEntropySource(func(p []byte) error {
+		for i := range p {
+			p[i] = byte(i/randomBytes) & 1
+		}
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := GenerateConcurrent(context.Background(), 3, 1)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("GenerateConcurrent() with a frozen clock error = nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GenerateConcurrent() with a frozen clock did not return")
+	}
+}
+
+func TestGenerateConcurrentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GenerateConcurrent(ctx, 10_000, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateConcurrent() error = %v, want context.Canceled", err)
+	}
+	if _, err := GenerateConcurrent(context.Background(), 10, 0); err == nil {
+		t.Error("GenerateConcurrent() expected error for zero parallelism")
+	}
+}
@@ -0,0 +1,278 @@
+package nano64
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GenerationMode selects which method of a Generator a Config hands out.
+type GenerationMode int
+
+const (
+	// ModeRandom draws fresh random bits for every ID (Generator.Generate).
+	ModeRandom GenerationMode = iota
+	// ModeMonotonic issues strictly increasing IDs (Generator.GenerateMonotonic).
+	ModeMonotonic
+)
+
+// String returns the name used in environment variables and flags.
+func (m GenerationMode) String() string {
+	switch m {
+	case ModeRandom:
+		return "random"
+	case ModeMonotonic:
+		return "monotonic"
+	default:
+		return fmt.Sprintf("GenerationMode(%d)", int(m))
+	}
+}
+
+// Config describes ID generation declaratively, so every service of a fleet can
+// be configured the same way from its environment or command line.
+type Config struct {
+	// Mode selects random or monotonic generation.
+	Mode GenerationMode
+
+	// TimestampBits sets the Layout; zero means DefaultLayout.
+	TimestampBits int
+
+	// Epoch offsets stored timestamps; the zero Time means the Unix epoch.
+	Epoch time.Time
+
+	// TenantKey, when set, draws random fields from TenantRNG(TenantKey) instead of DefaultRNG.
+	TenantKey []byte
+
+	// ClockOffset is added to the system clock, for hosts with a known skew.
+	ClockOffset time.Duration
+
+	// Clock replaces the system clock. It cannot be loaded and is meant for tests.
+	Clock Clock
+
+	// Representation is the storage form of IDs in the service's database. The
+	// Generator does not use it; it is carried so query builders and column
+	// helpers can be configured from the same source.
+	Representation Representation
+
+	// OverflowMargin is passed to WithOverflowMargin.
+	OverflowMargin time.Duration
+}
+
+// configSetting is one loadable Config field.
+type configSetting struct {
+	name  string
+	usage string
+	get   func(c *Config) string
+	set   func(c *Config, s string) error
+}
+
+// configSettings lists the loadable fields. Names are lower-case with dashes;
+// environment variables use them upper-cased with underscores.
+var configSettings = []configSetting{
+	{
+		name:  "mode",
+		usage: "ID generation mode: random or monotonic",
+		get:   func(c *Config) string { return c.Mode.String() },
+		set: func(c *Config, s string) error {
+			switch strings.ToLower(s) {
+			case "random":
+				c.Mode = ModeRandom
+			case "monotonic":
+				c.Mode = ModeMonotonic
+			default:
+				return fmt.Errorf("unknown generation mode %q", s)
+			}
+			return nil
+		},
+	},
+	{
+		name:  "timestamp-bits",
+		usage: "bits of the timestamp field (0 for the default 44)",
+		get:   func(c *Config) string { return strconv.Itoa(c.TimestampBits) },
+		set: func(c *Config, s string) error {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return fmt.Errorf("invalid timestamp bits %q: %w", s, err)
+			}
+			c.TimestampBits = n
+			return nil
+		},
+	},
+	{
+		name:  "epoch",
+		usage: "epoch of stored timestamps, RFC 3339 (empty for the Unix epoch)",
+		get: func(c *Config) string {
+			if c.Epoch.IsZero() {
+				return ""
+			}
+			return c.Epoch.Format(time.RFC3339)
+		},
+		set: func(c *Config, s string) error {
+			if s == "" {
+				c.Epoch = time.Time{}
+				return nil
+			}
+			epoch, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return fmt.Errorf("invalid epoch %q: %w", s, err)
+			}
+			c.Epoch = epoch
+			return nil
+		},
+	},
+	{
+		name:  "tenant-key",
+		usage: "hex-encoded 16-byte key of a per-tenant RNG (empty for the default RNG)",
+		get:   func(c *Config) string { return hex.EncodeToString(c.TenantKey) },
+		set: func(c *Config, s string) error {
+			key, err := hex.DecodeString(s)
+			if err != nil {
+				return fmt.Errorf("invalid tenant key: %w", err)
+			}
+			c.TenantKey = key
+			return nil
+		},
+	},
+	{
+		name:  "clock-offset",
+		usage: "duration added to the system clock",
+		get:   func(c *Config) string { return c.ClockOffset.String() },
+		set: func(c *Config, s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return fmt.Errorf("invalid clock offset %q: %w", s, err)
+			}
+			c.ClockOffset = d
+			return nil
+		},
+	},
+	{
+		name:  "representation",
+		usage: "storage form of IDs: bytes, signed or hex",
+		get:   func(c *Config) string { return c.Representation.String() },
+		set: func(c *Config, s string) error {
+			r, err := ParseRepresentation(s)
+			if err != nil {
+				return err
+			}
+			c.Representation = r
+			return nil
+		},
+	},
+	{
+		name:  "overflow-margin",
+		usage: "fail generation this long before the timestamp field overflows",
+		get:   func(c *Config) string { return c.OverflowMargin.String() },
+		set: func(c *Config, s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return fmt.Errorf("invalid overflow margin %q: %w", s, err)
+			}
+			c.OverflowMargin = d
+			return nil
+		},
+	},
+}
+
+// envName returns the environment variable of a setting.
+func envName(prefix, name string) string {
+	return prefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// ConfigFromEnv loads a Config from environment variables named prefix followed
+// by the upper-cased setting, e.g. NANO64_MODE, NANO64_TIMESTAMP_BITS,
+// NANO64_EPOCH, NANO64_TENANT_KEY, NANO64_CLOCK_OFFSET, NANO64_REPRESENTATION and
+// NANO64_OVERFLOW_MARGIN for prefix "NANO64_". Unset variables keep their defaults.
+func ConfigFromEnv(prefix string) (Config, error) {
+	return configFromLookup(prefix, os.LookupEnv)
+}
+
+// configFromLookup loads a Config through lookup.
+func configFromLookup(prefix string, lookup func(string) (string, bool)) (Config, error) {
+	var c Config
+	for _, setting := range configSettings {
+		name := envName(prefix, setting.name)
+		if value, ok := lookup(name); ok {
+			if err := setting.set(&c, value); err != nil {
+				return Config{}, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return c, nil
+}
+
+// RegisterFlags defines a flag for every loadable setting on fs, named prefix
+// followed by the setting (e.g. -nano64-mode for prefix "nano64-"). Parsing fs
+// stores the values in c; settings not given on the command line keep the
+// values c held when RegisterFlags was called, so flags can override a Config
+// loaded with ConfigFromEnv.
+func (c *Config) RegisterFlags(fs *flag.FlagSet, prefix string) {
+	for _, setting := range configSettings {
+		fs.Var(configFlag{c, setting}, prefix+setting.name, setting.usage)
+	}
+}
+
+// configFlag adapts a setting to flag.Value.
+type configFlag struct {
+	config  *Config
+	setting configSetting
+}
+
+func (f configFlag) String() string {
+	if f.config == nil {
+		return ""
+	}
+	return f.setting.get(f.config)
+}
+
+func (f configFlag) Set(s string) error {
+	return f.setting.set(f.config, s)
+}
+
+// NewGenerator builds the Generator the Config describes.
+func (c Config) NewGenerator() (*Generator, error) {
+	var opts []Option
+	if c.TimestampBits != 0 {
+		opts = append(opts, WithLayout(Layout{TimestampBits: c.TimestampBits}))
+	}
+	if !c.Epoch.IsZero() {
+		opts = append(opts, WithEpoch(c.Epoch))
+	}
+	if len(c.TenantKey) > 0 {
+		rng, err := TenantRNG(c.TenantKey)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithRNG(rng))
+	}
+	if clock, offset := c.Clock, c.ClockOffset.Milliseconds(); clock != nil || offset != 0 {
+		if clock == nil {
+			clock = DefaultClock
+		}
+		opts = append(opts, WithClock(func() int64 { return clock() + offset }))
+	}
+	if c.OverflowMargin != 0 {
+		opts = append(opts, WithOverflowMargin(c.OverflowMargin))
+	}
+	return NewGenerator(opts...)
+}
+
+// IDFunc builds the Generator and returns its method selected by Mode.
+func (c Config) IDFunc() (func() (Nano64, error), error) {
+	g, err := c.NewGenerator()
+	if err != nil {
+		return nil, err
+	}
+	switch c.Mode {
+	case ModeRandom:
+		return g.Generate, nil
+	case ModeMonotonic:
+		return g.GenerateMonotonic, nil
+	default:
+		return nil, fmt.Errorf("unknown generation mode %v", c.Mode)
+	}
+}
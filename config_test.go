@@ -0,0 +1,98 @@
+package nano64
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	env := map[string]string{
+		"IDS_MODE":            "monotonic",
+		"IDS_TIMESTAMP_BITS":  "42",
+		"IDS_EPOCH":           "2020-01-01T00:00:00Z",
+		"IDS_TENANT_KEY":      "000102030405060708090a0b0c0d0e0f",
+		"IDS_CLOCK_OFFSET":    "-250ms",
+		"IDS_REPRESENTATION":  "signed",
+		"IDS_OVERFLOW_MARGIN": "720h",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	c, err := configFromLookup("IDS_", lookup)
+	if err != nil {
+		t.Fatalf("configFromLookup() error = %v", err)
+	}
+	want := Config{
+		Mode:           ModeMonotonic,
+		TimestampBits:  42,
+		Epoch:          time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		TenantKey:      []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+		ClockOffset:    -250 * time.Millisecond,
+		Representation: RepresentationSigned,
+		OverflowMargin: 720 * time.Hour,
+	}
+	if c.Mode != want.Mode || c.TimestampBits != want.TimestampBits || !c.Epoch.Equal(want.Epoch) ||
+		!bytes.Equal(c.TenantKey, want.TenantKey) || c.ClockOffset != want.ClockOffset ||
+		c.Representation != want.Representation || c.OverflowMargin != want.OverflowMargin {
+		t.Errorf("configFromLookup() = %+v, want %+v", c, want)
+	}
+
+	env["IDS_MODE"] = "sequential"
+	if _, err := configFromLookup("IDS_", lookup); err == nil {
+		t.Error("configFromLookup() accepted an unknown mode")
+	}
+}
+
+func TestConfigFlags(t *testing.T) {
+	c := Config{Mode: ModeMonotonic, TimestampBits: 46}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	c.RegisterFlags(fs, "id-")
+
+	if err := fs.Parse([]string{"-id-mode=random", "-id-representation", "hex"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if c.Mode != ModeRandom || c.Representation != RepresentationHex || c.TimestampBits != 46 {
+		t.Errorf("after flags: %+v", c)
+	}
+	if err := fs.Parse([]string{"-id-timestamp-bits=many"}); err == nil {
+		t.Error("Parse() accepted invalid timestamp bits")
+	}
+}
+
+func TestConfigNewGenerator(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	c := Config{
+		Mode:          ModeMonotonic,
+		TimestampBits: 42,
+		Epoch:         time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		ClockOffset:   time.Second,
+		Clock:         func() int64 { return now },
+	}
+	g, err := c.NewGenerator()
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	next, err := c.IDFunc()
+	if err != nil {
+		t.Fatalf("IDFunc() error = %v", err)
+	}
+	a, _ := next()
+	b, _ := next()
+	if Compare(a, b) >= 0 {
+		t.Error("monotonic mode returned non-increasing IDs")
+	}
+	if got := g.Timestamp(a); got != now+1000 {
+		t.Errorf("Timestamp() = %d, want %d", got, now+1000)
+	}
+
+	c.TenantKey = []byte("short")
+	if _, err := c.NewGenerator(); err == nil {
+		t.Error("NewGenerator() accepted a short tenant key")
+	}
+}
@@ -0,0 +1,199 @@
+package nano64
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// confirmationAlphabet is Crockford's base32, which leaves out I, L, O and U.
+const confirmationAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const (
+	minConfirmationLength     = 6
+	maxConfirmationLength     = 8
+	defaultConfirmationWindow = 24 * time.Hour
+)
+
+var (
+	// ErrInvalidCode is returned for confirmation codes that are malformed or fail
+	// their check character, typically a typo.
+	ErrInvalidCode = errors.New("invalid confirmation code")
+
+	// ErrCodeNotFound is returned by Resolve for well-formed codes not issued
+	// within the window.
+	ErrCodeNotFound = errors.New("confirmation code not found")
+
+	// ErrCodeCollision is returned by Issue when another ID within the window
+	// already has the same code. Generate a new ID and try again.
+	ErrCodeCollision = errors.New("confirmation code collision")
+)
+
+// ConfirmationCodeOptions configures ConfirmationCodes.
+type ConfirmationCodeOptions struct {
+	// Length is the number of characters of a code including the check
+	// character, 6 to 8. Zero means 6.
+	Length int
+
+	// Window is how long an issued code stays resolvable and blocks colliding
+	// codes. Zero means 24 hours.
+	Window time.Duration
+
+	// Clock defaults to DefaultClock.
+	Clock Clock
+}
+
+// ConfirmationCodes derives short, human-friendly codes such as "7GQ2XM" from
+// IDs, for order confirmations read out over the phone while systems keep the
+// full ID. A code is the ID's random field plus as many low timestamp bits as
+// fit, in Crockford base32, followed by a check character that catches
+// single-character typos and most swapped neighbours.
+//
+// Codes are not unique forever: Issue guarantees uniqueness among the IDs issued
+// within the window and Resolve maps a code back to its ID. Persistent systems
+// can instead store the code, or search the window's ID range with Matches.
+// ConfirmationCodes is safe for concurrent use.
+type ConfirmationCodes struct {
+	length int
+	window int64
+	clock  Clock
+
+	mu     sync.Mutex
+	issued map[string]Nano64
+	order  []string // issued codes, oldest first
+}
+
+// NewConfirmationCodes creates ConfirmationCodes.
+func NewConfirmationCodes(opts ConfirmationCodeOptions) (*ConfirmationCodes, error) {
+	length := opts.Length
+	if length == 0 {
+		length = minConfirmationLength
+	}
+	if length < minConfirmationLength || length > maxConfirmationLength {
+		return nil, fmt.Errorf("confirmation code length must be %d-%d, got %d", minConfirmationLength, maxConfirmationLength, length)
+	}
+	if opts.Window < 0 {
+		return nil, fmt.Errorf("confirmation window must not be negative, got %v", opts.Window)
+	}
+	window := opts.Window
+	if window == 0 {
+		window = defaultConfirmationWindow
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	return &ConfirmationCodes{length: length, window: window.Milliseconds(), clock: clock, issued: make(map[string]Nano64)}, nil
+}
+
+// Code returns the confirmation code of id.
+func (c *ConfirmationCodes) Code(id Nano64) string {
+	digits := c.length - 1
+	timeBits := 5*digits - RandomBits
+	data := uint64(id.GetTimestamp())&(1<<timeBits-1)<<RandomBits | uint64(id.GetRandom())
+
+	code := make([]byte, c.length)
+	for i := digits - 1; i >= 0; i-- {
+		code[i] = confirmationAlphabet[data&31]
+		data >>= 5
+	}
+	code[digits] = confirmationAlphabet[confirmationCheck(code[:digits])]
+	return string(code)
+}
+
+// Matches reports whether code, as typed by a user, is the code of id.
+func (c *ConfirmationCodes) Matches(code string, id Nano64) bool {
+	normalized, err := c.normalize(code)
+	return err == nil && normalized == c.Code(id)
+}
+
+// Issue records the code of id for Resolve and returns it. It fails with
+// ErrCodeCollision if a different ID issued within the window has the same code.
+func (c *ConfirmationCodes) Issue(id Nano64) (string, error) {
+	code := c.Code(id)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune()
+	if existing, ok := c.issued[code]; ok {
+		if existing == id {
+			return code, nil
+		}
+		return "", fmt.Errorf("%w: %s is taken by %s", ErrCodeCollision, code, existing.ToHex())
+	}
+	c.issued[code] = id
+	c.order = append(c.order, code)
+	return code, nil
+}
+
+// Resolve returns the ID whose code was issued within the window. Input is
+// case-insensitive, ignores dashes and spaces, and reads I and L as 1 and O as 0.
+func (c *ConfirmationCodes) Resolve(code string) (Nano64, error) {
+	normalized, err := c.normalize(code)
+	if err != nil {
+		return Nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune()
+	id, ok := c.issued[normalized]
+	if !ok {
+		return Nil, ErrCodeNotFound
+	}
+	return id, nil
+}
+
+// prune forgets codes older than the window; c.mu must be held.
+func (c *ConfirmationCodes) prune() {
+	cutoff := c.clock() - c.window
+	n := 0
+	for n < len(c.order) && c.issued[c.order[n]].GetTimestamp() < cutoff {
+		delete(c.issued, c.order[n])
+		n++
+	}
+	c.order = c.order[n:]
+}
+
+// normalize canonicalizes a typed code and verifies its length and check character.
+func (c *ConfirmationCodes) normalize(code string) (string, error) {
+	code = strings.ToUpper(code)
+	normalized := make([]byte, 0, c.length)
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch ch {
+		case '-', ' ':
+			continue
+		case 'I', 'L':
+			ch = '1'
+		case 'O':
+			ch = '0'
+		}
+		if strings.IndexByte(confirmationAlphabet, ch) < 0 || len(normalized) == c.length {
+			return "", ErrInvalidCode
+		}
+		normalized = append(normalized, ch)
+	}
+	if len(normalized) != c.length {
+		return "", ErrInvalidCode
+	}
+	digits := c.length - 1
+	if confirmationAlphabet[confirmationCheck(normalized[:digits])] != normalized[digits] {
+		return "", ErrInvalidCode
+	}
+	return string(normalized), nil
+}
+
+// confirmationCheck returns the index of the Luhn mod 32 check character of digits.
+func confirmationCheck(digits []byte) int {
+	sum := 0
+	factor := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(confirmationAlphabet, digits[i])
+		sum += addend/32 + addend%32
+		factor = 3 - factor
+	}
+	return (32 - sum%32) % 32
+}
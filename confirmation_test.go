@@ -0,0 +1,79 @@
+package nano64
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfirmationCodes(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	codes, err := NewConfirmationCodes(ConfirmationCodeOptions{Window: time.Hour, Clock: func() int64 { return now }})
+	if err != nil {
+		t.Fatalf("NewConfirmationCodes() error = %v", err)
+	}
+
+	id := mustGenerate(t, now, 0xABCDE)
+	code, err := codes.Issue(id)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if len(code) != 6 || strings.ContainsAny(code, "ILOU") {
+		t.Errorf("Issue() = %q", code)
+	}
+
+	typed := strings.ToLower(code[:3] + "-" + code[3:])
+	if got, err := codes.Resolve(typed); err != nil || got != id {
+		t.Errorf("Resolve(%q) = %s, %v", typed, got.ToHex(), err)
+	}
+	if !codes.Matches(typed, id) || codes.Matches(code, mustGenerate(t, now, 0xABCDF)) {
+		t.Error("Matches() disagrees with Code()")
+	}
+
+	// Every single-character substitution fails the check character.
+	for i := range code {
+		for _, ch := range confirmationAlphabet {
+			if byte(ch) == code[i] {
+				continue
+			}
+			typo := code[:i] + string(ch) + code[i+1:]
+			if _, err := codes.Resolve(typo); !errors.Is(err, ErrInvalidCode) {
+				t.Fatalf("Resolve(%q) error = %v, want ErrInvalidCode", typo, err)
+			}
+		}
+	}
+	for _, bad := range []string{"", "ABC", code + "0", "U" + code[1:]} {
+		if _, err := codes.Resolve(bad); !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("Resolve(%q) error = %v, want ErrInvalidCode", bad, err)
+		}
+	}
+
+	// Same random field, timestamps 32 ms apart: a 6-character code keeps only 5 timestamp bits.
+	twin := mustGenerate(t, now+32, 0xABCDE)
+	if codes.Code(twin) != code {
+		t.Fatal("expected the twin to share the code")
+	}
+	if _, err := codes.Issue(twin); !errors.Is(err, ErrCodeCollision) {
+		t.Errorf("Issue(twin) error = %v, want ErrCodeCollision", err)
+	}
+	if again, err := codes.Issue(id); err != nil || again != code {
+		t.Errorf("reissuing the same ID = %q, %v", again, err)
+	}
+
+	now += time.Hour.Milliseconds() + 1
+	if _, err := codes.Resolve(code); !errors.Is(err, ErrCodeNotFound) {
+		t.Errorf("Resolve() after the window error = %v, want ErrCodeNotFound", err)
+	}
+	if _, err := codes.Issue(twin); err != nil {
+		t.Errorf("Issue(twin) after the window error = %v", err)
+	}
+
+	long, _ := NewConfirmationCodes(ConfirmationCodeOptions{Length: 8})
+	if long.Code(twin) == long.Code(id) || len(long.Code(id)) != 8 {
+		t.Error("8-character codes should tell the twins apart")
+	}
+	if _, err := NewConfirmationCodes(ConfirmationCodeOptions{Length: 9}); err == nil {
+		t.Error("NewConfirmationCodes() accepted length 9")
+	}
+}
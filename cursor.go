@@ -0,0 +1,128 @@
+package nano64
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// cursorVersion is the first byte of every cursor token.
+	cursorVersion = 1
+
+	// cursorMACLength is the length of the truncated HMAC-SHA256 tag.
+	cursorMACLength = 16
+
+	// cursorBodyLength is version + direction + bound + expiry.
+	cursorBodyLength = 1 + 1 + 8 + 8
+
+	// minCursorKeyLength is the minimum accepted HMAC key length.
+	minCursorKeyLength = 16
+)
+
+var (
+	// ErrInvalidCursor is returned for malformed or forged cursor tokens.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrCursorExpired is returned for authentic cursor tokens past their expiry.
+	ErrCursorExpired = errors.New("cursor expired")
+)
+
+// CursorDirection tells which side of the bound a page continues on.
+type CursorDirection uint8
+
+const (
+	// CursorAfter continues with IDs greater than the bound.
+	CursorAfter CursorDirection = iota
+	// CursorBefore continues with IDs less than the bound.
+	CursorBefore
+)
+
+// SecureCursor is a decoded pagination cursor.
+type SecureCursor struct {
+	Bound     Nano64
+	Direction CursorDirection
+	ExpiresAt time.Time
+}
+
+// CursorSigner issues and verifies opaque pagination tokens. Tokens are signed with
+// HMAC-SHA256, so clients can pass them back but cannot forge a cursor for an
+// arbitrary range or extend its lifetime.
+type CursorSigner struct {
+	key   []byte
+	ttl   time.Duration
+	clock Clock
+}
+
+// NewCursorSigner creates a signer. The key must be at least 16 bytes; ttl is the
+// lifetime of issued tokens and clock defaults to DefaultClock.
+func NewCursorSigner(key []byte, ttl time.Duration, clock Clock) (*CursorSigner, error) {
+	if len(key) < minCursorKeyLength {
+		return nil, fmt.Errorf("cursor key must be at least %d bytes, got %d", minCursorKeyLength, len(key))
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("cursor ttl must be positive, got %v", ttl)
+	}
+	if clock == nil {
+		clock = DefaultClock
+	}
+
+	keyCopy := make([]byte, len(key))
+	copy(keyCopy, key)
+	return &CursorSigner{key: keyCopy, ttl: ttl, clock: clock}, nil
+}
+
+// mac computes the truncated tag over a token body.
+func (s *CursorSigner) mac(body []byte) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write(body)
+	return h.Sum(nil)[:cursorMACLength]
+}
+
+// Encode returns an opaque URL-safe token for bound and direction.
+func (s *CursorSigner) Encode(bound Nano64, direction CursorDirection) string {
+	expiresAt := s.clock() + s.ttl.Milliseconds()
+
+	buf := make([]byte, cursorBodyLength, cursorBodyLength+cursorMACLength)
+	buf[0] = cursorVersion
+	buf[1] = byte(direction)
+	binary.BigEndian.PutUint64(buf[2:10], bound.value)
+	binary.BigEndian.PutUint64(buf[10:18], uint64(expiresAt))
+	buf = append(buf, s.mac(buf)...)
+
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// Decode verifies a token and returns the cursor it carries.
+// Returns ErrInvalidCursor for malformed or forged tokens and ErrCursorExpired for expired ones.
+func (s *CursorSigner) Decode(token string) (SecureCursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) != cursorBodyLength+cursorMACLength {
+		return SecureCursor{}, ErrInvalidCursor
+	}
+
+	body, tag := buf[:cursorBodyLength], buf[cursorBodyLength:]
+	if !hmac.Equal(tag, s.mac(body)) || body[0] != cursorVersion {
+		return SecureCursor{}, ErrInvalidCursor
+	}
+
+	direction := CursorDirection(body[1])
+	if direction != CursorAfter && direction != CursorBefore {
+		return SecureCursor{}, ErrInvalidCursor
+	}
+
+	expiresAt := int64(binary.BigEndian.Uint64(body[10:18]))
+	cursor := SecureCursor{
+		Bound:     Nano64{value: binary.BigEndian.Uint64(body[2:10])},
+		Direction: direction,
+		ExpiresAt: time.UnixMilli(expiresAt),
+	}
+	if s.clock() > expiresAt {
+		return cursor, ErrCursorExpired
+	}
+	return cursor, nil
+}
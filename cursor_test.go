@@ -0,0 +1,71 @@
+package nano64
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorSigner_Roundtrip(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	signer, err := NewCursorSigner([]byte("0123456789abcdef"), time.Minute, func() int64 { return now })
+	if err != nil {
+		t.Fatalf("NewCursorSigner() error = %v", err)
+	}
+
+	bound := New(0x123456789ABCDEF0)
+	token := signer.Encode(bound, CursorBefore)
+
+	cursor, err := signer.Decode(token)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !cursor.Bound.Equals(bound) || cursor.Direction != CursorBefore {
+		t.Errorf("Decode() = %+v", cursor)
+	}
+	if got, want := cursor.ExpiresAt.UnixMilli(), now+60_000; got != want {
+		t.Errorf("ExpiresAt = %d, want %d", got, want)
+	}
+
+	now += 60_001
+	if _, err := signer.Decode(token); !errors.Is(err, ErrCursorExpired) {
+		t.Errorf("Decode() expired error = %v, want ErrCursorExpired", err)
+	}
+}
+
+func TestCursorSigner_Forgery(t *testing.T) {
+	signer, err := NewCursorSigner([]byte("0123456789abcdef"), time.Minute, nil)
+	if err != nil {
+		t.Fatalf("NewCursorSigner() error = %v", err)
+	}
+	other, err := NewCursorSigner([]byte("fedcba9876543210"), time.Minute, nil)
+	if err != nil {
+		t.Fatalf("NewCursorSigner() error = %v", err)
+	}
+
+	token := signer.Encode(New(1), CursorAfter)
+	raw, _ := base64.RawURLEncoding.DecodeString(token)
+	raw[5] ^= 0x01 // move the bound
+	tampered := base64.RawURLEncoding.EncodeToString(raw)
+
+	for name, tok := range map[string]string{
+		"tampered":  tampered,
+		"other key": other.Encode(New(1), CursorAfter),
+		"garbage":   "not a token",
+		"empty":     "",
+	} {
+		if _, err := signer.Decode(tok); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: Decode() error = %v, want ErrInvalidCursor", name, err)
+		}
+	}
+}
+
+func TestNewCursorSigner_Errors(t *testing.T) {
+	if _, err := NewCursorSigner([]byte("short"), time.Minute, nil); err == nil {
+		t.Error("NewCursorSigner() expected error for short key")
+	}
+	if _, err := NewCursorSigner([]byte("0123456789abcdef"), 0, nil); err == nil {
+		t.Error("NewCursorSigner() expected error for zero ttl")
+	}
+}
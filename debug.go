@@ -0,0 +1,55 @@
+package nano64
+
+import "fmt"
+
+// Builds with the nano64debug tag check internal invariants and panic when one
+// fails, so staging environments catch misuse such as custom RNGs returning
+// more bits than asked for or clocks stepping backwards, instead of silently
+// producing out-of-order IDs. Without the tag debugEnabled is a false constant
+// and the checks compile away.
+
+// debugMaxClockStep is how far, in ms, the clock may step back between
+// monotonic IDs in debug builds before it is reported. Generators configured
+// with GeneratorSettings.MaxClockJump handle clock steps themselves and are
+// not checked.
+const debugMaxClockStep = 1000
+
+// debugMaxAhead is how far, in ms, a generator's clock may read ahead of
+// DefaultClock in debug builds. Further ahead usually means a custom clock in
+// the wrong unit or with a wrong offset. As with debugMaxClockStep, generators
+// configured with GeneratorSettings.MaxClockJump are not checked.
+const debugMaxAhead = 24 * 60 * 60 * 1000
+
+// debugFail reports a violated invariant. Call it only behind debugEnabled.
+func debugFail(format string, args ...any) {
+	panic("nano64: invariant violated: " + fmt.Sprintf(format, args...))
+}
+
+// debugCheckRandom reports an RNG that returned more than the bits requested;
+// the extra bits would otherwise be masked off silently.
+func debugCheckRandom(v uint32, bits int) {
+	if bits < 32 && v>>bits != 0 {
+		debugFail("RNG returned %#x for %d bits", v, bits)
+	}
+}
+
+// debugCheckFields reports a timestamp or random field that does not fit layout;
+// composing such an ID would silently drop the excess bits.
+func debugCheckFields(t int64, random uint64, layout Layout) {
+	if t < 0 || t > layout.maxTimestamp() {
+		debugFail("timestamp field %d does not fit %d bits", t, layout.TimestampBits)
+	}
+	if random&^layout.randomMask() != 0 {
+		debugFail("random field %#x does not fit %d bits", random, layout.RandomBits())
+	}
+}
+
+// debugCheckAhead reports a generator clock reading implausibly far ahead of
+// DefaultClock. Only clock readings are checked: IDs built from raw values or
+// from an explicit timestamp may lie in the future, like those of
+// GenerateExpiring.
+func debugCheckAhead(now int64) {
+	if ahead := now - DefaultClock(); ahead > debugMaxAhead {
+		debugFail("clock reads %dms ahead of DefaultClock", ahead)
+	}
+}
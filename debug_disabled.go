@@ -0,0 +1,6 @@
+//go:build !nano64debug
+
+package nano64
+
+// debugEnabled turns on the invariant checks of debug.go.
+const debugEnabled = false
@@ -0,0 +1,72 @@
+//go:build nano64debug
+
+package nano64
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// mustPanic runs f and fails unless it panics with an invariant violation.
+func mustPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		if msg, _ := r.(string); !strings.Contains(msg, "invariant violated") {
+			t.Errorf("%s: recovered %v, want an invariant violation", name, r)
+		}
+	}()
+	f()
+}
+
+func TestDebugInvariants(t *testing.T) {
+	wide := func(int) (uint32, error) { return 1 << 31, nil }
+	mustPanic(t, "wide RNG", func() { Generate(1_700_000_000_000, wide) })
+
+	now := int64(1_700_000_000_000)
+	g, err := NewGenerator(WithClock(func() int64 { return now }))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	if _, err := g.GenerateMonotonic(); err != nil {
+		t.Fatalf("GenerateMonotonic() error = %v", err)
+	}
+	now -= 500
+	if _, err := g.GenerateMonotonic(); err != nil {
+		t.Errorf("GenerateMonotonic() after a small clock step error = %v", err)
+	}
+	now -= 5000
+	mustPanic(t, "clock step", func() { g.GenerateMonotonic() })
+}
+
+func TestDebugRangeChecks(t *testing.T) {
+	// Raw values and explicit timestamps are not checked against the clock.
+	now := uint64(DefaultClock())
+	New((now + 2*debugMaxAhead) << RandomBits)
+	FromUint64(^uint64(0))
+	if _, err := GenerateExpiringAt(int64(now), 48*time.Hour, nil); err != nil {
+		t.Errorf("GenerateExpiringAt() error = %v", err)
+	}
+
+	ahead, err := NewGenerator(WithClock(func() int64 { return int64(now) + 2*debugMaxAhead }))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	mustPanic(t, "Generate ahead", func() { ahead.Generate() })
+	mustPanic(t, "GenerateMonotonic ahead", func() { ahead.GenerateMonotonic() })
+
+	g, err := NewGenerator(WithLayout(Layout{TimestampBits: 42}))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	mustPanic(t, "compose", func() { g.compose(1, 1<<22) })
+
+	priority, err := NewPriorityLayout(2)
+	if err != nil {
+		t.Fatalf("NewPriorityLayout() error = %v", err)
+	}
+	// 18 bits are left below the priority; a 19th would overwrite it.
+	wide := func(int) (uint32, error) { return 1 << 18, nil }
+	mustPanic(t, "priority RNG", func() { priority.Generate(int64(now), 0, wide) })
+}
@@ -0,0 +1,95 @@
+package nano64
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// dedupeMaxAhead is how far ahead of the clock an ID may be to move a Deduper's
+// window, allowing for clock skew between hosts.
+const dedupeMaxAhead = time.Minute
+
+// idHeap is a min-heap of IDs, ordered by value and therefore by embedded timestamp.
+type idHeap []Nano64
+
+func (h idHeap) Len() int           { return len(h) }
+func (h idHeap) Less(i, j int) bool { return h[i].value < h[j].value }
+func (h idHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *idHeap) Push(x any)        { *h = append(*h, x.(Nano64)) }
+func (h *idHeap) Pop() any {
+	old := *h
+	id := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return id
+}
+
+// Deduper remembers the IDs seen within a sliding window of embedded time.
+// The window trails the newest timestamp observed rather than the wall clock, so
+// replaying an old stream dedupes the same way as consuming it live. Only IDs at
+// most a minute ahead of the clock move the window, so a corrupt or forged ID
+// from the far future cannot evict everything tracked.
+// A Deduper is safe for concurrent use.
+type Deduper struct {
+	window int64
+	clock  Clock
+
+	mu     sync.Mutex
+	seen   map[Nano64]struct{}
+	order  idHeap
+	newest int64
+}
+
+// DedupeWindow creates a Deduper that remembers IDs for d of embedded time,
+// for at-least-once consumers keyed on Nano64 message IDs.
+func DedupeWindow(d time.Duration) *Deduper {
+	window := d.Milliseconds()
+	if window < 0 {
+		window = 0
+	}
+	return &Deduper{window: window, clock: DefaultClock, seen: make(map[Nano64]struct{})}
+}
+
+// Seen reports whether id was already seen within the window and records it.
+// IDs whose timestamp is older than the window are reported as unseen and not
+// recorded: their dedupe state, if any, has already been evicted. So are IDs
+// more than a minute ahead of the clock, which would otherwise stay tracked
+// until the window caught up with them.
+func (w *Deduper) Seen(id Nano64) bool {
+	ts := id.GetTimestamp()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if ts > w.newest {
+		if ts > w.clock()+dedupeMaxAhead.Milliseconds() {
+			return false
+		}
+		w.newest = ts
+		w.evict()
+	}
+	if ts < w.newest-w.window {
+		return false
+	}
+	if _, ok := w.seen[id]; ok {
+		return true
+	}
+	w.seen[id] = struct{}{}
+	heap.Push(&w.order, id)
+	return false
+}
+
+// Len returns the number of IDs currently tracked.
+func (w *Deduper) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.seen)
+}
+
+// evict drops IDs that have fallen out of the window.
+func (w *Deduper) evict() {
+	cutoff := w.newest - w.window
+	for len(w.order) > 0 && w.order[0].GetTimestamp() < cutoff {
+		delete(w.seen, heap.Pop(&w.order).(Nano64))
+	}
+}
@@ -0,0 +1,83 @@
+package nano64
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupeWindowSeen(t *testing.T) {
+	w := DedupeWindow(100 * time.Millisecond)
+	a := mustGenerate(t, 1000, 1)
+	b := mustGenerate(t, 1000, 2)
+
+	if w.Seen(a) {
+		t.Error("first Seen(a) = true, want false")
+	}
+	if !w.Seen(a) {
+		t.Error("second Seen(a) = false, want true")
+	}
+	if w.Seen(b) {
+		t.Error("Seen(b) = true, want false")
+	}
+	if w.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", w.Len())
+	}
+}
+
+func TestDedupeWindowEviction(t *testing.T) {
+	w := DedupeWindow(100 * time.Millisecond)
+	old := mustGenerate(t, 1000, 1)
+	w.Seen(old)
+	w.Seen(mustGenerate(t, 1100, 1))
+
+	if w.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2 while both are inside the window", w.Len())
+	}
+
+	w.Seen(mustGenerate(t, 1101, 1))
+	if w.Len() != 2 {
+		t.Errorf("Len() = %d, want 2 after eviction", w.Len())
+	}
+	// Evicted IDs are too old to track and come back as unseen.
+	if w.Seen(old) {
+		t.Error("Seen(evicted) = true, want false")
+	}
+	if w.Len() != 2 {
+		t.Errorf("Len() = %d, want 2; out-of-window IDs must not be recorded", w.Len())
+	}
+}
+
+func TestDedupeWindowFutureID(t *testing.T) {
+	const now = 1_700_000_000_000
+	w := DedupeWindow(100 * time.Millisecond)
+	w.clock = func() int64 { return now }
+	recent := mustGenerate(t, now-10, 1)
+	w.Seen(recent)
+
+	// A corrupt ID a year ahead must not move the window past everything tracked.
+	future := mustGenerate(t, now+365*24*time.Hour.Milliseconds(), 1)
+	if w.Seen(future) || w.Seen(future) {
+		t.Error("Seen(far future) = true, want false and not recorded")
+	}
+	if !w.Seen(recent) || w.Len() != 1 {
+		t.Errorf("Seen(recent) after a far-future ID = false or Len() = %d; want it still tracked", w.Len())
+	}
+
+	// Skew within a minute still moves the window.
+	skewed := mustGenerate(t, now+30*time.Second.Milliseconds(), 1)
+	if w.Seen(skewed) || !w.Seen(skewed) {
+		t.Error("Seen(slightly ahead) not recorded")
+	}
+	if w.Seen(recent) || w.Len() != 1 {
+		t.Errorf("Len() = %d, want the recent ID evicted by the skewed one", w.Len())
+	}
+}
+
+func mustGenerate(t *testing.T, ts int64, random uint32) Nano64 {
+	t.Helper()
+	id, err := Generate(ts, func(int) (uint32, error) { return random, nil })
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	return id
+}
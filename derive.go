@@ -0,0 +1,32 @@
+package nano64
+
+// mix64 is the SplitMix64 finalizer. It scrambles all 64 input bits so that
+// neighbouring inputs produce unrelated outputs.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xBF58476D1CE4E5B9
+	x ^= x >> 27
+	x *= 0x94D049BB133111EB
+	x ^= x >> 31
+	return x
+}
+
+// deriveMultiplier is an odd constant, so multiplying by it is a bijection on the random field.
+const deriveMultiplier = 0x9E3779B9
+
+// DeriveFrom produces a child ID that shares the parent's timestamp and carries
+// random bits derived deterministically from the parent and the discriminator.
+// The same (parent, discriminator) pair always yields the same child, which lets
+// parent/child rows created atomically co-locate in time-ordered storage.
+//
+// Distinct discriminators below 2^20 always yield distinct children of the same
+// parent. A child may coincide with the parent itself or with a
+// randomly generated ID from the same millisecond.
+func DeriveFrom(parent Nano64, discriminator uint32) Nano64 {
+	// Only the discriminator bits above the random field feed the offset, so the
+	// low bits map onto the random field one-to-one.
+	offset := mix64(parent.value ^ mix64(uint64(discriminator>>RandomBits)))
+
+	random := (uint64(discriminator)*deriveMultiplier + offset) & randomMask
+	return Nano64{value: (parent.value &^ randomMask) | random}
+}
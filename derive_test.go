@@ -0,0 +1,35 @@
+package nano64
+
+import "testing"
+
+func TestDeriveFrom_Deterministic(t *testing.T) {
+	parent := New(0x123456789ABCDEF0)
+
+	a := DeriveFrom(parent, 42)
+	b := DeriveFrom(parent, 42)
+	if !a.Equals(b) {
+		t.Errorf("DeriveFrom() not deterministic: %s != %s", a.ToHex(), b.ToHex())
+	}
+
+	if a.GetTimestamp() != parent.GetTimestamp() {
+		t.Errorf("child timestamp = %d, want %d", a.GetTimestamp(), parent.GetTimestamp())
+	}
+
+	other := DeriveFrom(New(0x123456789ABCDEF1), 42)
+	if a.Equals(other) {
+		t.Errorf("children of different parents should differ: %s", a.ToHex())
+	}
+}
+
+func TestDeriveFrom_DistinctDiscriminators(t *testing.T) {
+	parent := New(0x0123456789A00000)
+	seen := make(map[uint64]uint32)
+
+	for d := uint32(0); d < 1<<12; d++ {
+		child := DeriveFrom(parent, d)
+		if prev, ok := seen[child.Uint64Value()]; ok {
+			t.Fatalf("discriminators %d and %d collide on %s", prev, d, child.ToHex())
+		}
+		seen[child.Uint64Value()] = d
+	}
+}
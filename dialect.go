@@ -0,0 +1,87 @@
+package nano64
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect selects the SQL flavor produced by the query builders.
+type Dialect int
+
+const (
+	// DialectPostgres targets PostgreSQL: double-quoted identifiers, $n placeholders.
+	DialectPostgres Dialect = iota
+	// DialectMySQL targets MySQL and MariaDB: backquoted identifiers, ? placeholders.
+	DialectMySQL
+	// DialectSQLite targets SQLite: double-quoted identifiers, ? placeholders.
+	DialectSQLite
+)
+
+// String returns the canonical name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case DialectPostgres:
+		return "postgres"
+	case DialectMySQL:
+		return "mysql"
+	case DialectSQLite:
+		return "sqlite"
+	default:
+		return fmt.Sprintf("Dialect(%d)", int(d))
+	}
+}
+
+// ParseDialect returns the dialect named s, accepting common aliases such as
+// "postgresql", "pg", "mariadb" and "sqlite3".
+func ParseDialect(s string) (Dialect, error) {
+	switch strings.ToLower(s) {
+	case "postgres", "postgresql", "pg", "pgx":
+		return DialectPostgres, nil
+	case "mysql", "mariadb":
+		return DialectMySQL, nil
+	case "sqlite", "sqlite3":
+		return DialectSQLite, nil
+	default:
+		return 0, fmt.Errorf("unknown SQL dialect %q", s)
+	}
+}
+
+// QuoteIdent quotes an identifier. Dots separate qualified names ("schema.table"),
+// and each part is quoted separately.
+func (d Dialect) QuoteIdent(name string) string {
+	quote := `"`
+	if d == DialectMySQL {
+		quote = "`"
+	}
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quote + strings.ReplaceAll(part, quote, quote+quote) + quote
+	}
+	return strings.Join(parts, ".")
+}
+
+// Placeholder returns the bind parameter for the n-th argument, counting from 1.
+func (d Dialect) Placeholder(n int) string {
+	if d == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// maxParams returns the number of bind parameters a single statement may carry.
+func (d Dialect) maxParams() int {
+	if d == DialectSQLite {
+		// SQLITE_MAX_VARIABLE_NUMBER defaults to 32766 since SQLite 3.32.
+		return 32766
+	}
+	return 65535
+}
+
+// validate reports an error for unknown dialects.
+func (d Dialect) validate() error {
+	if d < DialectPostgres || d > DialectSQLite {
+		return fmt.Errorf("unknown SQL dialect %v", d)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package nano64
+
+import "testing"
+
+func TestParseDialect(t *testing.T) {
+	tests := []struct {
+		name string
+		want Dialect
+	}{
+		{"postgres", DialectPostgres},
+		{"PostgreSQL", DialectPostgres},
+		{"mariadb", DialectMySQL},
+		{"sqlite3", DialectSQLite},
+	}
+	for _, tt := range tests {
+		got, err := ParseDialect(tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDialect(%q) = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+		if again, _ := ParseDialect(got.String()); again != got {
+			t.Errorf("ParseDialect(%q) does not round-trip", got.String())
+		}
+	}
+	if _, err := ParseDialect("oracle"); err == nil {
+		t.Error("ParseDialect() expected error for unknown dialect")
+	}
+}
+
+func TestDialectQuoteIdent(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		name    string
+		want    string
+	}{
+		{DialectPostgres, "public.users", `"public"."users"`},
+		{DialectSQLite, `we"ird`, `"we""ird"`},
+		{DialectMySQL, "app.users", "`app`.`users`"},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.QuoteIdent(tt.name); got != tt.want {
+			t.Errorf("%v.QuoteIdent(%q) = %s, want %s", tt.dialect, tt.name, got, tt.want)
+		}
+	}
+}
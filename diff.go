@@ -0,0 +1,36 @@
+package nano64
+
+import "iter"
+
+// DiffSorted compares two ascending streams of IDs, such as exports of the same
+// table from two systems, and returns the IDs found only in a and only in b.
+// Repeated IDs are matched one for one.
+//
+// Each result walks both inputs in a single merge pass, in O(n) time and constant
+// memory, so a and b must be re-iterable: consuming both results iterates each
+// input twice. Inputs that are not sorted produce meaningless results.
+func DiffSorted(a, b iter.Seq[Nano64]) (onlyA, onlyB iter.Seq[Nano64]) {
+	return diffSorted(a, b), diffSorted(b, a)
+}
+
+// diffSorted returns the IDs of a missing from b.
+func diffSorted(a, b iter.Seq[Nano64]) iter.Seq[Nano64] {
+	return func(yield func(Nano64) bool) {
+		nextB, stop := iter.Pull(b)
+		defer stop()
+
+		other, more := nextB()
+		for id := range a {
+			for more && other.value < id.value {
+				other, more = nextB()
+			}
+			if more && other.value == id.value {
+				other, more = nextB()
+				continue
+			}
+			if !yield(id) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package nano64
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffSorted(t *testing.T) {
+	ids := func(values ...uint64) []Nano64 {
+		out := make([]Nano64, len(values))
+		for i, v := range values {
+			out[i] = New(v)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name         string
+		a, b         []Nano64
+		onlyA, onlyB []Nano64
+	}{
+		{"identical", ids(1, 2, 3), ids(1, 2, 3), nil, nil},
+		{"interleaved", ids(1, 3, 5, 7), ids(2, 3, 4, 7, 8), ids(1, 5), ids(2, 4, 8)},
+		{"empty a", nil, ids(1, 2), nil, ids(1, 2)},
+		{"duplicates", ids(1, 1, 2), ids(1, 2, 2), ids(1), ids(2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			onlyA, onlyB := DiffSorted(slices.Values(tt.a), slices.Values(tt.b))
+			if got := slices.Collect(onlyA); !slices.Equal(got, tt.onlyA) {
+				t.Errorf("onlyA = %v, want %v", got, tt.onlyA)
+			}
+			if got := slices.Collect(onlyB); !slices.Equal(got, tt.onlyB) {
+				t.Errorf("onlyB = %v, want %v", got, tt.onlyB)
+			}
+		})
+	}
+}
+
+func TestDiffSortedEarlyStop(t *testing.T) {
+	a := []Nano64{New(1), New(2), New(3)}
+	onlyA, _ := DiffSorted(slices.Values(a), slices.Values([]Nano64{New(2)}))
+	for id := range onlyA {
+		if id != New(1) {
+			t.Errorf("first ID = %v, want %v", id, New(1))
+		}
+		break
+	}
+}
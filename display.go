@@ -0,0 +1,59 @@
+package nano64
+
+import (
+	"strconv"
+	"time"
+)
+
+// FormatTime formats the embedded creation time with layout in loc.
+// A nil loc formats in UTC.
+func (n Nano64) FormatTime(layout string, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return n.ToDate().In(loc).Format(layout)
+}
+
+// HumanAge returns the age of the ID in a compact form such as "3h ago",
+// measured against DefaultClock.
+func (n Nano64) HumanAge() string {
+	return n.HumanAgeAt(time.UnixMilli(DefaultClock()))
+}
+
+// HumanAgeAt returns the age of the ID at now in the largest whole unit:
+// "just now" under a second, then "45s ago", "3m ago", "3h ago", "12d ago",
+// "5mo ago" and "2y ago". IDs from the future read as "in 3h".
+func (n Nano64) HumanAgeAt(now time.Time) string {
+	age := now.Sub(n.ToDate())
+	future := age < 0
+	if future {
+		age = -age
+	}
+	if age < time.Second {
+		return "just now"
+	}
+
+	const day = 24 * time.Hour
+	var amount int64
+	var unit string
+	switch {
+	case age < time.Minute:
+		amount, unit = int64(age/time.Second), "s"
+	case age < time.Hour:
+		amount, unit = int64(age/time.Minute), "m"
+	case age < day:
+		amount, unit = int64(age/time.Hour), "h"
+	case age < 30*day:
+		amount, unit = int64(age/day), "d"
+	case age < 365*day:
+		amount, unit = int64(age/(30*day)), "mo"
+	default:
+		amount, unit = int64(age/(365*day)), "y"
+	}
+
+	s := strconv.FormatInt(amount, 10) + unit
+	if future {
+		return "in " + s
+	}
+	return s + " ago"
+}
@@ -0,0 +1,42 @@
+package nano64
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTime(t *testing.T) {
+	id := mustGenerate(t, time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC).UnixMilli(), 0)
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	if got := id.FormatTime(time.DateTime, nil); got != "2024-03-10 23:30:00" {
+		t.Errorf("FormatTime(nil) = %s", got)
+	}
+	if got := id.FormatTime(time.DateTime+" MST", tokyo); got != "2024-03-11 08:30:00 JST" {
+		t.Errorf("FormatTime(JST) = %s", got)
+	}
+}
+
+func TestHumanAgeAt(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := mustGenerate(t, created.UnixMilli(), 0)
+
+	tests := []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{300 * time.Millisecond, "just now"},
+		{45 * time.Second, "45s ago"},
+		{3*time.Minute + 59*time.Second, "3m ago"},
+		{3 * time.Hour, "3h ago"},
+		{12 * 24 * time.Hour, "12d ago"},
+		{150 * 24 * time.Hour, "5mo ago"},
+		{800 * 24 * time.Hour, "2y ago"},
+		{-2 * time.Hour, "in 2h"},
+	}
+	for _, tt := range tests {
+		if got := id.HumanAgeAt(created.Add(tt.elapsed)); got != tt.want {
+			t.Errorf("HumanAgeAt(+%v) = %s, want %s", tt.elapsed, got, tt.want)
+		}
+	}
+}
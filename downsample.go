@@ -0,0 +1,38 @@
+package nano64
+
+import (
+	"iter"
+	"time"
+)
+
+// resolutionMillis converts a bucket resolution to whole milliseconds (at least 1).
+func resolutionMillis(resolution time.Duration) int64 {
+	return max(resolution.Milliseconds(), 1)
+}
+
+// DownsampleKey returns the stable key of the time bucket containing id: a Nano64
+// stamped at the bucket start with a zero random field. Buckets are aligned to
+// multiples of resolution since the Unix epoch, so rollup tables keyed by these
+// IDs line up with the raw table and sort with it. Resolutions below one
+// millisecond are treated as one millisecond.
+func DownsampleKey(id Nano64, resolution time.Duration) Nano64 {
+	step := resolutionMillis(resolution)
+	ts := id.GetTimestamp()
+	return Nano64{value: uint64(ts-ts%step) << timestampShift}
+}
+
+// DownsampleKeys yields the bucket keys of every bucket overlapping [start, end),
+// oldest first. Times outside the 44-bit timestamp range are clamped to it.
+func DownsampleKeys(start, end time.Time, resolution time.Duration) iter.Seq[Nano64] {
+	return func(yield func(Nano64) bool) {
+		step := resolutionMillis(resolution)
+		from := min(max(start.UnixMilli(), 0), maxTimestamp)
+		to := min(max(end.UnixMilli(), 0), maxTimestamp+1)
+
+		for ts := from - from%step; ts < to; ts += step {
+			if !yield(Nano64{value: uint64(ts) << timestampShift}) {
+				return
+			}
+		}
+	}
+}
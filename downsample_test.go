@@ -0,0 +1,46 @@
+package nano64
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDownsampleKey(t *testing.T) {
+	id := New(uint64(1_700_000_123_456)<<RandomBits | 0xABCDE)
+
+	key := DownsampleKey(id, time.Minute)
+	if key.GetRandom() != 0 {
+		t.Errorf("key random = %d, want 0", key.GetRandom())
+	}
+	if got, want := key.GetTimestamp(), int64(1_700_000_100_000); got != want {
+		t.Errorf("key timestamp = %d, want %d", got, want)
+	}
+	if Compare(key, id) > 0 {
+		t.Error("bucket key sorts after the IDs it contains")
+	}
+	if !DownsampleKey(key, time.Minute).Equals(key) {
+		t.Error("DownsampleKey() is not idempotent")
+	}
+}
+
+func TestDownsampleKeys(t *testing.T) {
+	start := time.UnixMilli(1_700_000_030_000)
+	end := start.Add(3 * time.Minute)
+
+	keys := slices.Collect(DownsampleKeys(start, end, time.Minute))
+	if len(keys) != 4 {
+		t.Fatalf("got %d keys, want 4", len(keys))
+	}
+	for i, key := range keys {
+		want := int64(1_699_999_980_000) + int64(i)*60_000
+		if key.GetTimestamp() != want {
+			t.Errorf("keys[%d] = %d, want %d", i, key.GetTimestamp(), want)
+		}
+	}
+
+	id := New(uint64(start.Add(90*time.Second).UnixMilli()) << RandomBits)
+	if !slices.Contains(keys, DownsampleKey(id, time.Minute)) {
+		t.Error("DownsampleKeys() misses the bucket of an ID in range")
+	}
+}
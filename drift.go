@@ -0,0 +1,119 @@
+package nano64
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DriftAlarm describes an observed ID whose timestamp disagrees with the local clock.
+type DriftAlarm struct {
+	// ID is the externally received ID that triggered the alarm.
+	ID Nano64
+	// Drift is the ID's embedded time minus the local clock; positive means the ID
+	// is from the local future, i.e. the local clock is behind.
+	Drift time.Duration
+	// At is the local clock reading when the ID was observed.
+	At time.Time
+}
+
+// DriftOptions configures a DriftMonitor.
+type DriftOptions struct {
+	// Threshold is the absolute drift that raises an alarm. Required.
+	Threshold time.Duration
+
+	// OnAlarm is invoked synchronously from Observe for every drift beyond Threshold,
+	// at most once per Cooldown. Required.
+	OnAlarm func(DriftAlarm)
+
+	// Cooldown suppresses repeated alarms for this long after one fired. Zero alarms every time.
+	Cooldown time.Duration
+
+	// Clock is the local clock that IDs are generated with; DefaultClock if nil.
+	Clock Clock
+}
+
+// DriftMonitor compares the local clock against the timestamps embedded in IDs
+// received from other services, catching a misconfigured NTP setup before the
+// skew corrupts ordering. Use its Now method as the timestamp source for local
+// generation so observation and generation share the same clock.
+//
+// Transit and queueing delay make received IDs look older than they are, so
+// observe IDs that were minted moments ago (e.g. in synchronous responses) and
+// choose a threshold comfortably above that latency.
+type DriftMonitor struct {
+	threshold time.Duration
+	cooldown  time.Duration
+	onAlarm   func(DriftAlarm)
+	clock     Clock
+
+	mu        sync.Mutex
+	maxDrift  time.Duration
+	lastAlarm int64
+	alarmed   bool
+}
+
+// NewDriftMonitor creates a DriftMonitor.
+func NewDriftMonitor(opts DriftOptions) (*DriftMonitor, error) {
+	if opts.Threshold <= 0 {
+		return nil, fmt.Errorf("drift threshold must be positive, got %v", opts.Threshold)
+	}
+	if opts.OnAlarm == nil {
+		return nil, fmt.Errorf("drift alarm callback is required")
+	}
+	if opts.Clock == nil {
+		opts.Clock = DefaultClock
+	}
+
+	return &DriftMonitor{
+		threshold: opts.Threshold,
+		cooldown:  opts.Cooldown,
+		onAlarm:   opts.OnAlarm,
+		clock:     opts.Clock,
+	}, nil
+}
+
+// Now returns the monitored clock's current epoch milliseconds; it satisfies Clock.
+func (m *DriftMonitor) Now() int64 {
+	return m.clock()
+}
+
+// Observe records an externally received ID and returns its drift against the local
+// clock. If the absolute drift exceeds the threshold the alarm callback is invoked.
+// Nil IDs are ignored.
+func (m *DriftMonitor) Observe(id Nano64) time.Duration {
+	if id.IsNil() {
+		return 0
+	}
+
+	now := m.clock()
+	drift := time.Duration(id.GetTimestamp()-now) * time.Millisecond
+	abs := drift
+	if abs < 0 {
+		abs = -abs
+	}
+
+	m.mu.Lock()
+	if abs > m.maxDrift {
+		m.maxDrift = abs
+	}
+	fire := abs > m.threshold &&
+		(!m.alarmed || time.Duration(now-m.lastAlarm)*time.Millisecond >= m.cooldown)
+	if fire {
+		m.alarmed = true
+		m.lastAlarm = now
+	}
+	m.mu.Unlock()
+
+	if fire {
+		m.onAlarm(DriftAlarm{ID: id, Drift: drift, At: time.UnixMilli(now)})
+	}
+	return drift
+}
+
+// MaxDrift returns the largest absolute drift observed so far.
+func (m *DriftMonitor) MaxDrift() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.maxDrift
+}
@@ -0,0 +1,55 @@
+package nano64
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDriftMonitor(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	var alarms []DriftAlarm
+
+	monitor, err := NewDriftMonitor(DriftOptions{
+		Threshold: time.Second,
+		Cooldown:  time.Minute,
+		OnAlarm:   func(a DriftAlarm) { alarms = append(alarms, a) },
+		Clock:     func() int64 { return now },
+	})
+	if err != nil {
+		t.Fatalf("NewDriftMonitor() error = %v", err)
+	}
+
+	at := func(ts int64) Nano64 { return New(uint64(ts) << RandomBits) }
+
+	if drift := monitor.Observe(at(now - 200)); drift != -200*time.Millisecond {
+		t.Errorf("Observe() = %v, want -200ms", drift)
+	}
+	if len(alarms) != 0 {
+		t.Fatalf("unexpected alarm for drift within threshold: %+v", alarms)
+	}
+
+	monitor.Observe(at(now + 5000))
+	monitor.Observe(at(now + 6000)) // suppressed by cooldown
+	if len(alarms) != 1 || alarms[0].Drift != 5*time.Second {
+		t.Fatalf("alarms = %+v, want one 5s alarm", alarms)
+	}
+
+	now += time.Minute.Milliseconds()
+	monitor.Observe(at(now - 3000))
+	if len(alarms) != 2 || alarms[1].Drift != -3*time.Second {
+		t.Errorf("alarms = %+v, want second -3s alarm", alarms)
+	}
+
+	if got := monitor.MaxDrift(); got != 6*time.Second {
+		t.Errorf("MaxDrift() = %v, want 6s", got)
+	}
+}
+
+func TestNewDriftMonitor_Errors(t *testing.T) {
+	if _, err := NewDriftMonitor(DriftOptions{OnAlarm: func(DriftAlarm) {}}); err == nil {
+		t.Error("NewDriftMonitor() expected error for zero threshold")
+	}
+	if _, err := NewDriftMonitor(DriftOptions{Threshold: time.Second}); err == nil {
+		t.Error("NewDriftMonitor() expected error for missing callback")
+	}
+}
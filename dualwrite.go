@@ -0,0 +1,191 @@
+package nano64
+
+import (
+	"cmp"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DualWriteAnomalyKind classifies a problem found by a DualWriteVerifier.
+type DualWriteAnomalyKind int
+
+const (
+	// DualWriteOrder marks a pair whose new ID is ordered against the previous
+	// pair's opposite to its old ID, at millisecond granularity.
+	DualWriteOrder DualWriteAnomalyKind = iota
+	// DualWriteDuplicate marks a new ID already paired with a different old ID.
+	DualWriteDuplicate
+	// DualWriteRemapped marks an old ID already paired with a different new ID.
+	DualWriteRemapped
+	// DualWriteTimestamp marks a new ID whose timestamp differs from the old
+	// ID's by more than the tolerance.
+	DualWriteTimestamp
+)
+
+// String returns a short name for the anomaly kind.
+func (k DualWriteAnomalyKind) String() string {
+	switch k {
+	case DualWriteOrder:
+		return "order"
+	case DualWriteDuplicate:
+		return "duplicate"
+	case DualWriteRemapped:
+		return "remapped"
+	case DualWriteTimestamp:
+		return "timestamp"
+	default:
+		return fmt.Sprintf("DualWriteAnomalyKind(%d)", int(k))
+	}
+}
+
+// DualWriteAnomaly describes one problem with an (old, new) pair. It is passed
+// to the metrics sink as the Err of an EventDualWriteAnomaly event.
+type DualWriteAnomaly struct {
+	Kind  DualWriteAnomalyKind
+	OldID uint64
+	NewID Nano64
+	// Other is the conflicting old ID of a DualWriteDuplicate, or the previous
+	// pair's old ID of a DualWriteOrder.
+	Other uint64
+	// Drift is the new ID's time minus the old ID's, set for DualWriteTimestamp.
+	Drift time.Duration
+}
+
+// Error describes the anomaly.
+func (a *DualWriteAnomaly) Error() string {
+	switch a.Kind {
+	case DualWriteOrder:
+		return fmt.Sprintf("dual-write order: %d and its predecessor %d are ordered unlike their new IDs", a.OldID, a.Other)
+	case DualWriteDuplicate:
+		return fmt.Sprintf("dual-write duplicate: %s is paired with both %d and %d", a.NewID, a.Other, a.OldID)
+	case DualWriteRemapped:
+		return fmt.Sprintf("dual-write remapped: %d is paired with a second ID %s", a.OldID, a.NewID)
+	default:
+		return fmt.Sprintf("dual-write %s: %s is %v away from %d", a.Kind, a.NewID, a.Drift, a.OldID)
+	}
+}
+
+// DualWriteOptions configures a DualWriteVerifier.
+type DualWriteOptions struct {
+	// Classifier decodes the timestamps of old IDs; nil means DefaultClassifier.
+	// Old IDs without a recognizable timestamp skip the order and timestamp checks.
+	Classifier *Classifier
+
+	// Tolerance is the largest accepted difference between the timestamps of
+	// an old and a new ID. Zero requires the same millisecond.
+	Tolerance time.Duration
+
+	// Metrics receives an EventDualWriteAnomaly event for every anomaly.
+	Metrics MetricsSink
+}
+
+// DualWriteStats counts the pairs a DualWriteVerifier has checked and the
+// anomalies by kind.
+type DualWriteStats struct {
+	Pairs      int64
+	Order      int64
+	Duplicates int64
+	Remapped   int64
+	Timestamp  int64
+}
+
+// OK reports whether no anomaly was found.
+func (s DualWriteStats) OK() bool {
+	return s.Order == 0 && s.Duplicates == 0 && s.Remapped == 0 && s.Timestamp == 0
+}
+
+// DualWriteVerifier checks the (old ID, new ID) pairs of a service writing both
+// formats during a migration, so a broken mapping is caught before cutover. Feed
+// it every pair in write order; it checks that new IDs keep the old IDs' order
+// across milliseconds, that the mapping is one-to-one and that timestamps match.
+//
+// The uniqueness checks keep every pair in memory; verify long migrations with
+// one verifier per period. A DualWriteVerifier is safe for concurrent use.
+type DualWriteVerifier struct {
+	classifier Classifier
+	tolerance  int64
+	metrics    MetricsSink
+
+	mu        sync.Mutex
+	byOld     map[uint64]Nano64
+	byNew     map[Nano64]uint64
+	prevOld   uint64
+	prevOldTs int64
+	prevNew   Nano64
+	hasPrev   bool
+	stats     DualWriteStats
+}
+
+// NewDualWriteVerifier creates a DualWriteVerifier.
+func NewDualWriteVerifier(opts DualWriteOptions) (*DualWriteVerifier, error) {
+	if opts.Tolerance < 0 {
+		return nil, fmt.Errorf("tolerance must not be negative, got %v", opts.Tolerance)
+	}
+	v := &DualWriteVerifier{
+		classifier: DefaultClassifier,
+		tolerance:  opts.Tolerance.Milliseconds(),
+		metrics:    opts.Metrics,
+		byOld:      make(map[uint64]Nano64),
+		byNew:      make(map[Nano64]uint64),
+	}
+	if opts.Classifier != nil {
+		v.classifier = *opts.Classifier
+	}
+	return v, nil
+}
+
+// Verify checks one pair and returns its anomalies, which are also counted and
+// sent to the metrics sink. Repeating a pair verified before is not an anomaly.
+func (v *DualWriteVerifier) Verify(oldID uint64, newID Nano64) []DualWriteAnomaly {
+	oldTs, _, hasTs := v.classifier.Timestamp(oldID)
+	newTs := newID.GetTimestamp()
+
+	v.mu.Lock()
+	var anomalies []DualWriteAnomaly
+	v.stats.Pairs++
+
+	if mapped, ok := v.byOld[oldID]; ok && mapped != newID {
+		v.stats.Remapped++
+		anomalies = append(anomalies, DualWriteAnomaly{Kind: DualWriteRemapped, OldID: oldID, NewID: newID})
+	} else if !ok {
+		v.byOld[oldID] = newID
+	}
+	if other, ok := v.byNew[newID]; ok && other != oldID {
+		v.stats.Duplicates++
+		anomalies = append(anomalies, DualWriteAnomaly{Kind: DualWriteDuplicate, OldID: oldID, NewID: newID, Other: other})
+	} else if !ok {
+		v.byNew[newID] = oldID
+	}
+
+	if hasTs {
+		if drift := newTs - oldTs; drift > v.tolerance || -drift > v.tolerance {
+			v.stats.Timestamp++
+			anomalies = append(anomalies, DualWriteAnomaly{Kind: DualWriteTimestamp, OldID: oldID, NewID: newID,
+				Drift: time.Duration(drift) * time.Millisecond})
+		}
+		if v.hasPrev {
+			oldCmp, newCmp := cmp.Compare(oldTs, v.prevOldTs), cmp.Compare(newTs, v.prevNew.GetTimestamp())
+			if oldCmp != 0 && newCmp != 0 && oldCmp != newCmp {
+				v.stats.Order++
+				anomalies = append(anomalies, DualWriteAnomaly{Kind: DualWriteOrder, OldID: oldID, NewID: newID, Other: v.prevOld})
+			}
+		}
+		v.prevOld, v.prevOldTs, v.prevNew, v.hasPrev = oldID, oldTs, newID, true
+	}
+	v.mu.Unlock()
+
+	if v.metrics != nil {
+		for i := range anomalies {
+			v.metrics(GeneratorEvent{Kind: EventDualWriteAnomaly, ID: newID, Err: &anomalies[i]})
+		}
+	}
+	return anomalies
+}
+
+// Stats returns the counts so far.
+func (v *DualWriteVerifier) Stats() DualWriteStats {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.stats
+}
@@ -0,0 +1,72 @@
+package nano64
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDualWriteVerifier(t *testing.T) {
+	const ms = int64(1_700_000_000_000)
+	c := DefaultClassifier
+	c.Clock = func() int64 { return ms }
+	snowflake := func(ts int64, seq uint64) uint64 {
+		return uint64(ts-TwitterSnowflakeEpoch)<<snowflakeTimeShift | seq
+	}
+
+	var events []GeneratorEvent
+	v, err := NewDualWriteVerifier(DualWriteOptions{
+		Classifier: &c,
+		Tolerance:  time.Millisecond,
+		Metrics:    func(e GeneratorEvent) { events = append(events, e) },
+	})
+	if err != nil {
+		t.Fatalf("NewDualWriteVerifier() error = %v", err)
+	}
+
+	steps := []struct {
+		name string
+		old  uint64
+		new  Nano64
+		want []DualWriteAnomalyKind
+	}{
+		{"first", snowflake(ms-100, 0), mustGenerate(t, ms-100, 5), nil},
+		{"same millisecond, any order", snowflake(ms-100, 1), mustGenerate(t, ms-100, 1), nil},
+		{"within tolerance", snowflake(ms-50, 0), mustGenerate(t, ms-49, 7), nil},
+		{"retry of a pair", snowflake(ms-50, 0), mustGenerate(t, ms-49, 7), nil},
+		{"order inverted", snowflake(ms-40, 0), mustGenerate(t, ms-60, 1), []DualWriteAnomalyKind{DualWriteTimestamp, DualWriteOrder}},
+		{"duplicate", snowflake(ms-30, 0), mustGenerate(t, ms-49, 7), []DualWriteAnomalyKind{DualWriteDuplicate, DualWriteTimestamp}},
+		{"remapped", snowflake(ms-100, 0), mustGenerate(t, ms-100, 9), []DualWriteAnomalyKind{DualWriteRemapped}},
+		{"random old ID", 0xFFFF_FFFF_FFFF_FFFF, mustGenerate(t, ms, 1), nil},
+	}
+	var wantTotal int
+	for _, step := range steps {
+		got := v.Verify(step.old, step.new)
+		if len(got) != len(step.want) {
+			t.Errorf("%s: Verify() = %v, want kinds %v", step.name, got, step.want)
+			continue
+		}
+		for i := range got {
+			if got[i].Kind != step.want[i] {
+				t.Errorf("%s: anomaly %d = %s, want %s", step.name, i, got[i].Kind, step.want[i])
+			}
+		}
+		wantTotal += len(step.want)
+	}
+
+	stats := v.Stats()
+	if stats.Pairs != int64(len(steps)) || stats.Order != 1 || stats.Duplicates != 1 || stats.Remapped != 1 || stats.Timestamp != 2 || stats.OK() {
+		t.Errorf("Stats() = %+v", stats)
+	}
+	if len(events) != wantTotal {
+		t.Fatalf("metrics received %d events, want %d", len(events), wantTotal)
+	}
+	var anomaly *DualWriteAnomaly
+	if events[0].Kind != EventDualWriteAnomaly || !errors.As(events[0].Err, &anomaly) || anomaly.Kind != DualWriteTimestamp {
+		t.Errorf("first event = %+v", events[0])
+	}
+
+	if _, err := NewDualWriteVerifier(DualWriteOptions{Tolerance: -time.Second}); err == nil {
+		t.Error("NewDualWriteVerifier() accepted a negative tolerance")
+	}
+}
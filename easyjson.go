@@ -0,0 +1,43 @@
+//go:build nano64_easyjson
+
+package nano64
+
+import (
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// Building with the `nano64_easyjson` tag adds the easyjson Marshaler and
+// Unmarshaler interfaces, so easyjson-generated code writes IDs straight into its
+// buffer instead of going through MarshalJSON and validating the result.
+
+// MarshalEasyJSON implements easyjson.Marshaler.
+func (n Nano64) MarshalEasyJSON(w *jwriter.Writer) {
+	var buf [hexLength + 2]byte
+	w.Buffer.AppendBytes(n.AppendJSON(buf[:0]))
+}
+
+// UnmarshalEasyJSON implements easyjson.Unmarshaler.
+func (n *Nano64) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	if err := n.UnmarshalJSON(l.Raw()); err != nil {
+		l.AddError(err)
+	}
+}
+
+// MarshalEasyJSON implements easyjson.Marshaler.
+func (n NullNano64) MarshalEasyJSON(w *jwriter.Writer) {
+	var buf [hexLength + 2]byte
+	w.Buffer.AppendBytes(n.AppendJSON(buf[:0]))
+}
+
+// UnmarshalEasyJSON implements easyjson.Unmarshaler.
+func (n *NullNano64) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	if l.IsNull() {
+		l.Skip()
+		*n = NullNano64{}
+		return
+	}
+	if err := n.UnmarshalJSON(l.Raw()); err != nil {
+		l.AddError(err)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build nano64_easyjson
+
+package nano64
+
+import (
+	"testing"
+
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+func TestEasyJSONRoundTrip(t *testing.T) {
+	id := New(0x0123456789ABCDEF)
+
+	var w jwriter.Writer
+	id.MarshalEasyJSON(&w)
+	NullNano64{}.MarshalEasyJSON(&w)
+	if got := string(w.Buffer.BuildBytes()); got != `"0123456789A-BCDEF"null` {
+		t.Fatalf("MarshalEasyJSON() wrote %s", got)
+	}
+
+	l := jlexer.Lexer{Data: []byte(`["0123456789A-BCDEF",null]`)}
+	var decoded Nano64
+	var null NullNano64
+	l.Delim('[')
+	decoded.UnmarshalEasyJSON(&l)
+	l.WantComma()
+	null.UnmarshalEasyJSON(&l)
+	l.WantComma()
+	l.Delim(']')
+	if err := l.Error(); err != nil {
+		t.Fatalf("UnmarshalEasyJSON() error = %v", err)
+	}
+	if decoded != id || null.Valid {
+		t.Errorf("UnmarshalEasyJSON() = %v, %v", decoded, null)
+	}
+
+	bad := jlexer.Lexer{Data: []byte(`"nope"`)}
+	decoded.UnmarshalEasyJSON(&bad)
+	if bad.Error() == nil {
+		t.Error("UnmarshalEasyJSON() expected error for invalid ID")
+	}
+}
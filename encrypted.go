@@ -3,7 +3,6 @@ package nano64
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
 	"fmt"
 )
 
@@ -77,7 +76,7 @@ func NewEncryptedIDConfig(aesKey []byte, clock Clock, rng RNG) (*EncryptedIDConf
 // generateIV generates a fresh 96-bit random IV.
 func (c *EncryptedIDConfig) generateIV() ([]byte, error) {
 	iv := make([]byte, IVLength)
-	if _, err := rand.Read(iv); err != nil {
+	if err := readEntropy(iv); err != nil {
 		return nil, fmt.Errorf("failed to generate IV: %w", err)
 	}
 	return iv, nil
@@ -0,0 +1,85 @@
+package nano64
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// entropyAttempts is how often a failed entropy read is tried before the
+	// failure is reported.
+	entropyAttempts = 3
+
+	// entropyRetryDelay is the pause before the first retry; it doubles for each
+	// further one.
+	entropyRetryDelay = time.Millisecond
+)
+
+var (
+	// entropySource holds the function installed with SetEntropySource, if any.
+	entropySource atomic.Pointer[func(p []byte) error]
+
+	// entropyErrorHandler holds the function installed with OnEntropyError, if any.
+	entropyErrorHandler atomic.Pointer[func(error)]
+)
+
+// SetEntropySource replaces the entropy behind DefaultRNG and the IVs of encrypted IDs.
+// fn must fill p completely with unpredictable bytes (for example from a hardware
+// TRNG) or return an error. Passing nil restores the built-in source.
+//
+// The built-in source is crypto/rand. Builds with the `tinygo` or `nano64_tiny` tag
+// do not import crypto/rand, which many TinyGo targets cannot provide, and fail to
+// generate IDs until a source has been set.
+func SetEntropySource(fn func(p []byte) error) {
+	if fn == nil {
+		entropySource.Store(nil)
+		return
+	}
+	entropySource.Store(&fn)
+}
+
+// OnEntropyError installs fn to be called whenever reading entropy fails, the one
+// realistic runtime failure of ID generation, so a service can log or alert on it
+// in one place. Failed reads are retried a few times within milliseconds before
+// they count as failures; fn then receives the final error, which is also returned
+// to the caller of the generation function. Passing nil removes the handler.
+func OnEntropyError(fn func(err error)) {
+	if fn == nil {
+		entropyErrorHandler.Store(nil)
+		return
+	}
+	entropyErrorHandler.Store(&fn)
+}
+
+// readEntropy fills p from the configured entropy source, retrying briefly on failure.
+func readEntropy(p []byte) error {
+	var err error
+	delay := entropyRetryDelay
+	for attempt := 1; ; attempt++ {
+		if err = readEntropyOnce(p); err == nil {
+			return nil
+		}
+		if attempt == entropyAttempts {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+
+	if fn := entropyErrorHandler.Load(); fn != nil {
+		(*fn)(err)
+	}
+	return err
+}
+
+// readEntropyOnce fills p from the configured entropy source.
+func readEntropyOnce(p []byte) error {
+	if fn := entropySource.Load(); fn != nil {
+		if err := (*fn)(p); err != nil {
+			return fmt.Errorf("entropy source failed: %w", err)
+		}
+		return nil
+	}
+	return defaultEntropy(p)
+}
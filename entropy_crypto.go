@@ -0,0 +1,11 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import "crypto/rand"
+
+// defaultEntropy reads from crypto/rand.
+func defaultEntropy(p []byte) error {
+	_, err := rand.Read(p)
+	return err
+}
@@ -0,0 +1,6 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+// testEntropy is the source tests restore after overriding it; nil selects crypto/rand.
+var testEntropy func([]byte) error
@@ -0,0 +1,77 @@
+package nano64
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetEntropySource(t *testing.T) {
+	defer SetEntropySource(testEntropy)
+
+	SetEntropySource(func(p []byte) error {
+		for i := range p {
+			p[i] = 0xAB
+		}
+		return nil
+	})
+
+	got, err := DefaultRNG(20)
+	if err != nil {
+		t.Fatalf("DefaultRNG() error = %v", err)
+	}
+	if got != 0xBABAB {
+		t.Errorf("DefaultRNG() = %#x, want 0xbabab", got)
+	}
+
+	failure := errors.New("trng offline")
+	SetEntropySource(func([]byte) error { return failure })
+	if _, err := DefaultRNG(20); !errors.Is(err, failure) {
+		t.Errorf("DefaultRNG() error = %v, want wrapped %v", err, failure)
+	}
+
+	SetEntropySource(testEntropy)
+	if _, err := DefaultRNG(20); err != nil {
+		t.Errorf("DefaultRNG() after reset error = %v", err)
+	}
+}
+
+func TestOnEntropyError(t *testing.T) {
+	defer SetEntropySource(testEntropy)
+	defer OnEntropyError(nil)
+
+	var reported []error
+	OnEntropyError(func(err error) { reported = append(reported, err) })
+
+	// A transient failure is retried without being reported.
+	calls := 0
+	SetEntropySource(func(p []byte) error {
+		calls++
+		if calls == 1 {
+			return errors.New("transient")
+		}
+		clear(p)
+		return nil
+	})
+	if _, err := GenerateDefault(); err != nil {
+		t.Fatalf("GenerateDefault() error = %v", err)
+	}
+	if calls != 2 || len(reported) != 0 {
+		t.Errorf("transient failure: %d calls, %d reports; want 2 calls, 0 reports", calls, len(reported))
+	}
+
+	failure := errors.New("trng offline")
+	calls = 0
+	SetEntropySource(func([]byte) error {
+		calls++
+		return failure
+	})
+	if _, err := GenerateDefault(); !errors.Is(err, failure) {
+		t.Errorf("GenerateDefault() error = %v, want wrapped %v", err, failure)
+	}
+	if calls != entropyAttempts {
+		t.Errorf("entropy source called %d times, want %d", calls, entropyAttempts)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], failure) {
+		t.Errorf("handler received %v, want one wrapped %v", reported, failure)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build tinygo || nano64_tiny
+
+package nano64
+
+import "errors"
+
+// defaultEntropy fails: tiny builds have no built-in entropy and rely on SetEntropySource.
+func defaultEntropy(p []byte) error {
+	return errors.New("no entropy source configured; call SetEntropySource")
+}
@@ -0,0 +1,20 @@
+//go:build tinygo || nano64_tiny
+
+package nano64
+
+import (
+	"crypto/rand"
+	"os"
+	"testing"
+)
+
+// testEntropy stands in for the board's TRNG so the tiny build can run the suite.
+var testEntropy = func(p []byte) error {
+	_, err := rand.Read(p)
+	return err
+}
+
+func TestMain(m *testing.M) {
+	SetEntropySource(testEntropy)
+	os.Exit(m.Run())
+}
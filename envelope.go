@@ -0,0 +1,98 @@
+package nano64
+
+import "slices"
+
+// Envelope is the header of an event in an event-sourced system. ID identifies the
+// event, CorrelationID the conversation it belongs to (the ID of its root event) and
+// CausationID the event that directly caused it, invalid for root events.
+type Envelope struct {
+	ID            Nano64     `json:"id"`
+	CorrelationID Nano64     `json:"correlation_id"`
+	CausationID   NullNano64 `json:"causation_id"`
+}
+
+// NewEnvelope creates the envelope of a root event with a monotonic ID, which is
+// also its correlation ID.
+func NewEnvelope() (Envelope, error) {
+	id, err := GenerateMonotonicDefault()
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{ID: id, CorrelationID: id}, nil
+}
+
+// Caused creates the envelope of an event caused by e. It shares e's correlation
+// ID and gets a monotonic ID, so within one process an effect always sorts after
+// its cause.
+func (e Envelope) Caused() (Envelope, error) {
+	id, err := GenerateMonotonicDefault()
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{
+		ID:            id,
+		CorrelationID: e.CorrelationID,
+		CausationID:   NullNano64{ID: e.ID, Valid: true},
+	}, nil
+}
+
+// IsRoot reports whether e has no cause.
+func (e Envelope) IsRoot() bool {
+	return !e.CausationID.Valid
+}
+
+// compareEnvelopes orders envelopes by event ID.
+func compareEnvelopes(a, b Envelope) int {
+	return Compare(a.ID, b.ID)
+}
+
+// CausationChain returns the events leading to id, from the root cause to the
+// event itself, in ID order. The chain stops early at a cause missing from
+// events; it is empty if id itself is missing.
+func CausationChain(events []Envelope, id Nano64) []Envelope {
+	byID := make(map[Nano64]Envelope, len(events))
+	for _, e := range events {
+		byID[e.ID] = e
+	}
+
+	var chain []Envelope
+	for {
+		e, ok := byID[id]
+		if !ok {
+			break
+		}
+		chain = append(chain, e)
+		// Deleting guards against cycles in corrupt data.
+		delete(byID, id)
+		if e.IsRoot() {
+			break
+		}
+		id = e.CausationID.ID
+	}
+	slices.SortFunc(chain, compareEnvelopes)
+	return chain
+}
+
+// Correlated returns the events of the conversation correlationID in ID order.
+func Correlated(events []Envelope, correlationID Nano64) []Envelope {
+	var matched []Envelope
+	for _, e := range events {
+		if e.CorrelationID == correlationID {
+			matched = append(matched, e)
+		}
+	}
+	slices.SortFunc(matched, compareEnvelopes)
+	return matched
+}
+
+// Effects returns the events directly caused by id, in ID order.
+func Effects(events []Envelope, id Nano64) []Envelope {
+	var effects []Envelope
+	for _, e := range events {
+		if e.CausationID.Valid && e.CausationID.ID == id {
+			effects = append(effects, e)
+		}
+	}
+	slices.SortFunc(effects, compareEnvelopes)
+	return effects
+}
@@ -0,0 +1,63 @@
+package nano64
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestEnvelope(t *testing.T) {
+	root, err := NewEnvelope()
+	if err != nil {
+		t.Fatalf("NewEnvelope() error = %v", err)
+	}
+	if !root.IsRoot() || root.CorrelationID != root.ID {
+		t.Errorf("NewEnvelope() = %+v, want a root correlated with itself", root)
+	}
+
+	child, err := root.Caused()
+	if err != nil {
+		t.Fatalf("Caused() error = %v", err)
+	}
+	sibling, _ := root.Caused()
+	grandchild, _ := child.Caused()
+	if child.IsRoot() || child.CausationID.ID != root.ID || child.CorrelationID != root.ID {
+		t.Errorf("Caused() = %+v, want caused by and correlated with %v", child, root.ID)
+	}
+	if Compare(root.ID, child.ID) >= 0 || Compare(child.ID, grandchild.ID) >= 0 {
+		t.Error("effects do not sort after their causes")
+	}
+
+	other, _ := NewEnvelope()
+	events := []Envelope{grandchild, other, sibling, child, root}
+
+	if got, want := CausationChain(events, grandchild.ID), []Envelope{root, child, grandchild}; !slices.Equal(got, want) {
+		t.Errorf("CausationChain() = %v, want %v", got, want)
+	}
+	if got := CausationChain(events[:2], grandchild.ID); !slices.Equal(got, []Envelope{grandchild}) {
+		t.Errorf("CausationChain() with missing cause = %v, want only the event", got)
+	}
+	if got := CausationChain(events, Nil); len(got) != 0 {
+		t.Errorf("CausationChain(Nil) = %v, want empty", got)
+	}
+	if got, want := Correlated(events, root.ID), []Envelope{root, child, sibling, grandchild}; !slices.Equal(got, want) {
+		t.Errorf("Correlated() = %v, want %v", got, want)
+	}
+	if got, want := Effects(events, root.ID), []Envelope{child, sibling}; !slices.Equal(got, want) {
+		t.Errorf("Effects() = %v, want %v", got, want)
+	}
+
+	for _, e := range []Envelope{root, grandchild} {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		var decoded Envelope
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error = %v", data, err)
+		}
+		if decoded != e {
+			t.Errorf("JSON round trip of %s = %+v, want %+v", data, decoded, e)
+		}
+	}
+}
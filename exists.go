@@ -0,0 +1,112 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ExistsQuery builds a query returning which of ids are absent from column of
+// table, for referential integrity checks before bulk inserts. The IDs are bound
+// as a VALUES list (a UNION ALL derived table on MySQL) and probed with NOT EXISTS,
+// so the lookup uses the column's index however many IDs are checked.
+//
+// IDs bind through Nano64.Value, so column must hold the 8-byte form. The query
+// selects a single column; read it with ScanMissing. ids must fit the dialect's
+// parameter limit; MissingIDs splits larger batches.
+func ExistsQuery(dialect Dialect, table, column string, ids []Nano64) (string, []any, error) {
+	if err := dialect.validate(); err != nil {
+		return "", nil, err
+	}
+	if len(ids) == 0 {
+		return "", nil, fmt.Errorf("no IDs to check")
+	}
+	if len(ids) > dialect.maxParams() {
+		return "", nil, fmt.Errorf("%d IDs exceed the %v limit of %d parameters", len(ids), dialect, dialect.maxParams())
+	}
+
+	var b strings.Builder
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	switch dialect {
+	case DialectMySQL:
+		b.WriteString("SELECT v.id FROM (")
+		for i := range ids {
+			if i > 0 {
+				b.WriteString(" UNION ALL ")
+			}
+			b.WriteString("SELECT ?")
+			if i == 0 {
+				b.WriteString(" AS id")
+			}
+		}
+		b.WriteString(") AS v")
+	default:
+		b.WriteString("WITH v(id) AS (VALUES ")
+		for i := range ids {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteByte('(')
+			b.WriteString(dialect.Placeholder(i + 1))
+			if dialect == DialectPostgres {
+				b.WriteString("::bytea")
+			}
+			b.WriteByte(')')
+		}
+		b.WriteString(") SELECT v.id FROM v")
+	}
+
+	table = dialect.QuoteIdent(table)
+	fmt.Fprintf(&b, " WHERE NOT EXISTS (SELECT 1 FROM %s WHERE %s.%s = v.id)", table, table, dialect.QuoteIdent(column))
+	return b.String(), args, nil
+}
+
+// ScanMissing reads the rows of an ExistsQuery into a set and closes them.
+func ScanMissing(rows *sql.Rows) (map[Nano64]struct{}, error) {
+	defer rows.Close()
+
+	missing := make(map[Nano64]struct{})
+	for rows.Next() {
+		var id Nano64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan missing ID: %w", err)
+		}
+		missing[id] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read missing IDs: %w", err)
+	}
+	return missing, nil
+}
+
+// MissingIDs returns the set of ids absent from column of table, running as many
+// ExistsQuery statements as the dialect's parameter limit requires.
+func MissingIDs(ctx context.Context, db Querier, dialect Dialect, table, column string, ids []Nano64) (map[Nano64]struct{}, error) {
+	missing := make(map[Nano64]struct{})
+	for start := 0; start < len(ids); start += dialect.maxParams() {
+		chunk := ids[start:min(start+dialect.maxParams(), len(ids))]
+		query, args, err := ExistsQuery(dialect, table, column, chunk)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("existence check failed: %w", err)
+		}
+		found, err := ScanMissing(rows)
+		if err != nil {
+			return nil, err
+		}
+		for id := range found {
+			missing[id] = struct{}{}
+		}
+	}
+	return missing, nil
+}
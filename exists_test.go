@@ -0,0 +1,68 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestExistsQuery(t *testing.T) {
+	ids := []Nano64{New(1), New(2)}
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{DialectPostgres, `WITH v(id) AS (VALUES ($1::bytea),($2::bytea)) SELECT v.id FROM v WHERE NOT EXISTS (SELECT 1 FROM "users" WHERE "users"."id" = v.id)`},
+		{DialectMySQL, "SELECT v.id FROM (SELECT ? AS id UNION ALL SELECT ?) AS v WHERE NOT EXISTS (SELECT 1 FROM `users` WHERE `users`.`id` = v.id)"},
+		{DialectSQLite, `WITH v(id) AS (VALUES (?),(?)) SELECT v.id FROM v WHERE NOT EXISTS (SELECT 1 FROM "users" WHERE "users"."id" = v.id)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.String(), func(t *testing.T) {
+			query, args, err := ExistsQuery(tt.dialect, "users", "id", ids)
+			if err != nil {
+				t.Fatalf("ExistsQuery() error = %v", err)
+			}
+			if query != tt.want {
+				t.Errorf("ExistsQuery() =\n%s\nwant\n%s", query, tt.want)
+			}
+			if len(args) != len(ids) {
+				t.Errorf("got %d args, want %d", len(args), len(ids))
+			}
+		})
+	}
+
+	if _, _, err := ExistsQuery(DialectSQLite, "users", "id", nil); err == nil {
+		t.Error("ExistsQuery() expected error for no IDs")
+	}
+}
+
+func TestMissingIDs(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE users (id BLOB PRIMARY KEY)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	present := []Nano64{mustGenerate(t, 1000, 1), mustGenerate(t, 1000, 2)}
+	for _, id := range present {
+		if _, err := db.Exec(`INSERT INTO users (id) VALUES (?)`, id); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+
+	absent := mustGenerate(t, 2000, 3)
+	missing, err := MissingIDs(context.Background(), db, DialectSQLite, "users", "id", append(present, absent, absent))
+	if err != nil {
+		t.Fatalf("MissingIDs() error = %v", err)
+	}
+	if _, ok := missing[absent]; !ok || len(missing) != 1 {
+		t.Errorf("MissingIDs() = %v, want only %v", missing, absent)
+	}
+}
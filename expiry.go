@@ -0,0 +1,48 @@
+package nano64
+
+import (
+	"fmt"
+	"time"
+)
+
+// Two conventions are supported for short-lived tokens keyed by Nano64:
+//
+//   - Issue-time: any ID's timestamp is its creation time and the TTL lives in the
+//     caller's policy. Check with TTLExpired.
+//   - Expiry-encoded: GenerateExpiring stamps the expiry instant itself into the
+//     timestamp field, so no policy is needed to check it. Check with Expired.
+//     Such IDs sort by expiry rather than by creation.
+
+// GenerateExpiring creates an expiry-encoded ID whose timestamp is now + ttl.
+func GenerateExpiring(ttl time.Duration) (Nano64, error) {
+	return GenerateExpiringAt(DefaultClock(), ttl, DefaultRNG)
+}
+
+// GenerateExpiringAt creates an expiry-encoded ID for a token issued at issuedAt (epoch ms).
+func GenerateExpiringAt(issuedAt int64, ttl time.Duration, rng RNG) (Nano64, error) {
+	if ttl <= 0 {
+		return Nano64{}, fmt.Errorf("ttl must be positive, got %v", ttl)
+	}
+	return Generate(issuedAt+ttl.Milliseconds(), rng)
+}
+
+// Expired reports whether an expiry-encoded ID has passed its expiry.
+func Expired(id Nano64) bool {
+	return ExpiredAt(id, DefaultClock())
+}
+
+// ExpiredAt reports whether an expiry-encoded ID has expired at now (epoch ms).
+func ExpiredAt(id Nano64, now int64) bool {
+	return now >= id.GetTimestamp()
+}
+
+// TTLExpired reports whether an ID created more than ttl ago has expired,
+// interpreting its timestamp as the issue time.
+func TTLExpired(id Nano64, ttl time.Duration) bool {
+	return TTLExpiredAt(id, ttl, DefaultClock())
+}
+
+// TTLExpiredAt is TTLExpired evaluated at now (epoch ms).
+func TTLExpiredAt(id Nano64, ttl time.Duration, now int64) bool {
+	return now >= id.GetTimestamp()+ttl.Milliseconds()
+}
@@ -0,0 +1,53 @@
+package nano64
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateExpiringAt(t *testing.T) {
+	issued := int64(1_700_000_000_000)
+	id, err := GenerateExpiringAt(issued, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("GenerateExpiringAt() error = %v", err)
+	}
+
+	if id.GetTimestamp() != issued+60_000 {
+		t.Errorf("GetTimestamp() = %d, want %d", id.GetTimestamp(), issued+60_000)
+	}
+	if ExpiredAt(id, issued+59_999) {
+		t.Error("ExpiredAt() = true before expiry")
+	}
+	if !ExpiredAt(id, issued+60_000) {
+		t.Error("ExpiredAt() = false at expiry")
+	}
+
+	if _, err := GenerateExpiringAt(issued, 0, nil); err == nil {
+		t.Error("GenerateExpiringAt() expected error for zero ttl")
+	}
+}
+
+func TestTTLExpiredAt(t *testing.T) {
+	issued := int64(1_700_000_000_000)
+	id := New(uint64(issued) << RandomBits)
+
+	if TTLExpiredAt(id, time.Second, issued+999) {
+		t.Error("TTLExpiredAt() = true within ttl")
+	}
+	if !TTLExpiredAt(id, time.Second, issued+1000) {
+		t.Error("TTLExpiredAt() = false after ttl")
+	}
+}
+
+func TestGenerateExpiring(t *testing.T) {
+	id, err := GenerateExpiring(time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateExpiring() error = %v", err)
+	}
+	if Expired(id) {
+		t.Error("freshly generated token reported expired")
+	}
+	if TTLExpired(New(1<<RandomBits), time.Hour) != true {
+		t.Error("ancient ID not reported expired")
+	}
+}
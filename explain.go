@@ -0,0 +1,236 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ExplainIssueKind classifies a problem found by ExplainCheck.
+type ExplainIssueKind int
+
+const (
+	// ExplainFunctionCall marks an ID column wrapped in a function call, such as hex(id).
+	ExplainFunctionCall ExplainIssueKind = iota
+	// ExplainCast marks an ID column cast to another type, such as CAST(id AS TEXT) or id::text.
+	ExplainCast
+	// ExplainExpression marks an ID column used in arithmetic or concatenation, such as id + 0.
+	ExplainExpression
+	// ExplainFullScan marks a query plan that scans a whole table.
+	ExplainFullScan
+)
+
+// String returns a short name for the issue kind.
+func (k ExplainIssueKind) String() string {
+	switch k {
+	case ExplainFunctionCall:
+		return "function-call"
+	case ExplainCast:
+		return "cast"
+	case ExplainExpression:
+		return "expression"
+	case ExplainFullScan:
+		return "full-scan"
+	default:
+		return fmt.Sprintf("ExplainIssueKind(%d)", int(k))
+	}
+}
+
+// ExplainIssue describes one problem found by ExplainCheck.
+type ExplainIssue struct {
+	Kind ExplainIssueKind
+	// Detail is the offending query fragment or plan line.
+	Detail string
+}
+
+// String returns the kind and detail.
+func (i ExplainIssue) String() string {
+	return i.Kind.String() + ": " + i.Detail
+}
+
+// ExplainOptions configures ExplainCheck.
+type ExplainOptions struct {
+	// Dialect selects the EXPLAIN syntax and how plans are read.
+	Dialect Dialect
+
+	// Columns are the names of the ID columns to check; nil means "id".
+	Columns []string
+
+	// SkipPlan checks the query text only, without running EXPLAIN.
+	SkipPlan bool
+}
+
+// ExplainCheck looks for ID predicates in query that cannot use an index: the
+// ID column wrapped in a function call, a cast or an expression in the WHERE,
+// ON or ORDER BY clauses, which forces the database to evaluate every row. It
+// then runs EXPLAIN with args and flags full table scans in the plan.
+//
+// Both checks are heuristics. The textual one only recognizes the columns named
+// in opts; the plan check depends on the planner, which may prefer a scan of a
+// small test table even for a sargable query. For PostgreSQL, run it on a
+// connection with SET enable_seqscan = off so only unavoidable scans are reported.
+func ExplainCheck(ctx context.Context, db Querier, query string, args []any, opts ExplainOptions) ([]ExplainIssue, error) {
+	if err := opts.Dialect.validate(); err != nil {
+		return nil, err
+	}
+	columns := opts.Columns
+	if columns == nil {
+		columns = []string{"id"}
+	}
+	issues := explainText(query, columns)
+	if opts.SkipPlan {
+		return issues, nil
+	}
+
+	plan, err := explainPlan(ctx, db, query, args, opts.Dialect)
+	if err != nil {
+		return issues, err
+	}
+	for _, line := range plan {
+		if explainFullScan(line, opts.Dialect) {
+			issues = append(issues, ExplainIssue{Kind: ExplainFullScan, Detail: line})
+		}
+	}
+	return issues, nil
+}
+
+// explainClause matches the keywords starting the clauses whose ID predicates matter.
+var explainClause = regexp.MustCompile(`(?i)\b(WHERE|ON|ORDER\s+BY)\b`)
+
+// explainText flags non-sargable uses of columns in the filtering and ordering
+// clauses of query.
+func explainText(query string, columns []string) []ExplainIssue {
+	loc := explainClause.FindStringIndex(query)
+	if loc == nil {
+		return nil
+	}
+	clauses := query[loc[0]:]
+
+	var issues []ExplainIssue
+	for _, column := range columns {
+		// The column, optionally qualified and quoted.
+		col := `(?:[\w"` + "`" + `]+\.)?["` + "`" + `]?` + regexp.QuoteMeta(column) + `["` + "`" + `]?`
+		patterns := []struct {
+			kind ExplainIssueKind
+			body string
+		}{
+			{ExplainCast, `(?:CAST|CONVERT)\s*\(\s*` + col + `(?:\s+AS\b|\s*,)[^)]*\)`},
+			{ExplainCast, col + `\s*::\s*\w+`},
+			{ExplainFunctionCall, `\w+\s*\(\s*` + col + `\s*[,)]`},
+			{ExplainExpression, col + `\s*(?:\|\||[-+*/%])\s*[^\s,)]+`},
+		}
+		for _, p := range patterns {
+			// The leading group keeps matches from starting inside a longer name.
+			re := regexp.MustCompile(`(?i)(?:^|[^\w.])(` + p.body + `)`)
+			for _, match := range re.FindAllStringSubmatch(clauses, -1) {
+				if p.kind == ExplainFunctionCall && explainNotFunction(match[1]) {
+					continue
+				}
+				issues = append(issues, ExplainIssue{Kind: p.kind, Detail: match[1]})
+			}
+		}
+	}
+	return issues
+}
+
+// explainNotFunction reports whether a function call match is a cast, reported
+// by the cast pattern, or a keyword followed by a parenthesized list.
+func explainNotFunction(match string) bool {
+	switch strings.ToUpper(strings.TrimSpace(match[:strings.IndexByte(match, '(')])) {
+	case "CAST", "CONVERT", "IN", "EXISTS", "ANY", "ALL", "SOME", "VALUES":
+		return true
+	default:
+		return false
+	}
+}
+
+// explainPlan runs EXPLAIN and returns the plan as one string per row.
+func explainPlan(ctx context.Context, db Querier, query string, args []any, dialect Dialect) ([]string, error) {
+	prefix := "EXPLAIN "
+	if dialect == DialectSQLite {
+		prefix = "EXPLAIN QUERY PLAN "
+	}
+	rows, err := db.QueryContext(ctx, prefix+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("explain failed: %w", err)
+	}
+	defer rows.Close()
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read explain columns: %w", err)
+	}
+
+	values := make([]any, len(names))
+	dest := make([]any, len(names))
+	for i := range dest {
+		dest[i] = &values[i]
+	}
+	var plan []string
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to read explain row: %w", err)
+		}
+		fields := make([]string, 0, len(names))
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			if v == nil {
+				continue
+			}
+			switch dialect {
+			case DialectMySQL:
+				// Tabular output: keep the column names to find the access type.
+				fields = append(fields, fmt.Sprintf("%s=%v", names[i], v))
+			case DialectSQLite:
+				// id, parent and notused precede the detail.
+				if names[i] == "detail" {
+					fields = append(fields, fmt.Sprint(v))
+				}
+			default:
+				fields = append(fields, fmt.Sprint(v))
+			}
+		}
+		plan = append(plan, strings.Join(fields, " "))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("explain iteration failed: %w", err)
+	}
+	return plan, nil
+}
+
+// explainFullScan reports whether a plan line describes a full table scan.
+func explainFullScan(line string, dialect Dialect) bool {
+	switch dialect {
+	case DialectPostgres:
+		return strings.Contains(line, "Seq Scan")
+	case DialectMySQL:
+		return strings.Contains(" "+line+" ", " type=ALL ")
+	default:
+		return strings.HasPrefix(line, "SCAN ") && !strings.HasPrefix(line, "SCAN CONSTANT ROW")
+	}
+}
+
+// ExplainTB is the subset of testing.TB used by AssertSargable.
+type ExplainTB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertSargable runs ExplainCheck in a test and reports every issue, and any
+// error, as a test failure:
+//
+//	nano64.AssertSargable(t, ctx, db, query, []any{since}, nano64.ExplainOptions{Dialect: nano64.DialectSQLite})
+func AssertSargable(t ExplainTB, ctx context.Context, db Querier, query string, args []any, opts ExplainOptions) {
+	t.Helper()
+	issues, err := ExplainCheck(ctx, db, query, args, opts)
+	if err != nil {
+		t.Errorf("ExplainCheck(%q) error = %v", query, err)
+	}
+	for _, issue := range issues {
+		t.Errorf("query %q is not index-friendly: %s", query, issue)
+	}
+}
@@ -0,0 +1,86 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestExplainCheckText(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []ExplainIssueKind
+	}{
+		{`SELECT id FROM events WHERE id >= $1 ORDER BY id LIMIT 10`, nil},
+		{`SELECT hex(id), count(id) FROM events WHERE id BETWEEN ? AND ?`, nil},
+		{`SELECT * FROM events WHERE user_id + 1 > ? AND lower(user_id) = ?`, nil},
+		{`SELECT * FROM events WHERE ? IN (id, parent_id)`, nil},
+		{`SELECT * FROM events WHERE hex(id) >= ?`, []ExplainIssueKind{ExplainFunctionCall}},
+		{`SELECT * FROM events e WHERE CAST(e."id" AS TEXT) LIKE ?`, []ExplainIssueKind{ExplainCast}},
+		{`SELECT * FROM events WHERE id::text = $1`, []ExplainIssueKind{ExplainCast}},
+		{`SELECT * FROM events WHERE id + 0 > ?`, []ExplainIssueKind{ExplainExpression}},
+		{`SELECT * FROM a JOIN b ON substr(b.id, 1, 11) = a.prefix`, []ExplainIssueKind{ExplainFunctionCall}},
+	}
+	for _, tt := range tests {
+		issues, err := ExplainCheck(context.Background(), nil, tt.query, nil, ExplainOptions{Dialect: DialectPostgres, SkipPlan: true})
+		if err != nil {
+			t.Fatalf("ExplainCheck() error = %v", err)
+		}
+		var kinds []ExplainIssueKind
+		for _, issue := range issues {
+			kinds = append(kinds, issue.Kind)
+		}
+		if fmt.Sprint(kinds) != fmt.Sprint(tt.want) {
+			t.Errorf("ExplainCheck(%q) = %v, want %v", tt.query, issues, tt.want)
+		}
+	}
+}
+
+// recordingTB collects the failures reported through ExplainTB.
+type recordingTB struct{ errors []string }
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestExplainCheckPlan(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE events (id BLOB PRIMARY KEY, kind TEXT)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	ctx := context.Background()
+	opts := ExplainOptions{Dialect: DialectSQLite}
+	id := mustGenerate(t, 1000, 1)
+
+	issues, err := ExplainCheck(ctx, db, `SELECT kind FROM events WHERE id >= ? ORDER BY id LIMIT 1`, []any{id}, opts)
+	if err != nil || len(issues) != 0 {
+		t.Errorf("sargable query: %v, %v", issues, err)
+	}
+
+	issues, err = ExplainCheck(ctx, db, `SELECT kind FROM events WHERE hex(id) >= ?`, []any{id.ToHex()}, opts)
+	if err != nil || len(issues) != 2 || issues[0].Kind != ExplainFunctionCall || issues[1].Kind != ExplainFullScan {
+		t.Errorf("wrapped column: %v, %v", issues, err)
+	}
+
+	var tb recordingTB
+	AssertSargable(&tb, ctx, db, `SELECT kind FROM events WHERE kind = ?`, []any{"x"}, opts)
+	if len(tb.errors) != 1 {
+		t.Errorf("AssertSargable() reported %v, want one full scan", tb.errors)
+	}
+	tb.errors = nil
+	AssertSargable(&tb, ctx, db, `SELECT kind FROM missing`, nil, opts)
+	if len(tb.errors) != 1 {
+		t.Errorf("AssertSargable() reported %v, want the EXPLAIN error", tb.errors)
+	}
+}
@@ -0,0 +1,145 @@
+package nano64
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Representation is the form in which an ID is stored in a database column.
+type Representation int
+
+const (
+	// RepresentationBytes stores the 8 big-endian bytes (BYTEA, BLOB, BINARY(8)).
+	RepresentationBytes Representation = iota
+	// RepresentationSigned stores the SignedNano64 form in a signed 64-bit integer (BIGINT).
+	RepresentationSigned
+	// RepresentationHex stores the dashed hex string produced by ToHex (TEXT, CHAR(17)).
+	RepresentationHex
+)
+
+// String returns the name of the representation.
+func (r Representation) String() string {
+	switch r {
+	case RepresentationBytes:
+		return "bytes"
+	case RepresentationSigned:
+		return "signed"
+	case RepresentationHex:
+		return "hex"
+	default:
+		return fmt.Sprintf("Representation(%d)", int(r))
+	}
+}
+
+// pgCopySignature starts every binary COPY stream.
+var pgCopySignature = []byte("PGCOPY\n\xff\r\n\x00")
+
+// WriteCopyText writes ids as the single-column payload of a Postgres
+// `COPY table (id) FROM STDIN` in text format, one row per line.
+func WriteCopyText(w io.Writer, ids []Nano64, repr Representation) error {
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 0, 32)
+	for _, id := range ids {
+		buf = buf[:0]
+		switch repr {
+		case RepresentationBytes:
+			// Backslashes are escaped in COPY text, so bytea's \x prefix becomes \\x.
+			buf = append(buf, `\\x`...)
+			buf = hex.AppendEncode(buf, id.ToBytes())
+		case RepresentationSigned:
+			buf = strconv.AppendInt(buf, SignedNano64.FromId(id), 10)
+		case RepresentationHex:
+			buf = append(buf, id.ToHex()...)
+		default:
+			return fmt.Errorf("unsupported representation %v", repr)
+		}
+		buf = append(buf, '\n')
+		if _, err := bw.Write(buf); err != nil {
+			return fmt.Errorf("failed to write COPY row: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write COPY data: %w", err)
+	}
+	return nil
+}
+
+// WriteCopyBinary writes ids as a complete single-column Postgres binary COPY
+// stream (`COPY table (id) FROM STDIN WITH (FORMAT binary)`), including header
+// and trailer. The column type must match repr: bytea, bigint or text.
+func WriteCopyBinary(w io.Writer, ids []Nano64, repr Representation) error {
+	bw := bufio.NewWriter(w)
+
+	header := make([]byte, 0, len(pgCopySignature)+8)
+	header = append(header, pgCopySignature...)
+	header = binary.BigEndian.AppendUint32(header, 0) // flags
+	header = binary.BigEndian.AppendUint32(header, 0) // header extension length
+	if _, err := bw.Write(header); err != nil {
+		return fmt.Errorf("failed to write COPY header: %w", err)
+	}
+
+	buf := make([]byte, 0, 32)
+	for _, id := range ids {
+		buf = binary.BigEndian.AppendUint16(buf[:0], 1) // field count
+		switch repr {
+		case RepresentationBytes:
+			buf = binary.BigEndian.AppendUint32(buf, 8)
+			buf = binary.BigEndian.AppendUint64(buf, id.value)
+		case RepresentationSigned:
+			buf = binary.BigEndian.AppendUint32(buf, 8)
+			buf = binary.BigEndian.AppendUint64(buf, uint64(SignedNano64.FromId(id)))
+		case RepresentationHex:
+			text := id.ToHex()
+			buf = binary.BigEndian.AppendUint32(buf, uint32(len(text)))
+			buf = append(buf, text...)
+		default:
+			return fmt.Errorf("unsupported representation %v", repr)
+		}
+		if _, err := bw.Write(buf); err != nil {
+			return fmt.Errorf("failed to write COPY row: %w", err)
+		}
+	}
+
+	if _, err := bw.Write([]byte{0xff, 0xff}); err != nil {
+		return fmt.Errorf("failed to write COPY trailer: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write COPY data: %w", err)
+	}
+	return nil
+}
+
+// ValuesFragment returns a multi-row Postgres VALUES list such as
+// `(-9223372036854775807),(-9223372036854775806)` for splicing into
+// `INSERT INTO t (id) VALUES ...` or `... FROM (VALUES ...) AS v(id)`.
+// Bytes are rendered with decode(..., 'hex') so the result does not depend on
+// standard_conforming_strings. It returns "" for no IDs.
+func ValuesFragment(ids []Nano64, repr Representation) (string, error) {
+	buf := make([]byte, 0, len(ids)*32)
+	for i, id := range ids {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, '(')
+		switch repr {
+		case RepresentationBytes:
+			buf = append(buf, "decode('"...)
+			buf = hex.AppendEncode(buf, id.ToBytes())
+			buf = append(buf, "','hex')"...)
+		case RepresentationSigned:
+			buf = strconv.AppendInt(buf, SignedNano64.FromId(id), 10)
+		case RepresentationHex:
+			buf = append(buf, '\'')
+			buf = append(buf, id.ToHex()...)
+			buf = append(buf, '\'')
+		default:
+			return "", fmt.Errorf("unsupported representation %v", repr)
+		}
+		buf = append(buf, ')')
+	}
+	return string(buf), nil
+}
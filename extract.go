@@ -0,0 +1,55 @@
+package nano64
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"time"
+)
+
+// FoundID is an ID located in a text stream by ExtractIDs.
+type FoundID struct {
+	ID Nano64
+	// Text is the ID as it appeared in the stream.
+	Text string
+	// Time is the embedded creation time.
+	Time time.Time
+
+	// Line and Column are 1-based; Column counts bytes. Offset is the 0-based
+	// byte offset from the start of the stream.
+	Line   int
+	Column int
+	Offset int64
+}
+
+// ExtractIDs scans a text stream such as a log file line by line and yields every
+// ID found in it, as FindIDs does for a byte slice, with its position and decoded
+// timestamp. Lines may be of any length. A read error other than io.EOF ends the
+// sequence; wrap r to observe it.
+func ExtractIDs(r io.Reader) iter.Seq[FoundID] {
+	return func(yield func(FoundID) bool) {
+		br := bufio.NewReader(r)
+		var offset int64
+		for line := 1; ; line++ {
+			text, err := br.ReadBytes('\n')
+			stopped := false
+			scanIDs(text, func(start, n int) bool {
+				value, _ := decodeCanonicalHex(text[start : start+n])
+				id := Nano64{value: value}
+				stopped = !yield(FoundID{
+					ID:     id,
+					Text:   string(text[start : start+n]),
+					Time:   id.ToDate(),
+					Line:   line,
+					Column: start + 1,
+					Offset: offset + int64(start),
+				})
+				return !stopped
+			})
+			if stopped || err != nil {
+				return
+			}
+			offset += int64(len(text))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package nano64
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestExtractIDs(t *testing.T) {
+	a, _ := FromHex("0199C6A1F5E-3B2D4")
+	b, _ := FromHex("0199C6A1F60-00001")
+	log := "start\n" +
+		"order 0199C6A1F5E-3B2D4 failed\n" +
+		strings.Repeat("x", 70000) + " 0199c6a1f6000001" // no trailing newline
+
+	var got []FoundID
+	for found := range ExtractIDs(strings.NewReader(log)) {
+		got = append(got, found)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ExtractIDs() found %d IDs, want 2", len(got))
+	}
+
+	want := []FoundID{
+		{ID: a, Text: "0199C6A1F5E-3B2D4", Line: 2, Column: 7, Offset: 12},
+		{ID: b, Text: "0199c6a1f6000001", Line: 3, Column: 70002, Offset: 37 + 70001},
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.ID != w.ID || g.Text != w.Text || g.Line != w.Line || g.Column != w.Column || g.Offset != w.Offset {
+			t.Errorf("found[%d] = %+v, want %+v", i, g, w)
+		}
+		if !g.Time.Equal(w.ID.ToDate()) {
+			t.Errorf("found[%d].Time = %v, want %v", i, g.Time, w.ID.ToDate())
+		}
+		if log[g.Offset:g.Offset+int64(len(g.Text))] != g.Text {
+			t.Errorf("found[%d].Offset does not point at the ID", i)
+		}
+	}
+}
+
+func TestExtractIDsStops(t *testing.T) {
+	text := strings.Repeat("0199C6A1F5E-3B2D4 ", 10)
+	n := 0
+	for range ExtractIDs(strings.NewReader(text)) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("iterated %d IDs, want 3", n)
+	}
+
+	r := iotest.ErrReader(errors.New("boom"))
+	for found := range ExtractIDs(r) {
+		t.Errorf("unexpected ID %v from failing reader", found.ID)
+	}
+}
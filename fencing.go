@@ -0,0 +1,137 @@
+package nano64
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrFenced is returned when a newer fencing token has been issued, i.e. a
+// newer leader has taken over.
+var ErrFenced = errors.New("fenced off by a newer token")
+
+// FencingStore persists the highest fencing token issued for a resource. It is
+// typically one row in a consistent database or a key in etcd or ZooKeeper.
+type FencingStore interface {
+	// Load returns the highest token issued, or Nil if none.
+	Load(ctx context.Context) (Nano64, error)
+
+	// Advance atomically replaces the highest token prev with next and reports
+	// whether it did; it must fail to if the stored token is no longer prev.
+	Advance(ctx context.Context, prev, next Nano64) (bool, error)
+}
+
+// FencingOptions configures a Fencer.
+type FencingOptions struct {
+	// Store persists the highest token. Required.
+	Store FencingStore
+
+	// Clock stamps the tokens; DefaultClock if nil.
+	Clock Clock
+}
+
+// Fencer issues fencing tokens for the current holder of a lease, such as the
+// elected leader of a group of writers. Tokens are time-ordered IDs that
+// strictly increase across every Fencer sharing the store, however their clocks
+// disagree: each is recorded in the store before use, and a Fencer whose last
+// token is no longer the highest learns it was deposed. Storage that accepts a
+// write only with the highest token seen so far (see AdmitFencingToken) then
+// rejects a deposed leader that still believes it holds the lease.
+// A Fencer is safe for concurrent use.
+type Fencer struct {
+	store     FencingStore
+	generator *Generator
+
+	mu   sync.Mutex
+	last Nano64
+	held bool
+}
+
+// NewFencer creates a Fencer. Call Acquire after obtaining the lease.
+func NewFencer(opts FencingOptions) (*Fencer, error) {
+	if opts.Store == nil {
+		return nil, fmt.Errorf("fencing Store is required")
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	generator, err := NewGenerator(WithClock(clock))
+	if err != nil {
+		return nil, err
+	}
+	return &Fencer{store: opts.Store, generator: generator}, nil
+}
+
+// Acquire starts a term after the caller obtained the lease: it issues a token
+// above every token issued before, by any Fencer, and returns it. It returns
+// ErrFenced if another Fencer issued a token meanwhile.
+func (f *Fencer) Acquire(ctx context.Context) (Nano64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.held = false
+	floor, err := f.store.Load(ctx)
+	if err != nil {
+		return Nil, fmt.Errorf("failed to load fencing token: %w", err)
+	}
+	return f.issueLocked(ctx, floor)
+}
+
+// Next issues a token above the last one and returns it. It returns ErrFenced
+// if another Fencer issued a token since, after which only Acquire succeeds.
+func (f *Fencer) Next(ctx context.Context) (Nano64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.held {
+		return Nil, fmt.Errorf("%w: no term acquired", ErrFenced)
+	}
+	return f.issueLocked(ctx, f.last)
+}
+
+// Token returns the last token issued and whether the term is still held as far
+// as this Fencer knows.
+func (f *Fencer) Token() (Nano64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.last, f.held
+}
+
+// issueLocked records a token above prev in the store; f.mu must be held.
+func (f *Fencer) issueLocked(ctx context.Context, prev Nano64) (Nano64, error) {
+	if prev.value == ^uint64(0) {
+		return Nil, fmt.Errorf("fencing tokens exhausted")
+	}
+	next, err := f.generator.GenerateMonotonic()
+	if err != nil {
+		return Nil, fmt.Errorf("failed to generate fencing token: %w", err)
+	}
+	if next.value <= prev.value {
+		next = Nano64{value: prev.value + 1}
+	}
+	ok, err := f.store.Advance(ctx, prev, next)
+	if err != nil {
+		return Nil, fmt.Errorf("failed to store fencing token: %w", err)
+	}
+	if !ok {
+		f.held = false
+		return Nil, fmt.Errorf("%w: token %s is no longer the highest", ErrFenced, prev.ToHex())
+	}
+	f.last, f.held = next, true
+	return next, nil
+}
+
+// AdmitFencingToken is the storage side of fencing: it records token in highest
+// if it is at least the highest token seen, and otherwise returns ErrFenced.
+// Writes carrying a rejected token come from a deposed leader.
+func AdmitFencingToken(highest *AtomicNano64, token Nano64) error {
+	for {
+		current := highest.Load()
+		if token.value < current.value {
+			return fmt.Errorf("%w: token %s is below %s", ErrFenced, token.ToHex(), current.ToHex())
+		}
+		if token == current || highest.CompareAndSwap(current, token) {
+			return nil
+		}
+	}
+}
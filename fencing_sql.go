@@ -0,0 +1,81 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"fmt"
+)
+
+// SQLFencingStore is a FencingStore keeping the highest token of each named
+// resource in a table:
+//
+//	CREATE TABLE fencing_tokens (name VARCHAR(255) PRIMARY KEY, token BYTEA NOT NULL)
+type SQLFencingStore struct {
+	dialect Dialect
+	table   string
+	name    string
+	execer  Execer
+	querier Querier
+}
+
+// NewSQLFencingStore creates a store for the resource name in table (columns
+// name and token). db is usually a *sql.DB.
+func NewSQLFencingStore(db interface {
+	Execer
+	Querier
+}, dialect Dialect, table, name string) (*SQLFencingStore, error) {
+	if err := dialect.validate(); err != nil {
+		return nil, err
+	}
+	if table == "" || name == "" {
+		return nil, fmt.Errorf("fencing table and name are required")
+	}
+	return &SQLFencingStore{dialect: dialect, table: table, name: name, execer: db, querier: db}, nil
+}
+
+// Load implements FencingStore.
+func (s *SQLFencingStore) Load(ctx context.Context) (Nano64, error) {
+	query := fmt.Sprintf("SELECT token FROM %s WHERE name = %s", s.dialect.QuoteIdent(s.table), s.dialect.Placeholder(1))
+	rows, err := s.querier.QueryContext(ctx, query, s.name)
+	if err != nil {
+		return Nil, err
+	}
+	defer rows.Close()
+	var token Nano64
+	if rows.Next() {
+		if err := rows.Scan(&token); err != nil {
+			return Nil, err
+		}
+	}
+	return token, rows.Err()
+}
+
+// Advance implements FencingStore with a conditional UPDATE, or an INSERT for
+// the first token of the resource.
+func (s *SQLFencingStore) Advance(ctx context.Context, prev, next Nano64) (bool, error) {
+	table := s.dialect.QuoteIdent(s.table)
+	var query string
+	var args []any
+	if prev.IsNil() {
+		insert := "INSERT INTO %s (name, token) VALUES (%s, %s) ON CONFLICT DO NOTHING"
+		if s.dialect == DialectMySQL {
+			insert = "INSERT IGNORE INTO %s (name, token) VALUES (%s, %s)"
+		}
+		query = fmt.Sprintf(insert, table, s.dialect.Placeholder(1), s.dialect.Placeholder(2))
+		args = []any{s.name, next}
+	} else {
+		query = fmt.Sprintf("UPDATE %s SET token = %s WHERE name = %s AND token = %s",
+			table, s.dialect.Placeholder(1), s.dialect.Placeholder(2), s.dialect.Placeholder(3))
+		args = []any{next, s.name, prev}
+	}
+	result, err := s.execer.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
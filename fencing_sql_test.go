@@ -0,0 +1,80 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestFencer(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `CREATE TABLE fencing_tokens (name TEXT PRIMARY KEY, token BLOB NOT NULL)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	store, err := NewSQLFencingStore(db, DialectSQLite, "fencing_tokens", "orders")
+	if err != nil {
+		t.Fatalf("NewSQLFencingStore() error = %v", err)
+	}
+
+	// The second leader's clock is an hour behind the first's.
+	const now = 1_700_000_000_000
+	first, _ := NewFencer(FencingOptions{Store: store, Clock: func() int64 { return now }})
+	second, _ := NewFencer(FencingOptions{Store: store, Clock: func() int64 { return now - 3_600_000 }})
+
+	if _, err := first.Next(ctx); !errors.Is(err, ErrFenced) {
+		t.Errorf("Next() before Acquire() error = %v, want ErrFenced", err)
+	}
+	a, err := first.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	b, err := first.Next(ctx)
+	if err != nil || Compare(b, a) <= 0 {
+		t.Fatalf("Next() = %s, %v, want above %s", b.ToHex(), err, a.ToHex())
+	}
+
+	c, err := second.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("second Acquire() error = %v", err)
+	}
+	if Compare(c, b) <= 0 {
+		t.Errorf("new leader's token %s is not above %s despite its slow clock", c.ToHex(), b.ToHex())
+	}
+	if _, err := first.Next(ctx); !errors.Is(err, ErrFenced) {
+		t.Errorf("deposed Next() error = %v, want ErrFenced", err)
+	}
+	if _, held := first.Token(); held {
+		t.Error("deposed Fencer still holds the term")
+	}
+	if stored, err := store.Load(ctx); err != nil || stored != c {
+		t.Errorf("Load() = %s, %v, want %s", stored.ToHex(), err, c.ToHex())
+	}
+
+	// Storage admits only the newest leader's writes.
+	var highest AtomicNano64
+	for _, token := range []Nano64{a, b, c, c} {
+		if err := AdmitFencingToken(&highest, token); err != nil {
+			t.Errorf("AdmitFencingToken(%s) error = %v", token.ToHex(), err)
+		}
+	}
+	if err := AdmitFencingToken(&highest, b); !errors.Is(err, ErrFenced) {
+		t.Errorf("AdmitFencingToken() of a stale token error = %v, want ErrFenced", err)
+	}
+}
+
+func TestNewSQLFencingStoreErrors(t *testing.T) {
+	if _, err := NewSQLFencingStore(&sql.DB{}, DialectPostgres, "", "orders"); err == nil {
+		t.Error("NewSQLFencingStore() accepted an empty table")
+	}
+}
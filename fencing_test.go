@@ -0,0 +1,9 @@
+package nano64
+
+import "testing"
+
+func TestNewFencerErrors(t *testing.T) {
+	if _, err := NewFencer(FencingOptions{}); err == nil {
+		t.Error("NewFencer() accepted a missing Store")
+	}
+}
@@ -0,0 +1,125 @@
+package nano64
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tagName is the struct tag key read by the reflection helpers.
+const tagName = "nano64"
+
+var (
+	nano64Type  = reflect.TypeOf(Nano64{})
+	nano128Type = reflect.TypeOf(Nano128{})
+)
+
+// hasTagOption reports whether the comma-separated nano64 struct tag contains option.
+func hasTagOption(tag reflect.StructTag, option string) bool {
+	for _, opt := range strings.Split(tag.Get(tagName), ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Fill walks v and populates every Nil Nano64 or Nano128 field tagged `nano64:"generate"`
+// with a freshly generated ID. Adding the `monotonic` option (`nano64:"generate,monotonic"`)
+// uses monotonic generation for that field. Fields that already hold an ID are left alone.
+//
+// v must be a non-nil pointer to a struct, or a slice of structs or struct pointers.
+// Nested structs, pointers, slices and arrays are visited recursively; maps and
+// unexported fields are skipped.
+func Fill(v any) error {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return fmt.Errorf("cannot fill nil %T", v)
+		}
+	case reflect.Slice:
+	default:
+		return fmt.Errorf("cannot fill %T: expected pointer or slice", v)
+	}
+
+	return fillValue(rv, make(map[uintptr]bool))
+}
+
+// fillValue recursively populates tagged fields reachable from rv.
+func fillValue(rv reflect.Value, visited map[uintptr]bool) error {
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() || visited[rv.Pointer()] {
+			return nil
+		}
+		visited[rv.Pointer()] = true
+		return fillValue(rv.Elem(), visited)
+	case reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return fillValue(rv.Elem(), visited)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := fillValue(rv.Index(i), visited); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		typ := rv.Type()
+		for i := 0; i < rv.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if err := fillField(rv.Field(i), field); err != nil {
+				return err
+			}
+			if field.Type != nano64Type && field.Type != nano128Type {
+				if err := fillValue(rv.Field(i), visited); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fillField generates an ID for a single tagged field if it is still Nil.
+func fillField(fv reflect.Value, field reflect.StructField) error {
+	if !fv.CanSet() || !hasTagOption(field.Tag, "generate") {
+		return nil
+	}
+	monotonic := hasTagOption(field.Tag, "monotonic")
+
+	switch field.Type {
+	case nano64Type:
+		if !fv.Interface().(Nano64).IsNil() {
+			return nil
+		}
+		generate := GenerateDefault
+		if monotonic {
+			generate = GenerateMonotonicDefault
+		}
+		id, err := generate()
+		if err != nil {
+			return fmt.Errorf("failed to generate ID for field %s: %w", field.Name, err)
+		}
+		fv.Set(reflect.ValueOf(id))
+	case nano128Type:
+		if !fv.Interface().(Nano128).IsNil() {
+			return nil
+		}
+		generate := GenerateDefault128
+		if monotonic {
+			generate = GenerateMonotonicDefault128
+		}
+		id, err := generate()
+		if err != nil {
+			return fmt.Errorf("failed to generate ID for field %s: %w", field.Name, err)
+		}
+		fv.Set(reflect.ValueOf(id))
+	}
+	return nil
+}
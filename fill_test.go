@@ -0,0 +1,63 @@
+package nano64
+
+import "testing"
+
+type fillOrder struct {
+	ID    Nano64 `nano64:"generate,monotonic"`
+	Ref   Nano64
+	Lines []fillLine
+}
+
+type fillLine struct {
+	ID       Nano64  `nano64:"generate"`
+	Tracking Nano128 `nano64:"generate"`
+	Parent   *fillOrder
+}
+
+func TestFill(t *testing.T) {
+	order := &fillOrder{Lines: make([]fillLine, 3)}
+	for i := range order.Lines {
+		order.Lines[i].Parent = order // cycles must not loop forever
+	}
+
+	if err := Fill(order); err != nil {
+		t.Fatalf("Fill() error = %v", err)
+	}
+
+	if order.ID.IsNil() {
+		t.Error("tagged field was not populated")
+	}
+	if !order.Ref.IsNil() {
+		t.Error("untagged field was populated")
+	}
+	for i, line := range order.Lines {
+		if line.ID.IsNil() || line.Tracking.IsNil() {
+			t.Errorf("line %d was not populated: %+v", i, line)
+		}
+	}
+}
+
+func TestFill_KeepsExistingAndSlices(t *testing.T) {
+	existing := New(42)
+	lines := []fillLine{{ID: existing}, {}}
+
+	if err := Fill(lines); err != nil {
+		t.Fatalf("Fill() error = %v", err)
+	}
+	if !lines[0].ID.Equals(existing) {
+		t.Errorf("existing ID overwritten: %s", lines[0].ID.ToHex())
+	}
+	if lines[1].ID.IsNil() {
+		t.Error("slice element was not populated")
+	}
+}
+
+func TestFill_Errors(t *testing.T) {
+	var nilOrder *fillOrder
+	if err := Fill(nilOrder); err == nil {
+		t.Error("Fill(nil pointer) expected error")
+	}
+	if err := Fill(fillOrder{}); err == nil {
+		t.Error("Fill(struct value) expected error")
+	}
+}
@@ -0,0 +1,53 @@
+package nano64
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrEntropyModule is returned by ModuleRNG when the entropy module cannot supply
+// approved randomness.
+var ErrEntropyModule = errors.New("entropy module unavailable")
+
+// EntropyModule is a caller-provided interface to a validated cryptographic module,
+// such as a FIPS 140-3 validated library or HSM.
+type EntropyModule interface {
+	// Fill fills p completely with output of the module's approved random bit
+	// generator, or returns an error.
+	Fill(p []byte) error
+	// Approved returns nil while the module operates in its approved mode and an
+	// error otherwise, for example after a failed self-test.
+	Approved() error
+}
+
+// ModuleRNG returns an RNG that draws every random bit from m and nothing else.
+// Unlike DefaultRNG it never falls back to crypto/rand, never retries and ignores
+// SetEntropySource and OnEntropyError: if m is not in its approved mode or fails
+// a read, generation fails with an error wrapping ErrEntropyModule.
+//
+// ModuleRNG checks m.Approved once up front, so a misconfigured deployment fails
+// at startup rather than on the first ID.
+func ModuleRNG(m EntropyModule) (RNG, error) {
+	if m == nil {
+		return nil, fmt.Errorf("%w: no module configured", ErrEntropyModule)
+	}
+	if err := m.Approved(); err != nil {
+		return nil, fmt.Errorf("%w: module not in approved mode: %w", ErrEntropyModule, err)
+	}
+
+	return func(bits int) (uint32, error) {
+		if bits <= 0 || bits > 32 {
+			return 0, fmt.Errorf("bits must be 1-32, got %d", bits)
+		}
+		if err := m.Approved(); err != nil {
+			return 0, fmt.Errorf("%w: module not in approved mode: %w", ErrEntropyModule, err)
+		}
+
+		var buf [4]byte
+		if err := m.Fill(buf[:]); err != nil {
+			return 0, fmt.Errorf("%w: read failed: %w", ErrEntropyModule, err)
+		}
+		return binary.BigEndian.Uint32(buf[:]) >> (32 - bits), nil
+	}, nil
+}
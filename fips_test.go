@@ -0,0 +1,64 @@
+package nano64
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeModule is an EntropyModule with switchable failures.
+type fakeModule struct {
+	fillErr, approvedErr error
+	reads                int
+}
+
+func (m *fakeModule) Fill(p []byte) error {
+	m.reads++
+	for i := range p {
+		p[i] = 0xA5
+	}
+	return m.fillErr
+}
+
+func (m *fakeModule) Approved() error { return m.approvedErr }
+
+func TestModuleRNG(t *testing.T) {
+	// The package source must never be consulted.
+	SetEntropySource(func([]byte) error {
+		t.Error("ModuleRNG read the package entropy source")
+		return nil
+	})
+	defer SetEntropySource(testEntropy)
+
+	module := &fakeModule{}
+	rng, err := ModuleRNG(module)
+	if err != nil {
+		t.Fatalf("ModuleRNG() error = %v", err)
+	}
+	id, err := Generate(1234567890123, rng)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := id.GetRandom(); got != 0xA5A5A {
+		t.Errorf("random field = %#x, want 0xa5a5a", got)
+	}
+
+	failure := errors.New("DRBG health test failed")
+	module.fillErr = failure
+	if _, err := rng(RandomBits); !errors.Is(err, ErrEntropyModule) || !errors.Is(err, failure) {
+		t.Errorf("rng() error = %v, want ErrEntropyModule wrapping the module error", err)
+	}
+	if module.reads != 2 {
+		t.Errorf("module read %d times, want 2 (no retries)", module.reads)
+	}
+
+	module.fillErr, module.approvedErr = nil, errors.New("self-test failed")
+	if _, err := rng(RandomBits); !errors.Is(err, ErrEntropyModule) {
+		t.Errorf("rng() error = %v, want ErrEntropyModule", err)
+	}
+	if _, err := ModuleRNG(module); !errors.Is(err, ErrEntropyModule) {
+		t.Errorf("ModuleRNG() error = %v, want ErrEntropyModule", err)
+	}
+	if _, err := ModuleRNG(nil); !errors.Is(err, ErrEntropyModule) {
+		t.Errorf("ModuleRNG(nil) error = %v, want ErrEntropyModule", err)
+	}
+}
@@ -0,0 +1,77 @@
+package fixture
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// script builds 10 users on day 1 with 5 orders each, an hour apart.
+func script(seed uint64) *Builder {
+	b := New(start, seed)
+	users := b.Day(1).Many("user", 10, time.Minute)
+	for i := range users {
+		b.At(time.Duration(i)*time.Minute).Wait(time.Hour).Many(fmt.Sprintf("user-%d/order", i+1), 5, time.Hour)
+	}
+	return b
+}
+
+func TestBuilder(t *testing.T) {
+	b := script(1)
+	handles := b.Handles()
+	if len(handles) != 60 {
+		t.Fatalf("Handles() has %d IDs, want 60", len(handles))
+	}
+
+	if got, want := b.ID("user-3").ToDate(), start.Add(2*time.Minute); !got.Equal(want) {
+		t.Errorf("user-3 created at %v, want %v", got, want)
+	}
+	for i := 1; i <= 5; i++ {
+		got := b.ID(fmt.Sprintf("user-3/order-%d", i)).ToDate()
+		if want := start.Add(2*time.Minute + time.Duration(i)*time.Hour); !got.Equal(want) {
+			t.Errorf("user-3/order-%d created at %v, want %v", i, got, want)
+		}
+	}
+
+	again := script(1).Handles()
+	if !slices.Equal(handles, again) {
+		t.Error("the same script and seed minted different IDs")
+	}
+	if other := script(2).Handles(); other[0].ID == handles[0].ID {
+		t.Error("a different seed minted the same IDs")
+	}
+
+	seen := make(map[nano64.Nano64]bool)
+	for _, h := range handles {
+		if seen[h.ID] {
+			t.Errorf("duplicate ID %s", h.ID.ToHex())
+		}
+		seen[h.ID] = true
+	}
+	if _, ok := b.Lookup("user-11"); ok {
+		t.Error("Lookup() found an unknown name")
+	}
+}
+
+func TestBuilderPanics(t *testing.T) {
+	tests := map[string]func(b *Builder){
+		"reused name":  func(b *Builder) { b.Add("a"); b.Add("a") },
+		"unknown name": func(b *Builder) { b.ID("missing") },
+		"day zero":     func(b *Builder) { b.Day(0) },
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("no panic")
+				}
+			}()
+			f(New(start, 1))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package nano64
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// The helpers below bind IDs from URL-encoded forms and query strings without relying
+// on encoding.TextUnmarshaler. Their signatures match the converter types of
+// gorilla/schema and go-playground/form, so they register directly:
+//
+//	dec := schema.NewDecoder()
+//	dec.RegisterConverter(nano64.Nano64{}, nano64.SchemaConverter)
+//	dec.RegisterConverter(nano64.NullNano64{}, nano64.SchemaNullConverter)
+//
+//	dec := form.NewDecoder()
+//	dec.RegisterCustomTypeFunc(nano64.FormDecoder, nano64.Nano64{})
+//	dec.RegisterCustomTypeFunc(nano64.FormNullDecoder, nano64.NullNano64{})
+//
+// Values are parsed with FromHex. An empty value decodes to Nil, or to an invalid
+// NullNano64. Absent fields are never passed to the helpers and keep their zero
+// value, so an absent NullNano64 stays invalid as well.
+
+// parseFormValue parses a single form value.
+func parseFormValue(value string) (NullNano64, error) {
+	if value == "" {
+		return NullNano64{}, nil
+	}
+	id, err := FromHex(value)
+	if err != nil {
+		return NullNano64{}, fmt.Errorf("invalid Nano64 form value %q: %w", value, err)
+	}
+	return NullNano64{ID: id, Valid: true}, nil
+}
+
+// SchemaConverter converts a gorilla/schema form value into a Nano64.
+// It returns the zero reflect.Value for malformed input, which gorilla/schema
+// reports as a conversion error.
+func SchemaConverter(value string) reflect.Value {
+	parsed, err := parseFormValue(value)
+	if err != nil {
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(parsed.ID)
+}
+
+// SchemaNullConverter converts a gorilla/schema form value into a NullNano64.
+func SchemaNullConverter(value string) reflect.Value {
+	parsed, err := parseFormValue(value)
+	if err != nil {
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(parsed)
+}
+
+// SchemaEncoder encodes a Nano64 or NullNano64 for gorilla/schema's Encoder.RegisterEncoder.
+// An invalid NullNano64 encodes as the empty string.
+func SchemaEncoder(v reflect.Value) string {
+	switch id := v.Interface().(type) {
+	case Nano64:
+		return id.ToHex()
+	case NullNano64:
+		if id.Valid {
+			return id.ID.ToHex()
+		}
+	}
+	return ""
+}
+
+// FormDecoder decodes go-playground/form values into a Nano64. Only the first value is used.
+func FormDecoder(values []string) (interface{}, error) {
+	if len(values) == 0 {
+		return Nil, nil
+	}
+	parsed, err := parseFormValue(values[0])
+	if err != nil {
+		return nil, err
+	}
+	return parsed.ID, nil
+}
+
+// FormNullDecoder decodes go-playground/form values into a NullNano64. Only the first value is used.
+func FormNullDecoder(values []string) (interface{}, error) {
+	if len(values) == 0 {
+		return NullNano64{}, nil
+	}
+	parsed, err := parseFormValue(values[0])
+	if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// FormEncoder encodes a Nano64 or NullNano64 for go-playground/form's
+// Encoder.RegisterCustomTypeFunc. An invalid NullNano64 encodes as no values.
+func FormEncoder(x interface{}) ([]string, error) {
+	switch id := x.(type) {
+	case Nano64:
+		return []string{id.ToHex()}, nil
+	case NullNano64:
+		if !id.Valid {
+			return nil, nil
+		}
+		return []string{id.ID.ToHex()}, nil
+	default:
+		return nil, fmt.Errorf("cannot encode %T as Nano64", x)
+	}
+}
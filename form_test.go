@@ -0,0 +1,76 @@
+package nano64
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaConverter(t *testing.T) {
+	id := New(0x0123456789ABCDEF)
+
+	tests := []struct {
+		name      string
+		convert   func(string) reflect.Value
+		input     string
+		want      any
+		wantValid bool
+	}{
+		{"id", SchemaConverter, id.ToHex(), id, true},
+		{"id empty", SchemaConverter, "", Nil, true},
+		{"id malformed", SchemaConverter, "xyz", nil, false},
+		{"null", SchemaNullConverter, id.ToHex(), NullNano64{ID: id, Valid: true}, true},
+		{"null empty", SchemaNullConverter, "", NullNano64{}, true},
+		{"null malformed", SchemaNullConverter, "xyz", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.convert(tt.input)
+			if got.IsValid() != tt.wantValid {
+				t.Fatalf("converter(%q) valid = %v, want %v", tt.input, got.IsValid(), tt.wantValid)
+			}
+			if tt.wantValid && got.Interface() != tt.want {
+				t.Errorf("converter(%q) = %v, want %v", tt.input, got.Interface(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFormDecoder(t *testing.T) {
+	id := New(0x0123456789ABCDEF)
+
+	got, err := FormDecoder([]string{id.ToHex(), "ignored"})
+	if err != nil || got != id {
+		t.Errorf("FormDecoder() = %v, %v; want %v", got, err, id)
+	}
+	if _, err := FormDecoder([]string{"xyz"}); err == nil {
+		t.Error("FormDecoder() expected error for malformed value")
+	}
+
+	got, err = FormNullDecoder([]string{""})
+	if err != nil || got != (NullNano64{}) {
+		t.Errorf("FormNullDecoder(empty) = %v, %v; want invalid NullNano64", got, err)
+	}
+}
+
+func TestFormEncoders(t *testing.T) {
+	id := New(0x0123456789ABCDEF)
+
+	values, err := FormEncoder(NullNano64{ID: id, Valid: true})
+	if err != nil || len(values) != 1 || values[0] != id.ToHex() {
+		t.Errorf("FormEncoder() = %v, %v", values, err)
+	}
+	if values, _ := FormEncoder(NullNano64{}); values != nil {
+		t.Errorf("FormEncoder(invalid) = %v, want no values", values)
+	}
+	if _, err := FormEncoder("nope"); err == nil {
+		t.Error("FormEncoder() expected error for foreign type")
+	}
+
+	if got := SchemaEncoder(reflect.ValueOf(id)); got != id.ToHex() {
+		t.Errorf("SchemaEncoder() = %q, want %q", got, id.ToHex())
+	}
+	if got := SchemaEncoder(reflect.ValueOf(NullNano64{})); got != "" {
+		t.Errorf("SchemaEncoder(invalid) = %q, want empty", got)
+	}
+}
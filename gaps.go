@@ -0,0 +1,100 @@
+package nano64
+
+import (
+	"sort"
+	"time"
+)
+
+// SequenceGap describes IDs missing between two consecutive IDs of a monotonic stream.
+type SequenceGap struct {
+	// Index is the position of After in the analysed slice.
+	Index int
+	// Before and After are the IDs surrounding the gap.
+	Before Nano64
+	After  Nano64
+	// Missing is the number of IDs that would have been issued in between.
+	Missing uint64
+}
+
+// SequenceRegression describes an ID that does not sort above its predecessor.
+type SequenceRegression struct {
+	// Index is the position of ID in the analysed slice.
+	Index    int
+	Previous Nano64
+	ID       Nano64
+}
+
+// GapBucket aggregates the gaps found within one hour.
+type GapBucket struct {
+	Start   time.Time
+	Gaps    int
+	Missing uint64
+}
+
+// SequenceReport summarizes GapReport.
+type SequenceReport struct {
+	// Count is the number of analysed IDs.
+	Count int
+
+	Gaps         []SequenceGap
+	TotalMissing uint64
+	// LargestGap is the gap with the most missing IDs; zero if there are no gaps.
+	LargestGap SequenceGap
+
+	Regressions []SequenceRegression
+
+	// Hourly distributes gaps over the hour in which they occurred, oldest first.
+	Hourly []GapBucket
+}
+
+// Complete reports whether the sequence has neither gaps nor regressions.
+func (r SequenceReport) Complete() bool {
+	return len(r.Gaps) == 0 && len(r.Regressions) == 0
+}
+
+// GapReport analyses IDs from a monotonic generator in issue order. Monotonic
+// generation increments the random field within a millisecond, so a jump of more
+// than one between consecutive IDs of the same millisecond means IDs were lost.
+// The first ID of a new millisecond starts from fresh randomness and is never
+// counted as a gap. IDs that do not sort above their predecessor are regressions.
+func GapReport(ids []Nano64) SequenceReport {
+	report := SequenceReport{Count: len(ids)}
+	hourly := make(map[int64]*GapBucket)
+
+	for i := 1; i < len(ids); i++ {
+		prev, cur := ids[i-1], ids[i]
+
+		if cur.value <= prev.value {
+			report.Regressions = append(report.Regressions, SequenceRegression{Index: i, Previous: prev, ID: cur})
+			continue
+		}
+		if cur.GetTimestamp() != prev.GetTimestamp() || cur.value-prev.value == 1 {
+			continue
+		}
+
+		gap := SequenceGap{Index: i, Before: prev, After: cur, Missing: cur.value - prev.value - 1}
+		report.Gaps = append(report.Gaps, gap)
+		report.TotalMissing += gap.Missing
+		if gap.Missing > report.LargestGap.Missing {
+			report.LargestGap = gap
+		}
+
+		hour := cur.ToDate().Truncate(time.Hour)
+		bucket, ok := hourly[hour.UnixMilli()]
+		if !ok {
+			bucket = &GapBucket{Start: hour}
+			hourly[hour.UnixMilli()] = bucket
+		}
+		bucket.Gaps++
+		bucket.Missing += gap.Missing
+	}
+
+	for _, bucket := range hourly {
+		report.Hourly = append(report.Hourly, *bucket)
+	}
+	sort.Slice(report.Hourly, func(i, j int) bool {
+		return report.Hourly[i].Start.Before(report.Hourly[j].Start)
+	})
+
+	return report
+}
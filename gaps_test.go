@@ -0,0 +1,55 @@
+package nano64
+
+import "testing"
+
+func TestGapReport(t *testing.T) {
+	ms := uint64(1_700_000_000_000)
+	id := func(ts, random uint64) Nano64 { return New(ts<<RandomBits | random) }
+
+	ids := []Nano64{
+		id(ms, 10),
+		id(ms, 11),
+		id(ms, 15), // 3 missing
+		id(ms+1, 7),
+		id(ms+1, 8),
+		id(ms+1, 8),  // regression (duplicate)
+		id(ms+1, 20), // 11 missing
+		id(ms+2, 0),
+	}
+
+	report := GapReport(ids)
+
+	if report.Count != len(ids) {
+		t.Errorf("Count = %d, want %d", report.Count, len(ids))
+	}
+	if len(report.Gaps) != 2 || report.TotalMissing != 14 {
+		t.Errorf("got %d gaps with %d missing, want 2 with 14", len(report.Gaps), report.TotalMissing)
+	}
+	if report.LargestGap.Missing != 11 || report.LargestGap.Index != 6 {
+		t.Errorf("LargestGap = %+v", report.LargestGap)
+	}
+	if len(report.Regressions) != 1 || report.Regressions[0].Index != 5 {
+		t.Errorf("Regressions = %+v", report.Regressions)
+	}
+	if len(report.Hourly) != 1 || report.Hourly[0].Gaps != 2 || report.Hourly[0].Missing != 14 {
+		t.Errorf("Hourly = %+v", report.Hourly)
+	}
+	if report.Complete() {
+		t.Error("Complete() = true, want false")
+	}
+}
+
+func TestGapReport_Complete(t *testing.T) {
+	var ids []Nano64
+	for i := 0; i < 100; i++ {
+		id, err := GenerateMonotonic(1_700_000_000_000, nil)
+		if err != nil {
+			t.Fatalf("GenerateMonotonic() error = %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if report := GapReport(ids); !report.Complete() {
+		t.Errorf("monotonic sequence reported incomplete: %+v", report)
+	}
+}
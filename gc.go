@@ -0,0 +1,118 @@
+package nano64
+
+import (
+	"fmt"
+	"time"
+)
+
+// GCPlan returns the exclusive upper bound for deleting rows older than
+// retention at now: every ID below it was created before now-retention, and
+// every ID at or above it was not. Pass it to GCDeleteSQL or GCDelete. A cutoff
+// before the epoch returns Nil, below which there is nothing.
+func GCPlan(retention time.Duration, now time.Time) (Nano64, error) {
+	if retention < 0 {
+		return Nil, fmt.Errorf("retention must not be negative, got %v", retention)
+	}
+	cutoff := now.Add(-retention).UnixMilli()
+	if cutoff <= 0 {
+		return Nil, nil
+	}
+	return Nano64{value: uint64(min(cutoff, maxTimestamp)) << timestampShift}, nil
+}
+
+// GCDeleteSQL returns a statement deleting at most chunk rows of table whose
+// column is below its single bind parameter, oldest first. Run it with the
+// bound from GCPlan until it affects fewer than chunk rows, as GCDelete does;
+// small chunks keep each transaction, and the locks and replication lag it
+// causes, short.
+//
+// MySQL deletes with ORDER BY and LIMIT directly; Postgres and SQLite, which do
+// not support that, delete the IDs selected by a limited subquery, so the column
+// must be unique.
+func (d Dialect) GCDeleteSQL(table, column string, repr Representation, chunk int) (string, error) {
+	if err := d.validate(); err != nil {
+		return "", err
+	}
+	if repr < RepresentationBytes || repr > RepresentationHex {
+		return "", fmt.Errorf("unsupported representation %v", repr)
+	}
+	if chunk < 1 {
+		return "", fmt.Errorf("chunk size must be positive, got %d", chunk)
+	}
+	tbl, col := d.QuoteIdent(table), d.QuoteIdent(column)
+	if d == DialectMySQL {
+		return fmt.Sprintf("DELETE FROM %s WHERE %s < %s ORDER BY %s LIMIT %d",
+			tbl, col, d.Placeholder(1), col, chunk), nil
+	}
+	return fmt.Sprintf("DELETE FROM %s WHERE %s IN (SELECT %s FROM %s WHERE %s < %s ORDER BY %s LIMIT %d)",
+		tbl, col, col, tbl, col, d.Placeholder(1), col, chunk), nil
+}
+
+// StorageTier is the storage class an ID-keyed object belongs in by age.
+type StorageTier int
+
+const (
+	// TierHot holds recent objects on the fastest storage.
+	TierHot StorageTier = iota
+	// TierWarm holds objects still read occasionally.
+	TierWarm
+	// TierCold holds objects kept for archival, on the cheapest storage.
+	TierCold
+	// TierExpired holds objects past retention, which GCPlan marks for deletion.
+	TierExpired
+)
+
+// String returns the name of the tier.
+func (t StorageTier) String() string {
+	switch t {
+	case TierHot:
+		return "hot"
+	case TierWarm:
+		return "warm"
+	case TierCold:
+		return "cold"
+	case TierExpired:
+		return "expired"
+	default:
+		return fmt.Sprintf("StorageTier(%d)", int(t))
+	}
+}
+
+// TierPolicy maps the age of IDs to storage tiers, for movers relocating
+// ID-keyed blobs between backends. An ID older than a threshold belongs in its
+// tier; an ID exactly that old, like one at the GCPlan bound, does not yet.
+// Zero thresholds disable their tier.
+type TierPolicy struct {
+	// Objects older than Warm belong in TierWarm.
+	Warm time.Duration
+
+	// Objects older than Cold belong in TierCold.
+	Cold time.Duration
+
+	// Objects older than Retention are TierExpired. Use the same retention
+	// for GCPlan so rows and blobs expire together.
+	Retention time.Duration
+
+	// Clock defaults to DefaultClock.
+	Clock Clock
+}
+
+// Tier returns the tier of id, the oldest one whose threshold its age exceeds.
+// IDs from the future are TierHot.
+func (p TierPolicy) Tier(id Nano64) StorageTier {
+	clock := p.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	age := time.Duration(clock()-id.GetTimestamp()) * time.Millisecond
+	switch {
+	case p.Retention > 0 && age > p.Retention:
+		return TierExpired
+	case p.Cold > 0 && age > p.Cold:
+		return TierCold
+	case p.Warm > 0 && age > p.Warm:
+		return TierWarm
+	default:
+		return TierHot
+	}
+}
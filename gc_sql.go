@@ -0,0 +1,36 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"fmt"
+)
+
+// GCDelete deletes the rows of table whose column is below bound in chunks of
+// chunk rows, as built by GCDeleteSQL, and returns the number deleted. It
+// checks ctx between chunks, so an interrupted run can simply be restarted.
+func GCDelete(ctx context.Context, db Execer, dialect Dialect, table, column string, repr Representation, bound Nano64, chunk int) (int64, error) {
+	query, err := dialect.GCDeleteSQL(table, column, repr, chunk)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		result, err := db.ExecContext(ctx, query, ColumnOf(&bound, repr))
+		if err != nil {
+			return total, fmt.Errorf("failed to delete rows below %s: %w", bound.ToHex(), err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return total, fmt.Errorf("failed to count deleted rows: %w", err)
+		}
+		total += n
+		if n < int64(chunk) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,47 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestGCDelete(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `CREATE TABLE events (id BIGINT PRIMARY KEY)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	const start = 1_700_000_000_000
+	for i := range 100 {
+		id := mustGenerate(t, start+int64(i)*60_000, uint32(i))
+		if _, err := db.ExecContext(ctx, `INSERT INTO events (id) VALUES (?)`, ColumnOf(&id, RepresentationSigned)); err != nil {
+			t.Fatalf("failed to insert: %v", err)
+		}
+	}
+
+	// Keep the last 30 minutes: rows 70 to 99.
+	bound, _ := GCPlan(30*time.Minute, time.UnixMilli(start+99*60_000+60_000))
+	deleted, err := GCDelete(ctx, db, DialectSQLite, "events", "id", RepresentationSigned, bound, 25)
+	if err != nil {
+		t.Fatalf("GCDelete() error = %v", err)
+	}
+	if deleted != 70 {
+		t.Errorf("GCDelete() = %d, want 70", deleted)
+	}
+	var left int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events`).Scan(&left); err != nil || left != 30 {
+		t.Errorf("%d rows left, %v, want 30", left, err)
+	}
+}
@@ -0,0 +1,83 @@
+package nano64
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGCPlan(t *testing.T) {
+	now := time.UnixMilli(1_700_000_000_000)
+	bound, err := GCPlan(time.Hour, now)
+	if err != nil {
+		t.Fatalf("GCPlan() error = %v", err)
+	}
+	cutoff := now.Add(-time.Hour).UnixMilli()
+	if before := mustGenerate(t, cutoff-1, randomMask); Compare(before, bound) >= 0 {
+		t.Errorf("ID created 1ms before the cutoff is not below %s", bound.ToHex())
+	}
+	if at := mustGenerate(t, cutoff, 0); Compare(at, bound) < 0 {
+		t.Errorf("ID created at the cutoff is below %s", bound.ToHex())
+	}
+
+	if bound, err := GCPlan(100*365*24*time.Hour, now); err != nil || bound != Nil {
+		t.Errorf("GCPlan() before the epoch = %s, %v, want Nil", bound.ToHex(), err)
+	}
+	if _, err := GCPlan(-time.Hour, now); err == nil {
+		t.Error("GCPlan() accepted a negative retention")
+	}
+}
+
+func TestGCDeleteSQL(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{DialectPostgres, `DELETE FROM "events" WHERE "id" IN (SELECT "id" FROM "events" WHERE "id" < $1 ORDER BY "id" LIMIT 500)`},
+		{DialectMySQL, "DELETE FROM `events` WHERE `id` < ? ORDER BY `id` LIMIT 500"},
+		{DialectSQLite, `DELETE FROM "events" WHERE "id" IN (SELECT "id" FROM "events" WHERE "id" < ? ORDER BY "id" LIMIT 500)`},
+	}
+	for _, tt := range tests {
+		got, err := tt.dialect.GCDeleteSQL("events", "id", RepresentationBytes, 500)
+		if err != nil || got != tt.want {
+			t.Errorf("%s GCDeleteSQL() = %q, %v, want %q", tt.dialect, got, err, tt.want)
+		}
+	}
+	if _, err := DialectPostgres.GCDeleteSQL("events", "id", RepresentationBytes, 0); err == nil {
+		t.Error("GCDeleteSQL() accepted a zero chunk")
+	}
+}
+
+func TestTierPolicy(t *testing.T) {
+	const now = 1_700_000_000_000
+	day := int64(24 * time.Hour / time.Millisecond)
+	clock := func() int64 { return now }
+	policy := TierPolicy{Warm: 7 * 24 * time.Hour, Cold: 30 * 24 * time.Hour, Retention: 365 * 24 * time.Hour, Clock: clock}
+
+	tests := []struct {
+		created int64
+		want    StorageTier
+	}{
+		{now + 1000, TierHot},
+		{now - day, TierHot},
+		{now - 7*day, TierHot},
+		{now - 7*day - 1, TierWarm},
+		{now - 31*day, TierCold},
+		{now - 366*day, TierExpired},
+	}
+	for _, tt := range tests {
+		if got := policy.Tier(mustGenerate(t, tt.created, 0)); got != tt.want {
+			t.Errorf("Tier(created %dms ago) = %v, want %v", now-tt.created, got, tt.want)
+		}
+	}
+
+	// The expired tier agrees with GCPlan.
+	bound, _ := GCPlan(policy.Retention, time.UnixMilli(now))
+	below := Nano64{value: bound.value - 1}
+	if policy.Tier(below) != TierExpired || policy.Tier(bound) == TierExpired {
+		t.Errorf("Tier() disagrees with GCPlan bound %s", bound.ToHex())
+	}
+
+	if got := (TierPolicy{Cold: time.Hour, Clock: clock}).Tier(mustGenerate(t, now-day, 0)); got != TierCold {
+		t.Errorf("Tier() without a warm tier = %v, want cold", got)
+	}
+}
@@ -0,0 +1,493 @@
+package nano64
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Bounds on Layout.TimestampBits. The random field must fit an RNG call (at most
+// 32 bits) and leave room for a useful number of IDs per millisecond.
+const (
+	minLayoutTimestampBits = 32
+	maxLayoutTimestampBits = 56
+)
+
+// DefaultLayout is the 44/20 split used by the package-level functions.
+var DefaultLayout = Layout{TimestampBits: TimestampBits}
+
+// Layout describes how a Generator splits the 64 bits between the millisecond
+// timestamp (high bits) and the random field (low bits). A 42/22 split trades
+// lifetime (about 139 years from the epoch) for four times the IDs per
+// millisecond; 46/18 does the opposite.
+//
+// IDs of different layouts are not comparable by timestamp; keep one layout per
+// keyspace and decode IDs with the Generator that created them.
+type Layout struct {
+	TimestampBits int
+}
+
+// validate checks the bit split.
+func (l Layout) validate() error {
+	if l.TimestampBits < minLayoutTimestampBits || l.TimestampBits > maxLayoutTimestampBits {
+		return fmt.Errorf("layout timestamp bits must be %d-%d, got %d", minLayoutTimestampBits, maxLayoutTimestampBits, l.TimestampBits)
+	}
+	return nil
+}
+
+// RandomBits returns the width of the random field.
+func (l Layout) RandomBits() int {
+	return 64 - l.TimestampBits
+}
+
+// maxTimestamp returns the largest value of the timestamp field.
+func (l Layout) maxTimestamp() int64 {
+	return 1<<l.TimestampBits - 1
+}
+
+// randomMask returns the mask of the random field.
+func (l Layout) randomMask() uint64 {
+	return 1<<l.RandomBits() - 1
+}
+
+// Option configures a Generator.
+type Option func(*Generator) error
+
+// WithClock sets the clock stamping generated IDs; the default is DefaultClock.
+func WithClock(clock Clock) Option {
+	return func(g *Generator) error {
+		if clock == nil {
+			return fmt.Errorf("clock must not be nil")
+		}
+		g.clock = clock
+		return nil
+	}
+}
+
+// WithRNG sets the source of the random field; the default is DefaultRNG. It
+// accepts RNGs such as TenantRNG and ModuleRNG.
+func WithRNG(rng RNG) Option {
+	return func(g *Generator) error {
+		if rng == nil {
+			return fmt.Errorf("RNG must not be nil")
+		}
+		g.rng = rng
+		return nil
+	}
+}
+
+// WithEntropy reads the random field from r instead of DefaultRNG. Reads are
+// serialized, so r need not be safe for concurrent use. A deterministic reader
+// makes the generated IDs reproducible in tests.
+func WithEntropy(r io.Reader) Option {
+	return func(g *Generator) error {
+		if r == nil {
+			return fmt.Errorf("entropy reader must not be nil")
+		}
+		var mu sync.Mutex
+		g.rng = func(bits int) (uint32, error) {
+			if bits <= 0 || bits > 32 {
+				return 0, fmt.Errorf("bits must be 1-32, got %d", bits)
+			}
+			var buf [4]byte
+			mu.Lock()
+			_, err := io.ReadFull(r, buf[:])
+			mu.Unlock()
+			if err != nil {
+				return 0, fmt.Errorf("failed to read entropy: %w", err)
+			}
+			return binary.BigEndian.Uint32(buf[:]) >> (32 - bits), nil
+		}
+		return nil
+	}
+}
+
+// WithEpoch stores timestamps as milliseconds since epoch instead of since the
+// Unix epoch, moving the layout's lifetime window. It must not be set after the
+// current time.
+func WithEpoch(epoch time.Time) Option {
+	return func(g *Generator) error {
+		g.epoch = epoch.UnixMilli()
+		return nil
+	}
+}
+
+// WithLayout sets the timestamp/random split; the default is DefaultLayout.
+func WithLayout(layout Layout) Option {
+	return func(g *Generator) error {
+		if err := layout.validate(); err != nil {
+			return err
+		}
+		g.layout = layout
+		return nil
+	}
+}
+
+// WithOverflowMargin makes generation fail with ErrNearOverflow once the clock
+// is within margin of the last timestamp the layout can represent. It sets
+// GeneratorSettings.OverflowMargin, which Reload can change later.
+func WithOverflowMargin(margin time.Duration) Option {
+	return func(g *Generator) error {
+		g.modifySettings(func(s *GeneratorSettings) { s.OverflowMargin = margin })
+		return nil
+	}
+}
+
+// Generator creates IDs from its own clock, entropy source, epoch and bit layout,
+// and keeps its own monotonic sequence. Services sharing a process can each use
+// their own Generator, and tests can make one fully deterministic.
+// It is safe for concurrent use.
+type Generator struct {
+	clock  Clock
+	rng    RNG
+	epoch  int64
+	layout Layout
+
+	// settings holds the parts of the configuration Reload may swap.
+	settings atomic.Pointer[GeneratorSettings]
+
+	mu            sync.Mutex
+	lastTimestamp int64
+	lastRandom    uint64
+	rollovers     uint64
+
+	// lastGood and lastGoodAt are the last clock reading (timestamp field) that
+	// passed the GeneratorSettings.MaxClockJump check and when it was taken,
+	// with the process's monotonic clock; degraded reports whether the last
+	// monotonic ID came from the logical counter. Guarded by mu.
+	lastGood   int64
+	lastGoodAt time.Time
+	degraded   bool
+
+	// suspect and suspectAt are the latest untrusted reading and when it was
+	// taken, and suspectSince when the run of consecutive untrusted readings
+	// consistent with each other began; reanchorAfter is how long such a run
+	// must last, clockReanchorAfter outside tests. See trustClock. Guarded by mu.
+	suspect       int64
+	suspectAt     time.Time
+	suspectSince  time.Time
+	reanchorAfter time.Duration
+
+	// rateTimestamp and rateCount count the IDs issued in the current
+	// millisecond for GeneratorSettings.MaxPerMillisecond; guarded by mu.
+	rateTimestamp int64
+	rateCount     int
+
+	// last128, last128Hi and last128Lo are the timestamp field and random field
+	// of the last ID from GenerateMonotonic128; guarded by mu.
+	last128   int64
+	last128Hi uint64
+	last128Lo uint64
+
+	// debugClock is the latest clock reading of GenerateMonotonic, kept in
+	// nano64debug builds only; guarded by mu.
+	debugClock int64
+}
+
+// newGenerator returns a Generator with the default configuration.
+func newGenerator() *Generator {
+	g := &Generator{clock: DefaultClock, rng: DefaultRNG, layout: DefaultLayout, lastTimestamp: -1, last128: -1, reanchorAfter: clockReanchorAfter}
+	g.settings.Store(&GeneratorSettings{})
+	return g
+}
+
+// defaultGenerator holds the monotonic state behind GenerateMonotonic.
+var defaultGenerator = newGenerator()
+
+// NewGenerator creates a Generator. Without options it behaves like the
+// package-level functions, except that its monotonic sequence is separate.
+func NewGenerator(opts ...Option) (*Generator, error) {
+	g := newGenerator()
+	for _, opt := range opts {
+		if err := opt(g); err != nil {
+			return nil, err
+		}
+	}
+	if err := g.settings.Load().validate(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Layout returns the generator's bit layout.
+func (g *Generator) Layout() Layout {
+	return g.layout
+}
+
+// field converts Unix milliseconds into the timestamp field value.
+func (g *Generator) field(timestamp int64) (int64, error) {
+	t := timestamp - g.epoch
+	if t < 0 {
+		if g.epoch == 0 {
+			return 0, fmt.Errorf("timestamp cannot be negative: %d", timestamp)
+		}
+		return 0, fmt.Errorf("timestamp %d is before the generator epoch %d", timestamp, g.epoch)
+	}
+	if t > g.layout.maxTimestamp() {
+		return 0, fmt.Errorf("timestamp exceeds %d-bit range: %d > %d", g.layout.TimestampBits, t, g.layout.maxTimestamp())
+	}
+	return t, nil
+}
+
+// stamp is field plus the overflow margin check applied when generating.
+func (g *Generator) stamp(timestamp int64, s *GeneratorSettings) (int64, error) {
+	t, err := g.field(timestamp)
+	if err != nil {
+		return 0, err
+	}
+	if remaining := g.layout.maxTimestamp() - t; s.OverflowMargin > 0 && remaining < s.OverflowMargin.Milliseconds() {
+		return 0, fmt.Errorf("%w: %v left in the %d-bit timestamp field", ErrNearOverflow,
+			time.Duration(remaining)*time.Millisecond, g.layout.TimestampBits)
+	}
+	return t, nil
+}
+
+// admit counts an ID against MaxPerMillisecond for field value t; g.mu must be held.
+func (g *Generator) admit(t int64, s *GeneratorSettings) error {
+	if s.MaxPerMillisecond <= 0 {
+		return nil
+	}
+	if t != g.rateTimestamp {
+		g.rateTimestamp, g.rateCount = t, 0
+	}
+	if g.rateCount >= s.MaxPerMillisecond {
+		return fmt.Errorf("%w: %d IDs in millisecond %d", ErrRateLimited, g.rateCount, t+g.epoch)
+	}
+	g.rateCount++
+	return nil
+}
+
+// compose builds an ID from a timestamp field and a random field.
+func (g *Generator) compose(t int64, random uint64) Nano64 {
+	if debugEnabled {
+		debugCheckFields(t, random, g.layout)
+	}
+	return Nano64{value: uint64(t)<<g.layout.RandomBits() | random&g.layout.randomMask()}
+}
+
+// Generate creates an ID with the generator's clock and entropy.
+func (g *Generator) Generate() (Nano64, error) {
+	s := g.settings.Load()
+	now := g.clock()
+	if debugEnabled && s.MaxClockJump == 0 {
+		debugCheckAhead(now)
+	}
+	id, err := g.generate(now, g.rng, s)
+	s.emit(GeneratorEvent{Kind: EventGenerated, ID: id, Err: err})
+	return id, err
+}
+
+// generate creates an ID at timestamp (Unix ms) with rng.
+func (g *Generator) generate(timestamp int64, rng RNG, s *GeneratorSettings) (Nano64, error) {
+	t, err := g.stamp(timestamp, s)
+	if err != nil {
+		return Nano64{}, err
+	}
+	if s.MaxPerMillisecond > 0 {
+		g.mu.Lock()
+		err := g.admit(t, s)
+		g.mu.Unlock()
+		if err != nil {
+			return Nano64{}, err
+		}
+	}
+	randVal, err := rng(g.layout.RandomBits())
+	if err != nil {
+		return Nano64{}, fmt.Errorf("failed to generate random value: %w", err)
+	}
+	if debugEnabled {
+		debugCheckRandom(randVal, g.layout.RandomBits())
+	}
+	return g.compose(t, uint64(randVal)), nil
+}
+
+// GenerateMonotonic creates an ID greater than every ID this generator returned
+// from GenerateMonotonic before. If the per-ms sequence wraps, the timestamp is
+// bumped by 1 ms and the random field resets to 0.
+func (g *Generator) GenerateMonotonic() (Nano64, error) {
+	s := g.settings.Load()
+	now := g.clock()
+	if debugEnabled && s.MaxClockJump == 0 {
+		debugCheckAhead(now)
+		g.debugCheckClock(now)
+	}
+	id, rolledOver, degraded, err := g.generateMonotonic(now, g.rng, s)
+	if rolledOver {
+		s.emit(GeneratorEvent{Kind: EventRollover, ID: id})
+	}
+	if degraded {
+		s.emit(GeneratorEvent{Kind: EventDegraded, ID: id, Monotonic: true})
+	}
+	s.emit(GeneratorEvent{Kind: EventGenerated, ID: id, Err: err, Monotonic: true})
+	return id, err
+}
+
+// generateMonotonic creates a monotonic ID at timestamp (Unix ms) with rng and
+// reports whether it had to move into the next millisecond and whether it came
+// from the logical counter of degraded mode.
+func (g *Generator) generateMonotonic(timestamp int64, rng RNG, s *GeneratorSettings) (id Nano64, rolledOver, degraded bool, err error) {
+	t, err := g.stamp(timestamp, s)
+	if err != nil {
+		return Nano64{}, false, false, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch {
+	case s.MaxClockJump == 0 || g.trustClock(t, s.MaxClockJump):
+		g.degraded = false
+	case s.Degrade:
+		// Clock untrusted → continue the logical counter from the last issued ID
+		g.degraded = true
+		t = g.lastTimestamp
+	default:
+		return Nano64{}, false, false, fmt.Errorf("%w: clock reads %d, expected about %d", ErrClockAnomaly,
+			t+g.epoch, g.lastGood+time.Since(g.lastGoodAt).Milliseconds()+g.epoch)
+	}
+
+	// Enforce nondecreasing time
+	if t < g.lastTimestamp {
+		t = g.lastTimestamp
+	}
+
+	var random uint64
+	if t == g.lastTimestamp {
+		// Same ms → increment
+		random = (g.lastRandom + 1) & g.layout.randomMask()
+		if random == 0 {
+			// Per-ms space exhausted → move to next ms and start at 0
+			rolledOver = true
+			t++
+			if t > g.layout.maxTimestamp() {
+				return Nano64{}, false, false, fmt.Errorf("timestamp overflow after incrementing for monotonic generation")
+			}
+		}
+	}
+	if err := g.admit(t, s); err != nil {
+		return Nano64{}, false, false, err
+	}
+	if t != g.lastTimestamp && !rolledOver {
+		// First ID in this newer ms
+		randVal, err := rng(g.layout.RandomBits())
+		if err != nil {
+			return Nano64{}, false, false, fmt.Errorf("failed to generate random value: %w", err)
+		}
+		if debugEnabled {
+			debugCheckRandom(randVal, g.layout.RandomBits())
+		}
+		random = uint64(randVal) & g.layout.randomMask()
+	}
+
+	if debugEnabled && g.lastTimestamp >= 0 && (t < g.lastTimestamp || t == g.lastTimestamp && random <= g.lastRandom) {
+		debugFail("monotonic ID %d/%d is not above the previous %d/%d", t, random, g.lastTimestamp, g.lastRandom)
+	}
+	if rolledOver {
+		g.rollovers++
+	}
+	g.lastTimestamp = t
+	g.lastRandom = random
+	return g.compose(t, random), rolledOver, g.degraded, nil
+}
+
+// debugCheckClock reports a clock reading more than debugMaxClockStep behind
+// the latest one seen by GenerateMonotonic.
+func (g *Generator) debugCheckClock(now int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.debugClock-now > debugMaxClockStep {
+		debugFail("clock stepped back %dms between monotonic IDs", g.debugClock-now)
+	}
+	g.debugClock = max(g.debugClock, now)
+}
+
+// clockReanchorAfter is how long untrusted readings must keep agreeing with each
+// other before they are accepted as the clock's new position, e.g. after an NTP
+// step, so a permanent step does not leave the generator untrusting the clock
+// forever. It is measured on the process's monotonic clock rather than counted
+// in readings, which a busy generator takes within microseconds.
+const clockReanchorAfter = 10 * time.Second
+
+// trustClock reports whether the timestamp field t is within maxJump of the
+// last trusted reading advanced by the monotonic time elapsed since, and if so
+// records it as the new trusted reading; g.mu must be held. Untrusted readings
+// that keep agreeing with each other for g.reanchorAfter re-anchor the trusted
+// reading.
+func (g *Generator) trustClock(t int64, maxJump time.Duration) bool {
+	now := time.Now()
+	if !g.lastGoodAt.IsZero() && !clockAgrees(t, g.lastGood, g.lastGoodAt, now, maxJump) {
+		if g.suspectSince.IsZero() || !clockAgrees(t, g.suspect, g.suspectAt, now, maxJump) {
+			g.suspectSince = now
+		}
+		g.suspect, g.suspectAt = t, now
+		if now.Sub(g.suspectSince) < g.reanchorAfter {
+			return false
+		}
+	}
+	g.lastGood, g.lastGoodAt = t, now
+	g.suspectSince = time.Time{}
+	return true
+}
+
+// clockAgrees reports whether the reading t taken at now is within maxJump of
+// the reading ref taken at refAt, advanced by the monotonic time elapsed since.
+func clockAgrees(t, ref int64, refAt, now time.Time, maxJump time.Duration) bool {
+	diff := t - (ref + now.Sub(refAt).Milliseconds())
+	return diff <= maxJump.Milliseconds() && -diff <= maxJump.Milliseconds()
+}
+
+// Degraded reports whether the last ID from GenerateMonotonic was issued from
+// the logical counter because the clock was untrusted. See GeneratorSettings.Degrade.
+func (g *Generator) Degraded() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.degraded
+}
+
+// MonotonicState returns the timestamp (Unix ms) and random field of the last ID
+// issued by GenerateMonotonic (-1 and 0 before the first), and how often a
+// millisecond's random space was exhausted. See the package-level MonotonicState.
+func (g *Generator) MonotonicState() (lastTs int64, lastRand uint32, rolloversTotal uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	lastTs = g.lastTimestamp
+	if lastTs >= 0 {
+		lastTs += g.epoch
+	}
+	return lastTs, uint32(g.lastRandom), g.rollovers
+}
+
+// Timestamp returns the Unix milliseconds embedded in an ID of this generator.
+func (g *Generator) Timestamp(id Nano64) int64 {
+	return int64(id.value>>g.layout.RandomBits()) + g.epoch
+}
+
+// Time returns the time embedded in an ID of this generator.
+func (g *Generator) Time(id Nano64) time.Time {
+	return time.UnixMilli(g.Timestamp(id))
+}
+
+// Random returns the random field of an ID of this generator.
+func (g *Generator) Random(id Nano64) uint32 {
+	return uint32(id.value & g.layout.randomMask())
+}
+
+// TimeRange returns the inclusive range of IDs this generator can produce between
+// timestampStart and timestampEnd (Unix ms), for range queries.
+func (g *Generator) TimeRange(timestampStart, timestampEnd int64) (IDRange, error) {
+	if timestampStart > timestampEnd {
+		return IDRange{}, fmt.Errorf("timestampStart must be <= timestampEnd")
+	}
+	start, err := g.field(timestampStart)
+	if err != nil {
+		return IDRange{}, err
+	}
+	end, err := g.field(timestampEnd)
+	if err != nil {
+		return IDRange{}, err
+	}
+	return IDRange{First: g.compose(start, 0), Last: g.compose(end, g.layout.randomMask())}, nil
+}
@@ -0,0 +1,129 @@
+package nano64
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerator(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch.UnixMilli() + 5_000
+	entropy := bytes.Repeat([]byte{0xFF, 0xFF, 0xF8, 0x00}, 3)
+
+	g, err := NewGenerator(
+		WithClock(func() int64 { return now }),
+		WithEntropy(bytes.NewReader(entropy)),
+		WithEpoch(epoch),
+		WithLayout(Layout{TimestampBits: 42}),
+	)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	if g.Layout().RandomBits() != 22 {
+		t.Errorf("RandomBits() = %d, want 22", g.Layout().RandomBits())
+	}
+
+	id, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if want := uint64(5_000)<<22 | 0x3FFFFE; id.Uint64Value() != want {
+		t.Errorf("Generate() = %#x, want %#x", id.Uint64Value(), want)
+	}
+	if g.Timestamp(id) != now || !g.Time(id).Equal(time.UnixMilli(now)) || g.Random(id) != 0x3FFFFE {
+		t.Errorf("Timestamp/Random = %d/%#x, want %d/0x3ffffe", g.Timestamp(id), g.Random(id), now)
+	}
+
+	// Monotonic state is per instance: the first ID starts near the top of the
+	// random space, so the third one rolls over into the next millisecond.
+	var ids []Nano64
+	for range 3 {
+		id, err := g.GenerateMonotonic()
+		if err != nil {
+			t.Fatalf("GenerateMonotonic() error = %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if g.Random(ids[1]) != 0x3FFFFF || g.Timestamp(ids[2]) != now+1 || g.Random(ids[2]) != 0 {
+		t.Errorf("monotonic IDs = %v, want a rollover on the third", ids)
+	}
+	if ts, random, rollovers := g.MonotonicState(); ts != now+1 || random != 0 || rollovers != 1 {
+		t.Errorf("MonotonicState() = %d, %d, %d; want %d, 0, 1", ts, random, rollovers, now+1)
+	}
+	if ts, _, _ := MonotonicState(); ts == now+1 {
+		t.Error("generator shares the package-level monotonic state")
+	}
+
+	r, err := g.TimeRange(now, now+1)
+	if err != nil {
+		t.Fatalf("TimeRange() error = %v", err)
+	}
+	for _, id := range ids {
+		if !r.Contains(id) {
+			t.Errorf("TimeRange() = %v does not contain %v", r, id)
+		}
+	}
+	if _, err := g.TimeRange(epoch.UnixMilli()-1, now); err == nil {
+		t.Error("TimeRange() accepted a timestamp before the epoch")
+	}
+
+	// The entropy reader is exhausted after three reads.
+	if _, err := g.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := g.Generate(); err == nil {
+		t.Error("Generate() succeeded with exhausted entropy")
+	}
+}
+
+func TestGeneratorDefaults(t *testing.T) {
+	g, err := NewGenerator()
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	id, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if g.Timestamp(id) != id.GetTimestamp() || g.Random(id) != id.GetRandom() {
+		t.Errorf("default generator decodes %s differently from Nano64", id.ToHex())
+	}
+}
+
+func TestGeneratorOptions(t *testing.T) {
+	invalid := []Option{
+		WithClock(nil),
+		WithRNG(nil),
+		WithEntropy(nil),
+		WithLayout(Layout{TimestampBits: 31}),
+		WithLayout(Layout{TimestampBits: 57}),
+		WithOverflowMargin(-time.Second),
+	}
+	for i, opt := range invalid {
+		if _, err := NewGenerator(opt); err == nil {
+			t.Errorf("NewGenerator(invalid option %d) succeeded", i)
+		}
+	}
+
+	// A 32-bit timestamp field from 2020 overflows after about 50 days.
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := epoch.Add(49 * 24 * time.Hour).UnixMilli()
+	g, err := NewGenerator(
+		WithEpoch(epoch),
+		WithLayout(Layout{TimestampBits: 32}),
+		WithClock(func() int64 { return late }),
+		WithOverflowMargin(7*24*time.Hour),
+		WithRNG(func(bits int) (uint32, error) { return 1, nil }),
+	)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	if _, err := g.Generate(); !errors.Is(err, ErrNearOverflow) {
+		t.Errorf("Generate() error = %v, want ErrNearOverflow", err)
+	}
+	if _, err := g.TimeRange(late, late); err != nil {
+		t.Errorf("TimeRange() error = %v; the margin only applies to generation", err)
+	}
+}
@@ -0,0 +1,15 @@
+package nano64
+
+// hashSeedIncrement is the SplitMix64 golden-ratio increment, used to spread seeds.
+const hashSeedIncrement = 0x9E3779B97F4A7C15
+
+// Hash64 returns a well-mixed 64-bit hash of the ID for cache sharding and hash
+// tables (groupcache, ristretto, consistent hashing). The raw value clusters by
+// timestamp, so IDs created together would land on the same shard; every bit of
+// Hash64 depends on every bit of the ID.
+//
+// For a given seed the hash is a bijection, so distinct IDs never collide.
+// Different seeds give independent hash families.
+func (n Nano64) Hash64(seed uint64) uint64 {
+	return mix64(n.value ^ mix64(seed+hashSeedIncrement))
+}
@@ -0,0 +1,46 @@
+package nano64
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestHash64Deterministic(t *testing.T) {
+	id := New(0x0123456789ABCDEF)
+	if id.Hash64(7) != id.Hash64(7) {
+		t.Error("Hash64() is not deterministic")
+	}
+	if id.Hash64(7) == id.Hash64(8) {
+		t.Error("Hash64() ignores the seed")
+	}
+}
+
+func TestHash64SpreadsShards(t *testing.T) {
+	// IDs generated within one millisecond share their top 44 bits; their hashes
+	// must still spread evenly over shards.
+	const shards = 16
+	const n = 1 << 14
+	counts := make([]int, shards)
+	for i := uint64(0); i < n; i++ {
+		id := New(1_700_000_000_000<<RandomBits | i)
+		counts[id.Hash64(0)%shards]++
+	}
+
+	for shard, c := range counts {
+		if c < n/shards*3/4 || c > n/shards*5/4 {
+			t.Errorf("shard %d got %d of %d IDs, want about %d", shard, c, n, n/shards)
+		}
+	}
+}
+
+func TestHash64Avalanche(t *testing.T) {
+	id := New(0x0123456789ABCDEF)
+	base := id.Hash64(0)
+
+	for bit := 0; bit < 64; bit++ {
+		flipped := New(id.value ^ 1<<bit).Hash64(0)
+		if d := bits.OnesCount64(base ^ flipped); d < 16 || d > 48 {
+			t.Errorf("flipping bit %d changed %d output bits", bit, d)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package nano64
 import (
 	"encoding/hex"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -43,3 +44,131 @@ func (hexHelpers) ToBytes(hexStr string) ([]byte, error) {
 
 	return bytes, nil
 }
+
+// upperHexDigits is the alphabet used by ToHex.
+const upperHexDigits = "0123456789ABCDEF"
+
+// hexLength is the length of the dashed hex form produced by ToHex.
+const hexLength = 17
+
+// appendHex appends the dashed hex form of id (as ToHex) to dst.
+func appendHex(dst []byte, id Nano64) []byte {
+	var buf [hexLength]byte
+	v := id.value
+	for i := hexLength - 1; i >= 0; i-- {
+		if i == 11 {
+			buf[i] = '-'
+			continue
+		}
+		buf[i] = upperHexDigits[v&0xF]
+		v >>= 4
+	}
+	return append(dst, buf[:]...)
+}
+
+// hexValues maps ASCII hex digits to their value and every other byte to 0xFF.
+var hexValues = func() (table [256]byte) {
+	for i := range table {
+		table[i] = 0xFF
+	}
+	for i, c := range "0123456789abcdef" {
+		table[c] = byte(i)
+	}
+	for i, c := range "ABCDEF" {
+		table[c] = byte(10 + i)
+	}
+	return table
+}()
+
+// decodeHexDigits decodes a run of at most 16 hex digits. Invalid digits are
+// collected in a single flag checked at the end, so the loop has no
+// data-dependent branches.
+func decodeHexDigits[T string | []byte](s T) (uint64, bool) {
+	var value uint64
+	var invalid byte
+	for i := 0; i < len(s); i++ {
+		d := hexValues[s[i]]
+		invalid |= d
+		value = value<<4 | uint64(d&0x0F)
+	}
+	return value, invalid&0xF0 == 0
+}
+
+// decodeCanonicalHex decodes the 17-char dashed form produced by ToHex or plain
+// 16-char hex.
+func decodeCanonicalHex[T string | []byte](s T) (uint64, bool) {
+	switch len(s) {
+	case 16:
+		return decodeHexDigits(s)
+	case 17:
+		hi, hiOK := decodeHexDigits(s[:11])
+		lo, loOK := decodeHexDigits(s[12:])
+		return hi<<RandomBits | lo, hiOK && loOK && s[11] == '-'
+	default:
+		return 0, false
+	}
+}
+
+// DecodeHexBatch decodes fixed-width records from data and appends the IDs to dst.
+// data holds consecutive records of stride bytes, each starting with an ID in
+// dashed (17-char) or plain (16-char) hex; the remaining bytes of a record, such as
+// a newline or padding, are ignored. The final record may omit them. For example,
+// a file of dashed IDs one per line has stride 18.
+//
+// It is meant for bulk ingest, where it avoids the per-call overhead of FromHex.
+func DecodeHexBatch(dst []Nano64, data []byte, stride int) ([]Nano64, error) {
+	if stride < 16 {
+		return dst, fmt.Errorf("stride must be at least 16, got %d", stride)
+	}
+
+	dst = slices.Grow(dst, (len(data)+stride-1)/stride)
+	for i, off := 0, 0; off < len(data); i, off = i+1, off+stride {
+		record := data[off:min(off+stride, len(data))]
+
+		width := 16
+		if len(record) >= 17 && record[11] == '-' {
+			width = 17
+		}
+		if len(record) < width {
+			return dst, fmt.Errorf("record %d is truncated: %d bytes", i, len(record))
+		}
+
+		value, ok := decodeCanonicalHex(record[:width])
+		if !ok {
+			return dst, fmt.Errorf("record %d is not valid hex: %q", i, record[:width])
+		}
+		dst = append(dst, Nano64{value: value})
+	}
+	return dst, nil
+}
+
+// DecodeHexInto decodes each element of src into the corresponding element of dst
+// and returns the number of IDs decoded. It accepts the forms FromHex does and
+// never allocates for the dashed and plain forms, with or without a 0x prefix,
+// so callers can reuse dst across batches.
+//
+// On failure it returns the index of the offending element, dst holding the IDs
+// decoded before it. dst must be at least as long as src.
+func DecodeHexInto(dst []Nano64, src [][]byte) (int, error) {
+	if len(dst) < len(src) {
+		return 0, fmt.Errorf("destination holds %d IDs, need %d", len(dst), len(src))
+	}
+	for i, s := range src {
+		digits := s
+		if len(digits) > 2 && digits[0] == '0' && (digits[1] == 'x' || digits[1] == 'X') {
+			digits = digits[2:]
+		}
+		if value, ok := decodeCanonicalHex(digits); ok {
+			dst[i] = Nano64{value: value}
+			continue
+		}
+
+		// Rare forms and invalid input take the general parser.
+		id, err := FromHex(string(s))
+		if err != nil {
+			return i, fmt.Errorf("element %d: %w", i, err)
+		}
+		dst[i] = id
+	}
+	return len(src), nil
+}
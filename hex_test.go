@@ -0,0 +1,147 @@
+package nano64
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestFromHexFastPathMatchesGeneralParser(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 1000; i++ {
+		id := New(rnd.Uint64())
+		dashed := id.ToHex()
+
+		for _, s := range []string{dashed, strings.ToLower(dashed), strings.Replace(dashed, "-", "", 1)} {
+			got, err := FromHex(s)
+			if err != nil || got != id {
+				t.Fatalf("FromHex(%q) = %v, %v; want %v", s, got, err, id)
+			}
+		}
+	}
+
+	// Inputs the fast path rejects must still get the general parser's verdict.
+	for _, s := range []string{"0123456789A-BCDEG", "0123456789AB-CDEF", "0123-456789A-BCDEF", "0123456789A_BCDEF"} {
+		_, fastOK := decodeCanonicalHex(s)
+		if fastOK {
+			t.Errorf("decodeCanonicalHex(%q) accepted non-canonical input", s)
+		}
+	}
+	if got, err := FromHex("0123-456789A-BCDEF"); err != nil || got != New(0x0123456789ABCDEF) {
+		t.Errorf("FromHex() with extra dash = %v, %v", got, err)
+	}
+}
+
+func TestDecodeHexBatch(t *testing.T) {
+	ids := []Nano64{New(0), New(0x0123456789ABCDEF), New(^uint64(0))}
+
+	var dashed, plain bytes.Buffer
+	for i, id := range ids {
+		if i > 0 {
+			dashed.WriteByte('\n')
+		}
+		dashed.WriteString(id.ToHex())
+		plain.WriteString(strings.Replace(id.ToHex(), "-", "", 1))
+		plain.WriteString("\r\n")
+	}
+
+	tests := []struct {
+		name   string
+		data   []byte
+		stride int
+	}{
+		{"dashed lines without final newline", dashed.Bytes(), 18},
+		{"plain CRLF lines", plain.Bytes(), 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeHexBatch(nil, tt.data, tt.stride)
+			if err != nil {
+				t.Fatalf("DecodeHexBatch() error = %v", err)
+			}
+			if len(got) != len(ids) {
+				t.Fatalf("DecodeHexBatch() decoded %d IDs, want %d", len(got), len(ids))
+			}
+			for i := range ids {
+				if got[i] != ids[i] {
+					t.Errorf("record %d = %v, want %v", i, got[i], ids[i])
+				}
+			}
+		})
+	}
+
+	if _, err := DecodeHexBatch(nil, []byte("0123456789A-BCDEZ\n"), 18); err == nil {
+		t.Error("DecodeHexBatch() expected error for invalid digit")
+	}
+	if _, err := DecodeHexBatch(nil, []byte("0123456789A-BCDEF\n0123"), 18); err == nil {
+		t.Error("DecodeHexBatch() expected error for truncated record")
+	}
+	if _, err := DecodeHexBatch(nil, nil, 8); err == nil {
+		t.Error("DecodeHexBatch() expected error for short stride")
+	}
+}
+
+func TestDecodeHexInto(t *testing.T) {
+	want := []Nano64{New(0x0123456789ABCDEF), New(0x0123456789ABCDEF), New(0x0123456789ABCDEF), New(0x0123456789ABCDEF)}
+	src := [][]byte{
+		[]byte("0123456789A-BCDEF"),
+		[]byte("0123456789abcdef"),
+		[]byte("0x0123456789ABCDEF"),
+		[]byte("0X0123456789A-BCDEF"),
+	}
+
+	dst := make([]Nano64, len(src))
+	n, err := DecodeHexInto(dst, src)
+	if err != nil || n != len(src) {
+		t.Fatalf("DecodeHexInto() = %d, %v", n, err)
+	}
+	for i := range want {
+		if dst[i] != want[i] {
+			t.Errorf("dst[%d] = %v, want %v", i, dst[i], want[i])
+		}
+	}
+
+	allocs := testing.AllocsPerRun(100, func() { DecodeHexInto(dst, src) })
+	if allocs != 0 {
+		t.Errorf("DecodeHexInto() allocated %v times per run, want 0", allocs)
+	}
+
+	src[2] = []byte("0123456789A-BCDEZ")
+	n, err = DecodeHexInto(dst, src)
+	if err == nil || n != 2 {
+		t.Errorf("DecodeHexInto() = %d, %v; want failure at index 2", n, err)
+	}
+	if _, err := DecodeHexInto(dst[:1], src); err == nil {
+		t.Error("DecodeHexInto() expected error for short destination")
+	}
+}
+
+func BenchmarkFromHex(b *testing.B) {
+	s := New(0x0123456789ABCDEF).ToHex()
+	for i := 0; i < b.N; i++ {
+		if _, err := FromHex(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeHexBatch(b *testing.B) {
+	const records = 1024
+	var data bytes.Buffer
+	for i := 0; i < records; i++ {
+		data.WriteString(New(uint64(i) * 0x9E3779B97F4A7C15).ToHex())
+		data.WriteByte('\n')
+	}
+	dst := make([]Nano64, 0, records)
+
+	b.SetBytes(int64(data.Len()))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if dst, err = DecodeHexBatch(dst[:0], data.Bytes(), 18); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package nano64
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timestampHexDigits is the number of hex digits holding the timestamp, the part
+// of ToHex before the dash.
+const timestampHexDigits = TimestampBits / 4
+
+// IDPrefixForTime returns the 11-digit uppercase hex prefix shared by every ID
+// created in the millisecond of t, for searching logs with grep.
+func IDPrefixForTime(t time.Time) string {
+	ms := min(max(t.UnixMilli(), 0), maxTimestamp)
+	return fmt.Sprintf("%0*X", timestampHexDigits, ms)
+}
+
+// CommonIDPrefix returns the longest hex prefix shared by every ID created in
+// [start, end), e.g. all IDs of a given minute. The prefix may also match a few
+// IDs just outside the span, since spans rarely align to hex digits; it is empty
+// if the span crosses the top-level digit.
+func CommonIDPrefix(start, end time.Time) string {
+	first := IDPrefixForTime(start)
+	last := IDPrefixForTime(end.Add(-time.Millisecond))
+	n := 0
+	for n < len(first) && first[n] == last[n] {
+		n++
+	}
+	return first[:n]
+}
+
+// TimeForHexPrefix returns the earliest creation time of IDs whose hex form starts
+// with prefix. prefix holds 1 to 11 hex digits in either case, optionally followed
+// by the dash; shorter prefixes denote longer spans of time.
+func TimeForHexPrefix(prefix string) (time.Time, error) {
+	digits := strings.TrimSuffix(prefix, "-")
+	if len(digits) == 0 || len(digits) > timestampHexDigits {
+		return time.Time{}, fmt.Errorf("hex prefix must have 1 to %d digits, got %q", timestampHexDigits, prefix)
+	}
+
+	var ms int64
+	for i := 0; i < len(digits); i++ {
+		v := hexValues[digits[i]]
+		if v == 0xFF {
+			return time.Time{}, fmt.Errorf("hex prefix contains non-hex character '%c' at position %d", digits[i], i)
+		}
+		ms = ms<<4 | int64(v)
+	}
+	ms <<= 4 * (timestampHexDigits - len(digits))
+	return time.UnixMilli(ms).UTC(), nil
+}
@@ -0,0 +1,57 @@
+package nano64
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIDPrefixForTime(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 30, 15, 250*int(time.Millisecond), time.UTC)
+	id := mustGenerate(t, at.UnixMilli(), 0xABCDE)
+
+	prefix := IDPrefixForTime(at)
+	if len(prefix) != 11 || !strings.HasPrefix(id.ToHex(), prefix+"-") {
+		t.Errorf("IDPrefixForTime() = %s, want prefix of %s", prefix, id.ToHex())
+	}
+
+	got, err := TimeForHexPrefix(prefix + "-")
+	if err != nil {
+		t.Fatalf("TimeForHexPrefix() error = %v", err)
+	}
+	if !got.Equal(at) {
+		t.Errorf("TimeForHexPrefix() = %v, want %v", got, at)
+	}
+}
+
+func TestCommonIDPrefix(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+	prefix := CommonIDPrefix(start, end)
+	if prefix == "" || len(prefix) >= 11 {
+		t.Fatalf("CommonIDPrefix() = %q, want a partial prefix", prefix)
+	}
+
+	for _, at := range []time.Time{start, start.Add(30 * time.Second), end.Add(-time.Millisecond)} {
+		id := mustGenerate(t, at.UnixMilli(), 1)
+		if !strings.HasPrefix(id.ToHex(), prefix) {
+			t.Errorf("ID at %v = %s, want prefix %s", at, id.ToHex(), prefix)
+		}
+	}
+
+	earliest, err := TimeForHexPrefix(strings.ToLower(prefix))
+	if err != nil {
+		t.Fatalf("TimeForHexPrefix() error = %v", err)
+	}
+	if earliest.After(start) {
+		t.Errorf("TimeForHexPrefix(%s) = %v, want at or before %v", prefix, earliest, start)
+	}
+}
+
+func TestTimeForHexPrefixErrors(t *testing.T) {
+	for _, prefix := range []string{"", "-", "0123456789AB", "12G"} {
+		if _, err := TimeForHexPrefix(prefix); err == nil {
+			t.Errorf("TimeForHexPrefix(%q) expected error", prefix)
+		}
+	}
+}
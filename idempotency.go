@@ -0,0 +1,197 @@
+package nano64
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyExpired is returned for keys older than their TTL.
+	ErrIdempotencyKeyExpired = errors.New("idempotency key expired")
+
+	// ErrIdempotencyConflict is returned when a key is reused for a different request.
+	ErrIdempotencyConflict = errors.New("idempotency key reused with a different request")
+
+	// ErrIdempotencyKeyFuture is returned for keys created further in the future
+	// than the allowed clock skew.
+	ErrIdempotencyKeyFuture = errors.New("idempotency key from the future")
+)
+
+// DefaultIdempotencySkew is how far ahead of the server's clock CheckExpiry and
+// CheckIdempotency accept a key's timestamp, allowing for client clock drift.
+const DefaultIdempotencySkew = time.Minute
+
+// IdempotencyKey identifies one logical client request across retries. Being a
+// Nano64, it carries its own creation time, so a server can reject stale keys
+// without a lookup and purge old ones with a single range delete.
+type IdempotencyKey struct {
+	id Nano64
+}
+
+// NewIdempotencyKey creates a key for a new request.
+func NewIdempotencyKey() (IdempotencyKey, error) {
+	id, err := GenerateDefault()
+	if err != nil {
+		return IdempotencyKey{}, err
+	}
+	return IdempotencyKey{id: id}, nil
+}
+
+// ParseIdempotencyKey parses a key in any hex form accepted by FromHex.
+func ParseIdempotencyKey(s string) (IdempotencyKey, error) {
+	id, err := FromHex(s)
+	if err != nil {
+		return IdempotencyKey{}, fmt.Errorf("invalid idempotency key: %w", err)
+	}
+	if id.IsNil() {
+		return IdempotencyKey{}, fmt.Errorf("invalid idempotency key: nil ID")
+	}
+	return IdempotencyKey{id: id}, nil
+}
+
+// ID returns the underlying ID.
+func (k IdempotencyKey) ID() Nano64 {
+	return k.id
+}
+
+// String returns the key in dashed hex form, as sent in an Idempotency-Key header.
+func (k IdempotencyKey) String() string {
+	return k.id.ToHex()
+}
+
+// Equal compares two keys in constant time.
+func (k IdempotencyKey) Equal(other IdempotencyKey) bool {
+	var a, b [8]byte
+	binary.BigEndian.PutUint64(a[:], k.id.value)
+	binary.BigEndian.PutUint64(b[:], other.id.value)
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+// ExpiresAt returns when the key stops being accepted under ttl.
+func (k IdempotencyKey) ExpiresAt(ttl time.Duration) time.Time {
+	return k.id.ToDate().Add(ttl)
+}
+
+// CheckExpiry returns ErrIdempotencyKeyExpired if the key is older than ttl, and
+// ErrIdempotencyKeyFuture if it is more than DefaultIdempotencySkew ahead,
+// according to clock (DefaultClock if nil). See CheckWindow.
+func (k IdempotencyKey) CheckExpiry(ttl time.Duration, clock Clock) error {
+	return k.CheckWindow(ttl, DefaultIdempotencySkew, clock)
+}
+
+// CheckWindow is CheckExpiry with a configurable skew. Rejecting keys from the
+// future matters as much as rejecting stale ones: a key dated years ahead would
+// never expire, so it could be replayed indefinitely and never be purged by
+// DeleteExpired.
+func (k IdempotencyKey) CheckWindow(ttl, skew time.Duration, clock Clock) error {
+	if clock == nil {
+		clock = DefaultClock
+	}
+	now := clock()
+	if now > k.ExpiresAt(ttl).UnixMilli() {
+		return ErrIdempotencyKeyExpired
+	}
+	if k.id.GetTimestamp()-now > skew.Milliseconds() {
+		return ErrIdempotencyKeyFuture
+	}
+	return nil
+}
+
+// RequestHash binds a key to the request it was first used with: it hashes the
+// method, path and body with SHA-256, length-prefixing each part so no two
+// requests share an encoding.
+func RequestHash(method, path string, body []byte) []byte {
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(method), []byte(path), body} {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(part)))
+		h.Write(length[:])
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
+
+// IdempotencyStore records which keys have been used and with which request hash.
+type IdempotencyStore interface {
+	// Reserve stores requestHash under key unless the key is already stored. It
+	// reports whether it stored the key and otherwise returns the stored hash.
+	// Stores may drop a key once ttl has passed.
+	Reserve(ctx context.Context, key IdempotencyKey, requestHash []byte, ttl time.Duration) (reserved bool, storedHash []byte, err error)
+}
+
+// CheckIdempotency validates key for a request and reserves it in store. It returns
+// true for the first use of the key, false for a retry of the same request, and
+// ErrIdempotencyKeyExpired, ErrIdempotencyKeyFuture or ErrIdempotencyConflict for
+// stale, future-dated or reused keys.
+// The request hashes are compared in constant time.
+func CheckIdempotency(ctx context.Context, store IdempotencyStore, key IdempotencyKey, requestHash []byte, ttl time.Duration, clock Clock) (bool, error) {
+	if err := key.CheckExpiry(ttl, clock); err != nil {
+		return false, err
+	}
+	reserved, stored, err := store.Reserve(ctx, key, requestHash, ttl)
+	if err != nil {
+		return false, err
+	}
+	if reserved {
+		return true, nil
+	}
+	if subtle.ConstantTimeCompare(stored, requestHash) != 1 {
+		return false, ErrIdempotencyConflict
+	}
+	return false, nil
+}
+
+// RedisClient is the subset of a Redis client used by RedisIdempotencyStore.
+// With go-redis, both methods are one-line adapters over SetNX and Get; Get must
+// return a nil slice and no error for a missing key.
+type RedisClient interface {
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// RedisIdempotencyStore is an IdempotencyStore backed by Redis. Keys expire on
+// their own when their TTL, measured from the key's embedded time, has passed.
+type RedisIdempotencyStore struct {
+	client RedisClient
+	prefix string
+	clock  Clock
+}
+
+// NewRedisIdempotencyStore creates a store naming entries prefix + key. clock
+// (DefaultClock if nil) computes the remaining lifetime of keys.
+func NewRedisIdempotencyStore(client RedisClient, prefix string, clock Clock) *RedisIdempotencyStore {
+	if clock == nil {
+		clock = DefaultClock
+	}
+	return &RedisIdempotencyStore{client: client, prefix: prefix, clock: clock}
+}
+
+// Reserve implements IdempotencyStore.
+func (s *RedisIdempotencyStore) Reserve(ctx context.Context, key IdempotencyKey, requestHash []byte, ttl time.Duration) (bool, []byte, error) {
+	remaining := time.Duration(key.ExpiresAt(ttl).UnixMilli()-s.clock()) * time.Millisecond
+	if remaining <= 0 {
+		return false, nil, ErrIdempotencyKeyExpired
+	}
+
+	name := s.prefix + key.String()
+	reserved, err := s.client.SetNX(ctx, name, requestHash, remaining)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if reserved {
+		return true, nil, nil
+	}
+	stored, err := s.client.Get(ctx, name)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+	if stored == nil {
+		return false, nil, fmt.Errorf("idempotency key %s vanished during reservation", key)
+	}
+	return false, stored, nil
+}
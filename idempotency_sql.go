@@ -0,0 +1,93 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SQLIdempotencyStore is an IdempotencyStore backed by a table with an 8-byte ID
+// primary key and a request hash column:
+//
+//	CREATE TABLE idempotency_keys (id BYTEA PRIMARY KEY, request_hash BYTEA NOT NULL)
+type SQLIdempotencyStore struct {
+	dialect Dialect
+	table   string
+	execer  Execer
+	querier Querier
+}
+
+// NewSQLIdempotencyStore creates a store using table (columns id and request_hash).
+// db is usually a *sql.DB.
+func NewSQLIdempotencyStore(db interface {
+	Execer
+	Querier
+}, dialect Dialect, table string) (*SQLIdempotencyStore, error) {
+	if err := dialect.validate(); err != nil {
+		return nil, err
+	}
+	if table == "" {
+		return nil, fmt.Errorf("idempotency table is required")
+	}
+	return &SQLIdempotencyStore{dialect: dialect, table: table, execer: db, querier: db}, nil
+}
+
+// Reserve implements IdempotencyStore. The ttl is not stored; use DeleteExpired
+// to purge old keys.
+func (s *SQLIdempotencyStore) Reserve(ctx context.Context, key IdempotencyKey, requestHash []byte, ttl time.Duration) (bool, []byte, error) {
+	table := s.dialect.QuoteIdent(s.table)
+	insert := "INSERT INTO %s (id, request_hash) VALUES (%s, %s) ON CONFLICT DO NOTHING"
+	if s.dialect == DialectMySQL {
+		insert = "INSERT IGNORE INTO %s (id, request_hash) VALUES (%s, %s)"
+	}
+	query := fmt.Sprintf(insert, table, s.dialect.Placeholder(1), s.dialect.Placeholder(2))
+	result, err := s.execer.ExecContext(ctx, query, key.id, requestHash)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return false, nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	} else if n == 1 {
+		return true, nil, nil
+	}
+
+	rows, err := s.querier.QueryContext(ctx,
+		fmt.Sprintf("SELECT request_hash FROM %s WHERE id = %s", table, s.dialect.Placeholder(1)), key.id)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return false, nil, fmt.Errorf("failed to read idempotency key: %w", err)
+		}
+		return false, nil, fmt.Errorf("idempotency key %s vanished during reservation", key)
+	}
+	var stored []byte
+	if err := rows.Scan(&stored); err != nil {
+		return false, nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+	return false, stored, nil
+}
+
+// DeleteExpired removes keys created more than ttl before now according to clock
+// (DefaultClock if nil). Keys are time-ordered, so this is a single range delete
+// on the primary key.
+func (s *SQLIdempotencyStore) DeleteExpired(ctx context.Context, ttl time.Duration, clock Clock) (int64, error) {
+	if clock == nil {
+		clock = DefaultClock
+	}
+	cutoff := clock() - ttl.Milliseconds()
+	if cutoff <= 0 {
+		return 0, nil
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id < %s", s.dialect.QuoteIdent(s.table), s.dialect.Placeholder(1))
+	result, err := s.execer.ExecContext(ctx, query, Nano64{value: uint64(cutoff) << timestampShift})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
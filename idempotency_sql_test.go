@@ -0,0 +1,66 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestIdempotencyStores(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE idempotency_keys (id BLOB PRIMARY KEY, request_hash BLOB NOT NULL)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	sqlStore, err := NewSQLIdempotencyStore(db, DialectSQLite, "idempotency_keys")
+	if err != nil {
+		t.Fatalf("NewSQLIdempotencyStore() error = %v", err)
+	}
+	redis := &mapRedis{values: map[string][]byte{}, ttls: map[string]time.Duration{}}
+
+	stores := map[string]IdempotencyStore{
+		"sql":   sqlStore,
+		"redis": NewRedisIdempotencyStore(redis, "idem:", nil),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			key, _ := NewIdempotencyKey()
+			hash := RequestHash("POST", "/orders", []byte(`{"sku":1}`))
+
+			first, err := CheckIdempotency(ctx, store, key, hash, time.Hour, nil)
+			if err != nil || !first {
+				t.Fatalf("CheckIdempotency() first use = %v, %v", first, err)
+			}
+			first, err = CheckIdempotency(ctx, store, key, hash, time.Hour, nil)
+			if err != nil || first {
+				t.Errorf("CheckIdempotency() retry = %v, %v; want false, nil", first, err)
+			}
+			_, err = CheckIdempotency(ctx, store, key, RequestHash("POST", "/orders", []byte(`{"sku":2}`)), time.Hour, nil)
+			if !errors.Is(err, ErrIdempotencyConflict) {
+				t.Errorf("CheckIdempotency() other request = %v, want ErrIdempotencyConflict", err)
+			}
+		})
+	}
+
+	for name, ttl := range redis.ttls {
+		if ttl <= 59*time.Minute || ttl > time.Hour {
+			t.Errorf("redis TTL of %s = %v, want the remaining hour", name, ttl)
+		}
+	}
+
+	later := func() int64 { return DefaultClock() + 2*time.Hour.Milliseconds() }
+	deleted, err := sqlStore.DeleteExpired(ctx, time.Hour, later)
+	if err != nil || deleted != 1 {
+		t.Errorf("DeleteExpired() = %d, %v; want 1", deleted, err)
+	}
+}
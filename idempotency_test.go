@@ -0,0 +1,68 @@
+package nano64
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// mapRedis is an in-memory RedisClient.
+type mapRedis struct {
+	values map[string][]byte
+	ttls   map[string]time.Duration
+}
+
+func (r *mapRedis) SetNX(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if _, ok := r.values[key]; ok {
+		return false, nil
+	}
+	r.values[key], r.ttls[key] = value, ttl
+	return true, nil
+}
+
+func (r *mapRedis) Get(_ context.Context, key string) ([]byte, error) {
+	return r.values[key], nil
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	key, err := NewIdempotencyKey()
+	if err != nil {
+		t.Fatalf("NewIdempotencyKey() error = %v", err)
+	}
+	parsed, err := ParseIdempotencyKey(key.String())
+	if err != nil || !parsed.Equal(key) {
+		t.Errorf("ParseIdempotencyKey(%s) = %v, %v", key, parsed, err)
+	}
+	other, _ := NewIdempotencyKey()
+	if key.Equal(other) {
+		t.Error("distinct keys compare equal")
+	}
+	for _, bad := range []string{"", "not-a-key", Nil.ToHex()} {
+		if _, err := ParseIdempotencyKey(bad); err == nil {
+			t.Errorf("ParseIdempotencyKey(%q) succeeded, want error", bad)
+		}
+	}
+
+	created := key.ID().GetTimestamp()
+	if err := key.CheckExpiry(time.Minute, func() int64 { return created + 60_000 }); err != nil {
+		t.Errorf("CheckExpiry() at the TTL = %v, want nil", err)
+	}
+	if err := key.CheckExpiry(time.Minute, func() int64 { return created + 60_001 }); !errors.Is(err, ErrIdempotencyKeyExpired) {
+		t.Errorf("CheckExpiry() past the TTL = %v, want ErrIdempotencyKeyExpired", err)
+	}
+	if err := key.CheckExpiry(time.Minute, func() int64 { return created - 60_000 }); err != nil {
+		t.Errorf("CheckExpiry() at the skew = %v, want nil", err)
+	}
+	if err := key.CheckExpiry(time.Minute, func() int64 { return created - 60_001 }); !errors.Is(err, ErrIdempotencyKeyFuture) {
+		t.Errorf("CheckExpiry() past the skew = %v, want ErrIdempotencyKeyFuture", err)
+	}
+	if err := key.CheckWindow(time.Minute, 0, func() int64 { return created - 1 }); !errors.Is(err, ErrIdempotencyKeyFuture) {
+		t.Errorf("CheckWindow() with no skew = %v, want ErrIdempotencyKeyFuture", err)
+	}
+
+	if bytes.Equal(RequestHash("POST", "/a", []byte("bc")), RequestHash("POST", "/ab", []byte("c"))) {
+		t.Error("RequestHash() does not separate its parts")
+	}
+}
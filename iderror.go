@@ -0,0 +1,38 @@
+package nano64
+
+import "errors"
+
+// IDError attaches the ID of the entity an operation failed on to an error, so
+// handlers at the top of the stack can log or report it without it being
+// threaded through every return. Retrieve it with IDFromError or errors.As.
+type IDError struct {
+	ID  Nano64
+	Err error
+}
+
+// Error returns the wrapped error's message prefixed with the ID.
+func (e *IDError) Error() string {
+	return "id " + e.ID.ToHex() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *IDError) Unwrap() error {
+	return e.Err
+}
+
+// WrapWithID returns err annotated with id, or nil if err is nil.
+func WrapWithID(err error, id Nano64) error {
+	if err == nil {
+		return nil
+	}
+	return &IDError{ID: id, Err: err}
+}
+
+// IDFromError returns the ID of the outermost IDError in err's chain.
+func IDFromError(err error) (Nano64, bool) {
+	var idErr *IDError
+	if errors.As(err, &idErr) {
+		return idErr.ID, true
+	}
+	return Nil, false
+}
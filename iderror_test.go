@@ -0,0 +1,36 @@
+package nano64
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestWrapWithID(t *testing.T) {
+	inner, outer := mustGenerate(t, 1000, 1), mustGenerate(t, 2000, 2)
+
+	if WrapWithID(nil, inner) != nil {
+		t.Error("WrapWithID(nil) is not nil")
+	}
+
+	err := fmt.Errorf("loading order: %w", WrapWithID(io.ErrUnexpectedEOF, inner))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Error("wrapped error lost its cause")
+	}
+	if id, ok := IDFromError(err); !ok || id != inner {
+		t.Errorf("IDFromError() = %s, %v, want %s", id.ToHex(), ok, inner.ToHex())
+	}
+	if want := "loading order: id " + inner.ToHex() + ": unexpected EOF"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	var idErr *IDError
+	if !errors.As(WrapWithID(err, outer), &idErr) || idErr.ID != outer {
+		t.Errorf("errors.As() found %+v, want the outermost ID %s", idErr, outer.ToHex())
+	}
+
+	if _, ok := IDFromError(io.EOF); ok {
+		t.Error("IDFromError() found an ID in a plain error")
+	}
+}
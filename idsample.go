@@ -0,0 +1,104 @@
+package nano64
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// SampleByID reports whether id belongs to a sample of the given rate (0 to 1).
+// The decision depends only on the ID's random field, scrambled so that
+// consecutive monotonic IDs are not kept in runs, so every service sampling at
+// the same rate keeps the same entities and a trace stays whole across them.
+// Samples are nested: an ID kept at one rate is kept at every higher rate.
+func SampleByID(id Nano64, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 || math.IsNaN(rate) {
+		return false
+	}
+	return mix64(uint64(id.GetRandom())) < uint64(rate*(1<<63))<<1
+}
+
+// SampleSchedule assigns sample rates to time buckets, so a shared schedule
+// lowers the rate during a traffic spike for every service at once while
+// decisions stay consistent: an ID is sampled by SampleByID at the rate of the
+// bucket it was created in, wherever it is seen.
+// A SampleSchedule is safe for concurrent use.
+type SampleSchedule struct {
+	resolution time.Duration
+	base       float64
+
+	mu    sync.RWMutex
+	rates map[Nano64]float64
+}
+
+// NewSampleSchedule creates a schedule with buckets of the given resolution,
+// all at baseRate until set otherwise.
+func NewSampleSchedule(resolution time.Duration, baseRate float64) (*SampleSchedule, error) {
+	if resolution < time.Millisecond {
+		return nil, fmt.Errorf("sample bucket resolution must be at least 1ms, got %v", resolution)
+	}
+	if err := validateSampleRate(baseRate); err != nil {
+		return nil, err
+	}
+	return &SampleSchedule{resolution: resolution, base: baseRate, rates: make(map[Nano64]float64)}, nil
+}
+
+// validateSampleRate rejects rates outside [0, 1].
+func validateSampleRate(rate float64) error {
+	if !(rate >= 0 && rate <= 1) {
+		return fmt.Errorf("sample rate must be between 0 and 1, got %v", rate)
+	}
+	return nil
+}
+
+// SetRate sets the rate of the bucket containing t.
+func (s *SampleSchedule) SetRate(t time.Time, rate float64) error {
+	if err := validateSampleRate(rate); err != nil {
+		return err
+	}
+	bucket := DownsampleKey(Nano64{value: uint64(max(t.UnixMilli(), 0)) << timestampShift}, s.resolution)
+	s.mu.Lock()
+	s.rates[bucket] = rate
+	s.mu.Unlock()
+	return nil
+}
+
+// Rate returns the rate of the bucket id was created in.
+func (s *SampleSchedule) Rate(id Nano64) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if rate, ok := s.rates[DownsampleKey(id, s.resolution)]; ok {
+		return rate
+	}
+	return s.base
+}
+
+// Sample reports whether id is sampled at the rate of its bucket.
+func (s *SampleSchedule) Sample(id Nano64) bool {
+	return SampleByID(id, s.Rate(id))
+}
+
+// Prune forgets the rates of buckets starting before t, which then fall back to
+// the base rate.
+func (s *SampleSchedule) Prune(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for bucket := range s.rates {
+		if bucket.GetTimestamp() < t.UnixMilli() {
+			delete(s.rates, bucket)
+		}
+	}
+}
+
+// RateForTarget returns the rate that keeps about target of the observed IDs of
+// a bucket, for deriving the next SetRate from traffic counts.
+func RateForTarget(observed, target int64) float64 {
+	if observed <= target {
+		return 1
+	}
+	return max(float64(target), 0) / float64(observed)
+}
@@ -0,0 +1,72 @@
+package nano64
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSampleByID(t *testing.T) {
+	// Consecutive random fields, as monotonic generation produces.
+	const n = 1 << 16
+	ids := make([]Nano64, n)
+	for i := range ids {
+		ids[i] = mustGenerate(t, 1000, uint32(i))
+	}
+
+	for _, rate := range []float64{0.01, 0.1, 0.5} {
+		kept, run, longest := 0, 0, 0
+		for _, id := range ids {
+			if SampleByID(id, rate) {
+				kept++
+				run++
+				longest = max(longest, run)
+				if !SampleByID(id, math.Min(rate*2, 1)) {
+					t.Errorf("ID %s kept at %v but not at %v", id.ToHex(), rate, rate*2)
+				}
+			} else {
+				run = 0
+			}
+		}
+		if got := float64(kept) / n; math.Abs(got-rate) > rate*0.1 {
+			t.Errorf("rate %v kept %.4f of IDs", rate, got)
+		}
+		if longest > 20 {
+			t.Errorf("rate %v kept a run of %d consecutive IDs", rate, longest)
+		}
+	}
+
+	if SampleByID(ids[0], 0) || !SampleByID(ids[0], 1) || SampleByID(ids[0], math.NaN()) {
+		t.Error("rates 0, 1 and NaN are not all-or-nothing")
+	}
+}
+
+func TestSampleSchedule(t *testing.T) {
+	s, err := NewSampleSchedule(time.Minute, 1)
+	if err != nil {
+		t.Fatalf("NewSampleSchedule() error = %v", err)
+	}
+	spike := time.UnixMilli(10 * 60_000)
+	if err := s.SetRate(spike.Add(30*time.Second), RateForTarget(1000, 10)); err != nil {
+		t.Fatalf("SetRate() error = %v", err)
+	}
+
+	inSpike, before := mustGenerate(t, spike.UnixMilli()+59_999, 7), mustGenerate(t, spike.UnixMilli()-1, 7)
+	if s.Rate(inSpike) != 0.01 || s.Rate(before) != 1 {
+		t.Errorf("Rate() = %v and %v, want 0.01 and 1", s.Rate(inSpike), s.Rate(before))
+	}
+	if s.Sample(inSpike) != SampleByID(inSpike, 0.01) || !s.Sample(before) {
+		t.Error("Sample() disagrees with SampleByID at the bucket rate")
+	}
+
+	s.Prune(spike.Add(time.Minute))
+	if s.Rate(inSpike) != 1 {
+		t.Errorf("Rate() after Prune() = %v, want the base rate", s.Rate(inSpike))
+	}
+	if err := s.SetRate(spike, 1.5); err == nil {
+		t.Error("SetRate() accepted a rate above 1")
+	}
+	if RateForTarget(5, 10) != 1 {
+		t.Error("RateForTarget() below target is not 1")
+	}
+}
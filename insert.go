@@ -0,0 +1,121 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+)
+
+// Execer is the subset of *sql.DB, *sql.Tx and *sql.Conn used to run statements.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// InsertHelper emulates a `DEFAULT nano64()` column default for engines without
+// native support: every Nil ID among a statement's arguments is replaced with a
+// freshly generated one before the statement runs.
+type InsertHelper struct {
+	// Generate creates the substituted IDs. Nil means GenerateDefault.
+	Generate func() (Nano64, error)
+}
+
+// Rewrite returns a copy of args in which every Nil Nano64 placeholder is replaced
+// by a generated ID, together with the generated IDs in argument order.
+// Placeholders may be passed as Nano64, *Nano64 or wrapped in sql.Named; a *Nano64
+// placeholder is also updated in place so the caller's struct sees the new ID.
+// Placeholders are only updated once every ID has been generated, so on error
+// the caller's values are left untouched.
+func (h InsertHelper) Rewrite(args []any) ([]any, []Nano64, error) {
+	rewritten, generated, assign, err := h.rewrite(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	assign()
+	return rewritten, generated, nil
+}
+
+// rewrite is Rewrite without updating *Nano64 placeholders; calling assign
+// updates them.
+func (h InsertHelper) rewrite(args []any) (rewritten []any, generated []Nano64, assign func(), err error) {
+	generate := h.Generate
+	if generate == nil {
+		generate = GenerateDefault
+	}
+
+	rewritten = make([]any, len(args))
+	var (
+		targets   []*Nano64
+		targetIDs []Nano64
+	)
+	for i, arg := range args {
+		named, isNamed := arg.(sql.NamedArg)
+		if isNamed {
+			arg = named.Value
+		}
+
+		switch v := arg.(type) {
+		case Nano64:
+			if v.IsNil() {
+				id, err := generate()
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("failed to generate ID for argument %d: %w", i, err)
+				}
+				arg = id
+				generated = append(generated, id)
+			}
+		case *Nano64:
+			if v == nil {
+				break
+			}
+			arg = *v
+			if v.IsNil() {
+				// The same pointer passed twice gets one ID, as it would if
+				// it were updated immediately.
+				if j := slices.Index(targets, v); j >= 0 {
+					arg = targetIDs[j]
+					break
+				}
+				id, err := generate()
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("failed to generate ID for argument %d: %w", i, err)
+				}
+				arg = id
+				generated = append(generated, id)
+				targets = append(targets, v)
+				targetIDs = append(targetIDs, id)
+			}
+		}
+
+		if isNamed {
+			named.Value = arg
+			arg = named
+		}
+		rewritten[i] = arg
+	}
+
+	assign = func() {
+		for i, v := range targets {
+			*v = targetIDs[i]
+		}
+	}
+	return rewritten, generated, assign, nil
+}
+
+// Exec rewrites args with Rewrite, runs the statement and returns the generated IDs.
+// *Nano64 placeholders are updated only if the statement succeeds.
+func (h InsertHelper) Exec(ctx context.Context, db Execer, query string, args ...any) (sql.Result, []Nano64, error) {
+	rewritten, generated, assign, err := h.rewrite(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := db.ExecContext(ctx, query, rewritten...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("insert failed: %w", err)
+	}
+	assign()
+	return result, generated, nil
+}
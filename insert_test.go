@@ -0,0 +1,103 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestInsertHelper_Rewrite(t *testing.T) {
+	fixed := New(7 << RandomBits)
+	helper := InsertHelper{Generate: func() (Nano64, error) { return fixed, nil }}
+
+	existing := New(42)
+	target := Nil
+	args, generated, err := helper.Rewrite([]any{Nil, existing, &target, sql.Named("parent", Nil), "name"})
+	if err != nil {
+		t.Fatalf("Rewrite() error = %v", err)
+	}
+
+	if len(generated) != 3 {
+		t.Fatalf("generated %d IDs, want 3", len(generated))
+	}
+	if !args[0].(Nano64).Equals(fixed) || !args[1].(Nano64).Equals(existing) || !args[2].(Nano64).Equals(fixed) {
+		t.Errorf("unexpected rewritten args: %v", args)
+	}
+	if !target.Equals(fixed) {
+		t.Errorf("pointer placeholder not updated: %s", target.ToHex())
+	}
+	if named := args[3].(sql.NamedArg); named.Name != "parent" || !named.Value.(Nano64).Equals(fixed) {
+		t.Errorf("named placeholder not rewritten: %+v", named)
+	}
+	if args[4] != "name" {
+		t.Errorf("unrelated argument changed: %v", args[4])
+	}
+}
+
+func TestInsertHelper_RewriteFailureLeavesArgs(t *testing.T) {
+	calls := 0
+	helper := InsertHelper{Generate: func() (Nano64, error) {
+		calls++
+		if calls == 2 {
+			return Nil, errors.New("entropy exhausted")
+		}
+		return New(7 << RandomBits), nil
+	}}
+
+	target := Nil
+	if _, _, err := helper.Rewrite([]any{&target, Nil}); err == nil {
+		t.Fatal("Rewrite() error = nil, want the generator's error")
+	}
+	if !target.IsNil() {
+		t.Errorf("pointer placeholder updated to %s despite the error", target.ToHex())
+	}
+}
+
+func TestInsertHelper_Exec(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE users (id BLOB PRIMARY KEY, name TEXT)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	_, generated, err := InsertHelper{}.Exec(context.Background(), db, "INSERT INTO users (id, name) VALUES (?, ?)", Nil, "Alice")
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if len(generated) != 1 || generated[0].IsNil() {
+		t.Fatalf("unexpected generated IDs: %v", generated)
+	}
+
+	var stored Nano64
+	if err := db.QueryRow("SELECT id FROM users WHERE name = ?", "Alice").Scan(&stored); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if !stored.Equals(generated[0]) {
+		t.Errorf("stored ID %s, want %s", stored.ToHex(), generated[0].ToHex())
+	}
+}
+
+func TestInsertHelper_ExecFailureLeavesArgs(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	target := Nil
+	if _, _, err := (InsertHelper{}).Exec(context.Background(), db, "INSERT INTO missing (id) VALUES (?)", &target); err == nil {
+		t.Fatal("Exec() into a missing table error = nil")
+	}
+	if !target.IsNil() {
+		t.Errorf("pointer placeholder updated to %s despite the failed insert", target.ToHex())
+	}
+}
@@ -129,17 +129,11 @@ func testConcurrentGeneration(totalCount int, numGoroutines int) {
 
 // testSustainedRate generates IDs at a target rate for a duration
 func testSustainedRate(targetRate int, duration time.Duration) {
-	seen := make(map[uint64]bool)
-	collisions := 0
-	totalGenerated := 0
+	var ids []nano64.Nano64
 
 	start := time.Now()
 	deadline := start.Add(duration)
 
-	// Track per-millisecond statistics
-	msStats := make(map[int64]int)
-	maxPerMs := 0
-
 	// Generate IDs in batches to achieve target rate
 	batchSize := 1000
 	batchInterval := time.Duration(float64(time.Second) / (float64(targetRate) / float64(batchSize)))
@@ -155,34 +149,24 @@ func testSustainedRate(targetRate int, duration time.Duration) {
 			if err != nil {
 				continue
 			}
-
-			value := id.Uint64Value()
-			if seen[value] {
-				collisions++
-			}
-			seen[value] = true
-			totalGenerated++
-
-			// Track IDs per millisecond
-			timestamp := id.GetTimestamp()
-			msStats[timestamp]++
-			if msStats[timestamp] > maxPerMs {
-				maxPerMs = msStats[timestamp]
-			}
+			ids = append(ids, id)
 		}
 	}
 
 	elapsed := time.Since(start)
-	actualRate := float64(totalGenerated) / elapsed.Seconds()
+	actualRate := float64(len(ids)) / elapsed.Seconds()
+
+	// Collisions and per-millisecond statistics
+	stats := nano64.Stats(ids)
 
 	fmt.Printf("  Target Rate: %s IDs/second\n", formatNumberWithCommas(int64(targetRate)))
 	fmt.Printf("  Duration: %v\n", duration)
-	fmt.Printf("  Generated: %s IDs\n", formatNumberWithCommas(int64(totalGenerated)))
+	fmt.Printf("  Generated: %s IDs\n", formatNumberWithCommas(int64(stats.Count)))
 	fmt.Printf("  Actual Rate: %s IDs/second\n", formatNumberWithCommas(int64(actualRate)))
-	fmt.Printf("  Collisions: %s (%.6f%%)\n", formatNumberWithCommas(int64(collisions)), float64(collisions)/float64(totalGenerated)*100)
-	fmt.Printf("  Unique IDs: %s\n", formatNumberWithCommas(int64(len(seen))))
-	fmt.Printf("  Max IDs in single millisecond: %s\n", formatNumberWithCommas(int64(maxPerMs)))
-	fmt.Printf("  Milliseconds with IDs: %s\n", formatNumberWithCommas(int64(len(msStats))))
+	fmt.Printf("  Collisions: %s (%.6f%%)\n", formatNumberWithCommas(int64(stats.Duplicates)), float64(stats.Duplicates)/float64(stats.Count)*100)
+	fmt.Printf("  Unique IDs: %s\n", formatNumberWithCommas(int64(stats.Unique)))
+	fmt.Printf("  Max IDs in single millisecond: %s\n", formatNumberWithCommas(int64(stats.PeakPerMillisecond)))
+	fmt.Printf("  Milliseconds with IDs: %s\n", formatNumberWithCommas(int64(stats.Milliseconds)))
 }
 
 // testMaxThroughput generates as many IDs as possible in a time window
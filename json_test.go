@@ -0,0 +1,89 @@
+package nano64
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAppendJSON(t *testing.T) {
+	for _, v := range []uint64{0, 1, 0x0123456789ABCDEF, ^uint64(0)} {
+		id := New(v)
+		want, err := json.Marshal(id.ToHex())
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		if got := string(id.AppendJSON([]byte("x"))); got != "x"+string(want) {
+			t.Errorf("AppendJSON(%#x) = %s, want x%s", v, got, want)
+		}
+	}
+
+	if got := string(NullNano64{}.AppendJSON(nil)); got != "null" {
+		t.Errorf("NullNano64{}.AppendJSON() = %s, want null", got)
+	}
+}
+
+func TestJSONAllocations(t *testing.T) {
+	id := New(0x0123456789ABCDEF)
+	buf := make([]byte, 0, 32)
+	data := []byte(`"0123456789A-BCDEF"`)
+
+	if allocs := testing.AllocsPerRun(100, func() { buf = id.AppendJSON(buf[:0]) }); allocs != 0 {
+		t.Errorf("AppendJSON() allocs = %v, want 0", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { _, _ = id.MarshalJSON() }); allocs > 1 {
+		t.Errorf("MarshalJSON() allocs = %v, want at most 1", allocs)
+	}
+
+	var decoded Nano64
+	if allocs := testing.AllocsPerRun(100, func() { _ = decoded.UnmarshalJSON(data) }); allocs != 0 {
+		t.Errorf("UnmarshalJSON() allocs = %v, want 0", allocs)
+	}
+	if decoded != id {
+		t.Errorf("UnmarshalJSON() = %v, want %v", decoded, id)
+	}
+}
+
+func TestUnmarshalJSONFallbacks(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    uint64
+		wantErr bool
+	}{
+		{`"0x0123456789ABCDEF"`, 0x0123456789ABCDEF, false},
+		{`"0123456789a-bcdef"`, 0x0123456789ABCDEF, false},
+		{`81985529216486895`, 0x0123456789ABCDEF, false},
+		{`"0123456789A-BCDEG"`, 0, true},
+		{`true`, 0, true},
+	}
+
+	for _, tt := range tests {
+		var got Nano64
+		err := json.Unmarshal([]byte(tt.data), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.Uint64Value() != tt.want {
+			t.Errorf("Unmarshal(%s) = %#x, want %#x", tt.data, got.Uint64Value(), tt.want)
+		}
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	id := New(0x0123456789ABCDEF)
+	for i := 0; i < b.N; i++ {
+		if _, err := id.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalJSON(b *testing.B) {
+	data := []byte(`"0123456789A-BCDEF"`)
+	var id Nano64
+	for i := 0; i < b.N; i++ {
+		if err := id.UnmarshalJSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
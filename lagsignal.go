@@ -0,0 +1,117 @@
+package nano64
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+)
+
+const (
+	// defaultLagQuantile is the age quantile LagSignal compares to its threshold.
+	defaultLagQuantile = 0.99
+
+	// defaultLagScaleDownRatio is the fraction of the threshold below which
+	// LagSignal recommends scaling down.
+	defaultLagScaleDownRatio = 0.5
+)
+
+// ScaleAction is an autoscaling recommendation.
+type ScaleAction int
+
+const (
+	// ScaleHold keeps the current capacity.
+	ScaleHold ScaleAction = iota
+	// ScaleUp adds consumers: the pipeline is falling behind.
+	ScaleUp
+	// ScaleDown removes consumers: the pipeline has ample headroom.
+	ScaleDown
+)
+
+// String returns a short name for the action.
+func (a ScaleAction) String() string {
+	switch a {
+	case ScaleHold:
+		return "hold"
+	case ScaleUp:
+		return "up"
+	case ScaleDown:
+		return "down"
+	default:
+		return fmt.Sprintf("ScaleAction(%d)", int(a))
+	}
+}
+
+// LagRecommendation is one output of a LagPolicy.
+type LagRecommendation struct {
+	Action ScaleAction
+	// Lag is the observed age quantile; zero if no IDs were observed.
+	Lag time.Duration
+	// Observed is the number of IDs the recommendation is based on.
+	Observed uint64
+}
+
+// LagPolicy turns the ages of processed IDs into scale-up/down recommendations:
+// how far behind the newest IDs a consumer is, is what it should autoscale on.
+// Create one with LagSignal and adjust the fields as needed.
+type LagPolicy struct {
+	// Threshold is the highest acceptable lag; above it, scale up.
+	Threshold time.Duration
+
+	// Quantile of the ages that is compared, e.g. 0.99 for the p99 lag.
+	Quantile float64
+
+	// ScaleDownRatio: below Threshold*ScaleDownRatio, scale down. Between the
+	// two, hold, so the recommendation does not flap around one value.
+	ScaleDownRatio float64
+}
+
+// LagSignal returns a policy scaling up when the p99 age of processed IDs exceeds
+// threshold and down when it is below half of it.
+func LagSignal(threshold time.Duration) LagPolicy {
+	return LagPolicy{Threshold: threshold, Quantile: defaultLagQuantile, ScaleDownRatio: defaultLagScaleDownRatio}
+}
+
+// Recommend evaluates a histogram snapshot. An empty snapshot holds, since
+// without traffic the lag is unknown.
+func (p LagPolicy) Recommend(s AgeHistogramSnapshot) LagRecommendation {
+	r := LagRecommendation{Observed: s.Count}
+	if s.Count == 0 {
+		return r
+	}
+	r.Lag = s.Quantile(p.Quantile)
+	switch {
+	case r.Lag > p.Threshold:
+		r.Action = ScaleUp
+	case float64(r.Lag) < float64(p.Threshold)*p.ScaleDownRatio:
+		r.Action = ScaleDown
+	}
+	return r
+}
+
+// Watch yields a recommendation every interval from the IDs h observed since the
+// previous one, resetting h each time, until ctx is done. h should be fed by the
+// consumers and read by nothing else. A non-positive interval yields nothing.
+func (p LagPolicy) Watch(ctx context.Context, h *AgeHistogram, interval time.Duration) iter.Seq[LagRecommendation] {
+	return func(yield func(LagRecommendation) bool) {
+		if interval <= 0 {
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.mu.Lock()
+				s := h.snapshotLocked()
+				h.resetLocked()
+				h.mu.Unlock()
+				if !yield(p.Recommend(s)) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package nano64
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLagSignal(t *testing.T) {
+	now := int64(10_000_000)
+	policy := LagSignal(time.Second)
+	tests := []struct {
+		name string
+		ages []int64
+		want ScaleAction
+	}{
+		{"no traffic", nil, ScaleHold},
+		{"behind", []int64{100, 200, 5000}, ScaleUp},
+		{"between", []int64{700, 800}, ScaleHold},
+		{"headroom", []int64{10, 20, 300}, ScaleDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewAgeHistogram(AgeHistogramOptions{Clock: func() int64 { return now }})
+			if err != nil {
+				t.Fatalf("NewAgeHistogram() error = %v", err)
+			}
+			for i, age := range tt.ages {
+				h.Observe(mustGenerate(t, now-age, uint32(i)))
+			}
+			r := policy.Recommend(h.Snapshot())
+			if r.Action != tt.want || r.Observed != uint64(len(tt.ages)) {
+				t.Errorf("Recommend() = %+v (%s), want %s", r, r.Action, tt.want)
+			}
+		})
+	}
+
+	h, err := NewAgeHistogram(AgeHistogramOptions{Clock: func() int64 { return now }})
+	if err != nil {
+		t.Fatalf("NewAgeHistogram() error = %v", err)
+	}
+	h.Observe(mustGenerate(t, now-5000, 0))
+	var got []ScaleAction
+	for r := range policy.Watch(context.Background(), h, time.Millisecond) {
+		if got = append(got, r.Action); len(got) == 2 {
+			break
+		}
+	}
+	// The second window starts empty because Watch resets the histogram.
+	if len(got) != 2 || got[0] != ScaleUp || got[1] != ScaleHold {
+		t.Errorf("Watch() yielded %v, want [up hold]", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range policy.Watch(ctx, h, time.Hour) {
+		t.Error("Watch() yielded after cancellation")
+	}
+}
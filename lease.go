@@ -0,0 +1,194 @@
+package nano64
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrLeased is returned by LeaseRegistry.Delete for IDs with live leases.
+	ErrLeased = errors.New("ID is leased")
+
+	// ErrDeleting is returned by LeaseRegistry.Acquire for IDs being deleted.
+	ErrDeleting = errors.New("ID is being deleted")
+)
+
+// LeaseOptions configures a LeaseRegistry.
+type LeaseOptions struct {
+	// TTL derives the lifetime of a lease from the age of its ID, as for cache
+	// entries: resources of fresh IDs are usually touched briefly, old ones by
+	// long scans and compactions. TTL.Min must be positive. TTL.Clock defaults
+	// to Clock.
+	TTL TTLPolicy
+
+	// Clock defaults to DefaultClock.
+	Clock Clock
+}
+
+// leaseEntry is the lease state of one ID.
+type leaseEntry struct {
+	count    int
+	expires  int64 // epoch ms; the latest expiry of the entry's leases
+	deleting bool
+}
+
+// Lease is a claim on an ID returned by LeaseRegistry.Acquire.
+type Lease struct {
+	ID Nano64
+
+	// Expires is when the lease lapses if not released, in epoch ms.
+	Expires int64
+
+	registry *LeaseRegistry
+	entry    *leaseEntry
+	once     sync.Once
+}
+
+// Release gives up the lease. Releasing twice, or after expiry, does nothing.
+func (l *Lease) Release() {
+	l.once.Do(func() {
+		r := l.registry
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.entries[l.ID] != l.entry {
+			return
+		}
+		if l.entry.count--; l.entry.count == 0 {
+			delete(r.entries, l.ID)
+		}
+	})
+}
+
+// LeaseRegistry counts the goroutines using ID-addressed resources, such as
+// blobs or cache files, so a compactor deletes only those nobody holds. Leases
+// expire after a TTL derived from the ID's age, so a holder that never releases
+// blocks deletion for a bounded time.
+// A LeaseRegistry is safe for concurrent use.
+type LeaseRegistry struct {
+	ttl   TTLPolicy
+	clock Clock
+
+	mu      sync.Mutex
+	entries map[Nano64]*leaseEntry
+}
+
+// NewLeaseRegistry creates a LeaseRegistry.
+func NewLeaseRegistry(opts LeaseOptions) (*LeaseRegistry, error) {
+	if opts.TTL.Min <= 0 {
+		return nil, fmt.Errorf("lease minimum ttl must be positive, got %v", opts.TTL.Min)
+	}
+	if opts.TTL.Max != 0 && opts.TTL.Max < opts.TTL.Min {
+		return nil, fmt.Errorf("lease maximum ttl %v is below the minimum %v", opts.TTL.Max, opts.TTL.Min)
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	ttl := opts.TTL
+	if ttl.Clock == nil {
+		ttl.Clock = clock
+	}
+	return &LeaseRegistry{ttl: ttl, clock: clock, entries: make(map[Nano64]*leaseEntry)}, nil
+}
+
+// liveLocked returns the entry of id if it has unexpired leases or is being
+// deleted, dropping it if expired; r.mu must be held.
+func (r *LeaseRegistry) liveLocked(id Nano64, now int64) *leaseEntry {
+	e, ok := r.entries[id]
+	if !ok {
+		return nil
+	}
+	if !e.deleting && e.expires <= now {
+		delete(r.entries, id)
+		return nil
+	}
+	return e
+}
+
+// Acquire leases id. It fails with ErrDeleting while Delete runs for id.
+func (r *LeaseRegistry) Acquire(id Nano64) (*Lease, error) {
+	now := r.clock()
+	expires := now + SuggestedTTL(id, r.ttl).Milliseconds()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.liveLocked(id, now)
+	if e == nil {
+		e = &leaseEntry{}
+		r.entries[id] = e
+	}
+	if e.deleting {
+		return nil, fmt.Errorf("%w: %s", ErrDeleting, id.ToHex())
+	}
+	e.count++
+	e.expires = max(e.expires, expires)
+	return &Lease{ID: id, Expires: expires, registry: r, entry: e}, nil
+}
+
+// Count returns the number of live leases on id.
+func (r *LeaseRegistry) Count(id Nano64) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e := r.liveLocked(id, r.clock()); e != nil {
+		return e.count
+	}
+	return 0
+}
+
+// Delete runs del for id if it has no live leases, blocking Acquire for id
+// meanwhile. It returns ErrLeased without calling del if id is leased, and
+// otherwise the error of del.
+func (r *LeaseRegistry) Delete(id Nano64, del func(id Nano64) error) error {
+	r.mu.Lock()
+	e := r.liveLocked(id, r.clock())
+	if e != nil {
+		deleting, count := e.deleting, e.count
+		r.mu.Unlock()
+		if deleting {
+			return fmt.Errorf("%w: %s", ErrDeleting, id.ToHex())
+		}
+		return fmt.Errorf("%w: %s has %d leases", ErrLeased, id.ToHex(), count)
+	}
+	e = &leaseEntry{deleting: true}
+	r.entries[id] = e
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.entries, id)
+		r.mu.Unlock()
+	}()
+	return del(id)
+}
+
+// Sweep drops expired leases and returns the number of IDs they were held on.
+// Expired leases are also dropped lazily; Sweep bounds the memory of IDs that
+// are never looked at again.
+func (r *LeaseRegistry) Sweep() int {
+	now := r.clock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for id, e := range r.entries {
+		if !e.deleting && e.expires <= now {
+			delete(r.entries, id)
+			n++
+		}
+	}
+	return n
+}
+
+// Len returns the number of IDs with live leases or a deletion in progress.
+func (r *LeaseRegistry) Len() int {
+	now := r.clock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, e := range r.entries {
+		if e.deleting || e.expires > now {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,84 @@
+package nano64
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLeaseRegistry(t *testing.T) {
+	const now = 1_700_000_000_000
+	clock := NewSimClock(now)
+	r, err := NewLeaseRegistry(LeaseOptions{TTL: TTLPolicy{Min: time.Minute, Max: time.Hour}, Clock: clock.Now})
+	if err != nil {
+		t.Fatalf("NewLeaseRegistry() error = %v", err)
+	}
+
+	fresh := mustGenerate(t, now, 1)
+	old := mustGenerate(t, now-int64(100*time.Hour/time.Millisecond), 2)
+
+	a, err := r.Acquire(fresh)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	b, _ := r.Acquire(fresh)
+	if got := a.Expires - now; got != time.Minute.Milliseconds() {
+		t.Errorf("fresh ID lease lasts %dms, want the minimum", got)
+	}
+	if c, _ := r.Acquire(old); c.Expires-now != time.Hour.Milliseconds() {
+		t.Errorf("old ID lease lasts %dms, want the maximum", c.Expires-now)
+	}
+	if r.Count(fresh) != 2 || r.Len() != 2 {
+		t.Errorf("Count() = %d, Len() = %d, want 2, 2", r.Count(fresh), r.Len())
+	}
+
+	deleted := false
+	del := func(Nano64) error { deleted = true; return nil }
+	if err := r.Delete(fresh, del); !errors.Is(err, ErrLeased) || deleted {
+		t.Errorf("Delete() of a leased ID error = %v, deleted %v", err, deleted)
+	}
+	a.Release()
+	a.Release()
+	if r.Count(fresh) != 1 {
+		t.Errorf("Count() after a double release = %d, want 1", r.Count(fresh))
+	}
+	b.Release()
+	if err := r.Delete(fresh, del); err != nil || !deleted {
+		t.Errorf("Delete() of a released ID error = %v, deleted %v", err, deleted)
+	}
+
+	// Acquire is blocked while the deletion runs.
+	err = r.Delete(fresh, func(id Nano64) error {
+		if _, err := r.Acquire(id); !errors.Is(err, ErrDeleting) {
+			t.Errorf("Acquire() during Delete() error = %v, want ErrDeleting", err)
+		}
+		return errors.New("boom")
+	})
+	if err == nil || err.Error() != "boom" {
+		t.Errorf("Delete() error = %v, want the error of del", err)
+	}
+	if _, err := r.Acquire(fresh); err != nil {
+		t.Errorf("Acquire() after Delete() error = %v", err)
+	}
+
+	// Leases lapse after their TTL.
+	clock.Advance(2 * time.Minute)
+	if r.Count(fresh) != 0 || r.Count(old) != 1 {
+		t.Errorf("Count() after 2m = %d, %d, want 0, 1", r.Count(fresh), r.Count(old))
+	}
+	clock.Advance(time.Hour)
+	if n := r.Sweep(); n != 1 {
+		t.Errorf("Sweep() = %d, want 1", n)
+	}
+	if r.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", r.Len())
+	}
+}
+
+func TestNewLeaseRegistryErrors(t *testing.T) {
+	for _, ttl := range []TTLPolicy{{}, {Min: time.Hour, Max: time.Minute}} {
+		if _, err := NewLeaseRegistry(LeaseOptions{TTL: ttl}); err == nil {
+			t.Errorf("NewLeaseRegistry(%+v) error = nil", ttl)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Command libnano64 exposes the Nano64 bit layout through a plain C ABI, so services
+// written in other languages can link the canonical implementation instead of
+// reimplementing it. Build it as a static or shared library:
+//
+//	go build -buildmode=c-archive -o libnano64.a ./libnano64
+//	go build -buildmode=c-shared -o libnano64.so ./libnano64
+//
+// The generated header declares:
+//
+//	int      nano64_generate(uint64_t *out);
+//	int      nano64_generate_monotonic(uint64_t *out);
+//	int      nano64_from_parts(int64_t timestamp_ms, uint32_t random, uint64_t *out);
+//	int64_t  nano64_timestamp(uint64_t id);
+//	uint32_t nano64_random(uint64_t id);
+//	int64_t  nano64_to_signed(uint64_t id);
+//	uint64_t nano64_from_signed(int64_t value);
+//	int      nano64_format(uint64_t id, char *buf, size_t len);
+//	int      nano64_parse(const char *hex, uint64_t *out);
+//
+// IDs are passed as uint64_t. Functions returning int report 0 on success and -1 on
+// failure. No memory is allocated on the caller's behalf: nano64_format writes the
+// NUL-terminated dashed hex form into a caller buffer of at least NANO64_HEX_LEN bytes.
+package main
+
+/*
+#include <stddef.h>
+#include <stdint.h>
+
+#define NANO64_HEX_LEN 18
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+
+	nano64 "github.com/pisoj/go-nano64"
+)
+
+// hexLen is the size of a formatted ID including its NUL terminator.
+const hexLen = C.NANO64_HEX_LEN
+
+//export nano64_generate
+func nano64_generate(out *C.uint64_t) C.int {
+	return store(out, nano64.GenerateDefault)
+}
+
+//export nano64_generate_monotonic
+func nano64_generate_monotonic(out *C.uint64_t) C.int {
+	return store(out, nano64.GenerateMonotonicDefault)
+}
+
+//export nano64_from_parts
+func nano64_from_parts(timestampMs C.int64_t, random C.uint32_t, out *C.uint64_t) C.int {
+	return store(out, func() (nano64.Nano64, error) {
+		return fromParts(int64(timestampMs), uint32(random))
+	})
+}
+
+//export nano64_timestamp
+func nano64_timestamp(id C.uint64_t) C.int64_t {
+	return C.int64_t(nano64.New(uint64(id)).GetTimestamp())
+}
+
+//export nano64_random
+func nano64_random(id C.uint64_t) C.uint32_t {
+	return C.uint32_t(nano64.New(uint64(id)).GetRandom())
+}
+
+//export nano64_to_signed
+func nano64_to_signed(id C.uint64_t) C.int64_t {
+	return C.int64_t(nano64.SignedNano64.FromId(nano64.New(uint64(id))))
+}
+
+//export nano64_from_signed
+func nano64_from_signed(value C.int64_t) C.uint64_t {
+	return C.uint64_t(nano64.SignedNano64.ToId(int64(value)).Uint64Value())
+}
+
+//export nano64_format
+func nano64_format(id C.uint64_t, buf *C.char, size C.size_t) C.int {
+	if buf == nil || size < hexLen {
+		return -1
+	}
+	dst := unsafe.Slice((*byte)(unsafe.Pointer(buf)), hexLen)
+	formatInto(dst, nano64.New(uint64(id)))
+	return 0
+}
+
+//export nano64_parse
+func nano64_parse(hex *C.char, out *C.uint64_t) C.int {
+	if hex == nil {
+		return -1
+	}
+	s := C.GoString(hex)
+	return store(out, func() (nano64.Nano64, error) { return nano64.FromHex(s) })
+}
+
+// store writes the ID returned by fn to out and converts the outcome to a status code.
+func store(out *C.uint64_t, fn func() (nano64.Nano64, error)) C.int {
+	if out == nil {
+		return -1
+	}
+	id, err := fn()
+	if err != nil {
+		return -1
+	}
+	*out = C.uint64_t(id.Uint64Value())
+	return 0
+}
+
+// fromParts builds an ID from its timestamp and random fields.
+func fromParts(timestampMs int64, random uint32) (nano64.Nano64, error) {
+	if random >= 1<<nano64.RandomBits {
+		return nano64.Nil, fmt.Errorf("random exceeds %d-bit range: %d", nano64.RandomBits, random)
+	}
+	return nano64.Generate(timestampMs, func(int) (uint32, error) { return random, nil })
+}
+
+// formatInto writes the NUL-terminated dashed hex form of id to dst.
+func formatInto(dst []byte, id nano64.Nano64) {
+	dst[copy(dst, id.ToHex())] = 0
+}
+
+func main() {}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	nano64 "github.com/pisoj/go-nano64"
+)
+
+func TestFromParts(t *testing.T) {
+	id, err := fromParts(1234, 0xABCDE)
+	if err != nil {
+		t.Fatalf("fromParts() error = %v", err)
+	}
+	if id.GetTimestamp() != 1234 || id.GetRandom() != 0xABCDE {
+		t.Errorf("fromParts() = (%d, %#x), want (1234, 0xabcde)", id.GetTimestamp(), id.GetRandom())
+	}
+
+	// Out-of-range random values must be rejected rather than silently masked.
+	if _, err := fromParts(1234, 1<<nano64.RandomBits); err == nil {
+		t.Error("fromParts() expected error for oversized random field")
+	}
+}
+
+func TestFormatInto(t *testing.T) {
+	buf := make([]byte, hexLen)
+	for i := range buf {
+		buf[i] = 'x'
+	}
+	formatInto(buf, nano64.New(0x0123456789ABCDEF))
+
+	if got := string(buf); got != "0123456789A-BCDEF\x00" {
+		t.Errorf("formatInto() = %q", got)
+	}
+}
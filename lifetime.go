@@ -0,0 +1,52 @@
+package nano64
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrNearOverflow is returned by generators configured with an overflow margin
+// once the clock comes within that margin of OverflowDate.
+var ErrNearOverflow = errors.New("timestamp near 44-bit overflow")
+
+// OverflowDate returns the last instant representable in the 44-bit timestamp
+// field, in the year 2527. IDs cannot be generated after it.
+func OverflowDate() time.Time {
+	return time.UnixMilli(maxTimestamp).UTC()
+}
+
+// RemainingLifetime returns the time left until OverflowDate according to
+// DefaultClock.
+func RemainingLifetime() time.Duration {
+	return RemainingLifetimeAt(time.UnixMilli(DefaultClock()))
+}
+
+// RemainingLifetimeAt returns the time left from now until OverflowDate, or zero
+// past it. A time.Duration spans about 292 years, so results saturate at the
+// maximum Duration until the 2230s.
+func RemainingLifetimeAt(now time.Time) time.Duration {
+	remaining := maxTimestamp - now.UnixMilli()
+	switch {
+	case remaining <= 0:
+		return 0
+	case remaining > math.MaxInt64/int64(time.Millisecond):
+		return math.MaxInt64
+	default:
+		return time.Duration(remaining) * time.Millisecond
+	}
+}
+
+// CheckOverflow returns an error wrapping ErrNearOverflow if timestamp (epoch ms)
+// lies within margin of OverflowDate. A zero margin disables the check.
+func CheckOverflow(timestamp int64, margin time.Duration) error {
+	if margin <= 0 {
+		return nil
+	}
+	if maxTimestamp-timestamp < margin.Milliseconds() {
+		return fmt.Errorf("%w: %v left until %s", ErrNearOverflow,
+			RemainingLifetimeAt(time.UnixMilli(timestamp)), OverflowDate().Format(time.RFC3339))
+	}
+	return nil
+}
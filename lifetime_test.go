@@ -0,0 +1,65 @@
+package nano64
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestOverflowDate(t *testing.T) {
+	date := OverflowDate()
+	if date.Year() != 2527 {
+		t.Errorf("OverflowDate() = %v, want year 2527", date)
+	}
+	if _, err := Generate(date.UnixMilli(), DefaultRNG); err != nil {
+		t.Errorf("Generate() at OverflowDate error = %v", err)
+	}
+	if _, err := Generate(date.UnixMilli()+1, DefaultRNG); err == nil {
+		t.Error("Generate() after OverflowDate expected error")
+	}
+}
+
+func TestRemainingLifetimeAt(t *testing.T) {
+	date := OverflowDate()
+	tests := []struct {
+		now  time.Time
+		want time.Duration
+	}{
+		{date.Add(-time.Hour), time.Hour},
+		{date, 0},
+		{date.Add(time.Hour), 0},
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), math.MaxInt64},
+	}
+	for _, tt := range tests {
+		if got := RemainingLifetimeAt(tt.now); got != tt.want {
+			t.Errorf("RemainingLifetimeAt(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestCheckOverflow(t *testing.T) {
+	year := 365 * 24 * time.Hour
+	late := OverflowDate().Add(-5 * year).UnixMilli()
+
+	if err := CheckOverflow(late, 10*year); !errors.Is(err, ErrNearOverflow) {
+		t.Errorf("CheckOverflow() within margin error = %v, want ErrNearOverflow", err)
+	}
+	if err := CheckOverflow(late, year); err != nil {
+		t.Errorf("CheckOverflow() outside margin error = %v", err)
+	}
+	if err := CheckOverflow(late, 0); err != nil {
+		t.Errorf("CheckOverflow() with zero margin error = %v", err)
+	}
+
+	g, err := NewPacedGenerator(PacedOptions{
+		OverflowMargin: 10 * year,
+		Clock:          func() int64 { return late },
+	})
+	if err != nil {
+		t.Fatalf("NewPacedGenerator() error = %v", err)
+	}
+	if _, err := g.Generate(); !errors.Is(err, ErrNearOverflow) {
+		t.Errorf("Generate() error = %v, want ErrNearOverflow", err)
+	}
+}
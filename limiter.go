@@ -0,0 +1,126 @@
+package nano64
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when a key has used up its ID quota for the current
+// window, and wrapped by a Generator that reached its MaxPerMillisecond.
+var ErrRateLimited = errors.New("rate limited")
+
+// limiterWindow tracks one key's usage within its current window.
+type limiterWindow struct {
+	start int64
+	count int
+}
+
+// KeyedLimiter caps how many IDs may be issued per key (tenant, user, ...) in fixed
+// time windows, for services where creating an ID stands in for creating a resource.
+// Windows are aligned to multiples of the window length since the epoch.
+// A KeyedLimiter is safe for concurrent use.
+type KeyedLimiter struct {
+	limit  int
+	window int64
+	clock  Clock
+	rng    RNG
+
+	mu      sync.Mutex
+	windows map[string]*limiterWindow
+	sweepAt int64
+}
+
+// NewKeyedLimiter creates a limiter allowing limit IDs per key and window.
+// clock and rng default to DefaultClock and DefaultRNG.
+func NewKeyedLimiter(limit int, window time.Duration, clock Clock, rng RNG) (*KeyedLimiter, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+	if window < time.Millisecond {
+		return nil, fmt.Errorf("window must be at least 1ms, got %v", window)
+	}
+	if clock == nil {
+		clock = DefaultClock
+	}
+	if rng == nil {
+		rng = DefaultRNG
+	}
+
+	return &KeyedLimiter{
+		limit:   limit,
+		window:  window.Milliseconds(),
+		clock:   clock,
+		rng:     rng,
+		windows: make(map[string]*limiterWindow),
+	}, nil
+}
+
+// Generate issues an ID for key, or returns ErrRateLimited if key's quota for the
+// current window is exhausted. A failed generation does not count against the quota.
+func (l *KeyedLimiter) Generate(key string) (Nano64, error) {
+	now := l.clock()
+	if err := l.take(key, now); err != nil {
+		return Nano64{}, err
+	}
+	id, err := Generate(now, l.rng)
+	if err != nil {
+		l.refund(key, now)
+		return Nano64{}, err
+	}
+	return id, nil
+}
+
+// Remaining returns how many IDs key may still be issued in the current window.
+func (l *KeyedLimiter) Remaining(key string) int {
+	now := l.clock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.windows[key]
+	if !ok || w.start != now-now%l.window {
+		return l.limit
+	}
+	return l.limit - w.count
+}
+
+// take consumes one unit of key's quota.
+func (l *KeyedLimiter) take(key string, now int64) error {
+	start := now - now%l.window
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now >= l.sweepAt {
+		// Drop keys idle for a full window so the map does not grow without bound.
+		for k, w := range l.windows {
+			if w.start < start {
+				delete(l.windows, k)
+			}
+		}
+		l.sweepAt = start + l.window
+	}
+
+	w, ok := l.windows[key]
+	if !ok || w.start != start {
+		w = &limiterWindow{start: start}
+		l.windows[key] = w
+	}
+	if w.count >= l.limit {
+		return fmt.Errorf("key %q exceeded %d IDs per %v: %w", key, l.limit, time.Duration(l.window)*time.Millisecond, ErrRateLimited)
+	}
+	w.count++
+	return nil
+}
+
+// refund returns the unit of key's quota taken at now, unless its window has
+// already been replaced.
+func (l *KeyedLimiter) refund(key string, now int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if w, ok := l.windows[key]; ok && w.start == now-now%l.window && w.count > 0 {
+		w.count--
+	}
+}
@@ -0,0 +1,78 @@
+package nano64
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKeyedLimiter(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	limiter, err := NewKeyedLimiter(2, time.Second, func() int64 { return now }, nil)
+	if err != nil {
+		t.Fatalf("NewKeyedLimiter() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := limiter.Generate("tenant-a"); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+	if _, err := limiter.Generate("tenant-a"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Generate() error = %v, want ErrRateLimited", err)
+	}
+	if got := limiter.Remaining("tenant-a"); got != 0 {
+		t.Errorf("Remaining() = %d, want 0", got)
+	}
+
+	id, err := limiter.Generate("tenant-b")
+	if err != nil {
+		t.Fatalf("Generate() for another key error = %v", err)
+	}
+	if id.GetTimestamp() != now {
+		t.Errorf("GetTimestamp() = %d, want %d", id.GetTimestamp(), now)
+	}
+
+	now += 1000
+	if _, err := limiter.Generate("tenant-a"); err != nil {
+		t.Errorf("Generate() in next window error = %v", err)
+	}
+	if got := len(limiter.windows); got != 1 {
+		t.Errorf("idle keys not swept: %d windows tracked", got)
+	}
+}
+
+func TestKeyedLimiterRefundsFailedGenerate(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	failing := true
+	rng := func(bits int) (uint32, error) {
+		if failing {
+			return 0, errors.New("entropy exhausted")
+		}
+		return 1, nil
+	}
+	limiter, err := NewKeyedLimiter(1, time.Second, func() int64 { return now }, rng)
+	if err != nil {
+		t.Fatalf("NewKeyedLimiter() error = %v", err)
+	}
+
+	if _, err := limiter.Generate("tenant-a"); err == nil || errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Generate() with a failing RNG error = %v, want the RNG's error", err)
+	}
+	if got := limiter.Remaining("tenant-a"); got != 1 {
+		t.Errorf("Remaining() after a failed Generate = %d, want 1", got)
+	}
+	failing = false
+	if _, err := limiter.Generate("tenant-a"); err != nil {
+		t.Errorf("Generate() after a failed Generate error = %v", err)
+	}
+}
+
+func TestNewKeyedLimiter_Errors(t *testing.T) {
+	if _, err := NewKeyedLimiter(0, time.Second, nil, nil); err == nil {
+		t.Error("NewKeyedLimiter() expected error for zero limit")
+	}
+	if _, err := NewKeyedLimiter(1, 0, nil, nil); err == nil {
+		t.Error("NewKeyedLimiter() expected error for zero window")
+	}
+}
@@ -0,0 +1,229 @@
+package nano64
+
+import (
+	"container/heap"
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// EvictionPolicy selects which entry an LRU evicts when it is full.
+type EvictionPolicy int
+
+const (
+	// EvictLeastRecent evicts the least recently used entry.
+	EvictLeastRecent EvictionPolicy = iota
+	// Evict2Q admits new entries to a small FIFO and promotes them to the LRU
+	// main queue only if they are requested again after being evicted from it,
+	// so one-off scans do not flush the hot set.
+	Evict2Q
+	// EvictOldestCreated evicts the entry whose ID has the oldest embedded
+	// timestamp, regardless of access; the right policy for entity caches where
+	// new entities are the ones being read.
+	EvictOldestCreated
+)
+
+// String returns the policy name.
+func (p EvictionPolicy) String() string {
+	switch p {
+	case EvictLeastRecent:
+		return "lru"
+	case Evict2Q:
+		return "2q"
+	case EvictOldestCreated:
+		return "oldest-created"
+	default:
+		return fmt.Sprintf("EvictionPolicy(%d)", int(p))
+	}
+}
+
+// LRUOptions configures NewLRU.
+type LRUOptions struct {
+	// Capacity is the maximum number of entries.
+	Capacity int
+
+	// Policy selects the eviction policy; EvictLeastRecent by default.
+	Policy EvictionPolicy
+}
+
+// lruEntry is a cached value and the queue holding it.
+type lruEntry[V any] struct {
+	id    Nano64
+	value V
+	queue *list.List
+}
+
+// LRU is a fixed-capacity cache keyed by Nano64.
+// An LRU is safe for concurrent use.
+type LRU[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	policy   EvictionPolicy
+	entries  map[Nano64]*list.Element
+
+	// main is the LRU queue, most recent at the front. Under Evict2Q, recent is
+	// the admission FIFO and ghosts remembers IDs recently evicted from it.
+	main      *list.List
+	recent    *list.List
+	recentMax int
+	ghosts    *list.List
+	ghostKeys map[Nano64]*list.Element
+
+	// created orders entries by ID under EvictOldestCreated. Removed IDs are
+	// dropped lazily when they reach the top.
+	created idHeap
+}
+
+// NewLRU creates a cache holding up to opts.Capacity entries.
+func NewLRU[V any](opts LRUOptions) (*LRU[V], error) {
+	if opts.Capacity < 1 {
+		return nil, fmt.Errorf("capacity must be positive, got %d", opts.Capacity)
+	}
+	if opts.Policy < EvictLeastRecent || opts.Policy > EvictOldestCreated {
+		return nil, fmt.Errorf("unknown eviction policy %v", opts.Policy)
+	}
+
+	c := &LRU[V]{
+		capacity: opts.Capacity,
+		policy:   opts.Policy,
+		entries:  make(map[Nano64]*list.Element),
+		main:     list.New(),
+	}
+	if opts.Policy == Evict2Q {
+		c.recent = list.New()
+		c.recentMax = max(opts.Capacity/4, 1)
+		c.ghosts = list.New()
+		c.ghostKeys = make(map[Nano64]*list.Element)
+	}
+	return c, nil
+}
+
+// Get returns the value cached for id and marks it as recently used.
+func (c *LRU[V]) Get(id Nano64) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[id]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	entry := el.Value.(*lruEntry[V])
+	if entry.queue == c.main {
+		c.main.MoveToFront(el)
+	}
+	return entry.value, true
+}
+
+// Peek returns the value cached for id without affecting eviction order.
+func (c *LRU[V]) Peek(id Nano64) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		return el.Value.(*lruEntry[V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Add caches value under id, evicting an entry if the cache is full.
+// Returns the evicted ID, if any.
+func (c *LRU[V]) Add(id Nano64, value V) (evicted Nano64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, exists := c.entries[id]; exists {
+		entry := el.Value.(*lruEntry[V])
+		entry.value = value
+		if entry.queue == c.main {
+			c.main.MoveToFront(el)
+		}
+		return Nil, false
+	}
+
+	if len(c.entries) >= c.capacity {
+		evicted, ok = c.evict()
+	}
+
+	queue := c.main
+	if c.policy == Evict2Q {
+		if ghost, wasGhost := c.ghostKeys[id]; wasGhost {
+			c.ghosts.Remove(ghost)
+			delete(c.ghostKeys, id)
+		} else {
+			queue = c.recent
+		}
+	}
+	entry := &lruEntry[V]{id: id, value: value, queue: queue}
+	c.entries[id] = queue.PushFront(entry)
+	if c.policy == EvictOldestCreated {
+		heap.Push(&c.created, id)
+	}
+	return evicted, ok
+}
+
+// evict removes one entry according to the policy.
+func (c *LRU[V]) evict() (Nano64, bool) {
+	switch c.policy {
+	case EvictOldestCreated:
+		for c.created.Len() > 0 {
+			id := heap.Pop(&c.created).(Nano64)
+			if el, ok := c.entries[id]; ok {
+				c.remove(el)
+				return id, true
+			}
+		}
+		return Nil, false
+	case Evict2Q:
+		if c.recent.Len() > 0 && (c.recent.Len() >= c.recentMax || c.main.Len() == 0) {
+			id := c.remove(c.recent.Back())
+			c.ghostKeys[id] = c.ghosts.PushFront(id)
+			if c.ghosts.Len() > max(c.capacity/2, 1) {
+				oldest := c.ghosts.Remove(c.ghosts.Back()).(Nano64)
+				delete(c.ghostKeys, oldest)
+			}
+			return id, true
+		}
+	}
+	if back := c.main.Back(); back != nil {
+		return c.remove(back), true
+	}
+	return Nil, false
+}
+
+// remove unlinks an entry and returns its ID.
+func (c *LRU[V]) remove(el *list.Element) Nano64 {
+	entry := el.Value.(*lruEntry[V])
+	entry.queue.Remove(el)
+	delete(c.entries, entry.id)
+	return entry.id
+}
+
+// Remove drops id from the cache and reports whether it was present.
+func (c *LRU[V]) Remove(id Nano64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[id]
+	if !ok {
+		return false
+	}
+	c.remove(el)
+	// Compact the lazily pruned heap once stale IDs outnumber live ones.
+	if c.policy == EvictOldestCreated && c.created.Len() > 2*len(c.entries)+16 {
+		c.created = c.created[:0]
+		for id := range c.entries {
+			c.created = append(c.created, id)
+		}
+		heap.Init(&c.created)
+	}
+	return true
+}
+
+// Len returns the number of cached entries.
+func (c *LRU[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,109 @@
+package nano64
+
+import "testing"
+
+func TestLRUPolicies(t *testing.T) {
+	a := mustGenerate(t, 1000, 1)
+	b := mustGenerate(t, 2000, 1)
+	c := mustGenerate(t, 3000, 1)
+	d := mustGenerate(t, 4000, 1)
+
+	tests := []struct {
+		policy EvictionPolicy
+		want   Nano64
+	}{
+		// b is added first, so it is least recent once a is read.
+		{EvictLeastRecent, b},
+		// a is read but was still created first.
+		{EvictOldestCreated, a},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			cache, err := NewLRU[string](LRUOptions{Capacity: 3, Policy: tt.policy})
+			if err != nil {
+				t.Fatalf("NewLRU() error = %v", err)
+			}
+			cache.Add(b, "b")
+			cache.Add(a, "a")
+			cache.Add(c, "c")
+			cache.Get(b)
+			cache.Get(a)
+			cache.Get(c)
+
+			evicted, ok := cache.Add(d, "d")
+			if !ok || evicted != tt.want {
+				t.Errorf("Add() evicted %v, %v; want %v", evicted, ok, tt.want)
+			}
+			if _, ok := cache.Peek(tt.want); ok {
+				t.Error("evicted entry is still cached")
+			}
+			if cache.Len() != 3 {
+				t.Errorf("Len() = %d, want 3", cache.Len())
+			}
+		})
+	}
+}
+
+func TestLRU2QResistsScans(t *testing.T) {
+	cache, err := NewLRU[int](LRUOptions{Capacity: 8, Policy: Evict2Q})
+	if err != nil {
+		t.Fatalf("NewLRU() error = %v", err)
+	}
+
+	// Evict the hot IDs from the admission queue once so they are promoted to
+	// the main queue when added again.
+	hot := []Nano64{mustGenerate(t, 1, 0), mustGenerate(t, 2, 0)}
+	for _, id := range hot {
+		cache.Add(id, 0)
+	}
+	for i := range 8 {
+		cache.Add(mustGenerate(t, int64(100+i), 0), i)
+	}
+	for _, id := range hot {
+		cache.Add(id, 0)
+	}
+
+	// A long scan of one-off IDs churns only the admission queue.
+	for i := range 100 {
+		cache.Add(mustGenerate(t, int64(1000+i), 0), i)
+	}
+	for _, id := range hot {
+		if _, ok := cache.Get(id); !ok {
+			t.Errorf("hot ID %v was evicted by a scan", id)
+		}
+	}
+}
+
+func TestLRURemove(t *testing.T) {
+	cache, _ := NewLRU[int](LRUOptions{Capacity: 2, Policy: EvictOldestCreated})
+	a, b, c := mustGenerate(t, 1, 0), mustGenerate(t, 2, 0), mustGenerate(t, 3, 0)
+
+	for range 100 {
+		cache.Add(a, 1)
+		if !cache.Remove(a) {
+			t.Fatal("Remove() = false for cached ID")
+		}
+	}
+	if cache.Remove(a) {
+		t.Error("Remove() = true for missing ID")
+	}
+
+	cache.Add(b, 2)
+	cache.Add(c, 3)
+	cache.Add(a, 1)
+	if _, ok := cache.Peek(a); !ok {
+		t.Error("re-added ID missing after eviction")
+	}
+	if _, ok := cache.Peek(b); ok {
+		t.Error("oldest remaining ID was not evicted")
+	}
+}
+
+func TestNewLRUValidation(t *testing.T) {
+	if _, err := NewLRU[int](LRUOptions{}); err == nil {
+		t.Error("NewLRU() expected error for zero capacity")
+	}
+	if _, err := NewLRU[int](LRUOptions{Capacity: 1, Policy: 99}); err == nil {
+		t.Error("NewLRU() expected error for unknown policy")
+	}
+}
@@ -0,0 +1,80 @@
+package nano64
+
+import "bytes"
+
+// Versioned is a value carrying the Revision of its last write, such as a
+// replicated record.
+type Versioned interface {
+	Revision() Revision
+}
+
+// LWWTiebreaker is implemented by Versioned values that can tell apart two writes
+// stamped with the same revision, which happens when two replicas stamp a write
+// with a revision they both derived, or their clocks and random fields collide.
+// TiebreakKey returns a stable encoding of the write, such as its payload or the
+// ID of the replica that made it; the write with the larger key wins.
+type LWWTiebreaker interface {
+	TiebreakKey() []byte
+}
+
+// ResolveLWW resolves concurrent versions of one entity by last-writer-wins: it
+// returns the version with the later revision timestamp and, for revisions
+// stamped in the same millisecond, the one with the larger random field. For
+// identical revisions it prefers the larger TiebreakKey when both versions
+// implement LWWTiebreaker; versions that do not are taken to be the same write,
+// and a is returned. The choice otherwise depends only on the versions, not the
+// argument order, so replicas resolving the same conflict agree.
+//
+// Last-writer-wins discards the losing write; it suits fields where the latest
+// value is the right one, such as settings or profile data, not counters.
+func ResolveLWW(a, b Versioned) Versioned {
+	if compareLWW(a, b) < 0 {
+		return b
+	}
+	return a
+}
+
+// compareLWW orders versions by revision timestamp, then by random field, then
+// by TiebreakKey when both have one.
+func compareLWW(a, b Versioned) int {
+	ar, br := a.Revision(), b.Revision()
+	at, bt := ar.id.GetTimestamp(), br.id.GetTimestamp()
+	switch {
+	case at < bt:
+		return -1
+	case at > bt:
+		return 1
+	}
+	switch arand, brand := ar.id.GetRandom(), br.id.GetRandom(); {
+	case arand < brand:
+		return -1
+	case arand > brand:
+		return 1
+	}
+	ak, aok := a.(LWWTiebreaker)
+	bk, bok := b.(LWWTiebreaker)
+	if !aok || !bok {
+		return 0
+	}
+	return bytes.Compare(ak.TiebreakKey(), bk.TiebreakKey())
+}
+
+// MergeLWW merges two replicas of a keyed collection, resolving entities
+// present in both with ResolveLWW. It returns a new map and the keys whose
+// version in local lost to remote, which the local side should apply. Replicas
+// merging each other's collections converge as long as versions with equal
+// revisions are either the same write or implement LWWTiebreaker.
+func MergeLWW[K comparable, V Versioned](local, remote map[K]V) (merged map[K]V, updated []K) {
+	merged = make(map[K]V, max(len(local), len(remote)))
+	for k, v := range local {
+		merged[k] = v
+	}
+	for k, r := range remote {
+		l, ok := local[k]
+		if !ok || compareLWW(l, r) < 0 {
+			merged[k] = r
+			updated = append(updated, k)
+		}
+	}
+	return merged, updated
+}
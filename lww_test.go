@@ -0,0 +1,100 @@
+package nano64
+
+import (
+	"slices"
+	"testing"
+)
+
+// lwwValue is a Versioned test value.
+type lwwValue struct {
+	name string
+	rev  Revision
+}
+
+func (v lwwValue) Revision() Revision { return v.rev }
+
+// lwwReplicaValue is a Versioned test value tie-broken by its replica name.
+type lwwReplicaValue struct {
+	replica string
+	rev     Revision
+}
+
+func (v lwwReplicaValue) Revision() Revision  { return v.rev }
+func (v lwwReplicaValue) TiebreakKey() []byte { return []byte(v.replica) }
+
+func TestResolveLWW(t *testing.T) {
+	rev := func(ts int64, random uint32) Revision { return RevisionOf(mustGenerate(t, ts, random)) }
+	tests := []struct {
+		name string
+		a, b lwwValue
+		want string
+	}{
+		{"later timestamp", lwwValue{"a", rev(2000, 1)}, lwwValue{"b", rev(1000, 9)}, "a"},
+		{"same millisecond", lwwValue{"a", rev(1000, 3)}, lwwValue{"b", rev(1000, 7)}, "b"},
+		{"identical", lwwValue{"a", rev(1000, 3)}, lwwValue{"b", rev(1000, 3)}, "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveLWW(tt.a, tt.b).(lwwValue); got.name != tt.want {
+				t.Errorf("ResolveLWW() = %s, want %s", got.name, tt.want)
+			}
+			if tt.a.rev != tt.b.rev && ResolveLWW(tt.b, tt.a) != ResolveLWW(tt.a, tt.b) {
+				t.Error("ResolveLWW() depends on the argument order")
+			}
+		})
+	}
+}
+
+func TestMergeLWW(t *testing.T) {
+	rev := func(ts int64) Revision { return RevisionOf(mustGenerate(t, ts, 0)) }
+	local := map[string]lwwValue{
+		"kept":    {"local", rev(2000)},
+		"stale":   {"local", rev(1000)},
+		"private": {"local", rev(1000)},
+	}
+	remote := map[string]lwwValue{
+		"kept":  {"remote", rev(1500)},
+		"stale": {"remote", rev(3000)},
+		"new":   {"remote", rev(1000)},
+	}
+	merged, updated := MergeLWW(local, remote)
+	want := map[string]string{"kept": "local", "stale": "remote", "private": "local", "new": "remote"}
+	if len(merged) != len(want) {
+		t.Errorf("MergeLWW() has %d entries, want %d", len(merged), len(want))
+	}
+	for k, name := range want {
+		if merged[k].name != name {
+			t.Errorf("merged[%s] = %s, want %s", k, merged[k].name, name)
+		}
+	}
+	slices.Sort(updated)
+	if !slices.Equal(updated, []string{"new", "stale"}) {
+		t.Errorf("MergeLWW() updated = %v, want [new stale]", updated)
+	}
+	if local["stale"].name != "local" {
+		t.Error("MergeLWW() modified its input")
+	}
+}
+
+func TestLWWTiebreak(t *testing.T) {
+	rev := RevisionOf(mustGenerate(t, 1000, 3))
+	a, b := lwwReplicaValue{"replica-a", rev}, lwwReplicaValue{"replica-b", rev}
+	if got := ResolveLWW(a, b); got != b {
+		t.Errorf("ResolveLWW(a, b) = %v, want the larger tiebreak key", got)
+	}
+	if got := ResolveLWW(b, a); got != b {
+		t.Errorf("ResolveLWW(b, a) = %v, want the larger tiebreak key", got)
+	}
+
+	// Each replica merges the other's collection; both must end up equal.
+	onA := map[string]lwwReplicaValue{"k": a}
+	onB := map[string]lwwReplicaValue{"k": b}
+	mergedA, updatedA := MergeLWW(onA, onB)
+	mergedB, updatedB := MergeLWW(onB, onA)
+	if mergedA["k"] != mergedB["k"] {
+		t.Errorf("replicas diverged: %v and %v", mergedA["k"], mergedB["k"])
+	}
+	if len(updatedA) != 1 || len(updatedB) != 0 {
+		t.Errorf("MergeLWW() updated %v and %v, want [k] and []", updatedA, updatedB)
+	}
+}
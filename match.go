@@ -0,0 +1,85 @@
+package nano64
+
+import "regexp"
+
+var (
+	// IDRegexp matches exactly the strings accepted by FromHex (see OpenAPIPattern).
+	IDRegexp = regexp.MustCompile(OpenAPIPattern)
+
+	// CanonicalIDRegexp matches exactly the dashed uppercase form produced by ToHex.
+	CanonicalIDRegexp = regexp.MustCompile(`^[0-9A-F]{11}-[0-9A-F]{5}$`)
+
+	// IDSearchRegexp finds candidate IDs, dashed or plain, within free text.
+	// FindIDs finds the same candidates without the regexp engine.
+	IDSearchRegexp = regexp.MustCompile(`\b[0-9A-Fa-f]{11}-?[0-9A-Fa-f]{5}\b`)
+)
+
+// isWordByte reports whether c is a regexp word character, [0-9A-Za-z_].
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// matchIDAt returns the length of the dashed or plain hex ID at the start of b,
+// or 0 if b does not start with one followed by a word boundary.
+func matchIDAt(b []byte) int {
+	if len(b) < 16 {
+		return 0
+	}
+	n := 16
+	if b[11] == '-' {
+		n = 17
+		if len(b) < n {
+			return 0
+		}
+	}
+	if len(b) > n && isWordByte(b[n]) {
+		return 0
+	}
+	if _, ok := decodeCanonicalHex(b[:n]); !ok {
+		return 0
+	}
+	return n
+}
+
+// LooksLikeID reports whether b holds a single ID in a form FromHex accepts:
+// dashed or plain hex in either case, with an optional 0x prefix. It matches the
+// same input as IDRegexp at a fraction of the cost.
+func LooksLikeID(b []byte) bool {
+	if len(b) > 2 && b[0] == '0' && (b[1] == 'x' || b[1] == 'X') {
+		b = b[2:]
+	}
+	return len(b) >= 16 && matchIDAt(b) == len(b)
+}
+
+// FindIDs returns the IDs found in free text such as logs or tickets, in order
+// of appearance: every dashed or plain hex ID standing as a separate word, as
+// matched by IDSearchRegexp. Runs of hex longer than an ID are skipped.
+func FindIDs(text []byte) []Nano64 {
+	var ids []Nano64
+	scanIDs(text, func(start, n int) bool {
+		value, _ := decodeCanonicalHex(text[start : start+n])
+		ids = append(ids, Nano64{value: value})
+		return true
+	})
+	return ids
+}
+
+// scanIDs calls fn with the offset and length of every ID FindIDs would return,
+// stopping early if fn returns false.
+func scanIDs(text []byte, fn func(start, n int) bool) {
+	for i := 0; i < len(text); {
+		if hexValues[text[i]] == 0xFF || (i > 0 && isWordByte(text[i-1])) {
+			i++
+			continue
+		}
+		n := matchIDAt(text[i:])
+		if n == 0 {
+			i++
+			continue
+		}
+		if !fn(i, n) {
+			return
+		}
+		i += n
+	}
+}
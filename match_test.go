@@ -0,0 +1,67 @@
+package nano64
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLooksLikeID(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"0199C6A1F5E-3B2D4", true},
+		{"0199c6a1f5e3b2d4", true},
+		{"0x0199C6A1F5E3B2D4", true},
+		{"0199C6A1F5E-3B2D", false},
+		{"0199C6A1F5E3B2D45", false},
+		{"0199C6A1F5-E3B2D4", false},
+		{"0199C6A1F5E-3B2DG", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := LooksLikeID([]byte(tt.in)); got != tt.want {
+			t.Errorf("LooksLikeID(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+		if got := IDRegexp.MatchString(tt.in); got != tt.want {
+			t.Errorf("IDRegexp.MatchString(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	id, _ := GenerateDefault()
+	if !CanonicalIDRegexp.MatchString(id.ToHex()) {
+		t.Errorf("CanonicalIDRegexp does not match %s", id.ToHex())
+	}
+}
+
+func TestFindIDs(t *testing.T) {
+	a, _ := FromHex("0199C6A1F5E-3B2D4")
+	b, _ := FromHex("0199C6A1F60-00001")
+	text := "order 0199C6A1F5E-3B2D4 failed; retried as 0199c6a1f6000001.\n" +
+		"not ids: X0199C6A1F5E-3B2D4 0199C6A1F5E-3B2D4F deadbeefdeadbeefdeadbeef"
+
+	got := FindIDs([]byte(text))
+	if want := []Nano64{a, b}; !slices.Equal(got, want) {
+		t.Errorf("FindIDs() = %v, want %v", got, want)
+	}
+
+	var fromRegexp []Nano64
+	for _, m := range IDSearchRegexp.FindAllString(text, -1) {
+		id, err := FromHex(m)
+		if err != nil {
+			t.Fatalf("FromHex(%q) error = %v", m, err)
+		}
+		fromRegexp = append(fromRegexp, id)
+	}
+	if !slices.Equal(got, fromRegexp) {
+		t.Errorf("FindIDs() = %v, IDSearchRegexp found %v", got, fromRegexp)
+	}
+}
+
+func BenchmarkFindIDs(b *testing.B) {
+	line := []byte("2024-05-01T10:30:00Z INFO request id=0199C6A1F5E-3B2D4 user=0199C6A1F60-00001 took 12ms\n")
+	b.SetBytes(int64(len(line)))
+	for i := 0; i < b.N; i++ {
+		FindIDs(line)
+	}
+}
@@ -0,0 +1,178 @@
+package nano64
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"sort"
+)
+
+// MerkleDigest is the SHA-256 digest of a Merkle tree node.
+type MerkleDigest [sha256.Size]byte
+
+// MerkleNode identifies a node of a MerkleTree: the IDs whose top Level bits equal
+// Prefix. Level 0 is the root covering every ID; the leaves sit at the tree's depth.
+// Because IDs start with their timestamp, every node covers a contiguous time span.
+type MerkleNode struct {
+	Level  int
+	Prefix uint64
+}
+
+// Range returns the IDs covered by the node.
+func (n MerkleNode) Range() IDRange {
+	if n.Level == 0 {
+		return IDRange{First: Nil, Last: Nano64{value: ^uint64(0)}}
+	}
+	first := n.Prefix << (64 - n.Level)
+	return IDRange{First: Nano64{value: first}, Last: Nano64{value: first | (1<<(64-n.Level) - 1)}}
+}
+
+// Children returns the node's two children.
+func (n MerkleNode) Children() (MerkleNode, MerkleNode) {
+	return MerkleNode{Level: n.Level + 1, Prefix: n.Prefix << 1},
+		MerkleNode{Level: n.Level + 1, Prefix: n.Prefix<<1 | 1}
+}
+
+// merkleLeaf accumulates the entries of one leaf bucket.
+type merkleLeaf struct {
+	digest MerkleDigest
+	count  int
+}
+
+// MerkleTree summarizes an ID-keyed dataset for anti-entropy sync between replicas.
+// Entries are bucketed by the top depth bits of their ID, a timestamp prefix, and
+// each bucket's digest is the XOR of its entries' hashes, so entries can be added
+// and removed in any order. Inner nodes hash their children, so comparing digests
+// top-down finds differing buckets while exchanging few digests.
+// A MerkleTree is not safe for concurrent use.
+type MerkleTree struct {
+	depth  int
+	leaves map[uint64]*merkleLeaf
+	cache  map[MerkleNode]MerkleDigest
+}
+
+// NewMerkleTree creates an empty tree whose leaves cover the top depth bits of the
+// ID (1..TimestampBits). With depth d each leaf spans 2^(TimestampBits-d) ms.
+func NewMerkleTree(depth int) (*MerkleTree, error) {
+	if depth < 1 || depth > TimestampBits {
+		return nil, fmt.Errorf("depth must be 1-%d, got %d", TimestampBits, depth)
+	}
+	return &MerkleTree{
+		depth:  depth,
+		leaves: make(map[uint64]*merkleLeaf),
+		cache:  make(map[MerkleNode]MerkleDigest),
+	}, nil
+}
+
+// Depth returns the level of the leaves.
+func (t *MerkleTree) Depth() int {
+	return t.depth
+}
+
+// Add adds an entry. content identifies the entry's state, e.g. a row version or
+// hash, so replicas holding different versions of an ID disagree; it may be nil.
+func (t *MerkleTree) Add(id Nano64, content []byte) {
+	t.update(id, content, 1)
+}
+
+// Remove removes an entry previously added with the same content.
+func (t *MerkleTree) Remove(id Nano64, content []byte) {
+	t.update(id, content, -1)
+}
+
+// update XORs an entry's hash into its leaf and invalidates the cached ancestors.
+func (t *MerkleTree) update(id Nano64, content []byte, delta int) {
+	h := sha256.New()
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], id.value)
+	h.Write(key[:])
+	h.Write(content)
+	var entry MerkleDigest
+	h.Sum(entry[:0])
+
+	prefix := id.value >> (64 - t.depth)
+	leaf := t.leaves[prefix]
+	if leaf == nil {
+		leaf = &merkleLeaf{}
+		t.leaves[prefix] = leaf
+	}
+	for i := range leaf.digest {
+		leaf.digest[i] ^= entry[i]
+	}
+	leaf.count += delta
+	if leaf.count == 0 && leaf.digest == (MerkleDigest{}) {
+		delete(t.leaves, prefix)
+	}
+
+	for level := t.depth; level >= 0; level-- {
+		delete(t.cache, MerkleNode{Level: level, Prefix: prefix >> (t.depth - level)})
+	}
+}
+
+// Root returns the digest of the whole tree.
+func (t *MerkleTree) Root() MerkleDigest {
+	return t.Digest(MerkleNode{})
+}
+
+// Digest returns the digest of a node. Empty subtrees have the zero digest.
+func (t *MerkleTree) Digest(n MerkleNode) MerkleDigest {
+	if n.Level >= t.depth {
+		leaf := t.leaves[n.Prefix>>(n.Level-t.depth)]
+		if leaf == nil {
+			return MerkleDigest{}
+		}
+		return leaf.digest
+	}
+	if d, ok := t.cache[n]; ok {
+		return d
+	}
+
+	left, right := n.Children()
+	ld, rd := t.Digest(left), t.Digest(right)
+	var d MerkleDigest
+	if ld != (MerkleDigest{}) || rd != (MerkleDigest{}) {
+		h := sha256.New()
+		h.Write([]byte{1})
+		h.Write(ld[:])
+		h.Write(rd[:])
+		h.Sum(d[:0])
+	}
+	t.cache[n] = d
+	return d
+}
+
+// Count returns the number of entries under a node.
+func (t *MerkleTree) Count(n MerkleNode) int {
+	if n.Level >= t.depth {
+		if leaf := t.leaves[n.Prefix>>(n.Level-t.depth)]; leaf != nil {
+			return leaf.count
+		}
+		return 0
+	}
+	count := 0
+	shift := t.depth - n.Level
+	for prefix, leaf := range t.leaves {
+		if prefix>>shift == n.Prefix {
+			count += leaf.count
+		}
+	}
+	return count
+}
+
+// Leaves iterates over the non-empty leaves and their digests in ID order.
+func (t *MerkleTree) Leaves() iter.Seq2[MerkleNode, MerkleDigest] {
+	return func(yield func(MerkleNode, MerkleDigest) bool) {
+		prefixes := make([]uint64, 0, len(t.leaves))
+		for prefix := range t.leaves {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Slice(prefixes, func(i, j int) bool { return prefixes[i] < prefixes[j] })
+
+		for _, prefix := range prefixes {
+			if !yield(MerkleNode{Level: t.depth, Prefix: prefix}, t.leaves[prefix].digest) {
+				return
+			}
+		}
+	}
+}
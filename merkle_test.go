@@ -0,0 +1,77 @@
+package nano64
+
+import (
+	"testing"
+)
+
+func TestNewMerkleTreeDepth(t *testing.T) {
+	for _, depth := range []int{0, TimestampBits + 1} {
+		if _, err := NewMerkleTree(depth); err == nil {
+			t.Errorf("NewMerkleTree(%d) expected error", depth)
+		}
+	}
+}
+
+func TestMerkleTreeOrderIndependent(t *testing.T) {
+	ids := []Nano64{mustGenerate(t, 1000, 1), mustGenerate(t, 1000, 2), mustGenerate(t, 1<<40, 3)}
+
+	a, _ := NewMerkleTree(16)
+	b, _ := NewMerkleTree(16)
+	for i := range ids {
+		a.Add(ids[i], nil)
+		b.Add(ids[len(ids)-1-i], nil)
+	}
+	if a.Root() != b.Root() {
+		t.Error("Root() depends on insertion order")
+	}
+	if a.Root() == (MerkleDigest{}) {
+		t.Error("Root() of non-empty tree is zero")
+	}
+
+	b.Remove(ids[2], nil)
+	b.Add(ids[2], []byte("v2"))
+	if a.Root() == b.Root() {
+		t.Error("Root() ignores entry content")
+	}
+	b.Remove(ids[2], []byte("v2"))
+	b.Add(ids[2], nil)
+	if a.Root() != b.Root() {
+		t.Error("Root() did not return after restoring the entry")
+	}
+}
+
+func TestMerkleTreeLocalizesDifferences(t *testing.T) {
+	a, _ := NewMerkleTree(8)
+	b, _ := NewMerkleTree(8)
+	early := mustGenerate(t, 1000, 1)
+	late := mustGenerate(t, 1<<43, 1)
+	a.Add(early, nil)
+	b.Add(early, nil)
+	a.Add(late, nil)
+
+	left, right := MerkleNode{}.Children()
+	if a.Digest(left) != b.Digest(left) {
+		t.Error("left subtree differs although it holds the same entries")
+	}
+	if a.Digest(right) == b.Digest(right) {
+		t.Error("right subtree matches although only one side holds the late entry")
+	}
+	if !right.Range().Contains(late) || right.Range().Contains(early) {
+		t.Errorf("right.Range() = %v", right.Range())
+	}
+
+	if a.Count(MerkleNode{}) != 2 || a.Count(right) != 1 {
+		t.Errorf("Count() = %d/%d, want 2/1", a.Count(MerkleNode{}), a.Count(right))
+	}
+
+	var leaves int
+	for node, digest := range a.Leaves() {
+		leaves++
+		if node.Level != 8 || digest != a.Digest(node) {
+			t.Errorf("leaf %+v digest mismatch", node)
+		}
+	}
+	if leaves != 2 {
+		t.Errorf("Leaves() yielded %d leaves, want 2", leaves)
+	}
+}
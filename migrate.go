@@ -0,0 +1,277 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// defaultMigrationBatchSize is the batch size of a ColumnMigrator when
+// ColumnMigratorOptions.BatchSize is zero.
+const defaultMigrationBatchSize = 1000
+
+// migratedRandomBit is set in the random field of every converted ID. It puts
+// the Snowflake sequence field of the value at 16 or above, so Classify never
+// mistakes a converted ID for a Snowflake and converts it twice.
+const migratedRandomBit = 1 << 4
+
+// ColumnMigratorOptions configures a ColumnMigrator.
+type ColumnMigratorOptions struct {
+	Dialect Dialect
+
+	// Table and Column name the integer column to migrate. Required.
+	Table  string
+	Column string
+
+	// Signed stores IDs as SignedNano64 values, the recommended form for BIGINT
+	// columns; otherwise the column holds the raw 64 bits. Applies both to
+	// recognizing rows that are already Nano64 and to writing converted ones.
+	Signed bool
+
+	// BatchSize is the number of rows read per query. Zero means 1000.
+	BatchSize int
+
+	// Classifier recognizes the formats; nil means DefaultClassifier.
+	Classifier *Classifier
+
+	// Journal receives an "old,new,kind" line per converted row, with the old
+	// and new column values in decimal, before the row is updated. Required.
+	// Keep it: it is the mapping for rewriting foreign keys, and Resume reads
+	// it to continue an interrupted run.
+	Journal io.Writer
+}
+
+// MigrationStats counts the rows a ColumnMigrator has processed.
+type MigrationStats struct {
+	// Scanned is the number of rows read, by Kind. Rows already in Nano64 form
+	// count as KindNano64.
+	Scanned map[Kind]int64
+	// Converted is the number of rows rewritten to Nano64.
+	Converted int64
+}
+
+// ColumnMigrator converts a column holding several generations of 64-bit IDs to
+// Nano64 in place. It streams the table in column order, classifies every value
+// and rewrites Snowflakes and raw Unix timestamps to Nano64s with the same
+// millisecond; Nano64s and values of no recognized format are left alone.
+//
+// Converted IDs are deterministic: the random field is derived from the old
+// value, so a rerun maps every row to the same ID. Without Signed, converted IDs
+// usually sort above the rows still to come and are read again and skipped.
+//
+// A ColumnMigrator is not safe for concurrent use.
+type ColumnMigrator struct {
+	opts       ColumnMigratorOptions
+	classifier Classifier
+
+	started bool
+	cursor  int64
+	stats   MigrationStats
+}
+
+// NewColumnMigrator creates a ColumnMigrator.
+func NewColumnMigrator(opts ColumnMigratorOptions) (*ColumnMigrator, error) {
+	if err := opts.Dialect.validate(); err != nil {
+		return nil, err
+	}
+	if opts.Table == "" || opts.Column == "" {
+		return nil, fmt.Errorf("migration table and column are required")
+	}
+	if opts.Journal == nil {
+		return nil, fmt.Errorf("migration journal is required")
+	}
+	if opts.BatchSize < 0 {
+		return nil, fmt.Errorf("batch size must not be negative, got %d", opts.BatchSize)
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = defaultMigrationBatchSize
+	}
+	m := &ColumnMigrator{opts: opts, classifier: DefaultClassifier, stats: MigrationStats{Scanned: make(map[Kind]int64)}}
+	if opts.Classifier != nil {
+		m.classifier = *opts.Classifier
+	}
+	return m, nil
+}
+
+// Stats returns the counts of this run, not including rows processed before Resume.
+func (m *ColumnMigrator) Stats() MigrationStats {
+	stats := MigrationStats{Scanned: make(map[Kind]int64, len(m.stats.Scanned)), Converted: m.stats.Converted}
+	for kind, n := range m.stats.Scanned {
+		stats.Scanned[kind] = n
+	}
+	return stats
+}
+
+// Convert returns the Nano64 a column value is migrated to and its classified
+// kind, or false if the value is left alone.
+func (m *ColumnMigrator) Convert(old int64) (Nano64, Kind, bool) {
+	raw := uint64(old)
+	if m.opts.Signed && m.classifier.Classify(raw^signBit) == KindNano64 {
+		return Nil, KindNano64, false
+	}
+	ts, kind, ok := m.classifier.Timestamp(raw)
+	if !ok || kind == KindNano64 {
+		return Nil, kind, false
+	}
+	random := mix64(raw)&randomMask | migratedRandomBit
+	return Nano64{value: uint64(ts)<<timestampShift | random}, kind, true
+}
+
+// encode returns the column value of id.
+func (m *ColumnMigrator) encode(id Nano64) int64 {
+	if m.opts.Signed {
+		return SignedNano64.FromId(id)
+	}
+	return int64(id.value)
+}
+
+// updateQuery returns the statement rewriting one row.
+func (m *ColumnMigrator) updateQuery() string {
+	d := m.opts.Dialect
+	column := d.QuoteIdent(m.opts.Column)
+	return fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s = %s",
+		d.QuoteIdent(m.opts.Table), column, d.Placeholder(1), column, d.Placeholder(2))
+}
+
+// Step migrates the next batch and reports whether the end of the table was
+// reached. The batch's journal lines are written in a single Write before its
+// rows are updated; pass a *sql.Tx as db to commit every batch atomically.
+func (m *ColumnMigrator) Step(ctx context.Context, db interface {
+	Execer
+	Querier
+}) (bool, error) {
+	d := m.opts.Dialect
+	column := d.QuoteIdent(m.opts.Column)
+	query := fmt.Sprintf("SELECT %s FROM %s", column, d.QuoteIdent(m.opts.Table))
+	var args []any
+	if m.started {
+		query += fmt.Sprintf(" WHERE %s > %s", column, d.Placeholder(1))
+		args = append(args, m.cursor)
+	}
+	query += fmt.Sprintf(" ORDER BY %s LIMIT %d", column, m.opts.BatchSize)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("migration query failed: %w", err)
+	}
+	var olds []int64
+	for rows.Next() {
+		var old int64
+		if err := rows.Scan(&old); err != nil {
+			rows.Close()
+			return false, fmt.Errorf("failed to scan migration row: %w", err)
+		}
+		olds = append(olds, old)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("migration iteration failed: %w", err)
+	}
+
+	type rewrite struct{ old, converted int64 }
+	var rewrites []rewrite
+	var journal []byte
+	for _, old := range olds {
+		id, kind, ok := m.Convert(old)
+		m.stats.Scanned[kind]++
+		if !ok {
+			continue
+		}
+		u := rewrite{old, m.encode(id)}
+		rewrites = append(rewrites, u)
+		journal = strconv.AppendInt(journal, u.old, 10)
+		journal = append(journal, ',')
+		journal = strconv.AppendInt(journal, u.converted, 10)
+		journal = append(journal, ',')
+		journal = append(journal, kind.String()...)
+		journal = append(journal, '\n')
+	}
+	if len(journal) > 0 {
+		if _, err := m.opts.Journal.Write(journal); err != nil {
+			return false, fmt.Errorf("failed to write migration journal: %w", err)
+		}
+	}
+
+	update := m.updateQuery()
+	for _, u := range rewrites {
+		if _, err := db.ExecContext(ctx, update, u.converted, u.old); err != nil {
+			return false, fmt.Errorf("failed to migrate %d: %w", u.old, err)
+		}
+		m.stats.Converted++
+	}
+
+	if len(olds) > 0 {
+		m.started = true
+		m.cursor = olds[len(olds)-1]
+	}
+	return len(olds) < m.opts.BatchSize, nil
+}
+
+// Run migrates batches until the end of the table.
+func (m *ColumnMigrator) Run(ctx context.Context, db interface {
+	Execer
+	Querier
+}) (MigrationStats, error) {
+	for {
+		done, err := m.Step(ctx, db)
+		if err != nil {
+			return m.Stats(), err
+		}
+		if done {
+			return m.Stats(), nil
+		}
+	}
+}
+
+// Resume continues an interrupted run from its journal. It moves past the last
+// journaled row and reapplies the final batch's updates, which may not all have
+// run; updates that did run match no row and have no effect. Rows after the last
+// converted one are scanned again.
+func (m *ColumnMigrator) Resume(ctx context.Context, db Execer, journal io.Reader) error {
+	type entry struct{ old, converted int64 }
+	var tail []entry // the last BatchSize entries
+	scanner := bufio.NewScanner(journal)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		if len(fields) != 3 {
+			return fmt.Errorf("journal line %d: expected old,new,kind", line)
+		}
+		old, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("journal line %d: %w", line, err)
+		}
+		converted, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("journal line %d: %w", line, err)
+		}
+		if len(tail) == m.opts.BatchSize {
+			tail = tail[1:]
+		}
+		tail = append(tail, entry{old, converted})
+		if !m.started || old > m.cursor {
+			m.started, m.cursor = true, old
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read migration journal: %w", err)
+	}
+
+	update := m.updateQuery()
+	for _, e := range tail {
+		if _, err := db.ExecContext(ctx, update, e.converted, e.old); err != nil {
+			return fmt.Errorf("failed to reapply migration of %d: %w", e.old, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestColumnMigrator(t *testing.T) {
+	const ms = int64(1_700_000_000_000)
+	snowflake := int64(uint64(ms-TwitterSnowflakeEpoch)<<snowflakeTimeShift | 7<<snowflakeSequenceBits | 1)
+	existing := SignedNano64.FromId(mustGenerate(t, ms, 0x12345))
+	values := []int64{
+		ms / 1000,          // Unix seconds
+		ms + 1,             // Unix milliseconds
+		snowflake,          // Snowflake
+		snowflake + 1,      // Snowflake, next sequence
+		existing,           // already migrated
+		-0x1234567890ABCDE, // random
+	}
+	wantTimestamps := map[int64]int64{ms / 1000: ms, ms + 1: ms + 1, snowflake: ms, snowflake + 1: ms}
+
+	setup := func(t *testing.T) *sql.DB {
+		db, err := sql.Open("sqlite", ":memory:")
+		if err != nil {
+			t.Fatalf("failed to open sqlite: %v", err)
+		}
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		if _, err := db.Exec(`CREATE TABLE events (id INTEGER PRIMARY KEY)`); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+		for _, v := range values {
+			if _, err := db.Exec("INSERT INTO events (id) VALUES (?)", v); err != nil {
+				t.Fatalf("insert failed: %v", err)
+			}
+		}
+		return db
+	}
+	column := func(t *testing.T, db *sql.DB) map[int64]bool {
+		rows, err := db.Query("SELECT id FROM events")
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		defer rows.Close()
+		got := make(map[int64]bool)
+		for rows.Next() {
+			var v int64
+			if err := rows.Scan(&v); err != nil {
+				t.Fatalf("scan failed: %v", err)
+			}
+			got[v] = true
+		}
+		return got
+	}
+	opts := func(journal *bytes.Buffer) ColumnMigratorOptions {
+		return ColumnMigratorOptions{Dialect: DialectSQLite, Table: "events", Column: "id", Signed: true, BatchSize: 2, Journal: journal}
+	}
+	ctx := context.Background()
+
+	db := setup(t)
+	var journal bytes.Buffer
+	m, err := NewColumnMigrator(opts(&journal))
+	if err != nil {
+		t.Fatalf("NewColumnMigrator() error = %v", err)
+	}
+	stats, err := m.Run(ctx, db)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if stats.Converted != 4 || stats.Scanned[KindSnowflake] != 2 || stats.Scanned[KindRandom] != 1 || stats.Scanned[KindNano64] != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	want := map[int64]bool{existing: true, -0x1234567890ABCDE: true}
+	for old, ts := range wantTimestamps {
+		id, _, ok := m.Convert(old)
+		if !ok || id.GetTimestamp() != ts {
+			t.Errorf("Convert(%d) = %v with timestamp %d, want timestamp %d", old, ok, id.GetTimestamp(), ts)
+		}
+		want[SignedNano64.FromId(id)] = true
+	}
+	if got := column(t, db); len(got) != len(want) {
+		t.Errorf("column = %v, want %v", got, want)
+	} else {
+		for v := range want {
+			if !got[v] {
+				t.Errorf("column lacks %d", v)
+			}
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(journal.String()), "\n")
+	if len(lines) != 4 || !strings.HasSuffix(lines[0], ",unix-seconds") {
+		t.Errorf("journal = %q", journal.String())
+	}
+
+	// A second run finds nothing to do.
+	again, _ := NewColumnMigrator(opts(new(bytes.Buffer)))
+	if stats, err := again.Run(ctx, db); err != nil || stats.Converted != 0 {
+		t.Errorf("rerun converted %d rows, error = %v", stats.Converted, err)
+	}
+
+	// Resume after a crash that journaled the first batch but updated nothing.
+	crashed := setup(t)
+	resumed, _ := NewColumnMigrator(opts(&journal))
+	firstBatch := strings.Join(lines[:2], "\n")
+	if err := resumed.Resume(ctx, crashed, strings.NewReader(firstBatch)); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if _, err := resumed.Run(ctx, crashed); err != nil {
+		t.Fatalf("Run() after Resume error = %v", err)
+	}
+	if got := column(t, crashed); len(got) != len(want) {
+		t.Errorf("resumed column = %v, want %v", got, want)
+	} else {
+		for v := range want {
+			if !got[v] {
+				t.Errorf("resumed column lacks %d", v)
+			}
+		}
+	}
+
+	if err := resumed.Resume(ctx, crashed, strings.NewReader("1,2\n")); err == nil {
+		t.Error("Resume() accepted a malformed journal")
+	}
+	if _, err := NewColumnMigrator(ColumnMigratorOptions{Table: "events", Column: "id"}); err == nil {
+		t.Error("NewColumnMigrator() accepted a missing journal")
+	}
+}
@@ -0,0 +1,111 @@
+package nano64
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"os"
+	"sort"
+	"time"
+)
+
+// MMapIDs is a read-only view of a file of IDs stored back to back as 8
+// big-endian bytes each, as written by ToBytes. On Unix systems the file is
+// memory-mapped, so multi-gigabyte dumps are paged in on demand rather than
+// loaded; elsewhere it is read into memory.
+//
+// The search and range methods require the file to be sorted in ascending order.
+// An MMapIDs is safe for concurrent use; it must not be used after Close.
+type MMapIDs struct {
+	data  []byte
+	unmap func([]byte) error
+}
+
+// OpenMMapIDs maps the file at path.
+func OpenMMapIDs(path string) (*MMapIDs, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size%8 != 0 {
+		return nil, fmt.Errorf("%s: size %d is not a multiple of 8 bytes", path, size)
+	}
+	if size == 0 {
+		return &MMapIDs{}, nil
+	}
+
+	data, unmap, err := mapFile(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map %s: %w", path, err)
+	}
+	return &MMapIDs{data: data, unmap: unmap}, nil
+}
+
+// Close releases the mapping.
+func (m *MMapIDs) Close() error {
+	data := m.data
+	m.data = nil
+	if data == nil || m.unmap == nil {
+		return nil
+	}
+	return m.unmap(data)
+}
+
+// Len returns the number of IDs in the file.
+func (m *MMapIDs) Len() int {
+	return len(m.data) / 8
+}
+
+// At returns the i-th ID. It panics if i is out of range.
+func (m *MMapIDs) At(i int) Nano64 {
+	return Nano64{value: binary.BigEndian.Uint64(m.data[i*8 : i*8+8])}
+}
+
+// Search returns the index of the first ID greater than or equal to id, or Len()
+// if there is none.
+func (m *MMapIDs) Search(id Nano64) int {
+	return sort.Search(m.Len(), func(i int) bool { return m.At(i).value >= id.value })
+}
+
+// SearchTime returns the index of the first ID created at or after t, or Len()
+// if there is none.
+func (m *MMapIDs) SearchTime(t time.Time) int {
+	ms := max(t.UnixMilli(), 0)
+	if ms > maxTimestamp {
+		return m.Len()
+	}
+	return m.Search(Nano64{value: uint64(ms) << timestampShift})
+}
+
+// Range iterates over the indexes and values of the IDs within r.
+func (m *MMapIDs) Range(r IDRange) iter.Seq2[int, Nano64] {
+	return func(yield func(int, Nano64) bool) {
+		if r.Empty() {
+			return
+		}
+		for i := m.Search(r.First); i < m.Len(); i++ {
+			id := m.At(i)
+			if id.value > r.Last.value || !yield(i, id) {
+				return
+			}
+		}
+	}
+}
+
+// All iterates over every ID in file order.
+func (m *MMapIDs) All() iter.Seq2[int, Nano64] {
+	return func(yield func(int, Nano64) bool) {
+		for i := 0; i < m.Len(); i++ {
+			if !yield(i, m.At(i)) {
+				return
+			}
+		}
+	}
+}
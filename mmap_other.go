@@ -0,0 +1,18 @@
+//go:build !unix || tinygo || nano64_tiny
+
+package nano64
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the first size bytes of f, where memory mapping is unavailable
+// or, in tiny builds, left out.
+func mapFile(f *os.File, size int64) ([]byte, func([]byte) error, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, nil, err
+	}
+	return data, nil, nil
+}
@@ -0,0 +1,95 @@
+package nano64
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeIDFile(t *testing.T, ids []Nano64) string {
+	t.Helper()
+	data := make([]byte, 0, len(ids)*8)
+	for _, id := range ids {
+		data = append(data, id.ToBytes()...)
+	}
+	path := filepath.Join(t.TempDir(), "ids.bin")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMMapIDs(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	ids := make([]Nano64, 1000)
+	for i := range ids {
+		ids[i] = mustGenerate(t, base.Add(time.Duration(i)*time.Second).UnixMilli(), uint32(i))
+	}
+
+	m, err := OpenMMapIDs(writeIDFile(t, ids))
+	if err != nil {
+		t.Fatalf("OpenMMapIDs() error = %v", err)
+	}
+	defer m.Close()
+
+	if m.Len() != len(ids) {
+		t.Fatalf("Len() = %d, want %d", m.Len(), len(ids))
+	}
+	if m.At(500) != ids[500] {
+		t.Errorf("At(500) = %v, want %v", m.At(500), ids[500])
+	}
+
+	tests := []struct {
+		at   time.Time
+		want int
+	}{
+		{base.Add(-time.Hour), 0},
+		{base.Add(100 * time.Second), 100},
+		{base.Add(100*time.Second + time.Millisecond), 101},
+		{base.Add(time.Hour), 1000},
+	}
+	for _, tt := range tests {
+		if got := m.SearchTime(tt.at); got != tt.want {
+			t.Errorf("SearchTime(%v) = %d, want %d", tt.at, got, tt.want)
+		}
+	}
+
+	var got []int
+	for i, id := range m.Range(RangeForTime(base.Add(10*time.Second), base.Add(13*time.Second))) {
+		if id != ids[i] {
+			t.Errorf("Range() yielded %v at %d, want %v", id, i, ids[i])
+		}
+		got = append(got, i)
+	}
+	if len(got) != 3 || got[0] != 10 {
+		t.Errorf("Range() yielded indexes %v, want [10 11 12]", got)
+	}
+
+	n := 0
+	for range m.All() {
+		n++
+	}
+	if n != len(ids) {
+		t.Errorf("All() yielded %d IDs, want %d", n, len(ids))
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestOpenMMapIDsErrors(t *testing.T) {
+	empty, err := OpenMMapIDs(writeIDFile(t, nil))
+	if err != nil || empty.Len() != 0 {
+		t.Errorf("OpenMMapIDs(empty) = %v, %v", empty, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "odd.bin")
+	os.WriteFile(path, []byte{1, 2, 3}, 0o644)
+	if _, err := OpenMMapIDs(path); err == nil {
+		t.Error("OpenMMapIDs() expected error for truncated file")
+	}
+	if _, err := OpenMMapIDs(filepath.Join(t.TempDir(), "missing.bin")); err == nil {
+		t.Error("OpenMMapIDs() expected error for missing file")
+	}
+}
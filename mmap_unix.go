@@ -0,0 +1,17 @@
+//go:build unix && !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile memory-maps the first size bytes of f read-only.
+func mapFile(f *os.File, size int64) ([]byte, func([]byte) error, error) {
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, syscall.Munmap, nil
+}
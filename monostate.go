@@ -0,0 +1,12 @@
+package nano64
+
+// MonotonicState returns a snapshot of the state shared by GenerateMonotonic and
+// its variants: the timestamp and random field of the last ID issued (-1 and 0
+// before the first), and the total number of times a millisecond's random space
+// was exhausted, forcing the next ID into the following millisecond.
+//
+// A lastRand close to 2^20-1 or a growing rollover count means generation
+// runs near the per-millisecond limit and IDs are drifting ahead of the clock.
+func MonotonicState() (lastTs int64, lastRand uint32, rolloversTotal uint64) {
+	return defaultGenerator.MonotonicState()
+}
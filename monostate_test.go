@@ -0,0 +1,33 @@
+package nano64
+
+import "testing"
+
+func TestMonotonicState(t *testing.T) {
+	t.Cleanup(func() {
+		// Far-future state would make later monotonic tests skip ahead.
+		defaultGenerator.mu.Lock()
+		defaultGenerator.lastTimestamp, defaultGenerator.lastRandom = -1, 0
+		defaultGenerator.mu.Unlock()
+	})
+	_, _, before := MonotonicState()
+
+	// Start at the top of the random space so the second ID after it rolls over.
+	ts := int64(1) << 42
+	if _, err := GenerateMonotonic(ts, func(int) (uint32, error) { return randomMask - 1, nil }); err != nil {
+		t.Fatalf("GenerateMonotonic() error = %v", err)
+	}
+	gotTs, gotRandom, rollovers := MonotonicState()
+	if gotTs != ts || gotRandom != randomMask-1 || rollovers != before {
+		t.Errorf("MonotonicState() = %d, %d, %d; want %d, %d, %d", gotTs, gotRandom, rollovers, ts, randomMask-1, before)
+	}
+
+	for range 2 {
+		if _, err := GenerateMonotonic(ts, DefaultRNG); err != nil {
+			t.Fatalf("GenerateMonotonic() error = %v", err)
+		}
+	}
+	gotTs, gotRandom, rollovers = MonotonicState()
+	if gotTs != ts+1 || gotRandom != 0 || rollovers != before+1 {
+		t.Errorf("MonotonicState() = %d, %d, %d; want %d, 0, %d", gotTs, gotRandom, rollovers, ts+1, before+1)
+	}
+}
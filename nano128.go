@@ -0,0 +1,304 @@
+package nano64
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Random128Bits is the number of random bits in a Nano128 (2^84 patterns per millisecond).
+	Random128Bits = 128 - TimestampBits
+
+	// random128HighBits is the number of random bits stored in the high word, below the timestamp.
+	random128HighBits = Random128Bits - 64
+)
+
+var (
+	// Nil128 is the zero value for Nano128. It represents an uninitialized or invalid ID.
+	Nil128 = Nano128{}
+)
+
+// Nano128 represents a 128-bit time-sortable identifier with a 44-bit timestamp and an 84-bit random field.
+// It mirrors the Nano64 API for tables where collisions must be negligible at any rate.
+// The high word uses exactly the Nano64 layout, so its first 8 bytes sort like a Nano64.
+type Nano128 struct {
+	hi uint64
+	lo uint64
+}
+
+// New128 creates a new Nano128 from its high and low 64-bit words.
+func New128(hi, lo uint64) Nano128 {
+	return Nano128{hi: hi, lo: lo}
+}
+
+// Uint64Values returns the high and low 64-bit words.
+func (n Nano128) Uint64Values() (hi, lo uint64) {
+	return n.hi, n.lo
+}
+
+// GetTimestamp extracts the embedded UNIX-epoch milliseconds from the ID.
+func (n Nano128) GetTimestamp() int64 {
+	return int64((n.hi >> timestampShift) & timestampMask)
+}
+
+// GetRandom extracts the 84-bit random field as its upper 20 bits and lower 64 bits.
+func (n Nano128) GetRandom() (hi uint32, lo uint64) {
+	return uint32(n.hi & randomMask), n.lo
+}
+
+// ToDate builds a time.Time from the embedded timestamp.
+func (n Nano128) ToDate() time.Time {
+	return time.UnixMilli(n.GetTimestamp())
+}
+
+// random128 draws 84 random bits from rng.
+func random128(rng RNG) (hi, lo uint64, err error) {
+	top, err := rng(random128HighBits)
+	if err != nil {
+		return 0, 0, err
+	}
+	upper, err := rng(32)
+	if err != nil {
+		return 0, 0, err
+	}
+	lower, err := rng(32)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint64(top) & randomMask, uint64(upper)<<32 | uint64(lower), nil
+}
+
+// Generate128 creates a Nano128 with a given timestamp.
+// The random field is filled with 84 bits from rng (DefaultRNG if nil).
+func Generate128(timestamp int64, rng RNG) (Nano128, error) {
+	if timestamp < 0 {
+		return Nano128{}, fmt.Errorf("timestamp cannot be negative: %d", timestamp)
+	}
+	if timestamp > maxTimestamp {
+		return Nano128{}, fmt.Errorf("timestamp exceeds 44-bit range: %d > %d", timestamp, int64(maxTimestamp))
+	}
+
+	if rng == nil {
+		rng = DefaultRNG
+	}
+
+	hi, lo, err := random128(rng)
+	if err != nil {
+		return Nano128{}, fmt.Errorf("failed to generate random value: %w", err)
+	}
+
+	ms := uint64(timestamp) & timestampMask
+	return Nano128{hi: (ms << timestampShift) | hi, lo: lo}, nil
+}
+
+// GenerateNow128 creates a Nano128 with the current timestamp using DefaultClock.
+func GenerateNow128(rng RNG) (Nano128, error) {
+	return Generate128(DefaultClock(), rng)
+}
+
+// GenerateDefault128 creates a Nano128 with the current timestamp and default RNG.
+func GenerateDefault128() (Nano128, error) {
+	return GenerateNow128(DefaultRNG)
+}
+
+// GenerateMonotonic128 creates monotonic Nano128 IDs. Nondecreasing across calls in one process.
+// Within a millisecond the 84-bit random field is incremented; if it wraps, the timestamp
+// is bumped by 1 ms and the random field resets to 0. The sequence is kept by the
+// Generator behind GenerateMonotonic, separately from its Nano64 sequence.
+func GenerateMonotonic128(timestamp int64, rng RNG) (Nano128, error) {
+	if rng == nil {
+		rng = DefaultRNG
+	}
+	return defaultGenerator.generateMonotonic128(timestamp, rng, defaultGenerator.settings.Load())
+}
+
+// GenerateMonotonic128 creates a Nano128 greater than every Nano128 this
+// generator returned from GenerateMonotonic128 before, with the generator's
+// clock and entropy. Nano128 embeds Unix milliseconds in the default layout, so
+// generators with another layout or an epoch return an error.
+func (g *Generator) GenerateMonotonic128() (Nano128, error) {
+	return g.generateMonotonic128(g.clock(), g.rng, g.settings.Load())
+}
+
+// generateMonotonic128 creates a monotonic Nano128 at timestamp (Unix ms) with rng.
+func (g *Generator) generateMonotonic128(timestamp int64, rng RNG, s *GeneratorSettings) (Nano128, error) {
+	if g.layout != DefaultLayout || g.epoch != 0 {
+		return Nano128{}, fmt.Errorf("Nano128 requires the default layout and the Unix epoch")
+	}
+	t, err := g.stamp(timestamp, s)
+	if err != nil {
+		return Nano128{}, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Enforce nondecreasing time
+	if t < g.last128 {
+		t = g.last128
+	}
+
+	var hi, lo uint64
+	if t == g.last128 {
+		// Same ms → increment with carry into the high random bits
+		lo = g.last128Lo + 1
+		hi = g.last128Hi
+		if lo == 0 {
+			hi = (hi + 1) & randomMask
+		}
+		if hi == 0 && lo == 0 {
+			// Per-ms space exhausted → move to next ms and start at 0
+			t++
+			if t > maxTimestamp {
+				return Nano128{}, fmt.Errorf("timestamp overflow after incrementing for monotonic generation")
+			}
+		}
+	} else {
+		// First ID in this newer ms
+		hi, lo, err = random128(rng)
+		if err != nil {
+			return Nano128{}, fmt.Errorf("failed to generate random value: %w", err)
+		}
+	}
+
+	g.last128, g.last128Hi, g.last128Lo = t, hi, lo
+	return Nano128{hi: uint64(t)<<timestampShift | hi, lo: lo}, nil
+}
+
+// GenerateMonotonicNow128 creates a monotonic Nano128 with the current timestamp.
+func GenerateMonotonicNow128(rng RNG) (Nano128, error) {
+	return GenerateMonotonic128(DefaultClock(), rng)
+}
+
+// GenerateMonotonicDefault128 creates a monotonic Nano128 with current timestamp and default RNG.
+func GenerateMonotonicDefault128() (Nano128, error) {
+	return GenerateMonotonicNow128(DefaultRNG)
+}
+
+// Compare128 compares two IDs as unsigned 128-bit numbers.
+// Returns -1 if a < b, 0 if a == b, 1 if a > b.
+func Compare128(a, b Nano128) int {
+	switch {
+	case a.hi < b.hi:
+		return -1
+	case a.hi > b.hi:
+		return 1
+	case a.lo < b.lo:
+		return -1
+	case a.lo > b.lo:
+		return 1
+	}
+	return 0
+}
+
+// Equals checks equality by unsigned value.
+func (n Nano128) Equals(other Nano128) bool {
+	return n == other
+}
+
+// IsNil returns true if the ID is the zero value (Nil128).
+func (n Nano128) IsNil() bool {
+	return n.hi == 0 && n.lo == 0
+}
+
+// String returns a string representation for debugging.
+func (n Nano128) String() string {
+	return fmt.Sprintf("Nano128{hex: %s, timestamp: %d}", n.ToHex(), n.GetTimestamp())
+}
+
+// ToHex returns uppercase 32-char hex encoding with a dash between timestamp and random parts.
+func (n Nano128) ToHex() string {
+	full := fmt.Sprintf("%016X%016X", n.hi, n.lo)
+	// 44-bit (11 hex digits) timestamp + 84-bit (21 hex digits) random = 32 hex total
+	const split = 11 // ceil(44 / 4)
+	return full[:split] + "-" + full[split:]
+}
+
+// ToBytes returns 16-byte big-endian encoding of the ID.
+func (n Nano128) ToBytes() []byte {
+	bytes := make([]byte, 16)
+	binary.BigEndian.PutUint64(bytes[:8], n.hi)
+	binary.BigEndian.PutUint64(bytes[8:], n.lo)
+	return bytes
+}
+
+// FromHex128 parses from 33-char dashed hex (timestamp-random) or plain 32-char hex.
+// Accepts uppercase or lowercase, optional `0x` prefix.
+func FromHex128(hexStr string) (Nano128, error) {
+	clean := strings.ReplaceAll(hexStr, "-", "")
+	if strings.HasPrefix(clean, "0x") || strings.HasPrefix(clean, "0X") {
+		clean = clean[2:]
+	}
+
+	if len(clean) != 32 {
+		return Nano128{}, fmt.Errorf("hex must be 32 chars after removing dash, got %d", len(clean))
+	}
+
+	bytes, err := Hex.ToBytes(clean)
+	if err != nil {
+		return Nano128{}, fmt.Errorf("invalid hex: %w", err)
+	}
+
+	return FromBytes128(bytes)
+}
+
+// FromBytes128 parses from 16 big-endian bytes.
+func FromBytes128(bytes []byte) (Nano128, error) {
+	if len(bytes) != 16 {
+		return Nano128{}, fmt.Errorf("must be 16 bytes, got %d", len(bytes))
+	}
+	return Nano128{
+		hi: binary.BigEndian.Uint64(bytes[:8]),
+		lo: binary.BigEndian.Uint64(bytes[8:]),
+	}, nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// Encodes the Nano128 as a hex string in JSON.
+func (n Nano128) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.ToHex())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Accepts a hex string.
+func (n *Nano128) UnmarshalJSON(data []byte) error {
+	var hexStr string
+	if err := json.Unmarshal(data, &hexStr); err != nil {
+		return fmt.Errorf("failed to unmarshal Nano128: expected hex string")
+	}
+	parsed, err := FromHex128(hexStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse hex string: %w", err)
+	}
+	*n = parsed
+	return nil
+}
+
+// NullNano128 represents a Nano128 that may be null.
+// NullNano128 implements the Scanner and Valuer interfaces so it can be used as a nullable database field.
+type NullNano128 struct {
+	ID    Nano128
+	Valid bool // Valid is true if ID is not NULL
+}
+
+// MarshalJSON implements the json.Marshaler interface for NullNano128.
+func (n NullNano128) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.ID.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for NullNano128.
+func (n *NullNano128) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		n.Valid = false
+		n.ID = Nil128
+		return nil
+	}
+	n.Valid = true
+	return n.ID.UnmarshalJSON(data)
+}
@@ -0,0 +1,39 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestNano128_Database(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE items (id BLOB PRIMARY KEY, parent BLOB)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	id, err := GenerateDefault128()
+	if err != nil {
+		t.Fatalf("GenerateDefault128() error = %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO items (id, parent) VALUES (?, ?)", id, NullNano128{}); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+
+	var got Nano128
+	var parent NullNano128
+	if err := db.QueryRow("SELECT id, parent FROM items").Scan(&got, &parent); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if !got.Equals(id) || parent.Valid {
+		t.Errorf("database roundtrip failed: %s, %+v", got.ToHex(), parent)
+	}
+}
@@ -0,0 +1,145 @@
+package nano64
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNano128_Generate(t *testing.T) {
+	timestamp := int64(1234567890123)
+	rng := func(bits int) (uint32, error) {
+		return 0xFFFFFFFF, nil
+	}
+
+	id, err := Generate128(timestamp, rng)
+	if err != nil {
+		t.Fatalf("Generate128() error = %v", err)
+	}
+
+	if got := id.GetTimestamp(); got != timestamp {
+		t.Errorf("GetTimestamp() = %d, want %d", got, timestamp)
+	}
+
+	hi, lo := id.GetRandom()
+	if hi != randomMask || lo != ^uint64(0) {
+		t.Errorf("GetRandom() = (%#x, %#x), want all ones", hi, lo)
+	}
+
+	if _, err := Generate128(-1, rng); err == nil {
+		t.Error("Generate128(-1) expected error")
+	}
+}
+
+func TestNano128_HexBytesRoundtrip(t *testing.T) {
+	id := New128(0x123456789ABCDEF0, 0x0FEDCBA987654321)
+
+	if got, want := id.ToHex(), "123456789AB-CDEF00FEDCBA987654321"; got != want {
+		t.Errorf("ToHex() = %s, want %s", got, want)
+	}
+
+	parsed, err := FromHex128(id.ToHex())
+	if err != nil {
+		t.Fatalf("FromHex128() error = %v", err)
+	}
+	if !parsed.Equals(id) {
+		t.Errorf("hex roundtrip failed: %s != %s", parsed.ToHex(), id.ToHex())
+	}
+
+	parsed, err = FromBytes128(id.ToBytes())
+	if err != nil {
+		t.Fatalf("FromBytes128() error = %v", err)
+	}
+	if !parsed.Equals(id) {
+		t.Errorf("bytes roundtrip failed: %s != %s", parsed.ToHex(), id.ToHex())
+	}
+
+	if _, err := FromHex128("123"); err == nil {
+		t.Error("FromHex128() expected error for short input")
+	}
+}
+
+func TestNano128_GenerateMonotonic(t *testing.T) {
+	timestamp := int64(1234567890123)
+	rng := func(bits int) (uint32, error) {
+		return 0xFFFFFFFF, nil
+	}
+
+	prev, err := GenerateMonotonic128(timestamp, rng)
+	if err != nil {
+		t.Fatalf("GenerateMonotonic128() error = %v", err)
+	}
+
+	// The random field starts at its maximum, so the next call must roll over.
+	next, err := GenerateMonotonic128(timestamp, rng)
+	if err != nil {
+		t.Fatalf("GenerateMonotonic128() error = %v", err)
+	}
+	if Compare128(next, prev) <= 0 {
+		t.Errorf("monotonic IDs not increasing: %s <= %s", next.ToHex(), prev.ToHex())
+	}
+	if next.GetTimestamp() != timestamp+1 {
+		t.Errorf("rollover timestamp = %d, want %d", next.GetTimestamp(), timestamp+1)
+	}
+
+	after, err := GenerateMonotonic128(timestamp, rng)
+	if err != nil {
+		t.Fatalf("GenerateMonotonic128() error = %v", err)
+	}
+	if Compare128(after, next) <= 0 {
+		t.Errorf("monotonic IDs not increasing: %s <= %s", after.ToHex(), next.ToHex())
+	}
+}
+
+func TestNano128_JSON(t *testing.T) {
+	type payload struct {
+		ID     Nano128     `json:"id"`
+		Parent NullNano128 `json:"parent"`
+	}
+
+	in := payload{ID: New128(1, 2)}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got, want := string(data), `{"id":"00000000000-000010000000000000002","parent":null}`; got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+
+	var out payload
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out != in {
+		t.Errorf("JSON roundtrip failed: %+v != %+v", out, in)
+	}
+}
+
+func TestGenerator_GenerateMonotonic128(t *testing.T) {
+	const now = int64(1_700_000_000_000)
+	g, err := NewGenerator(WithClock(func() int64 { return now }), WithRNG(func(int) (uint32, error) { return 7, nil }))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	var prev Nano128
+	for i := 0; i < 3; i++ {
+		id, err := g.GenerateMonotonic128()
+		if err != nil {
+			t.Fatalf("GenerateMonotonic128() error = %v", err)
+		}
+		if id.GetTimestamp() != now || Compare128(id, prev) <= 0 {
+			t.Errorf("GenerateMonotonic128() = %s after %s", id.ToHex(), prev.ToHex())
+		}
+		prev = id
+	}
+	if ts, _, _ := g.MonotonicState(); ts != -1 {
+		t.Errorf("GenerateMonotonic128() advanced the Nano64 sequence to %d", ts)
+	}
+
+	wide, err := NewGenerator(WithLayout(Layout{TimestampBits: 42}))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	if _, err := wide.GenerateMonotonic128(); err == nil {
+		t.Error("GenerateMonotonic128() accepted a non-default layout")
+	}
+}
@@ -1,13 +1,10 @@
 package nano64
 
 import (
-	"crypto/rand"
-	"database/sql/driver"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -48,18 +45,8 @@ type Nano64 struct {
 	value uint64
 }
 
-var (
-	// lastTimestamp is used by GenerateMonotonic to track the last used timestamp.
-	lastTimestamp int64 = -1
-
-	// lastRandom is used by GenerateMonotonic to track the last used random value.
-	lastRandom uint64
-
-	// monotonicMutex protects the monotonic generation state.
-	monotonicMutex sync.Mutex
-)
-
-// DefaultRNG provides a cryptographically-secure RNG using crypto/rand.
+// DefaultRNG provides a cryptographically-secure RNG using crypto/rand, or the
+// source installed with SetEntropySource.
 // Returns an unsigned integer with exactly `bits` bits of entropy.
 func DefaultRNG(bits int) (uint32, error) {
 	if bits <= 0 || bits > 32 {
@@ -68,7 +55,7 @@ func DefaultRNG(bits int) (uint32, error) {
 
 	// Generate 4 bytes for simplicity
 	buf := make([]byte, 4)
-	if _, err := rand.Read(buf); err != nil {
+	if err := readEntropy(buf); err != nil {
 		return 0, fmt.Errorf("failed to generate random bytes: %w", err)
 	}
 
@@ -104,61 +91,6 @@ func (n Nano64) Uint64Value() uint64 {
 	return n.value
 }
 
-// Value implements the driver.Valuer interface for SQL database support.
-// Returns the ID as a byte slice for storage in SQL databases as BYTEA.
-func (n Nano64) Value() (driver.Value, error) {
-	return n.ToBytes(), nil
-}
-
-// Scan implements the sql.Scanner interface for SQL database support.
-// Accepts int64 or uint64 values from SQL databases.
-func (n *Nano64) Scan(value interface{}) error {
-	if value == nil {
-		n.value = 0
-		return nil
-	}
-
-	switch v := value.(type) {
-	case int64:
-		n.value = uint64(v)
-		return nil
-	case uint64:
-		n.value = v
-		return nil
-	case []byte:
-		if len(v) != 8 {
-			return fmt.Errorf("invalid byte length for Nano64: %d", len(v))
-		}
-		parsed, err := BigIntHelpers.FromBytesBE(v)
-		if err != nil {
-			return fmt.Errorf("failed to scan bytes: %w", err)
-		}
-		n.value = parsed
-		return nil
-	default:
-		return fmt.Errorf("cannot scan type %T into Nano64", value)
-	}
-}
-
-// Value implements the driver.Valuer interface for NullNano64.
-func (n NullNano64) Value() (driver.Value, error) {
-	if !n.Valid {
-		return nil, nil
-	}
-	return n.ID.Value()
-}
-
-// Scan implements the sql.Scanner interface for NullNano64.
-func (n *NullNano64) Scan(value interface{}) error {
-	if value == nil {
-		n.ID = Nil
-		n.Valid = false
-		return nil
-	}
-	n.Valid = true
-	return n.ID.Scan(value)
-}
-
 // MarshalJSON implements the json.Marshaler interface for NullNano64.
 func (n NullNano64) MarshalJSON() ([]byte, error) {
 	if !n.Valid {
@@ -167,6 +99,15 @@ func (n NullNano64) MarshalJSON() ([]byte, error) {
 	return n.ID.MarshalJSON()
 }
 
+// AppendJSON appends the JSON encoding of the NullNano64 to dst: null when invalid,
+// the ID's encoding otherwise.
+func (n NullNano64) AppendJSON(dst []byte) []byte {
+	if !n.Valid {
+		return append(dst, "null"...)
+	}
+	return n.ID.AppendJSON(dst)
+}
+
 // UnmarshalJSON implements the json.Unmarshaler interface for NullNano64.
 func (n *NullNano64) UnmarshalJSON(data []byte) error {
 	// Check for null
@@ -182,12 +123,28 @@ func (n *NullNano64) UnmarshalJSON(data []byte) error {
 // MarshalJSON implements the json.Marshaler interface.
 // Encodes the Nano64 as a hex string in JSON.
 func (n Nano64) MarshalJSON() ([]byte, error) {
-	return json.Marshal(n.ToHex())
+	return n.AppendJSON(make([]byte, 0, hexLength+2)), nil
+}
+
+// AppendJSON appends the JSON encoding of the ID (a quoted dashed hex string, as
+// MarshalJSON) to dst. It does not allocate when dst has room.
+func (n Nano64) AppendJSON(dst []byte) []byte {
+	dst = append(dst, '"')
+	dst = appendHex(dst, n)
+	return append(dst, '"')
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 // Accepts either a hex string or a numeric value from JSON.
 func (n *Nano64) UnmarshalJSON(data []byte) error {
+	// Fast path: a quoted canonical hex string is decoded in place, without copies.
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		if value, ok := decodeCanonicalHex(data[1 : len(data)-1]); ok {
+			n.value = value
+			return nil
+		}
+	}
+
 	// Try to unmarshal as string first (hex format)
 	var hexStr string
 	if err := json.Unmarshal(data, &hexStr); err == nil {
@@ -231,7 +188,7 @@ func Generate(timestamp int64, rng RNG) (Nano64, error) {
 		return Nano64{}, fmt.Errorf("timestamp cannot be negative: %d", timestamp)
 	}
 	if timestamp > maxTimestamp {
-		return Nano64{}, fmt.Errorf("timestamp exceeds 44-bit range: %d > %d", timestamp, maxTimestamp)
+		return Nano64{}, fmt.Errorf("timestamp exceeds 44-bit range: %d > %d", timestamp, int64(maxTimestamp))
 	}
 
 	if rng == nil {
@@ -242,6 +199,9 @@ func Generate(timestamp int64, rng RNG) (Nano64, error) {
 	if err != nil {
 		return Nano64{}, fmt.Errorf("failed to generate random value: %w", err)
 	}
+	if debugEnabled {
+		debugCheckRandom(randVal, RandomBits)
+	}
 
 	ms := uint64(timestamp) & timestampMask
 	random := uint64(randVal) & randomMask
@@ -263,57 +223,11 @@ func GenerateDefault() (Nano64, error) {
 // GenerateMonotonic creates monotonic IDs. Nondecreasing across calls in one process.
 // If the per-ms sequence wraps, the timestamp is bumped by 1 ms and the random field resets to 0.
 func GenerateMonotonic(timestamp int64, rng RNG) (Nano64, error) {
-	if timestamp < 0 {
-		return Nano64{}, fmt.Errorf("timestamp cannot be negative: %d", timestamp)
-	}
-	if timestamp > maxTimestamp {
-		return Nano64{}, fmt.Errorf("timestamp exceeds 44-bit range: %d > %d", timestamp, maxTimestamp)
-	}
-
 	if rng == nil {
 		rng = DefaultRNG
 	}
-
-	monotonicMutex.Lock()
-	defer monotonicMutex.Unlock()
-
-	// Enforce nondecreasing time
-	t := timestamp
-	if t < lastTimestamp {
-		t = lastTimestamp
-	}
-
-	var random uint64
-	if t == lastTimestamp {
-		// Same ms → increment
-		random = (lastRandom + 1) & randomMask
-		if random == 0 {
-			// Per-ms space exhausted → move to next ms and start at 0
-			t++
-			if t > maxTimestamp {
-				return Nano64{}, fmt.Errorf("timestamp overflow after incrementing for monotonic generation")
-			}
-			lastTimestamp = t
-			lastRandom = 0
-			ms := uint64(t) & timestampMask
-			value := ms << timestampShift
-			return Nano64{value: value}, nil
-		}
-	} else {
-		// First ID in this newer ms
-		randVal, err := rng(RandomBits)
-		if err != nil {
-			return Nano64{}, fmt.Errorf("failed to generate random value: %w", err)
-		}
-		random = uint64(randVal) & randomMask
-	}
-
-	lastTimestamp = t
-	lastRandom = random
-
-	ms := uint64(t) & timestampMask
-	value := (ms << timestampShift) | random
-	return Nano64{value: value}, nil
+	id, _, _, err := defaultGenerator.generateMonotonic(timestamp, rng, defaultGenerator.settings.Load())
+	return id, err
 }
 
 // GenerateMonotonicNow creates a monotonic ID with the current timestamp.
@@ -355,10 +269,9 @@ func (n Nano64) String() string {
 
 // ToHex returns uppercase 16-char hex encoding of the u64, with a dash between timestamp and random parts.
 func (n Nano64) ToHex() string {
-	full := fmt.Sprintf("%016X", n.value)
-	// Split 44-bit (11 hex digits) timestamp + 20-bit (5 hex digits) random = 16 hex total
-	const split = 11 // ceil(44 / 4)
-	return full[:split] + "-" + full[split:]
+	// 44-bit (11 hex digits) timestamp + dash + 20-bit (5 hex digits) random
+	var buf [hexLength]byte
+	return string(appendHex(buf[:0], n))
 }
 
 // ToBytes returns 8-byte big-endian encoding of the u64.
@@ -369,6 +282,12 @@ func (n Nano64) ToBytes() []byte {
 // FromHex parses from 17-char dashed hex (timestamp-random) or plain 16-char hex.
 // Accepts uppercase or lowercase, optional `0x` prefix.
 func FromHex(hexStr string) (Nano64, error) {
+	// Fast path for the two canonical forms; anything else, including invalid
+	// input, goes through the general parser below for its error reporting.
+	if value, ok := decodeCanonicalHex(hexStr); ok {
+		return Nano64{value: value}, nil
+	}
+
 	clean := strings.ReplaceAll(hexStr, "-", "")
 	if strings.HasPrefix(clean, "0x") || strings.HasPrefix(clean, "0X") {
 		clean = clean[2:]
@@ -0,0 +1,338 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// setupTestDB creates a temporary SQLite database for testing.
+func setupTestDB(t *testing.T) (*sql.DB, func()) {
+	t.Helper()
+
+	// Create a temporary directory for the test database
+	tmpDir, err := os.MkdirTemp("", "nano64_test_*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+
+	dbPath := filepath.Join(tmpDir, "test.db")
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	// Create a test table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS items (
+			id INTEGER PRIMARY KEY,
+			nano64_id INTEGER NOT NULL,
+			name TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	// Return cleanup function
+	cleanup := func() {
+		db.Close()
+		os.RemoveAll(tmpDir)
+	}
+
+	return db, cleanup
+}
+
+func TestNano64_DatabaseWrite(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	tests := []struct {
+		name     string
+		nano64ID Nano64
+		itemName string
+	}{
+		{"zero value", New(0), "zero item"},
+		{"small value", New(12345), "small item"},
+		{"large value", New(0x123456789ABCDEF0), "large item"},
+		{"max value", New(^uint64(0)), "max item"},
+		{"generated ID", func() Nano64 { id, _ := GenerateDefault(); return id }(), "generated item"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Write to database
+			result, err := db.Exec(
+				"INSERT INTO items (nano64_id, name) VALUES (?, ?)",
+				tt.nano64ID,
+				tt.itemName,
+			)
+			if err != nil {
+				t.Fatalf("failed to insert: %v", err)
+			}
+
+			rowID, err := result.LastInsertId()
+			if err != nil {
+				t.Fatalf("failed to get last insert id: %v", err)
+			}
+
+			if rowID <= 0 {
+				t.Errorf("expected positive row ID, got %d", rowID)
+			}
+		})
+	}
+}
+
+func TestNano64_DatabaseRead(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Insert test data
+	testID := New(0x123456789ABCDEF0)
+	testName := "test item"
+
+	_, err := db.Exec(
+		"INSERT INTO items (nano64_id, name) VALUES (?, ?)",
+		testID,
+		testName,
+	)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	// Read back
+	var scannedID Nano64
+	var scannedName string
+
+	err = db.QueryRow("SELECT nano64_id, name FROM items WHERE name = ?", testName).Scan(
+		&scannedID,
+		&scannedName,
+	)
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+
+	if !scannedID.Equals(testID) {
+		t.Errorf("ID mismatch: got %d, want %d", scannedID.Uint64Value(), testID.Uint64Value())
+	}
+
+	if scannedName != testName {
+		t.Errorf("name mismatch: got %s, want %s", scannedName, testName)
+	}
+}
+
+func TestNano64_DatabaseWriteReadRoundtrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	tests := []struct {
+		name  string
+		value uint64
+	}{
+		{"zero", 0},
+		{"small", 12345},
+		{"medium", 0x123456789ABC},
+		{"large", 0x123456789ABCDEF0},
+		{"max", ^uint64(0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := New(tt.value)
+
+			// Write to database
+			_, err := db.Exec(
+				"INSERT INTO items (nano64_id, name) VALUES (?, ?)",
+				original,
+				tt.name,
+			)
+			if err != nil {
+				t.Fatalf("failed to insert: %v", err)
+			}
+
+			// Read back
+			var scanned Nano64
+			err = db.QueryRow("SELECT nano64_id FROM items WHERE name = ?", tt.name).Scan(&scanned)
+			if err != nil {
+				t.Fatalf("failed to query: %v", err)
+			}
+
+			// Verify roundtrip
+			if !scanned.Equals(original) {
+				t.Errorf("roundtrip failed: got %d, want %d", scanned.Uint64Value(), original.Uint64Value())
+			}
+
+			// Verify timestamp and random fields are preserved
+			if scanned.GetTimestamp() != original.GetTimestamp() {
+				t.Errorf("timestamp mismatch: got %d, want %d", scanned.GetTimestamp(), original.GetTimestamp())
+			}
+
+			if scanned.GetRandom() != original.GetRandom() {
+				t.Errorf("random mismatch: got %d, want %d", scanned.GetRandom(), original.GetRandom())
+			}
+		})
+	}
+}
+
+func TestNano64_DatabaseMultipleRecords(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Generate multiple IDs
+	ids := make([]Nano64, 10)
+	for i := 0; i < len(ids); i++ {
+		id, err := GenerateDefault()
+		if err != nil {
+			t.Fatalf("failed to generate ID: %v", err)
+		}
+		ids[i] = id
+
+		// Insert into database
+		_, err = db.Exec(
+			"INSERT INTO items (nano64_id, name) VALUES (?, ?)",
+			id,
+			"item_"+string(rune('0'+i)),
+		)
+		if err != nil {
+			t.Fatalf("failed to insert ID %d: %v", i, err)
+		}
+	}
+
+	// Query all records ordered by nano64_id
+	rows, err := db.Query("SELECT nano64_id, name FROM items ORDER BY nano64_id ASC")
+	if err != nil {
+		t.Fatalf("failed to query all: %v", err)
+	}
+	defer rows.Close()
+
+	scannedIDs := make([]Nano64, 0, len(ids))
+	for rows.Next() {
+		var id Nano64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatalf("failed to scan row: %v", err)
+		}
+		scannedIDs = append(scannedIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows error: %v", err)
+	}
+
+	// Verify all IDs were retrieved
+	if len(scannedIDs) != len(ids) {
+		t.Errorf("expected %d records, got %d", len(ids), len(scannedIDs))
+	}
+
+	// Verify ordering (should be sorted by timestamp)
+	for i := 1; i < len(scannedIDs); i++ {
+		if Compare(scannedIDs[i-1], scannedIDs[i]) > 0 {
+			t.Errorf("IDs not properly sorted at index %d: %d > %d",
+				i, scannedIDs[i-1].Uint64Value(), scannedIDs[i].Uint64Value())
+		}
+	}
+}
+
+func TestNano64_DatabaseNullHandling(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Create a table that allows NULL values
+	_, err := db.Exec(`
+		CREATE TABLE nullable_items (
+			id INTEGER PRIMARY KEY,
+			nano64_id INTEGER,
+			name TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create nullable table: %v", err)
+	}
+
+	// Insert NULL value
+	_, err = db.Exec("INSERT INTO nullable_items (nano64_id, name) VALUES (NULL, ?)", "null item")
+	if err != nil {
+		t.Fatalf("failed to insert NULL: %v", err)
+	}
+
+	// Read back NULL
+	var nullID Nano64
+	err = db.QueryRow("SELECT nano64_id FROM nullable_items WHERE name = ?", "null item").Scan(&nullID)
+	if err != nil {
+		t.Fatalf("failed to scan NULL: %v", err)
+	}
+
+	// NULL should scan as zero value
+	if nullID.Uint64Value() != 0 {
+		t.Errorf("NULL scanned as %d, expected 0", nullID.Uint64Value())
+	}
+}
+
+// TestBigIntHelpers_FromBytesBE_Error tests error handling for invalid byte lengths
+
+func TestNullNano64_Database(t *testing.T) {
+	// Create in-memory SQLite database
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test.db")
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// Create test table
+	_, err = db.Exec(`
+		CREATE TABLE test_null (
+			id INTEGER PRIMARY KEY,
+			nullable_id BLOB,
+			non_null_id BLOB NOT NULL
+		)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	// Test inserting NULL value
+	nullID := NullNano64{Valid: false}
+	validID, err := GenerateDefault()
+	if err != nil {
+		t.Fatalf("GenerateDefault() error = %v", err)
+	}
+	validNullID := NullNano64{ID: validID, Valid: true}
+
+	_, err = db.Exec("INSERT INTO test_null (id, nullable_id, non_null_id) VALUES (?, ?, ?)",
+		1, nullID, validNullID)
+	if err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	// Test querying NULL value
+	var retrievedNull NullNano64
+	var retrievedValid NullNano64
+	err = db.QueryRow("SELECT nullable_id, non_null_id FROM test_null WHERE id = ?", 1).
+		Scan(&retrievedNull, &retrievedValid)
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+
+	if retrievedNull.Valid {
+		t.Error("Retrieved null ID should be invalid")
+	}
+
+	if !retrievedValid.Valid {
+		t.Error("Retrieved valid ID should be valid")
+	}
+
+	if !retrievedValid.ID.Equals(validID) {
+		t.Error("Retrieved ID does not match original")
+	}
+}
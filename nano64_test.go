@@ -1,16 +1,11 @@
 package nano64
 
 import (
-	"database/sql"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"testing"
 	"time"
-
-	_ "modernc.org/sqlite"
 )
 
 func TestNano64_New(t *testing.T) {
@@ -282,153 +277,6 @@ func TestGenerate_Errors(t *testing.T) {
 	}
 }
 
-func TestNano64_Value(t *testing.T) {
-	tests := []struct {
-		name    string
-		value   uint64
-		want    []byte
-		wantErr bool
-	}{
-		{"zero", 0, []byte{0, 0, 0, 0, 0, 0, 0, 0}, false},
-		{"positive", 12345, []byte{0, 0, 0, 0, 0, 0, 0x30, 0x39}, false},
-		{"large value", 0x123456789ABCDEF0, []byte{0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0xDE, 0xF0}, false},
-		{"max", ^uint64(0), []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			id := New(tt.value)
-			got, err := id.Value()
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Value() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !tt.wantErr {
-				gotBytes, ok := got.([]byte)
-				if !ok {
-					t.Errorf("Value() returned type %T, want []byte", got)
-					return
-				}
-				if len(gotBytes) != len(tt.want) {
-					t.Errorf("Value() returned %d bytes, want %d", len(gotBytes), len(tt.want))
-					return
-				}
-				for i := range gotBytes {
-					if gotBytes[i] != tt.want[i] {
-						t.Errorf("Value() = %v, want %v", gotBytes, tt.want)
-						break
-					}
-				}
-			}
-		})
-	}
-}
-
-func TestNano64_Scan(t *testing.T) {
-	tests := []struct {
-		name    string
-		input   interface{}
-		want    uint64
-		wantErr bool
-	}{
-		{"nil", nil, 0, false},
-		{"int64 zero", int64(0), 0, false},
-		{"int64 positive", int64(12345), 12345, false},
-		{"int64 large", int64(0x123456789ABCDEF0), 0x123456789ABCDEF0, false},
-		{"uint64 zero", uint64(0), 0, false},
-		{"uint64 positive", uint64(12345), 12345, false},
-		{"uint64 max", ^uint64(0), ^uint64(0), false},
-		{"bytes 8 bytes", []byte{0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0xDE, 0xF0}, 0x123456789ABCDEF0, false},
-		{"bytes zero", []byte{0, 0, 0, 0, 0, 0, 0, 0}, 0, false},
-		{"bytes wrong length", []byte{1, 2, 3}, 0, true},
-		{"string invalid type", "invalid", 0, true},
-		{"float invalid type", 3.14, 0, true},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			var id Nano64
-			err := id.Scan(tt.input)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Scan() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !tt.wantErr && id.Uint64Value() != tt.want {
-				t.Errorf("Scan() resulted in value %d, want %d", id.Uint64Value(), tt.want)
-			}
-		})
-	}
-}
-
-func TestNano64_ValueScan_Roundtrip(t *testing.T) {
-	tests := []struct {
-		name  string
-		value uint64
-	}{
-		{"zero", 0},
-		{"small", 12345},
-		{"large", 0x123456789ABCDEF0},
-		{"max", ^uint64(0)},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			original := New(tt.value)
-
-			// Convert to driver.Value
-			driverValue, err := original.Value()
-			if err != nil {
-				t.Fatalf("Value() error = %v", err)
-			}
-
-			// Scan back
-			var scanned Nano64
-			err = scanned.Scan(driverValue)
-			if err != nil {
-				t.Fatalf("Scan() error = %v", err)
-			}
-
-			// Compare
-			if scanned.Uint64Value() != original.Uint64Value() {
-				t.Errorf("roundtrip failed: %d != %d", scanned.Uint64Value(), original.Uint64Value())
-			}
-		})
-	}
-}
-
-func TestNano64_Scan_BytesRoundtrip(t *testing.T) {
-	tests := []struct {
-		name  string
-		value uint64
-	}{
-		{"zero", 0},
-		{"small", 12345},
-		{"large", 0x123456789ABCDEF0},
-		{"max", ^uint64(0)},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			original := New(tt.value)
-
-			// Convert to bytes
-			bytes := original.ToBytes()
-
-			// Scan from bytes
-			var scanned Nano64
-			err := scanned.Scan(bytes)
-			if err != nil {
-				t.Fatalf("Scan() error = %v", err)
-			}
-
-			// Compare
-			if scanned.Uint64Value() != original.Uint64Value() {
-				t.Errorf("bytes roundtrip failed: %d != %d", scanned.Uint64Value(), original.Uint64Value())
-			}
-		})
-	}
-}
-
 func TestNano64_MarshalJSON(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -554,272 +402,6 @@ func BenchmarkGenerateMonotonic(b *testing.B) {
 	}
 }
 
-// setupTestDB creates a temporary SQLite database for testing.
-func setupTestDB(t *testing.T) (*sql.DB, func()) {
-	t.Helper()
-
-	// Create a temporary directory for the test database
-	tmpDir, err := os.MkdirTemp("", "nano64_test_*")
-	if err != nil {
-		t.Fatalf("failed to create temp dir: %v", err)
-	}
-
-	dbPath := filepath.Join(tmpDir, "test.db")
-	db, err := sql.Open("sqlite", dbPath)
-	if err != nil {
-		os.RemoveAll(tmpDir)
-		t.Fatalf("failed to open database: %v", err)
-	}
-
-	// Create a test table
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS items (
-			id INTEGER PRIMARY KEY,
-			nano64_id INTEGER NOT NULL,
-			name TEXT NOT NULL
-		)
-	`)
-	if err != nil {
-		db.Close()
-		os.RemoveAll(tmpDir)
-		t.Fatalf("failed to create table: %v", err)
-	}
-
-	// Return cleanup function
-	cleanup := func() {
-		db.Close()
-		os.RemoveAll(tmpDir)
-	}
-
-	return db, cleanup
-}
-
-func TestNano64_DatabaseWrite(t *testing.T) {
-	db, cleanup := setupTestDB(t)
-	defer cleanup()
-
-	tests := []struct {
-		name     string
-		nano64ID Nano64
-		itemName string
-	}{
-		{"zero value", New(0), "zero item"},
-		{"small value", New(12345), "small item"},
-		{"large value", New(0x123456789ABCDEF0), "large item"},
-		{"max value", New(^uint64(0)), "max item"},
-		{"generated ID", func() Nano64 { id, _ := GenerateDefault(); return id }(), "generated item"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Write to database
-			result, err := db.Exec(
-				"INSERT INTO items (nano64_id, name) VALUES (?, ?)",
-				tt.nano64ID,
-				tt.itemName,
-			)
-			if err != nil {
-				t.Fatalf("failed to insert: %v", err)
-			}
-
-			rowID, err := result.LastInsertId()
-			if err != nil {
-				t.Fatalf("failed to get last insert id: %v", err)
-			}
-
-			if rowID <= 0 {
-				t.Errorf("expected positive row ID, got %d", rowID)
-			}
-		})
-	}
-}
-
-func TestNano64_DatabaseRead(t *testing.T) {
-	db, cleanup := setupTestDB(t)
-	defer cleanup()
-
-	// Insert test data
-	testID := New(0x123456789ABCDEF0)
-	testName := "test item"
-
-	_, err := db.Exec(
-		"INSERT INTO items (nano64_id, name) VALUES (?, ?)",
-		testID,
-		testName,
-	)
-	if err != nil {
-		t.Fatalf("failed to insert test data: %v", err)
-	}
-
-	// Read back
-	var scannedID Nano64
-	var scannedName string
-
-	err = db.QueryRow("SELECT nano64_id, name FROM items WHERE name = ?", testName).Scan(
-		&scannedID,
-		&scannedName,
-	)
-	if err != nil {
-		t.Fatalf("failed to query: %v", err)
-	}
-
-	if !scannedID.Equals(testID) {
-		t.Errorf("ID mismatch: got %d, want %d", scannedID.Uint64Value(), testID.Uint64Value())
-	}
-
-	if scannedName != testName {
-		t.Errorf("name mismatch: got %s, want %s", scannedName, testName)
-	}
-}
-
-func TestNano64_DatabaseWriteReadRoundtrip(t *testing.T) {
-	db, cleanup := setupTestDB(t)
-	defer cleanup()
-
-	tests := []struct {
-		name  string
-		value uint64
-	}{
-		{"zero", 0},
-		{"small", 12345},
-		{"medium", 0x123456789ABC},
-		{"large", 0x123456789ABCDEF0},
-		{"max", ^uint64(0)},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			original := New(tt.value)
-
-			// Write to database
-			_, err := db.Exec(
-				"INSERT INTO items (nano64_id, name) VALUES (?, ?)",
-				original,
-				tt.name,
-			)
-			if err != nil {
-				t.Fatalf("failed to insert: %v", err)
-			}
-
-			// Read back
-			var scanned Nano64
-			err = db.QueryRow("SELECT nano64_id FROM items WHERE name = ?", tt.name).Scan(&scanned)
-			if err != nil {
-				t.Fatalf("failed to query: %v", err)
-			}
-
-			// Verify roundtrip
-			if !scanned.Equals(original) {
-				t.Errorf("roundtrip failed: got %d, want %d", scanned.Uint64Value(), original.Uint64Value())
-			}
-
-			// Verify timestamp and random fields are preserved
-			if scanned.GetTimestamp() != original.GetTimestamp() {
-				t.Errorf("timestamp mismatch: got %d, want %d", scanned.GetTimestamp(), original.GetTimestamp())
-			}
-
-			if scanned.GetRandom() != original.GetRandom() {
-				t.Errorf("random mismatch: got %d, want %d", scanned.GetRandom(), original.GetRandom())
-			}
-		})
-	}
-}
-
-func TestNano64_DatabaseMultipleRecords(t *testing.T) {
-	db, cleanup := setupTestDB(t)
-	defer cleanup()
-
-	// Generate multiple IDs
-	ids := make([]Nano64, 10)
-	for i := 0; i < len(ids); i++ {
-		id, err := GenerateDefault()
-		if err != nil {
-			t.Fatalf("failed to generate ID: %v", err)
-		}
-		ids[i] = id
-
-		// Insert into database
-		_, err = db.Exec(
-			"INSERT INTO items (nano64_id, name) VALUES (?, ?)",
-			id,
-			"item_"+string(rune('0'+i)),
-		)
-		if err != nil {
-			t.Fatalf("failed to insert ID %d: %v", i, err)
-		}
-	}
-
-	// Query all records ordered by nano64_id
-	rows, err := db.Query("SELECT nano64_id, name FROM items ORDER BY nano64_id ASC")
-	if err != nil {
-		t.Fatalf("failed to query all: %v", err)
-	}
-	defer rows.Close()
-
-	scannedIDs := make([]Nano64, 0, len(ids))
-	for rows.Next() {
-		var id Nano64
-		var name string
-		if err := rows.Scan(&id, &name); err != nil {
-			t.Fatalf("failed to scan row: %v", err)
-		}
-		scannedIDs = append(scannedIDs, id)
-	}
-
-	if err := rows.Err(); err != nil {
-		t.Fatalf("rows error: %v", err)
-	}
-
-	// Verify all IDs were retrieved
-	if len(scannedIDs) != len(ids) {
-		t.Errorf("expected %d records, got %d", len(ids), len(scannedIDs))
-	}
-
-	// Verify ordering (should be sorted by timestamp)
-	for i := 1; i < len(scannedIDs); i++ {
-		if Compare(scannedIDs[i-1], scannedIDs[i]) > 0 {
-			t.Errorf("IDs not properly sorted at index %d: %d > %d",
-				i, scannedIDs[i-1].Uint64Value(), scannedIDs[i].Uint64Value())
-		}
-	}
-}
-
-func TestNano64_DatabaseNullHandling(t *testing.T) {
-	db, cleanup := setupTestDB(t)
-	defer cleanup()
-
-	// Create a table that allows NULL values
-	_, err := db.Exec(`
-		CREATE TABLE nullable_items (
-			id INTEGER PRIMARY KEY,
-			nano64_id INTEGER,
-			name TEXT NOT NULL
-		)
-	`)
-	if err != nil {
-		t.Fatalf("failed to create nullable table: %v", err)
-	}
-
-	// Insert NULL value
-	_, err = db.Exec("INSERT INTO nullable_items (nano64_id, name) VALUES (NULL, ?)", "null item")
-	if err != nil {
-		t.Fatalf("failed to insert NULL: %v", err)
-	}
-
-	// Read back NULL
-	var nullID Nano64
-	err = db.QueryRow("SELECT nano64_id FROM nullable_items WHERE name = ?", "null item").Scan(&nullID)
-	if err != nil {
-		t.Fatalf("failed to scan NULL: %v", err)
-	}
-
-	// NULL should scan as zero value
-	if nullID.Uint64Value() != 0 {
-		t.Errorf("NULL scanned as %d, expected 0", nullID.Uint64Value())
-	}
-}
-
-// TestBigIntHelpers_FromBytesBE_Error tests error handling for invalid byte lengths
 func TestBigIntHelpers_FromBytesBE_Error(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -997,10 +579,10 @@ func TestNano64_FromUint64(t *testing.T) {
 // TestNano64_GenerateMonotonicNow tests GenerateMonotonicNow
 func TestNano64_GenerateMonotonicNow(t *testing.T) {
 	// Reset monotonic state
-	monotonicMutex.Lock()
-	lastTimestamp = -1
-	lastRandom = 0
-	monotonicMutex.Unlock()
+	defaultGenerator.mu.Lock()
+	defaultGenerator.lastTimestamp = -1
+	defaultGenerator.lastRandom = 0
+	defaultGenerator.mu.Unlock()
 
 	id1, err := GenerateMonotonicNow(nil)
 	if err != nil {
@@ -1020,10 +602,10 @@ func TestNano64_GenerateMonotonicNow(t *testing.T) {
 // TestNano64_GenerateMonotonicDefault tests GenerateMonotonicDefault
 func TestNano64_GenerateMonotonicDefault(t *testing.T) {
 	// Reset monotonic state
-	monotonicMutex.Lock()
-	lastTimestamp = -1
-	lastRandom = 0
-	monotonicMutex.Unlock()
+	defaultGenerator.mu.Lock()
+	defaultGenerator.lastTimestamp = -1
+	defaultGenerator.lastRandom = 0
+	defaultGenerator.mu.Unlock()
 
 	id, err := GenerateMonotonicDefault()
 	if err != nil {
@@ -1271,10 +853,10 @@ func TestEncryptedNano64_DifferentKeySizes(t *testing.T) {
 // TestGenerateMonotonic_Overflow tests overflow handling in monotonic generation
 func TestGenerateMonotonic_Overflow(t *testing.T) {
 	// Reset monotonic state
-	monotonicMutex.Lock()
-	lastTimestamp = maxTimestamp
-	lastRandom = randomMask // Max random value
-	monotonicMutex.Unlock()
+	defaultGenerator.mu.Lock()
+	defaultGenerator.lastTimestamp = maxTimestamp
+	defaultGenerator.lastRandom = randomMask // Max random value
+	defaultGenerator.mu.Unlock()
 
 	// This should cause an overflow
 	_, err := GenerateMonotonic(maxTimestamp, nil)
@@ -1286,10 +868,10 @@ func TestGenerateMonotonic_Overflow(t *testing.T) {
 // TestGenerateMonotonic_BackwardsTime tests monotonic generation with backwards time
 func TestGenerateMonotonic_BackwardsTime(t *testing.T) {
 	// Reset monotonic state
-	monotonicMutex.Lock()
-	lastTimestamp = 1000000
-	lastRandom = 100
-	monotonicMutex.Unlock()
+	defaultGenerator.mu.Lock()
+	defaultGenerator.lastTimestamp = 1000000
+	defaultGenerator.lastRandom = 100
+	defaultGenerator.mu.Unlock()
 
 	// Try to generate with an earlier timestamp
 	id, err := GenerateMonotonic(500000, nil)
@@ -1376,10 +958,10 @@ func TestGenerate_RNGError(t *testing.T) {
 // TestGenerateMonotonic_RNGError tests error handling when RNG fails in monotonic generation
 func TestGenerateMonotonic_RNGError(t *testing.T) {
 	// Reset monotonic state to a different timestamp
-	monotonicMutex.Lock()
-	lastTimestamp = 1000
-	lastRandom = 0
-	monotonicMutex.Unlock()
+	defaultGenerator.mu.Lock()
+	defaultGenerator.lastTimestamp = 1000
+	defaultGenerator.lastRandom = 0
+	defaultGenerator.mu.Unlock()
 
 	failingRNG := func(bits int) (uint32, error) {
 		return 0, fmt.Errorf("RNG failure")
@@ -1440,10 +1022,10 @@ func TestEncryptedNano64_CiphertextLengthCheck(t *testing.T) {
 // TestGenerateMonotonic_SameTimestampIncrement tests incrementing within same millisecond
 func TestGenerateMonotonic_SameTimestampIncrement(t *testing.T) {
 	// Reset monotonic state
-	monotonicMutex.Lock()
-	lastTimestamp = 1000
-	lastRandom = 50
-	monotonicMutex.Unlock()
+	defaultGenerator.mu.Lock()
+	defaultGenerator.lastTimestamp = 1000
+	defaultGenerator.lastRandom = 50
+	defaultGenerator.mu.Unlock()
 
 	// Generate multiple IDs with the same timestamp
 	id1, err := GenerateMonotonic(1000, nil)
@@ -1486,10 +1068,10 @@ func TestGenerate_WithNilRNG(t *testing.T) {
 // TestGenerateMonotonic_WithNilRNG tests that nil RNG uses default
 func TestGenerateMonotonic_WithNilRNG(t *testing.T) {
 	// Reset monotonic state
-	monotonicMutex.Lock()
-	lastTimestamp = -1
-	lastRandom = 0
-	monotonicMutex.Unlock()
+	defaultGenerator.mu.Lock()
+	defaultGenerator.lastTimestamp = -1
+	defaultGenerator.lastRandom = 0
+	defaultGenerator.mu.Unlock()
 
 	id, err := GenerateMonotonic(12345, nil)
 	if err != nil {
@@ -1648,67 +1230,6 @@ func TestNil_DirectComparison(t *testing.T) {
 	}
 }
 
-func TestNullNano64_Scan(t *testing.T) {
-	tests := []struct {
-		name      string
-		input     interface{}
-		wantValid bool
-		wantError bool
-	}{
-		{"nil value", nil, false, false},
-		{"uint64 value", uint64(12345), true, false},
-		{"int64 value", int64(12345), true, false},
-		{"bytes value", []byte{0, 0, 0, 0, 0, 0, 0x30, 0x39}, true, false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			var n NullNano64
-			err := n.Scan(tt.input)
-
-			if (err != nil) != tt.wantError {
-				t.Errorf("Scan() error = %v, wantError %v", err, tt.wantError)
-				return
-			}
-
-			if n.Valid != tt.wantValid {
-				t.Errorf("Valid = %v, want %v", n.Valid, tt.wantValid)
-			}
-
-			if !tt.wantValid && !n.ID.IsNil() {
-				t.Error("Invalid NullNano64 should have Nil ID")
-			}
-		})
-	}
-}
-
-func TestNullNano64_Value(t *testing.T) {
-	// Test valid NullNano64
-	id, err := GenerateDefault()
-	if err != nil {
-		t.Fatalf("GenerateDefault() error = %v", err)
-	}
-
-	validNull := NullNano64{ID: id, Valid: true}
-	val, err := validNull.Value()
-	if err != nil {
-		t.Fatalf("Value() error = %v", err)
-	}
-	if val == nil {
-		t.Error("Valid NullNano64.Value() should not be nil")
-	}
-
-	// Test invalid NullNano64
-	invalidNull := NullNano64{Valid: false}
-	val, err = invalidNull.Value()
-	if err != nil {
-		t.Fatalf("Value() error = %v", err)
-	}
-	if val != nil {
-		t.Errorf("Invalid NullNano64.Value() = %v, want nil", val)
-	}
-}
-
 func TestNullNano64_JSON(t *testing.T) {
 	// Test marshaling valid NullNano64
 	id, err := GenerateDefault()
@@ -1753,62 +1274,3 @@ func TestNullNano64_JSON(t *testing.T) {
 		t.Error("Unmarshaled null should be invalid")
 	}
 }
-
-func TestNullNano64_Database(t *testing.T) {
-	// Create in-memory SQLite database
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	db, err := sql.Open("sqlite", dbPath)
-	if err != nil {
-		t.Fatalf("failed to open database: %v", err)
-	}
-	defer db.Close()
-
-	// Create test table
-	_, err = db.Exec(`
-		CREATE TABLE test_null (
-			id INTEGER PRIMARY KEY,
-			nullable_id BLOB,
-			non_null_id BLOB NOT NULL
-		)
-	`)
-	if err != nil {
-		t.Fatalf("failed to create table: %v", err)
-	}
-
-	// Test inserting NULL value
-	nullID := NullNano64{Valid: false}
-	validID, err := GenerateDefault()
-	if err != nil {
-		t.Fatalf("GenerateDefault() error = %v", err)
-	}
-	validNullID := NullNano64{ID: validID, Valid: true}
-
-	_, err = db.Exec("INSERT INTO test_null (id, nullable_id, non_null_id) VALUES (?, ?, ?)",
-		1, nullID, validNullID)
-	if err != nil {
-		t.Fatalf("failed to insert: %v", err)
-	}
-
-	// Test querying NULL value
-	var retrievedNull NullNano64
-	var retrievedValid NullNano64
-	err = db.QueryRow("SELECT nullable_id, non_null_id FROM test_null WHERE id = ?", 1).
-		Scan(&retrievedNull, &retrievedValid)
-	if err != nil {
-		t.Fatalf("failed to query: %v", err)
-	}
-
-	if retrievedNull.Valid {
-		t.Error("Retrieved null ID should be invalid")
-	}
-
-	if !retrievedValid.Valid {
-		t.Error("Retrieved valid ID should be valid")
-	}
-
-	if !retrievedValid.ID.Equals(validID) {
-		t.Error("Retrieved ID does not match original")
-	}
-}
@@ -0,0 +1,459 @@
+// Package nano64bench compares Nano64 with other time-ordered ID schemes on the
+// host it runs on: generation and text encoding cost, SQL insert and lookup cost
+// against a real database, and how close to the end of an index new IDs land.
+//
+//	report, err := nano64bench.Run(ctx, nano64bench.Options{DB: db, Dialect: nano64.DialectPostgres})
+//	if err != nil {
+//		return err
+//	}
+//	json.NewEncoder(os.Stdout).Encode(report)
+//
+// Schemes returns adapters for Nano64 in its random and monotonic modes, UUIDv7
+// (google/uuid), ULID (oklog/ulid) and xid (rs/xid); other libraries are
+// compared by adding a Scheme.
+package nano64bench
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+	"github.com/pisoj/go-nano64"
+	"github.com/rs/xid"
+)
+
+// Scheme adapts an ID library to the suite. IDs are handled in their binary
+// form, which is also the form stored in SQL and compared for index locality.
+type Scheme struct {
+	// Name identifies the scheme in reports and table names.
+	Name string
+	// Size is the length of the binary form in bytes.
+	Size int
+	// Generate appends a new ID to dst.
+	Generate func(dst []byte) ([]byte, error)
+	// Encode returns the canonical text form of an ID.
+	Encode func(id []byte) string
+	// Decode appends the binary form of the text s to dst.
+	Decode func(dst []byte, s string) ([]byte, error)
+}
+
+// Nano64 is the Scheme of nano64.GenerateDefault and the dashed hex form. Its
+// random field is drawn afresh for every ID, so IDs generated within one
+// millisecond land in random order; see Nano64Monotonic.
+func Nano64() Scheme {
+	return nano64Scheme("nano64", nano64.GenerateDefault)
+}
+
+// Nano64Monotonic is the Scheme of nano64.GenerateMonotonicDefault, which
+// increments the random field within a millisecond. It is the mode to compare
+// with the monotonic UUIDv7 and ULID generators.
+func Nano64Monotonic() Scheme {
+	return nano64Scheme("nano64-monotonic", nano64.GenerateMonotonicDefault)
+}
+
+// nano64Scheme returns a Nano64 Scheme named name that generates with generate.
+func nano64Scheme(name string, generate func() (nano64.Nano64, error)) Scheme {
+	return Scheme{
+		Name: name,
+		Size: 8,
+		Generate: func(dst []byte) ([]byte, error) {
+			id, err := generate()
+			return binary.BigEndian.AppendUint64(dst, id.Uint64Value()), err
+		},
+		Encode: func(id []byte) string {
+			return nano64.FromUint64(binary.BigEndian.Uint64(id)).ToHex()
+		},
+		Decode: func(dst []byte, s string) ([]byte, error) {
+			id, err := nano64.FromHex(s)
+			return binary.BigEndian.AppendUint64(dst, id.Uint64Value()), err
+		},
+	}
+}
+
+// UUIDv7 is the Scheme of github.com/google/uuid version 7 UUIDs.
+func UUIDv7() Scheme {
+	return Scheme{
+		Name: "uuidv7",
+		Size: 16,
+		Generate: func(dst []byte) ([]byte, error) {
+			id, err := uuid.NewV7()
+			return append(dst, id[:]...), err
+		},
+		Encode: func(id []byte) string {
+			return uuid.UUID(id).String()
+		},
+		Decode: func(dst []byte, s string) ([]byte, error) {
+			id, err := uuid.Parse(s)
+			return append(dst, id[:]...), err
+		},
+	}
+}
+
+// ULID is the Scheme of github.com/oklog/ulid/v2 with its default monotonic
+// entropy.
+func ULID() Scheme {
+	return Scheme{
+		Name: "ulid",
+		Size: 16,
+		Generate: func(dst []byte) ([]byte, error) {
+			id := ulid.Make()
+			return append(dst, id[:]...), nil
+		},
+		Encode: func(id []byte) string {
+			return ulid.ULID(id).String()
+		},
+		Decode: func(dst []byte, s string) ([]byte, error) {
+			id, err := ulid.ParseStrict(s)
+			return append(dst, id[:]...), err
+		},
+	}
+}
+
+// XID is the Scheme of github.com/rs/xid.
+func XID() Scheme {
+	return Scheme{
+		Name: "xid",
+		Size: 12,
+		Generate: func(dst []byte) ([]byte, error) {
+			id := xid.New()
+			return append(dst, id[:]...), nil
+		},
+		Encode: func(id []byte) string {
+			return xid.ID(id).String()
+		},
+		Decode: func(dst []byte, s string) ([]byte, error) {
+			id, err := xid.FromString(s)
+			return append(dst, id[:]...), err
+		},
+	}
+}
+
+// Schemes returns Nano64, Nano64Monotonic, UUIDv7, ULID and XID.
+func Schemes() []Scheme {
+	return []Scheme{Nano64(), Nano64Monotonic(), UUIDv7(), ULID(), XID()}
+}
+
+// Options configures Run.
+type Options struct {
+	// Schemes are the schemes compared; Schemes() if nil.
+	Schemes []Scheme
+
+	// Duration is how long each operation is timed per scheme; 1s if zero.
+	Duration time.Duration
+
+	// LocalityIDs is the number of IDs generated to measure index locality;
+	// 100000 if zero.
+	LocalityIDs int
+
+	// DB, when set, is used for the SQL round trip: a table per scheme is created,
+	// filled with Rows IDs and read back by primary key, then dropped.
+	DB      *sql.DB
+	Dialect nano64.Dialect
+	// Rows is the number of rows inserted per scheme; 10000 if zero.
+	Rows int
+	// TablePrefix prefixes the scheme name to form table names; "nano64bench_"
+	// if empty.
+	TablePrefix string
+}
+
+// Report is the result of Run. It is meant to be stored as JSON and compared
+// across hosts and versions.
+type Report struct {
+	Started   time.Time `json:"started"`
+	GoVersion string    `json:"go_version"`
+	GOOS      string    `json:"goos"`
+	GOARCH    string    `json:"goarch"`
+	NumCPU    int       `json:"num_cpu"`
+	Dialect   string    `json:"dialect,omitempty"`
+	Results   []Result  `json:"results"`
+}
+
+// Result holds the measurements of one scheme.
+type Result struct {
+	Scheme string `json:"scheme"`
+	// Size and TextSize are the lengths of the binary and text forms in bytes.
+	Size     int `json:"size"`
+	TextSize int `json:"text_size"`
+
+	Generate Measurement `json:"generate"`
+	Encode   Measurement `json:"encode"`
+	Decode   Measurement `json:"decode"`
+
+	// Insert and Select are per row; they are nil without Options.DB.
+	Insert *Measurement `json:"insert,omitempty"`
+	Select *Measurement `json:"select,omitempty"`
+
+	Locality Locality `json:"locality"`
+}
+
+// Measurement is the cost of one operation.
+type Measurement struct {
+	Ops         int64   `json:"ops"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+}
+
+// Locality describes where IDs generated in sequence land in a sorted index.
+// Time-ordered IDs approach Appended 1 and Displacement 0; random IDs approach
+// Appended 0 and Displacement 0.5.
+type Locality struct {
+	IDs int `json:"ids"`
+	// Appended is the fraction of IDs greater than every ID generated before them,
+	// which a B-tree appends to its rightmost page.
+	Appended float64 `json:"appended"`
+	// Displacement is the mean fraction of earlier IDs that sort after each ID,
+	// i.e. how far from the end of the index inserts land.
+	Displacement float64 `json:"displacement"`
+}
+
+// Run measures every scheme of opts in turn.
+func Run(ctx context.Context, opts Options) (Report, error) {
+	if opts.Schemes == nil {
+		opts.Schemes = Schemes()
+	}
+	if opts.Duration == 0 {
+		opts.Duration = time.Second
+	}
+	if opts.LocalityIDs == 0 {
+		opts.LocalityIDs = 100_000
+	}
+	if opts.Rows == 0 {
+		opts.Rows = 10_000
+	}
+	if opts.TablePrefix == "" {
+		opts.TablePrefix = "nano64bench_"
+	}
+	if opts.Duration < 0 || opts.LocalityIDs < 0 || opts.Rows < 0 {
+		return Report{}, errors.New("Duration, LocalityIDs and Rows must not be negative")
+	}
+
+	report := Report{
+		Started:   time.Now(),
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+	}
+	if opts.DB != nil {
+		report.Dialect = opts.Dialect.String()
+	}
+	for _, s := range opts.Schemes {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		r, err := runScheme(ctx, s, opts)
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", s.Name, err)
+		}
+		report.Results = append(report.Results, r)
+	}
+	return report, nil
+}
+
+// runScheme measures one scheme.
+func runScheme(ctx context.Context, s Scheme, opts Options) (Result, error) {
+	if s.Name == "" || s.Size <= 0 || s.Generate == nil || s.Encode == nil || s.Decode == nil {
+		return Result{}, errors.New("incomplete Scheme")
+	}
+	sample, err := s.Generate(nil)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(sample) != s.Size {
+		return Result{}, fmt.Errorf("generated %d bytes, want %d", len(sample), s.Size)
+	}
+	text := s.Encode(sample)
+	if decoded, err := s.Decode(nil, text); err != nil || !bytes.Equal(decoded, sample) {
+		return Result{}, fmt.Errorf("text %q does not round trip: %x, %v", text, decoded, err)
+	}
+	r := Result{Scheme: s.Name, Size: s.Size, TextSize: len(text)}
+
+	buf := make([]byte, 0, s.Size)
+	if r.Generate, err = measure(opts.Duration, func() error {
+		_, err := s.Generate(buf[:0])
+		return err
+	}); err != nil {
+		return r, err
+	}
+	r.Encode, _ = measure(opts.Duration, func() error {
+		_ = s.Encode(sample)
+		return nil
+	})
+	if r.Decode, err = measure(opts.Duration, func() error {
+		_, err := s.Decode(buf[:0], text)
+		return err
+	}); err != nil {
+		return r, err
+	}
+
+	if r.Locality, err = locality(s, opts.LocalityIDs); err != nil {
+		return r, err
+	}
+	if opts.DB != nil {
+		if r.Insert, r.Select, err = roundTrip(ctx, s, opts); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// measure calls op repeatedly for at least d.
+func measure(d time.Duration, op func() error) (Measurement, error) {
+	const batch = 256
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	var ops int64
+	for ops == 0 || time.Since(start) < d {
+		for range batch {
+			if err := op(); err != nil {
+				return Measurement{}, err
+			}
+		}
+		ops += batch
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return Measurement{
+		Ops:         ops,
+		NsPerOp:     float64(elapsed.Nanoseconds()) / float64(ops),
+		AllocsPerOp: float64(after.Mallocs-before.Mallocs) / float64(ops),
+		BytesPerOp:  float64(after.TotalAlloc-before.TotalAlloc) / float64(ops),
+	}, nil
+}
+
+// locality generates n IDs in sequence and measures where each lands among the
+// earlier ones, counting them with a Fenwick tree over the final sort order.
+func locality(s Scheme, n int) (Locality, error) {
+	if n == 0 {
+		return Locality{}, nil
+	}
+	ids := make([][]byte, n)
+	for i := range ids {
+		id, err := s.Generate(nil)
+		if err != nil {
+			return Locality{}, err
+		}
+		ids[i] = id
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return bytes.Compare(ids[a], ids[b]) })
+	rank := make([]int, n)
+	for r, i := range order {
+		rank[i] = r
+	}
+
+	tree := make([]int, n+1)
+	appended := 0
+	displacement := 0.0
+	for i, r := range rank {
+		// Earlier IDs sorting at or before this one.
+		before := 0
+		for j := r + 1; j > 0; j -= j & -j {
+			before += tree[j]
+		}
+		if after := i - before; after == 0 {
+			appended++
+		} else {
+			displacement += float64(after) / float64(i)
+		}
+		for j := r + 1; j <= n; j += j & -j {
+			tree[j]++
+		}
+	}
+	return Locality{
+		IDs:          n,
+		Appended:     float64(appended) / float64(n),
+		Displacement: displacement / float64(n),
+	}, nil
+}
+
+// roundTrip inserts opts.Rows IDs of s into a fresh table in one transaction and
+// reads each back by primary key, returning the cost per row of both.
+func roundTrip(ctx context.Context, s Scheme, opts Options) (insert, lookup *Measurement, err error) {
+	d := opts.Dialect
+	if _, err := nano64.ParseDialect(d.String()); err != nil {
+		return nil, nil, err
+	}
+	columnType := "BLOB"
+	switch d {
+	case nano64.DialectPostgres:
+		columnType = "bytea"
+	case nano64.DialectMySQL:
+		columnType = fmt.Sprintf("VARBINARY(%d)", s.Size)
+	}
+	table := d.QuoteIdent(opts.TablePrefix + strings.ToLower(s.Name))
+	drop := "DROP TABLE IF EXISTS " + table
+	if _, err := opts.DB.ExecContext(ctx, drop); err != nil {
+		return nil, nil, err
+	}
+	defer opts.DB.ExecContext(context.WithoutCancel(ctx), drop)
+	if _, err := opts.DB.ExecContext(ctx, "CREATE TABLE "+table+" (id "+columnType+" PRIMARY KEY, n INTEGER NOT NULL)"); err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([][]byte, opts.Rows)
+	for i := range ids {
+		if ids[i], err = s.Generate(nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	start := time.Now()
+	tx, err := opts.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO "+table+" (id, n) VALUES ("+d.Placeholder(1)+", "+d.Placeholder(2)+")")
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, id := range ids {
+		if _, err := stmt.ExecContext(ctx, id, i); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	insert = perRow(time.Since(start), len(ids))
+
+	start = time.Now()
+	query, err := opts.DB.PrepareContext(ctx, "SELECT n FROM "+table+" WHERE id = "+d.Placeholder(1))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer query.Close()
+	for i, id := range ids {
+		var n int
+		if err := query.QueryRowContext(ctx, id).Scan(&n); err != nil {
+			return nil, nil, fmt.Errorf("select %s: %w", s.Encode(id), err)
+		}
+		if n != i {
+			return nil, nil, fmt.Errorf("select %s returned row %d, want %d", s.Encode(id), n, i)
+		}
+	}
+	return insert, perRow(time.Since(start), len(ids)), nil
+}
+
+// perRow returns the Measurement of rows operations taking elapsed in total.
+func perRow(elapsed time.Duration, rows int) *Measurement {
+	if rows == 0 {
+		return &Measurement{}
+	}
+	return &Measurement{Ops: int64(rows), NsPerOp: float64(elapsed.Nanoseconds()) / float64(rows)}
+}
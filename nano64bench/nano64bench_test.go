@@ -0,0 +1,108 @@
+package nano64bench
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+	_ "modernc.org/sqlite"
+)
+
+// random is a Scheme of unordered IDs, the worst case for index locality.
+var random = Scheme{
+	Name: "random",
+	Size: 8,
+	Generate: func(dst []byte) ([]byte, error) {
+		var b [8]byte
+		_, err := rand.Read(b[:])
+		return append(dst, b[:]...), err
+	},
+	Encode: hex.EncodeToString,
+	Decode: func(dst []byte, s string) ([]byte, error) {
+		b, err := hex.DecodeString(s)
+		return append(dst, b...), err
+	},
+}
+
+func TestRun(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "bench.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	report, err := Run(context.Background(), Options{
+		Schemes:     append(Schemes(), random),
+		Duration:    5 * time.Millisecond,
+		LocalityIDs: 5000,
+		DB:          db,
+		Dialect:     nano64.DialectSQLite,
+		Rows:        200,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(report.Results) != 6 || report.Dialect != "sqlite" {
+		t.Fatalf("Run() = %d results for %q", len(report.Results), report.Dialect)
+	}
+
+	results := make(map[string]Result)
+	for _, r := range report.Results {
+		results[r.Scheme] = r
+		if r.Generate.Ops == 0 || r.Generate.NsPerOp <= 0 || r.Decode.Ops == 0 {
+			t.Errorf("%s: missing timings %+v", r.Scheme, r)
+		}
+		if r.Insert == nil || r.Select == nil || r.Insert.Ops != 200 || r.Select.Ops != 200 {
+			t.Errorf("%s: SQL round trip = %+v, %+v", r.Scheme, r.Insert, r.Select)
+		}
+	}
+	sizes := map[string][2]int{"nano64": {8, 17}, "nano64-monotonic": {8, 17}, "uuidv7": {16, 36}, "ulid": {16, 26}, "xid": {12, 20}}
+	for name, want := range sizes {
+		if r := results[name]; r.Size != want[0] || r.TextSize != want[1] {
+			t.Errorf("%s: sizes %d, %d; want %v", name, r.Size, r.TextSize, want)
+		}
+	}
+	for _, name := range []string{"nano64-monotonic", "uuidv7", "ulid"} {
+		if l := results[name].Locality; l.Appended != 1 || l.Displacement != 0 {
+			t.Errorf("%s: monotonic scheme has locality %+v", name, l)
+		}
+	}
+	if l := results["random"].Locality; l.Appended > 0.05 || math.Abs(l.Displacement-0.5) > 0.05 {
+		t.Errorf("random: locality %+v, want about 0 appended and 0.5 displacement", l)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"ns_per_op"`) {
+		t.Errorf("report JSON %s", data)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := map[string]Options{
+		"incomplete scheme": {Schemes: []Scheme{{Name: "broken", Size: 8}}},
+		"wrong size":        {Schemes: []Scheme{func() Scheme { s := random; s.Size = 16; return s }()}},
+		"negative duration": {Duration: -time.Second},
+	}
+	for name, opts := range tests {
+		if _, err := Run(context.Background(), opts); err == nil {
+			t.Errorf("%s: Run() error = nil", name)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run(ctx, Options{Duration: time.Millisecond}); err == nil {
+		t.Error("Run() with a canceled context error = nil")
+	}
+}
@@ -0,0 +1,93 @@
+// Package nano64blake3 pairs IDs with a BLAKE3 digest of the content they name,
+// for blob stores that want time-sortable keys with integrity checking built in:
+//
+//	loc := nano64blake3.WithContentHash(id, blob)
+//	store.Put(loc.String(), blob)
+//	...
+//	if err := loc.Verify(fetched); err != nil { ... }
+package nano64blake3
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	nano64 "github.com/pisoj/go-nano64"
+	"lukechampine.com/blake3"
+)
+
+// hashLength is the length of the truncated BLAKE3 digest in a Locator.
+const hashLength = 16
+
+// ErrMismatch is returned by Locator.Verify for data whose digest differs from
+// the locator's.
+var ErrMismatch = errors.New("content does not match locator")
+
+// Locator identifies a blob by an ID and a 128-bit BLAKE3 digest of its
+// content, giving blob stores keys that sort by time and also let every read be
+// checked for corruption. Its text form is the hex ID, a dot and the hex digest,
+// e.g. "018F1C2B3D4-5E6F7.9a0b..."; it sorts by ID.
+type Locator struct {
+	ID   nano64.Nano64
+	Hash [hashLength]byte
+}
+
+// WithContentHash returns the locator of data stored under id.
+func WithContentHash(id nano64.Nano64, data []byte) Locator {
+	sum := blake3.Sum256(data)
+	loc := Locator{ID: id}
+	copy(loc.Hash[:], sum[:hashLength])
+	return loc
+}
+
+// Verify returns ErrMismatch unless data hashes to the locator's digest.
+func (l Locator) Verify(data []byte) error {
+	expected := WithContentHash(l.ID, data)
+	if subtle.ConstantTimeCompare(expected.Hash[:], l.Hash[:]) != 1 {
+		return fmt.Errorf("%w: %s", ErrMismatch, l)
+	}
+	return nil
+}
+
+// String returns the text form of the locator.
+func (l Locator) String() string {
+	return l.ID.ToHex() + "." + hex.EncodeToString(l.Hash[:])
+}
+
+// Parse parses the form produced by Locator.String. The digest may be in either
+// case.
+func Parse(s string) (Locator, error) {
+	idHex, hashHex, ok := strings.Cut(s, ".")
+	if !ok {
+		return Locator{}, fmt.Errorf("content locator %q has no digest", s)
+	}
+	id, err := nano64.FromHex(idHex)
+	if err != nil {
+		return Locator{}, fmt.Errorf("invalid content locator ID: %w", err)
+	}
+	loc := Locator{ID: id}
+	if len(hashHex) != 2*hashLength {
+		return Locator{}, fmt.Errorf("content locator digest must be %d hex characters, got %d", 2*hashLength, len(hashHex))
+	}
+	if _, err := hex.Decode(loc.Hash[:], []byte(hashHex)); err != nil {
+		return Locator{}, fmt.Errorf("invalid content locator digest: %w", err)
+	}
+	return loc, nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Locator) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *Locator) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
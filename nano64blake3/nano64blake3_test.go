@@ -0,0 +1,58 @@
+package nano64blake3
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	nano64 "github.com/pisoj/go-nano64"
+)
+
+func TestLocator(t *testing.T) {
+	id := mustGenerate(t, 1718000000000)
+	data := []byte("hello, blob")
+	loc := WithContentHash(id, data)
+
+	// The digest is BLAKE3 truncated to 128 bits.
+	if got := WithContentHash(id, nil).String(); got != id.ToHex()+".af1349b9f5f9a1a6a0404dea36dcc949" {
+		t.Errorf("locator of empty data = %q", got)
+	}
+	if got := loc.String(); !strings.HasPrefix(got, id.ToHex()+".") || len(got) != len(id.ToHex())+1+32 {
+		t.Errorf("String() = %q", got)
+	}
+	if err := loc.Verify(data); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := loc.Verify([]byte("hello, blob!")); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Verify(altered) error = %v, want ErrMismatch", err)
+	}
+	if WithContentHash(mustGenerate(t, 1718000000001), data).Hash != loc.Hash {
+		t.Error("digest depends on the ID")
+	}
+
+	parsed, err := Parse(strings.ToUpper(loc.String()))
+	if err != nil || parsed != loc {
+		t.Errorf("Parse() = %v, %v, want %v", parsed, err, loc)
+	}
+	encoded, _ := json.Marshal(loc)
+	var decoded Locator
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded != loc {
+		t.Errorf("JSON round trip via %s = %v, %v", encoded, decoded, err)
+	}
+
+	for _, bad := range []string{id.ToHex(), id.ToHex() + ".abc", "zz." + strings.Repeat("0", 32), id.ToHex() + "." + strings.Repeat("g", 32)} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded", bad)
+		}
+	}
+}
+
+func mustGenerate(t *testing.T, ts int64) nano64.Nano64 {
+	t.Helper()
+	id, err := nano64.Generate(ts, nil)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	return id
+}
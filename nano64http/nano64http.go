@@ -0,0 +1,47 @@
+// Package nano64http applies Nano64 ID policies to HTTP handlers, keeping
+// net/http out of the core package:
+//
+//	gate := nano64http.AgeGate{Policy: nano64.AgeGate(24 * time.Hour)}
+//	mux.Handle("POST /webhook", gate.Middleware(eventID, handler))
+package nano64http
+
+import (
+	"net/http"
+
+	"github.com/pisoj/go-nano64"
+)
+
+// AgeGate admits requests by the age of the ID they reference, per Policy.
+type AgeGate struct {
+	Policy nano64.AgePolicy
+
+	// FlagOnly reports violations to OnViolation but lets the request through,
+	// for rolling a policy out in observation mode first.
+	FlagOnly bool
+
+	// OnViolation, if set, is called for every violation, rejected or flagged.
+	OnViolation func(*http.Request, nano64.AgeViolation)
+}
+
+// Middleware gates next on the ID extract finds in each request, such as a path
+// value or an event ID header. Requests without one pass through. A violating
+// request gets 403 Forbidden, or passes if FlagOnly is set.
+func (g AgeGate) Middleware(extract func(*http.Request) (nano64.Nano64, bool), next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := extract(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if v, violates := g.Policy.Violation(id); violates {
+			if g.OnViolation != nil {
+				g.OnViolation(r, v)
+			}
+			if !g.FlagOnly {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
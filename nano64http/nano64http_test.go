@@ -0,0 +1,60 @@
+package nano64http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pisoj/go-nano64"
+)
+
+func TestAgeGateMiddleware(t *testing.T) {
+	const now = int64(1_700_000_000_000)
+	generate := func(ts int64) nano64.Nano64 {
+		id, err := nano64.Generate(ts, func(int) (uint32, error) { return 1, nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	stale, fresh := generate(now-48*3600*1000), generate(now)
+
+	var violations []nano64.AgeViolation
+	g := AgeGate{Policy: nano64.AgeGate(time.Hour)}
+	g.Policy.Clock = func() int64 { return now }
+	g.OnViolation = func(_ *http.Request, v nano64.AgeViolation) { violations = append(violations, v) }
+
+	extract := func(r *http.Request) (nano64.Nano64, bool) {
+		id, err := nano64.FromHex(r.Header.Get("Event-Id"))
+		return id, err == nil
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	serve := func(g AgeGate, id string) int {
+		r := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+		if id != "" {
+			r.Header.Set("Event-Id", id)
+		}
+		w := httptest.NewRecorder()
+		g.Middleware(extract, next).ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := serve(g, fresh.ToHex()); code != http.StatusNoContent {
+		t.Errorf("fresh ID: status %d", code)
+	}
+	if code := serve(g, ""); code != http.StatusNoContent {
+		t.Errorf("no ID: status %d", code)
+	}
+	if code := serve(g, stale.ToHex()); code != http.StatusForbidden {
+		t.Errorf("stale ID: status %d, want 403", code)
+	}
+	g.FlagOnly = true
+	if code := serve(g, stale.ToHex()); code != http.StatusNoContent {
+		t.Errorf("stale ID in flag-only mode: status %d", code)
+	}
+	if len(violations) != 2 || violations[0].ID != stale || violations[0].Age != 48*time.Hour {
+		t.Errorf("violations = %+v", violations)
+	}
+}
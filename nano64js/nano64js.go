@@ -0,0 +1,144 @@
+//go:build js && wasm
+
+// Package nano64js converts Nano64 IDs to and from JavaScript values for programs
+// compiled with GOOS=js GOARCH=wasm. IDs cross the boundary as BigInt, since a JS
+// Number cannot hold 64 bits, or as the dashed hex string produced by ToHex.
+//
+// Export builds an object that a frontend can call directly:
+//
+//	js.Global().Set("nano64", nano64js.Export())
+//
+//	const id = nano64.generate();           // 0x0123...n
+//	nano64.toHex(id);                       // "0123456789A-BCDEF"
+//	nano64.timestamp(nano64.fromHex(hex));  // Unix milliseconds
+//
+// Exported functions return a JS Error object instead of throwing when an argument
+// is invalid.
+package nano64js
+
+import (
+	"fmt"
+	"strconv"
+	"syscall/js"
+
+	nano64 "github.com/pisoj/go-nano64"
+)
+
+// ToBigInt returns id as an unsigned JS BigInt.
+func ToBigInt(id nano64.Nano64) js.Value {
+	return js.Global().Get("BigInt").Invoke(strconv.FormatUint(id.Uint64Value(), 10))
+}
+
+// ToSignedBigInt returns id in its SignedNano64 form as a JS BigInt, matching the
+// value stored in a BIGINT column.
+func ToSignedBigInt(id nano64.Nano64) js.Value {
+	return js.Global().Get("BigInt").Invoke(strconv.FormatInt(nano64.SignedNano64.FromId(id), 10))
+}
+
+// FromBigInt converts a JS BigInt to a Nano64. Negative values are read as their
+// 64-bit two's complement, so both ToBigInt and ToSignedBigInt results are accepted
+// as long as the caller knows which form it holds; use FromSignedBigInt for the latter.
+func FromBigInt(v js.Value) (nano64.Nano64, error) {
+	bits, err := bigIntBits(v)
+	if err != nil {
+		return nano64.Nil, err
+	}
+	return nano64.FromUint64(bits), nil
+}
+
+// FromSignedBigInt converts a JS BigInt in SignedNano64 form to a Nano64.
+func FromSignedBigInt(v js.Value) (nano64.Nano64, error) {
+	bits, err := bigIntBits(v)
+	if err != nil {
+		return nano64.Nil, err
+	}
+	return nano64.SignedNano64.ToId(int64(bits)), nil
+}
+
+// ToHexString returns id's dashed hex form as a JS string.
+func ToHexString(id nano64.Nano64) js.Value {
+	return js.ValueOf(id.ToHex())
+}
+
+// FromHexString parses a JS string produced by ToHex.
+func FromHexString(v js.Value) (nano64.Nano64, error) {
+	if isBigInt(v) || v.Type() != js.TypeString {
+		return nano64.Nil, fmt.Errorf("expected string")
+	}
+	return nano64.FromHex(v.String())
+}
+
+// isBigInt reports whether v is a BigInt. syscall/js has no Type for BigInt (and
+// Value.Type panics on one), so the check goes through the BigInt constructor:
+// it returns BigInts unchanged, converts some other values and throws on the rest.
+// Methods cannot be called on a BigInt Value either, so conversions go through
+// global functions.
+func isBigInt(v js.Value) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, isJSError := r.(js.Error); !isJSError {
+				panic(r)
+			}
+			ok = false
+		}
+	}()
+	return js.Global().Get("BigInt").Invoke(v).Equal(v)
+}
+
+// bigIntBits returns the low 64 bits of a BigInt.
+func bigIntBits(v js.Value) (uint64, error) {
+	if !isBigInt(v) {
+		return 0, fmt.Errorf("expected bigint")
+	}
+	wrapped := js.Global().Get("BigInt").Call("asUintN", 64, v)
+	bits, err := strconv.ParseUint(js.Global().Get("String").Invoke(wrapped).String(), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bigint: %w", err)
+	}
+	return bits, nil
+}
+
+// Export returns a JS object exposing generate, generateMonotonic, toHex, fromHex,
+// toSigned, fromSigned and timestamp. IDs are passed as unsigned BigInt.
+func Export() js.Value {
+	obj := js.Global().Get("Object").New()
+	obj.Set("generate", js.FuncOf(func(js.Value, []js.Value) any {
+		id, err := nano64.GenerateDefault()
+		if err != nil {
+			return jsError(err)
+		}
+		return ToBigInt(id)
+	}))
+	obj.Set("generateMonotonic", js.FuncOf(func(js.Value, []js.Value) any {
+		id, err := nano64.GenerateMonotonicDefault()
+		if err != nil {
+			return jsError(err)
+		}
+		return ToBigInt(id)
+	}))
+	obj.Set("toHex", exportID(FromBigInt, func(id nano64.Nano64) any { return ToHexString(id) }))
+	obj.Set("fromHex", exportID(FromHexString, func(id nano64.Nano64) any { return ToBigInt(id) }))
+	obj.Set("toSigned", exportID(FromBigInt, func(id nano64.Nano64) any { return ToSignedBigInt(id) }))
+	obj.Set("fromSigned", exportID(FromSignedBigInt, func(id nano64.Nano64) any { return ToBigInt(id) }))
+	obj.Set("timestamp", exportID(FromBigInt, func(id nano64.Nano64) any { return id.GetTimestamp() }))
+	return obj
+}
+
+// exportID wraps a one-argument conversion as a JS function.
+func exportID(parse func(js.Value) (nano64.Nano64, error), format func(nano64.Nano64) any) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		if len(args) != 1 {
+			return jsError(fmt.Errorf("expected 1 argument, got %d", len(args)))
+		}
+		id, err := parse(args[0])
+		if err != nil {
+			return jsError(err)
+		}
+		return format(id)
+	})
+}
+
+// jsError wraps err in a JS Error object.
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
@@ -0,0 +1,68 @@
+//go:build js && wasm
+
+package nano64js
+
+import (
+	"syscall/js"
+	"testing"
+
+	nano64 "github.com/pisoj/go-nano64"
+)
+
+func TestBigIntRoundTrip(t *testing.T) {
+	ids := []nano64.Nano64{nano64.New(0), nano64.New(1), nano64.New(0x0123456789ABCDEF), nano64.New(^uint64(0))}
+
+	for _, id := range ids {
+		got, err := FromBigInt(ToBigInt(id))
+		if err != nil {
+			t.Fatalf("FromBigInt() error = %v", err)
+		}
+		if !got.Equals(id) {
+			t.Errorf("unsigned round trip = %s, want %s", got, id)
+		}
+
+		got, err = FromSignedBigInt(ToSignedBigInt(id))
+		if err != nil {
+			t.Fatalf("FromSignedBigInt() error = %v", err)
+		}
+		if !got.Equals(id) {
+			t.Errorf("signed round trip = %s, want %s", got, id)
+		}
+	}
+}
+
+func TestToBigIntValue(t *testing.T) {
+	got := js.Global().Get("String").Invoke(ToBigInt(nano64.New(^uint64(0)))).String()
+	if got != "18446744073709551615" {
+		t.Errorf("ToBigInt() = %s, want 18446744073709551615", got)
+	}
+}
+
+func TestFromBigIntRejectsNonBigInt(t *testing.T) {
+	for _, v := range []js.Value{js.ValueOf(42), js.ValueOf(1.5), js.ValueOf("42"), js.Null()} {
+		if _, err := FromBigInt(v); err == nil {
+			t.Errorf("FromBigInt(%v) expected error", v)
+		}
+	}
+	if _, err := FromHexString(ToBigInt(nano64.New(1))); err == nil {
+		t.Error("FromHexString(bigint) expected error")
+	}
+}
+
+func TestExport(t *testing.T) {
+	obj := Export()
+	id := obj.Call("generate")
+	if !isBigInt(id) {
+		t.Fatal("generate() did not return a bigint")
+	}
+
+	hex := obj.Call("toHex", id)
+	back := obj.Call("fromHex", hex)
+	if !obj.Call("toHex", back).Equal(hex) {
+		t.Errorf("toHex(fromHex(%s)) mismatch", hex.String())
+	}
+
+	if res := obj.Call("fromHex", "nope"); !res.InstanceOf(js.Global().Get("Error")) {
+		t.Errorf("fromHex(invalid) = %v, want Error", res)
+	}
+}
@@ -0,0 +1,57 @@
+// Package nano64jsoniter registers encoders and decoders for nano64.Nano64 and
+// nano64.NullNano64 with github.com/json-iterator/go. Without them jsoniter calls
+// MarshalJSON and then re-validates its output; the registered functions append
+// the ID directly to the stream buffer and decode without intermediate copies.
+// Output matches encoding/json, including omitempty, which never omits either
+// type since both are structs: a Nil ID is written as its hex form.
+//
+//	func init() { nano64jsoniter.Register() }
+package nano64jsoniter
+
+import (
+	"sync"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	nano64 "github.com/pisoj/go-nano64"
+)
+
+var registerOnce sync.Once
+
+// Register installs the codecs for every jsoniter API created afterwards.
+// It is safe to call more than once.
+func Register() {
+	registerOnce.Do(func() {
+		jsoniter.RegisterTypeEncoderFunc("nano64.Nano64", encodeNano64, neverEmpty)
+		jsoniter.RegisterTypeDecoderFunc("nano64.Nano64", decodeNano64)
+		jsoniter.RegisterTypeEncoderFunc("nano64.NullNano64", encodeNullNano64, neverEmpty)
+		jsoniter.RegisterTypeDecoderFunc("nano64.NullNano64", decodeNullNano64)
+	})
+}
+
+// neverEmpty keeps omitempty fields, as encoding/json does for struct types.
+func neverEmpty(unsafe.Pointer) bool {
+	return false
+}
+
+func encodeNano64(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.SetBuffer((*nano64.Nano64)(ptr).AppendJSON(stream.Buffer()))
+}
+
+func decodeNano64(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	var buf [32]byte
+	if err := (*nano64.Nano64)(ptr).UnmarshalJSON(iter.SkipAndAppendBytes(buf[:0])); err != nil {
+		iter.ReportError("decode Nano64", err.Error())
+	}
+}
+
+func encodeNullNano64(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.SetBuffer((*nano64.NullNano64)(ptr).AppendJSON(stream.Buffer()))
+}
+
+func decodeNullNano64(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	var buf [32]byte
+	if err := (*nano64.NullNano64)(ptr).UnmarshalJSON(iter.SkipAndAppendBytes(buf[:0])); err != nil {
+		iter.ReportError("decode NullNano64", err.Error())
+	}
+}
@@ -0,0 +1,48 @@
+package nano64jsoniter
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	nano64 "github.com/pisoj/go-nano64"
+)
+
+type payload struct {
+	ID     nano64.Nano64     `json:"id"`
+	Parent nano64.NullNano64 `json:"parent"`
+	Ref    nano64.Nano64     `json:"ref,omitempty"`
+	Link   nano64.NullNano64 `json:"link,omitempty"`
+}
+
+func TestRegister(t *testing.T) {
+	Register()
+	Register()
+	api := jsoniter.ConfigCompatibleWithStandardLibrary
+
+	in := payload{ID: nano64.New(0x0123456789ABCDEF)}
+	data, err := api.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	// Like encoding/json, omitempty keeps Nil and invalid IDs.
+	want, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != string(want) {
+		t.Errorf("Marshal() = %s, want %s as from encoding/json", data, want)
+	}
+
+	var out payload
+	if err := api.Unmarshal([]byte(`{"id":"0123456789a-bcdef","parent":"00000000000-00001"}`), &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out.ID != in.ID || !out.Parent.Valid || out.Parent.ID != nano64.New(1) {
+		t.Errorf("Unmarshal() = %+v", out)
+	}
+
+	if err := api.Unmarshal([]byte(`{"id":"nope"}`), &out); err == nil {
+		t.Error("Unmarshal() expected error for invalid ID")
+	}
+}
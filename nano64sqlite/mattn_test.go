@@ -0,0 +1,68 @@
+//go:build nano64_mattn
+
+package nano64sqlite
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/pisoj/go-nano64"
+)
+
+func TestRegisterMattn(t *testing.T) {
+	sql.Register("sqlite3_nano64_test", &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return Register(conn)
+		},
+	})
+	db, err := sql.Open("sqlite3_nano64_test", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`CREATE TABLE events (id BLOB PRIMARY KEY DEFAULT (nano64()), name TEXT)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	old := nano64.New(1000 << nano64.RandomBits)
+	if _, err := db.Exec("INSERT INTO events (id, name) VALUES (?, 'old')", old.ToBytes()); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO events (name) VALUES ('new')"); err != nil {
+		t.Fatalf("insert with default failed: %v", err)
+	}
+
+	var ts int64
+	var hex string
+	err = db.QueryRow("SELECT nano64_ts(id), nano64_hex(id) FROM events WHERE id BETWEEN nano64_range_start(?) AND nano64_range_end(?)", 1000, 1000).Scan(&ts, &hex)
+	if err != nil {
+		t.Fatalf("range query failed: %v", err)
+	}
+	if ts != 1000 || hex != old.ToHex() {
+		t.Errorf("got (%d, %s), want (1000, %s)", ts, hex, old.ToHex())
+	}
+
+	var signed int64
+	if err := db.QueryRow("SELECT nano64_signed(id) FROM events WHERE name = 'old'").Scan(&signed); err != nil {
+		t.Fatalf("signed query failed: %v", err)
+	}
+	if got := nano64.SignedNano64.ToId(signed); !got.Equals(old) {
+		t.Errorf("nano64_signed() = %s, want %s", got.ToHex(), old.ToHex())
+	}
+
+	var blob []byte
+	if err := db.QueryRow("SELECT nano64_blob(nano64_hex(id)) FROM events WHERE name = 'new'").Scan(&blob); err != nil {
+		t.Fatalf("default query failed: %v", err)
+	}
+	if generated, err := nano64.FromBytes(blob); err != nil || generated.IsNil() {
+		t.Errorf("nano64() default produced %x, %v", blob, err)
+	}
+
+	var null sql.NullString
+	if err := db.QueryRow("SELECT nano64_hex(NULL)").Scan(&null); err != nil || null.Valid {
+		t.Errorf("nano64_hex(NULL) = %v, %v; want NULL", null, err)
+	}
+}
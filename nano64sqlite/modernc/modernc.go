@@ -0,0 +1,39 @@
+// Package modernc registers the nano64sqlite SQL functions with modernc.org/sqlite.
+package modernc
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"github.com/pisoj/go-nano64/nano64sqlite"
+	"modernc.org/sqlite"
+)
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+)
+
+// Register makes the functions available to every modernc.org/sqlite connection
+// opened afterwards. It is safe to call more than once.
+func Register() error {
+	registerOnce.Do(func() {
+		for _, fn := range nano64sqlite.Functions() {
+			register := sqlite.RegisterScalarFunction
+			if fn.Deterministic {
+				register = sqlite.RegisterDeterministicScalarFunction
+			}
+
+			call := fn.Call
+			err := register(fn.Name, int32(fn.NArgs), func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+				return call(args)
+			})
+			if err != nil {
+				registerErr = fmt.Errorf("failed to register %s: %w", fn.Name, err)
+				return
+			}
+		}
+	})
+	return registerErr
+}
@@ -0,0 +1,69 @@
+package modernc
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/pisoj/go-nano64"
+)
+
+func TestRegister(t *testing.T) {
+	if err := Register(); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := Register(); err != nil {
+		t.Fatalf("second Register() error = %v", err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE events (id BLOB PRIMARY KEY DEFAULT (nano64()), name TEXT)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	old := nano64.New(1000 << nano64.RandomBits)
+	if _, err := db.Exec("INSERT INTO events (id, name) VALUES (?, 'old')", old); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO events (name) VALUES ('new')"); err != nil {
+		t.Fatalf("insert with default failed: %v", err)
+	}
+
+	var ts int64
+	var hex string
+	err = db.QueryRow("SELECT nano64_ts(id), nano64_hex(id) FROM events WHERE id BETWEEN nano64_range_start(?) AND nano64_range_end(?)", 1000, 1000).Scan(&ts, &hex)
+	if err != nil {
+		t.Fatalf("range query failed: %v", err)
+	}
+	if ts != 1000 || hex != old.ToHex() {
+		t.Errorf("got (%d, %s), want (1000, %s)", ts, hex, old.ToHex())
+	}
+
+	var signed int64
+	if err := db.QueryRow("SELECT nano64_signed(id) FROM events WHERE name = 'old'").Scan(&signed); err != nil {
+		t.Fatalf("signed query failed: %v", err)
+	}
+	if got := nano64.SignedNano64.ToId(signed); !got.Equals(old) {
+		t.Errorf("nano64_signed() = %s, want %s", got.ToHex(), old.ToHex())
+	}
+
+	var generated nano64.Nano64
+	if err := db.QueryRow("SELECT id FROM events WHERE name = 'new'").Scan(&generated); err != nil {
+		t.Fatalf("default query failed: %v", err)
+	}
+	if generated.IsNil() {
+		t.Error("nano64() default produced Nil")
+	}
+
+	var null sql.NullInt64
+	if err := db.QueryRow("SELECT nano64_ts(NULL)").Scan(&null); err != nil {
+		t.Fatalf("NULL query failed: %v", err)
+	}
+	if null.Valid {
+		t.Errorf("nano64_ts(NULL) = %d, want NULL", null.Int64)
+	}
+}
@@ -0,0 +1,156 @@
+// Package nano64sqlite registers SQLite application-defined functions for working
+// with Nano64 IDs directly in SQL:
+//
+//	nano64()                  new ID as an 8-byte BLOB
+//	nano64_signed(id)         ID as a signed INTEGER (see nano64.SignedNano64)
+//	nano64_blob(id)           ID as an 8-byte BLOB
+//	nano64_ts(id)             embedded timestamp in Unix milliseconds
+//	nano64_hex(id)            dashed hex form
+//	nano64_range_start(ms)    lowest BLOB ID for a millisecond
+//	nano64_range_end(ms)      highest BLOB ID for a millisecond
+//
+// ID arguments may be 8-byte BLOBs, signed INTEGERs or hex TEXT; NULL yields NULL.
+//
+// There is no single nano64_range(start, end): an application-defined scalar
+// function returns one value, and SQLite has no range type it could return. A
+// range is instead written as its two bounds, which keeps the condition a plain
+// BETWEEN that SQLite answers with a primary key seek:
+//
+//	SELECT * FROM events WHERE id BETWEEN nano64_range_start(?) AND nano64_range_end(?)
+//
+// Both bounds are inclusive, so nano64_range_end takes the last millisecond of the
+// range, not the one after it.
+//
+// Register wires the functions into a mattn/go-sqlite3 connection; the modernc
+// subpackage does the same for modernc.org/sqlite.
+package nano64sqlite
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/pisoj/go-nano64"
+)
+
+// Function describes one SQL function provided by this package.
+type Function struct {
+	Name string
+	// NArgs is the number of arguments the function accepts.
+	NArgs int
+	// Deterministic is true if the function always returns the same result for the same arguments.
+	Deterministic bool
+	// Call evaluates the function.
+	Call func(args []driver.Value) (driver.Value, error)
+}
+
+// Functions returns the SQL functions provided by this package.
+func Functions() []Function {
+	return []Function{
+		{Name: "nano64", NArgs: 0, Call: generate},
+		{Name: "nano64_signed", NArgs: 1, Deterministic: true, Call: withID(func(id nano64.Nano64) driver.Value {
+			return nano64.SignedNano64.FromId(id)
+		})},
+		{Name: "nano64_blob", NArgs: 1, Deterministic: true, Call: withID(func(id nano64.Nano64) driver.Value {
+			return id.ToBytes()
+		})},
+		{Name: "nano64_ts", NArgs: 1, Deterministic: true, Call: withID(func(id nano64.Nano64) driver.Value {
+			return id.GetTimestamp()
+		})},
+		{Name: "nano64_hex", NArgs: 1, Deterministic: true, Call: withID(func(id nano64.Nano64) driver.Value {
+			return id.ToHex()
+		})},
+		{Name: "nano64_range_start", NArgs: 1, Deterministic: true, Call: rangeBound(0)},
+		{Name: "nano64_range_end", NArgs: 1, Deterministic: true, Call: rangeBound(1<<nano64.RandomBits - 1)},
+	}
+}
+
+// FuncRegistrar is implemented by *sqlite3.SQLiteConn from github.com/mattn/go-sqlite3.
+type FuncRegistrar interface {
+	RegisterFunc(name string, impl any, pure bool) error
+}
+
+// Register adds the functions to a mattn/go-sqlite3 connection, typically from the
+// driver's ConnectHook:
+//
+//	sql.Register("sqlite3_nano64", &sqlite3.SQLiteDriver{
+//		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+//			return nano64sqlite.Register(conn)
+//		},
+//	})
+func Register(conn FuncRegistrar) error {
+	for _, fn := range Functions() {
+		var impl any
+		switch call := fn.Call; fn.NArgs {
+		case 0:
+			impl = func() (any, error) { return call(nil) }
+		case 1:
+			impl = func(arg any) (any, error) {
+				// mattn passes NULL to an any parameter as a nil []byte.
+				if b, ok := arg.([]byte); ok && b == nil {
+					arg = nil
+				}
+				return call([]driver.Value{arg})
+			}
+		default:
+			return fmt.Errorf("unsupported arity %d for %s", fn.NArgs, fn.Name)
+		}
+		if err := conn.RegisterFunc(fn.Name, impl, fn.Deterministic); err != nil {
+			return fmt.Errorf("failed to register %s: %w", fn.Name, err)
+		}
+	}
+	return nil
+}
+
+// generate implements nano64().
+func generate([]driver.Value) (driver.Value, error) {
+	id, err := nano64.GenerateDefault()
+	if err != nil {
+		return nil, err
+	}
+	return id.ToBytes(), nil
+}
+
+// withID adapts a conversion of a single ID argument, passing NULL through.
+func withID(convert func(id nano64.Nano64) driver.Value) func(args []driver.Value) (driver.Value, error) {
+	return func(args []driver.Value) (driver.Value, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		id, err := decodeID(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return convert(id), nil
+	}
+}
+
+// rangeBound returns the ID of a millisecond with the given random field.
+func rangeBound(random uint64) func(args []driver.Value) (driver.Value, error) {
+	return func(args []driver.Value) (driver.Value, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		ms, ok := args[0].(int64)
+		if !ok {
+			return nil, fmt.Errorf("timestamp must be an integer, got %T", args[0])
+		}
+		if ms < 0 || ms > 1<<nano64.TimestampBits-1 {
+			return nil, fmt.Errorf("timestamp out of %d-bit range: %d", nano64.TimestampBits, ms)
+		}
+		return nano64.New(uint64(ms)<<nano64.RandomBits | random).ToBytes(), nil
+	}
+}
+
+// decodeID converts a SQL value in any supported representation into an ID.
+func decodeID(v driver.Value) (nano64.Nano64, error) {
+	switch v := v.(type) {
+	case int64:
+		return nano64.SignedNano64.ToId(v), nil
+	case []byte:
+		return nano64.FromBytes(v)
+	case string:
+		return nano64.FromHex(v)
+	default:
+		return nano64.Nil, fmt.Errorf("cannot convert %T to Nano64", v)
+	}
+}
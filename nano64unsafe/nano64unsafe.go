@@ -0,0 +1,70 @@
+// Package nano64unsafe reinterprets slices of Nano64 as []uint64 or []byte and
+// back without copying, for bulk I/O and tight numeric loops over large ID sets.
+//
+// The returned slices alias their input: writes through one are visible through
+// the other, and the input must stay alive and unchanged in length while the view
+// is used. Byte views hold each ID in the host's byte order (little-endian on
+// amd64 and arm64), not the big-endian order of Nano64.ToBytes, so raw dumps are
+// only portable between machines of the same endianness; see HostBigEndian.
+package nano64unsafe
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+
+	"github.com/pisoj/go-nano64"
+)
+
+// A Nano64 must be laid out exactly like a uint64 for the casts to be valid;
+// these fail to compile otherwise.
+var (
+	_ [unsafe.Sizeof(nano64.Nano64{}) - unsafe.Sizeof(uint64(0))]struct{}
+	_ [unsafe.Sizeof(uint64(0)) - unsafe.Sizeof(nano64.Nano64{})]struct{}
+	_ [unsafe.Alignof(nano64.Nano64{}) - unsafe.Alignof(uint64(0))]struct{}
+)
+
+// HostBigEndian reports whether byte views hold IDs in big-endian order, i.e. in
+// the same order as Nano64.ToBytes.
+var HostBigEndian = binary.NativeEndian.Uint16([]byte{0, 1}) == 1
+
+// AsUint64s returns ids viewed as their raw uint64 values.
+func AsUint64s(ids []nano64.Nano64) []uint64 {
+	if len(ids) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*uint64)(unsafe.Pointer(unsafe.SliceData(ids))), len(ids))
+}
+
+// FromUint64s returns raw uint64 values viewed as IDs.
+func FromUint64s(values []uint64) []nano64.Nano64 {
+	if len(values) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*nano64.Nano64)(unsafe.Pointer(unsafe.SliceData(values))), len(values))
+}
+
+// AsBytes returns the memory of ids as bytes, 8 per ID in host byte order.
+func AsBytes(ids []nano64.Nano64) []byte {
+	if len(ids) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(ids))), len(ids)*8)
+}
+
+// FromBytes returns b viewed as IDs, 8 bytes per ID in host byte order. b must be
+// a multiple of 8 bytes long and start at an 8-byte aligned address, as slices
+// returned by make([]byte, n) with n >= 8 do on all supported platforms;
+// subslices at other offsets are rejected rather than risking unaligned access.
+func FromBytes(b []byte) ([]nano64.Nano64, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if len(b)%8 != 0 {
+		return nil, fmt.Errorf("byte length must be a multiple of 8, got %d", len(b))
+	}
+	if uintptr(unsafe.Pointer(unsafe.SliceData(b)))%unsafe.Alignof(uint64(0)) != 0 {
+		return nil, fmt.Errorf("byte slice is not 8-byte aligned")
+	}
+	return unsafe.Slice((*nano64.Nano64)(unsafe.Pointer(unsafe.SliceData(b))), len(b)/8), nil
+}
@@ -0,0 +1,63 @@
+package nano64unsafe
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/pisoj/go-nano64"
+)
+
+func TestCasts(t *testing.T) {
+	ids := []nano64.Nano64{nano64.New(1), nano64.New(0x0199C6A1F5E3B2D4), nano64.New(^uint64(0))}
+
+	values := AsUint64s(ids)
+	for i, id := range ids {
+		if values[i] != id.Uint64Value() {
+			t.Errorf("AsUint64s()[%d] = %#x, want %#x", i, values[i], id.Uint64Value())
+		}
+	}
+	values[0] = 42
+	if ids[0] != nano64.New(42) {
+		t.Error("AsUint64s() view does not alias the input")
+	}
+	if back := FromUint64s(values); &back[0] != &ids[0] {
+		t.Error("FromUint64s() copied instead of aliasing")
+	}
+
+	b := AsBytes(ids)
+	if len(b) != 24 {
+		t.Fatalf("AsBytes() length = %d, want 24", len(b))
+	}
+	if got := binary.NativeEndian.Uint64(b[8:]); got != ids[1].Uint64Value() {
+		t.Errorf("AsBytes() second ID = %#x, want %#x", got, ids[1].Uint64Value())
+	}
+	if HostBigEndian != (b[8] == 0x01) {
+		t.Errorf("HostBigEndian = %v disagrees with the byte view", HostBigEndian)
+	}
+
+	for _, empty := range [][]nano64.Nano64{nil, {}} {
+		if AsUint64s(empty) != nil || AsBytes(empty) != nil {
+			t.Error("views of an empty slice should be nil")
+		}
+	}
+}
+
+func TestFromBytes(t *testing.T) {
+	buf := make([]byte, 32)
+	binary.NativeEndian.PutUint64(buf[8:], 0x0199C6A1F5E3B2D4)
+
+	ids, err := FromBytes(buf)
+	if err != nil {
+		t.Fatalf("FromBytes() error = %v", err)
+	}
+	if len(ids) != 4 || ids[1] != nano64.New(0x0199C6A1F5E3B2D4) {
+		t.Errorf("FromBytes() = %v", ids)
+	}
+
+	if _, err := FromBytes(buf[:12]); err == nil {
+		t.Error("FromBytes() expected error for partial ID")
+	}
+	if _, err := FromBytes(buf[1:9]); err == nil {
+		t.Error("FromBytes() expected error for unaligned slice")
+	}
+}
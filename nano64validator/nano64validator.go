@@ -0,0 +1,99 @@
+// Package nano64validator registers Nano64 validation tags with
+// github.com/go-playground/validator:
+//
+//	nano64         value is a well-formed ID (hex strings must parse with FromHex)
+//	nano64_notnil  value is a non-Nil ID
+//	nano64_past    value is an ID whose embedded timestamp is not in the future
+//
+// The tags apply to nano64.Nano64, *nano64.Nano64, nano64.NullNano64 and hex
+// string fields:
+//
+//	type CreateOrder struct {
+//		CustomerID nano64.Nano64      `validate:"nano64_notnil,nano64_past"`
+//		ParentID   nano64.NullNano64  `validate:"omitempty,nano64_notnil"`
+//		Reference  string             `validate:"omitempty,nano64"`
+//	}
+//
+// Nil IDs and invalid NullNano64 values are treated as empty fields, so they fail
+// `required` and are skipped by `omitempty`.
+package nano64validator
+
+import (
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+	nano64 "github.com/pisoj/go-nano64"
+)
+
+// Tag names registered by RegisterValidations.
+const (
+	TagNano64 = "nano64"
+	TagNotNil = "nano64_notnil"
+	TagPast   = "nano64_past"
+)
+
+// RegisterValidations registers the Nano64 tags and the type conversions they rely
+// on with v, using DefaultClock for nano64_past.
+func RegisterValidations(v *validator.Validate) error {
+	return RegisterValidationsWithClock(v, nano64.DefaultClock)
+}
+
+// RegisterValidationsWithClock is like RegisterValidations but reads the current
+// time for nano64_past from clock.
+func RegisterValidationsWithClock(v *validator.Validate, clock nano64.Clock) error {
+	if clock == nil {
+		clock = nano64.DefaultClock
+	}
+
+	v.RegisterCustomTypeFunc(idValue, nano64.Nano64{}, nano64.NullNano64{})
+
+	validations := map[string]validator.Func{
+		TagNano64: func(fl validator.FieldLevel) bool {
+			_, ok := fieldID(fl.Field())
+			return ok
+		},
+		TagNotNil: func(fl validator.FieldLevel) bool {
+			id, ok := fieldID(fl.Field())
+			return ok && !id.IsNil()
+		},
+		TagPast: func(fl validator.FieldLevel) bool {
+			id, ok := fieldID(fl.Field())
+			return ok && id.GetTimestamp() <= clock()
+		},
+	}
+	for tag, fn := range validations {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// idValue exposes Nano64 fields to the validator as their uint64 value, so the
+// built-in `required` and `omitempty` tags treat Nil as empty. Invalid NullNano64
+// values become nil.
+func idValue(field reflect.Value) any {
+	switch v := field.Interface().(type) {
+	case nano64.Nano64:
+		return v.Uint64Value()
+	case nano64.NullNano64:
+		if !v.Valid {
+			return nil
+		}
+		return v.ID.Uint64Value()
+	}
+	return nil
+}
+
+// fieldID reads an ID from a field value after custom type conversion.
+func fieldID(field reflect.Value) (nano64.Nano64, bool) {
+	switch field.Kind() {
+	case reflect.Uint64:
+		return nano64.FromUint64(field.Uint()), true
+	case reflect.String:
+		id, err := nano64.FromHex(field.String())
+		return id, err == nil
+	default:
+		return nano64.Nil, false
+	}
+}
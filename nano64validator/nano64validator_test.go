@@ -0,0 +1,73 @@
+package nano64validator
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	nano64 "github.com/pisoj/go-nano64"
+)
+
+type request struct {
+	ID        nano64.Nano64     `validate:"nano64_notnil,nano64_past"`
+	Parent    nano64.NullNano64 `validate:"omitempty,nano64_notnil"`
+	Ref       *nano64.Nano64    `validate:"omitempty,nano64"`
+	Reference string            `validate:"omitempty,nano64"`
+}
+
+func TestRegisterValidations(t *testing.T) {
+	const now = 1_700_000_000_000
+	v := validator.New()
+	if err := RegisterValidationsWithClock(v, func() int64 { return now }); err != nil {
+		t.Fatalf("RegisterValidationsWithClock() error = %v", err)
+	}
+
+	past := mustGenerate(t, now-1000)
+	future := mustGenerate(t, now+60_000)
+
+	tests := []struct {
+		name    string
+		req     request
+		wantErr bool
+	}{
+		{"valid", request{ID: past}, false},
+		{"nil ID", request{ID: nano64.Nil}, true},
+		{"future ID", request{ID: future}, true},
+		{"valid parent", request{ID: past, Parent: nano64.NullNano64{ID: past, Valid: true}}, false},
+		{"nil parent is empty", request{ID: past, Parent: nano64.NullNano64{Valid: true}}, false},
+		{"pointer", request{ID: past, Ref: &future}, false},
+		{"hex reference", request{ID: past, Reference: past.ToHex()}, false},
+		{"bad reference", request{ID: past, Reference: "not-an-id"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Struct(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Struct() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequiredNullNano64(t *testing.T) {
+	v := validator.New()
+	if err := RegisterValidations(v); err != nil {
+		t.Fatalf("RegisterValidations() error = %v", err)
+	}
+
+	type form struct {
+		ID nano64.NullNano64 `validate:"required"`
+	}
+	if err := v.Struct(form{}); err == nil {
+		t.Error("Struct() expected error for missing required NullNano64")
+	}
+}
+
+func mustGenerate(t *testing.T, ts int64) nano64.Nano64 {
+	t.Helper()
+	id, err := nano64.Generate(ts, nil)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	return id
+}
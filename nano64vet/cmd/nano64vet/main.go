@@ -0,0 +1,16 @@
+// Command nano64vet runs the nano64vet analyzer:
+//
+//	go install github.com/pisoj/go-nano64/nano64vet/cmd/nano64vet@latest
+//	nano64vet ./...
+//
+// or through go vet with `go vet -vettool=$(which nano64vet) ./...`.
+package main
+
+import (
+	"github.com/pisoj/go-nano64/nano64vet"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(nano64vet.Analyzer)
+}
@@ -0,0 +1,190 @@
+// Package nano64vet provides a go vet-style analyzer flagging common misuse of
+// Nano64 IDs:
+//
+//   - comparing an ID with the zero value (id == nano64.Nano64{}, id == nano64.New(0)
+//     or id.Uint64Value() == 0) instead of calling IsNil;
+//   - formatting an ID with fmt.Sprint, Sprintf or Sprintln into a database/sql
+//     call argument instead of passing the ID, which implements driver.Valuer;
+//   - discarding the error of GenerateDefault or another Generate function.
+//
+// Run it standalone with the nano64vet command in cmd/nano64vet, or add
+// Analyzer to a multichecker.
+package nano64vet
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// nano64Path is the import path of the nano64 package.
+const nano64Path = "github.com/pisoj/go-nano64"
+
+// Analyzer reports misuse of Nano64 IDs.
+var Analyzer = &analysis.Analyzer{
+	Name:     "nano64vet",
+	Doc:      "report comparisons with the zero ID, IDs formatted into SQL arguments and ignored generation errors",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	in := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	filter := []ast.Node{(*ast.BinaryExpr)(nil), (*ast.CallExpr)(nil), (*ast.ExprStmt)(nil), (*ast.AssignStmt)(nil)}
+	in.Preorder(filter, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			checkZeroComparison(pass, n)
+		case *ast.CallExpr:
+			checkSQLFormatting(pass, n)
+		case *ast.ExprStmt:
+			if call, ok := n.X.(*ast.CallExpr); ok && isGenerateCall(pass, call) {
+				pass.Reportf(call.Pos(), "error returned by %s is not checked", calleeName(pass, call))
+			}
+		case *ast.AssignStmt:
+			checkDiscardedError(pass, n)
+		}
+	})
+	return nil, nil
+}
+
+// isNano64 reports whether t is nano64.Nano64.
+func isNano64(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == nano64Path && obj.Name() == "Nano64"
+}
+
+// callee returns the object the function expression of call refers to.
+func callee(pass *analysis.Pass, call *ast.CallExpr) types.Object {
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		return pass.TypesInfo.Uses[fun]
+	case *ast.SelectorExpr:
+		return pass.TypesInfo.Uses[fun.Sel]
+	}
+	return nil
+}
+
+// nano64Func returns the nano64 function or method call calls, or nil.
+func nano64Func(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	fn, ok := callee(pass, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != nano64Path {
+		return nil
+	}
+	return fn
+}
+
+// calleeName returns the name of the nano64 function call calls.
+func calleeName(pass *analysis.Pass, call *ast.CallExpr) string {
+	return nano64Func(pass, call).Name()
+}
+
+// isZeroID reports whether e is a literal zero ID: nano64.Nano64{},
+// nano64.New(0) or nano64.FromUint64(0).
+func isZeroID(pass *analysis.Pass, e ast.Expr) bool {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.CompositeLit:
+		return len(e.Elts) == 0 && isNano64(pass.TypesInfo.TypeOf(e))
+	case *ast.CallExpr:
+		fn := nano64Func(pass, e)
+		if fn == nil || (fn.Name() != "New" && fn.Name() != "FromUint64") || len(e.Args) != 1 {
+			return false
+		}
+		return isConstZero(pass, e.Args[0])
+	}
+	return false
+}
+
+// isConstZero reports whether e is the constant 0.
+func isConstZero(pass *analysis.Pass, e ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[e]
+	return ok && tv.Value != nil && constant.Sign(tv.Value) == 0
+}
+
+// checkZeroComparison reports == and != comparisons with the zero ID.
+func checkZeroComparison(pass *analysis.Pass, e *ast.BinaryExpr) {
+	if e.Op != token.EQL && e.Op != token.NEQ {
+		return
+	}
+	for _, pair := range [2][2]ast.Expr{{e.X, e.Y}, {e.Y, e.X}} {
+		id, other := pair[0], pair[1]
+		if isNano64(pass.TypesInfo.TypeOf(id)) && isZeroID(pass, other) {
+			pass.Reportf(e.Pos(), "comparison with the zero ID; use IsNil")
+			return
+		}
+		if call, ok := ast.Unparen(id).(*ast.CallExpr); ok && isConstZero(pass, other) {
+			if fn := nano64Func(pass, call); fn != nil && fn.Name() == "Uint64Value" {
+				pass.Reportf(e.Pos(), "comparison of Uint64Value with 0; use IsNil")
+				return
+			}
+		}
+	}
+}
+
+// sqlMethods are the database/sql methods whose arguments are stored or compared
+// by the database.
+var sqlMethods = map[string]bool{
+	"Exec": true, "ExecContext": true,
+	"Query": true, "QueryContext": true,
+	"QueryRow": true, "QueryRowContext": true,
+}
+
+// checkSQLFormatting reports IDs formatted with fmt.Sprint* into the arguments
+// of a database/sql call.
+func checkSQLFormatting(pass *analysis.Pass, call *ast.CallExpr) {
+	fn, ok := callee(pass, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "database/sql" || !sqlMethods[fn.Name()] {
+		return
+	}
+	for _, arg := range call.Args {
+		inner, ok := ast.Unparen(arg).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		f, ok := callee(pass, inner).(*types.Func)
+		if !ok || f.Pkg() == nil || f.Pkg().Path() != "fmt" || !strings.HasPrefix(f.Name(), "Sprint") {
+			continue
+		}
+		for _, a := range inner.Args {
+			if isNano64(pass.TypesInfo.TypeOf(a)) {
+				pass.Reportf(inner.Pos(), "ID formatted with fmt.%s as an SQL argument; pass the ID or nano64.ColumnOf instead", f.Name())
+				break
+			}
+		}
+	}
+}
+
+// isGenerateCall reports whether call calls a nano64 Generate function or
+// method returning an error.
+func isGenerateCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn := nano64Func(pass, call)
+	if fn == nil || !strings.HasPrefix(fn.Name(), "Generate") {
+		return false
+	}
+	results := fn.Type().(*types.Signature).Results()
+	return results.Len() > 0 && types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type())
+}
+
+// checkDiscardedError reports assignments of a Generate call's error to _.
+func checkDiscardedError(pass *analysis.Pass, s *ast.AssignStmt) {
+	if len(s.Rhs) != 1 || len(s.Lhs) < 2 {
+		return
+	}
+	call, ok := ast.Unparen(s.Rhs[0]).(*ast.CallExpr)
+	if !ok || !isGenerateCall(pass, call) {
+		return
+	}
+	if blank, ok := s.Lhs[len(s.Lhs)-1].(*ast.Ident); ok && blank.Name == "_" {
+		pass.Reportf(call.Pos(), "error returned by %s is discarded", calleeName(pass, call))
+	}
+}
@@ -0,0 +1,35 @@
+package a
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	nano64 "github.com/pisoj/go-nano64"
+)
+
+func compare(id nano64.Nano64) bool {
+	_ = id == nano64.Nano64{}      // want "comparison with the zero ID; use IsNil"
+	_ = nano64.New(0) != id        // want "comparison with the zero ID; use IsNil"
+	_ = id == nano64.FromUint64(0) // want "comparison with the zero ID; use IsNil"
+	_ = id.Uint64Value() == 0      // want "comparison of Uint64Value with 0; use IsNil"
+	_ = id == nano64.New(1)
+	return id.IsNil()
+}
+
+func store(ctx context.Context, db *sql.DB, id nano64.Nano64) {
+	db.ExecContext(ctx, "INSERT INTO t (id) VALUES ($1)", fmt.Sprint(id)) // want "ID formatted with fmt.Sprint as an SQL argument"
+	db.QueryRow("SELECT 1 WHERE id = ?", fmt.Sprintf("%v", id))           // want "ID formatted with fmt.Sprintf as an SQL argument"
+	db.ExecContext(ctx, "INSERT INTO t (id) VALUES ($1)", id)
+	_ = fmt.Sprint(id)
+}
+
+func generate(g *nano64.Generator) {
+	nano64.GenerateDefault()          // want "error returned by GenerateDefault is not checked"
+	id, _ := nano64.GenerateDefault() // want "error returned by GenerateDefault is discarded"
+	_, _ = g.GenerateMonotonic()      // want "error returned by GenerateMonotonic is discarded"
+	if id, err := g.GenerateMonotonic(); err == nil {
+		_ = id
+	}
+	_ = id
+}
@@ -0,0 +1,15 @@
+// Package nano64 is a stub of the API the analyzer inspects.
+package nano64
+
+type Nano64 struct{ value uint64 }
+
+type Generator struct{}
+
+func New(value uint64) Nano64        { return Nano64{value: value} }
+func FromUint64(value uint64) Nano64 { return Nano64{value: value} }
+func (n Nano64) IsNil() bool         { return n.value == 0 }
+func (n Nano64) Uint64Value() uint64 { return n.value }
+func (n Nano64) String() string      { return "" }
+
+func GenerateDefault() (Nano64, error)                  { return Nano64{}, nil }
+func (g *Generator) GenerateMonotonic() (Nano64, error) { return Nano64{}, nil }
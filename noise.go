@@ -0,0 +1,72 @@
+package nano64
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// defaultNoiseSensitivity is the shift in creation time TimeNoise protects by default.
+const defaultNoiseSensitivity = time.Hour
+
+// maxNoise bounds the noise, in nanoseconds, to about 146 years either way.
+const maxNoise = 1 << 62
+
+// TimeNoise adds Laplace noise to the creation times of IDs for exports where
+// exact times cannot be shared. The noise is derived from the ID, so an ID's
+// noisy time is the same in every export and query: averaging repeated answers
+// does not recover the true time.
+type TimeNoise struct {
+	// Key keys the noise derivation; recipients without it cannot recompute the
+	// noise of a guessed ID. Nil derives it from the ID alone.
+	Key []byte
+
+	// Sensitivity is the difference in creation time the privacy guarantee is
+	// stated for: with budget epsilon, times that far apart yield output
+	// distributions within a factor of e^epsilon. Zero means one hour.
+	Sensitivity time.Duration
+}
+
+// NoisyTime returns the creation time of id with Laplace noise of scale
+// 1h/epsilon, using TimeNoise with no key.
+func NoisyTime(id Nano64, epsilon float64) (time.Time, error) {
+	return TimeNoise{}.Time(id, epsilon)
+}
+
+// Time returns the creation time of id with Laplace noise of scale
+// Sensitivity/epsilon, truncated to the millisecond. epsilon must be positive.
+func (n TimeNoise) Time(id Nano64, epsilon float64) (time.Time, error) {
+	offset, err := n.Offset(id, epsilon)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return id.ToDate().Add(offset), nil
+}
+
+// Offset returns the noise Time adds to the creation time of id.
+func (n TimeNoise) Offset(id Nano64, epsilon float64) (time.Duration, error) {
+	if !(epsilon > 0) {
+		return 0, fmt.Errorf("noise epsilon must be positive, got %v", epsilon)
+	}
+	sensitivity := n.Sensitivity
+	if sensitivity <= 0 {
+		sensitivity = defaultNoiseSensitivity
+	}
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], id.value)
+	mac := hmac.New(sha256.New, n.Key)
+	mac.Write(buf[:])
+	sum := mac.Sum(nil)
+
+	// u is uniform in (-0.5, 0.5); inverting the Laplace CDF gives the noise.
+	u := (float64(binary.BigEndian.Uint64(sum)>>11)+0.5)/(1<<53) - 0.5
+	scale := float64(sensitivity) / epsilon
+	noise := -scale * math.Copysign(math.Log1p(-2*math.Abs(u)), u)
+	// Tiny budgets can exceed the range of a Duration.
+	noise = min(max(noise, -maxNoise), maxNoise)
+	return time.Duration(noise).Truncate(time.Millisecond), nil
+}
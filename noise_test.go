@@ -0,0 +1,53 @@
+package nano64
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNoisyTime(t *testing.T) {
+	id := mustGenerate(t, 1_700_000_000_000, 42)
+	first, err := NoisyTime(id, 1)
+	if err != nil {
+		t.Fatalf("NoisyTime() error = %v", err)
+	}
+	if again, _ := NoisyTime(id, 1); again != first {
+		t.Error("NoisyTime() is not deterministic")
+	}
+	if keyed, _ := (TimeNoise{Key: []byte("export-2024")}).Time(id, 1); keyed == first {
+		t.Error("the key does not change the noise")
+	}
+	if first.Sub(id.ToDate())%time.Millisecond != 0 {
+		t.Errorf("NoisyTime() = %v is not whole milliseconds", first)
+	}
+
+	// Laplace noise of scale b has mean 0 and mean absolute value b.
+	const n = 20000
+	noise := TimeNoise{Sensitivity: time.Minute}
+	for _, epsilon := range []float64{0.5, 2} {
+		scale := float64(time.Minute) / epsilon
+		var sum, abs float64
+		for i := range n {
+			offset, err := noise.Offset(mustGenerate(t, int64(1000+i), uint32(i)), epsilon)
+			if err != nil {
+				t.Fatalf("Offset() error = %v", err)
+			}
+			sum += float64(offset)
+			abs += math.Abs(float64(offset))
+		}
+		if mean := sum / n; math.Abs(mean) > 0.05*scale {
+			t.Errorf("epsilon %v: mean noise %v, want about 0", epsilon, time.Duration(mean))
+		}
+		if meanAbs := abs / n; math.Abs(meanAbs-scale) > 0.05*scale {
+			t.Errorf("epsilon %v: mean absolute noise %v, want about %v", epsilon, time.Duration(meanAbs), time.Duration(scale))
+		}
+	}
+
+	if offset, err := noise.Offset(id, 1e-12); err != nil || offset.Abs() > maxNoise {
+		t.Errorf("noise for a tiny epsilon = %v, %v, want it clamped", offset, err)
+	}
+	if _, err := NoisyTime(id, 0); err == nil {
+		t.Error("NoisyTime() accepted epsilon 0")
+	}
+}
@@ -0,0 +1,152 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// defaultNormalizeBatchSize is the number of rows NormalizeColumn reads per
+// query when NormalizeOptions.BatchSize is zero.
+const defaultNormalizeBatchSize = 1000
+
+// NormalizeOptions configures NormalizeColumn.
+type NormalizeOptions struct {
+	Dialect Dialect
+
+	// Target is the representation every row is rewritten to.
+	Target Representation
+
+	// Sources are the representations to look for; nil means every one but
+	// Target. Engines with static column types reject comparing a column with a
+	// value of another type, so list only the forms the column can hold there.
+	Sources []Representation
+
+	// BatchSize is the number of rows read per query. Zero means 1000.
+	BatchSize int
+
+	// DryRun only counts the rows that would be rewritten.
+	DryRun bool
+}
+
+// NormalizeReport summarizes the result of NormalizeColumn.
+type NormalizeReport struct {
+	// Found counts the rows stored in each source representation.
+	Found map[Representation]int64
+	// Rewritten is the number of rows converted to the target representation.
+	Rewritten int64
+	// Invalid counts rows in a source representation's range that do not
+	// decode as an ID, such as text of the wrong length. They are left alone.
+	Invalid int64
+}
+
+// NormalizeColumn repairs a column holding IDs in several representations, such
+// as a dynamically typed SQLite column written by services that disagreed on
+// hex text, 8-byte blobs and SignedNano64 integers. For every source
+// representation it walks the full ID range in chunks of BatchSize rows,
+// selecting values of that type with a range predicate, and rewrites each to
+// the target representation with the package's converters.
+//
+// Hex text must be uppercase and dashed as written by ToHex, since the range
+// predicate compares strings; integers are read as SignedNano64 values.
+func NormalizeColumn(ctx context.Context, db interface {
+	Execer
+	Querier
+}, table, column string, opts NormalizeOptions) (NormalizeReport, error) {
+	if err := opts.Dialect.validate(); err != nil {
+		return NormalizeReport{}, err
+	}
+	if opts.BatchSize < 0 {
+		return NormalizeReport{}, fmt.Errorf("batch size must not be negative, got %d", opts.BatchSize)
+	}
+	batchSize := opts.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultNormalizeBatchSize
+	}
+	if _, err := normalizedValue(Nil, opts.Target); err != nil {
+		return NormalizeReport{}, err
+	}
+	sources := opts.Sources
+	if sources == nil {
+		for _, repr := range []Representation{RepresentationBytes, RepresentationSigned, RepresentationHex} {
+			if repr != opts.Target {
+				sources = append(sources, repr)
+			}
+		}
+	}
+
+	d := opts.Dialect
+	col := d.QuoteIdent(column)
+	first := fmt.Sprintf("SELECT %s FROM %s WHERE %s >= %s AND %s <= %s ORDER BY %s LIMIT %d",
+		col, d.QuoteIdent(table), col, d.Placeholder(1), col, d.Placeholder(2), col, batchSize)
+	next := fmt.Sprintf("SELECT %s FROM %s WHERE %s > %s AND %s <= %s ORDER BY %s LIMIT %d",
+		col, d.QuoteIdent(table), col, d.Placeholder(1), col, d.Placeholder(2), col, batchSize)
+	update := fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s = %s",
+		d.QuoteIdent(table), col, d.Placeholder(1), col, d.Placeholder(2))
+
+	report := NormalizeReport{Found: make(map[Representation]int64)}
+	for _, repr := range sources {
+		if repr == opts.Target {
+			return report, fmt.Errorf("source %v is the target representation", repr)
+		}
+		low, err := normalizedValue(Nil, repr)
+		if err != nil {
+			return report, err
+		}
+		high, _ := normalizedValue(Nano64{value: math.MaxUint64}, repr)
+
+		query, cursor := first, low
+		for {
+			values, err := normalizeBatch(ctx, db, query, cursor, high)
+			if err != nil {
+				return report, fmt.Errorf("failed to read %v rows: %w", repr, err)
+			}
+			for _, value := range values {
+				var id Nano64
+				if err := ColumnOf(&id, RepresentationSigned).Scan(value); err != nil {
+					report.Invalid++
+					continue
+				}
+				report.Found[repr]++
+				if opts.DryRun {
+					continue
+				}
+				target, _ := normalizedValue(id, opts.Target)
+				if _, err := db.ExecContext(ctx, update, target, value); err != nil {
+					return report, fmt.Errorf("failed to rewrite %s: %w", id.ToHex(), err)
+				}
+				report.Rewritten++
+			}
+			if len(values) < batchSize {
+				break
+			}
+			query, cursor = next, values[len(values)-1]
+		}
+	}
+	return report, nil
+}
+
+// normalizeBatch runs one chunk query and returns the raw values.
+func normalizeBatch(ctx context.Context, db Querier, query string, args ...any) ([]any, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var values []any
+	for rows.Next() {
+		var value any
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// normalizedValue returns id as a statement argument in repr.
+func normalizedValue(id Nano64, repr Representation) (any, error) {
+	return ColumnOf(&id, repr).Value()
+}
@@ -0,0 +1,89 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestNormalizeColumn(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`CREATE TABLE events (id ANY)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	ids := make([]Nano64, 7)
+	for i := range ids {
+		ids[i] = mustGenerate(t, 1_700_000_000_000+int64(i), uint32(i))
+	}
+	stored := []any{
+		ids[0].ToBytes(),
+		SignedNano64.FromId(ids[1]),
+		ids[2].ToHex(),
+		SignedNano64.FromId(ids[3]),
+		ids[4].ToHex(),
+		SignedNano64.FromId(ids[5]),
+		ids[6].ToBytes(),
+		"0BAD", // undecodable text
+	}
+	for _, v := range stored {
+		if _, err := db.Exec("INSERT INTO events (id) VALUES (?)", v); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	opts := NormalizeOptions{Dialect: DialectSQLite, Target: RepresentationBytes, BatchSize: 2, DryRun: true}
+	report, err := NormalizeColumn(ctx, db, "events", "id", opts)
+	if err != nil {
+		t.Fatalf("NormalizeColumn() dry run error = %v", err)
+	}
+	if report.Found[RepresentationSigned] != 3 || report.Found[RepresentationHex] != 2 || report.Rewritten != 0 || report.Invalid != 1 {
+		t.Errorf("dry run report = %+v", report)
+	}
+
+	opts.DryRun = false
+	report, err = NormalizeColumn(ctx, db, "events", "id", opts)
+	if err != nil {
+		t.Fatalf("NormalizeColumn() error = %v", err)
+	}
+	if report.Rewritten != 5 {
+		t.Errorf("report = %+v, want 5 rewritten", report)
+	}
+
+	rows, err := db.Query("SELECT id FROM events WHERE typeof(id) = 'blob' ORDER BY id")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer rows.Close()
+	var got []Nano64
+	for rows.Next() {
+		var id Nano64
+		if err := rows.Scan(ColumnOf(&id, RepresentationBytes)); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		got = append(got, id)
+	}
+	if len(got) != len(ids) {
+		t.Fatalf("got %d blob rows, want %d", len(got), len(ids))
+	}
+	for i := range ids {
+		if got[i] != ids[i] {
+			t.Errorf("row %d = %s, want %s", i, got[i].ToHex(), ids[i].ToHex())
+		}
+	}
+
+	opts.Sources = []Representation{RepresentationBytes}
+	if _, err := NormalizeColumn(ctx, db, "events", "id", opts); err == nil {
+		t.Error("NormalizeColumn() accepted the target as a source")
+	}
+}
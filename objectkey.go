@@ -0,0 +1,153 @@
+package nano64
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KeyLayout is the time granularity of the directories ObjectKey puts IDs in.
+type KeyLayout int
+
+const (
+	// KeyLayoutDay gives keys like "prefix/2024/06/01/<hex>".
+	KeyLayoutDay KeyLayout = iota
+	// KeyLayoutHour gives keys like "prefix/2024/06/01/12/<hex>".
+	KeyLayoutHour
+	// KeyLayoutMinute gives keys like "prefix/2024/06/01/12/34/<hex>".
+	KeyLayoutMinute
+)
+
+// objectKeyLevels are the path components of object keys, coarsest first. A
+// layout uses the first depth() of them.
+var objectKeyLevels = []struct {
+	format string
+	add    func(time.Time) time.Time
+}{
+	{"2006", func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+	{"01", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	{"02", func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+	{"15", func(t time.Time) time.Time { return t.Add(time.Hour) }},
+	{"04", func(t time.Time) time.Time { return t.Add(time.Minute) }},
+}
+
+// depth returns the number of date components of the layout.
+func (l KeyLayout) depth() int {
+	return int(l) + 3
+}
+
+// String returns the layout name.
+func (l KeyLayout) String() string {
+	switch l {
+	case KeyLayoutDay:
+		return "day"
+	case KeyLayoutHour:
+		return "hour"
+	case KeyLayoutMinute:
+		return "minute"
+	default:
+		return fmt.Sprintf("KeyLayout(%d)", int(l))
+	}
+}
+
+// validate reports an unknown layout.
+func (l KeyLayout) validate() error {
+	if l < KeyLayoutDay || l > KeyLayoutMinute {
+		return fmt.Errorf("unknown key layout %d", int(l))
+	}
+	return nil
+}
+
+// objectKeyDir returns the prefix joined with the first n date components of t,
+// ending in a slash.
+func objectKeyDir(prefix string, t time.Time, n int) string {
+	var b strings.Builder
+	if prefix = strings.TrimSuffix(prefix, "/"); prefix != "" {
+		b.WriteString(prefix)
+		b.WriteByte('/')
+	}
+	t = t.UTC()
+	for _, level := range objectKeyLevels[:n] {
+		b.WriteString(t.Format(level.format))
+		b.WriteByte('/')
+	}
+	return b.String()
+}
+
+// ObjectKey returns the object store key of id: prefix, the UTC date of id down
+// to the layout's granularity, and the hex ID, e.g. "blobs/2024/06/01/12/<hex>".
+// Keys sort by time, and listing a time span only touches the directories of
+// that span (see ObjectListPrefixes). It panics on an unknown layout.
+func ObjectKey(prefix string, id Nano64, layout KeyLayout) string {
+	if err := layout.validate(); err != nil {
+		panic(err)
+	}
+	return objectKeyDir(prefix, id.ToDate(), layout.depth()) + id.ToHex()
+}
+
+// ParseObjectKey returns the ID of a key produced by ObjectKey with the same
+// prefix and layout, checking that the date directories match the ID.
+func ParseObjectKey(prefix, key string, layout KeyLayout) (Nano64, error) {
+	if err := layout.validate(); err != nil {
+		return Nil, err
+	}
+	slash := strings.LastIndexByte(key, '/')
+	id, err := FromHex(key[slash+1:])
+	if err != nil {
+		return Nil, fmt.Errorf("invalid object key %q: %w", key, err)
+	}
+	if want := objectKeyDir(prefix, id.ToDate(), layout.depth()); key[:slash+1] != want {
+		return Nil, fmt.Errorf("object key %q does not match %s layout %q for its ID", key, layout, want)
+	}
+	return id, nil
+}
+
+// ObjectListPrefixes returns the list prefixes covering every key of IDs created
+// in [start, end), in time order. Whole years, months, days or hours within the
+// span are covered by a single coarser prefix, so few list calls are needed; the
+// partial units at either end use the layout's full granularity and may include
+// keys from just outside the span.
+func ObjectListPrefixes(prefix string, start, end time.Time, layout KeyLayout) ([]string, error) {
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	depth := layout.depth()
+	start, end = start.UTC(), end.UTC()
+
+	// Align start down to the layout's granularity.
+	t := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	t = t.AddDate(0, 0, start.Day()-1)
+	if depth > 3 {
+		t = t.Add(time.Duration(start.Hour()) * time.Hour)
+	}
+	if depth > 4 {
+		t = t.Add(time.Duration(start.Minute()) * time.Minute)
+	}
+
+	var prefixes []string
+	for t.Before(end) {
+		// Use the coarsest unit t is aligned to that ends within the span.
+		n := depth
+		for level := 1; level < depth; level++ {
+			next := objectKeyLevels[level-1].add(t)
+			if objectKeyAligned(t, level) && !next.After(end) {
+				n = level
+				break
+			}
+		}
+		prefixes = append(prefixes, objectKeyDir(prefix, t, n))
+		t = objectKeyLevels[n-1].add(t)
+	}
+	return prefixes, nil
+}
+
+// objectKeyAligned reports whether t starts a unit of the first n levels.
+func objectKeyAligned(t time.Time, n int) bool {
+	fields := []int{t.Year(), int(t.Month()) - 1, t.Day() - 1, t.Hour(), t.Minute()}
+	for _, v := range fields[n:] {
+		if v != 0 {
+			return false
+		}
+	}
+	return t.Second() == 0 && t.Nanosecond() == 0
+}
@@ -0,0 +1,93 @@
+package nano64
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestObjectKey(t *testing.T) {
+	id := mustGenerate(t, time.Date(2024, 6, 1, 12, 34, 56, 0, time.UTC).UnixMilli(), 0xABC)
+	tests := []struct {
+		prefix string
+		layout KeyLayout
+		want   string
+	}{
+		{"blobs", KeyLayoutDay, "blobs/2024/06/01/" + id.ToHex()},
+		{"blobs/", KeyLayoutHour, "blobs/2024/06/01/12/" + id.ToHex()},
+		{"", KeyLayoutMinute, "2024/06/01/12/34/" + id.ToHex()},
+	}
+	for _, tt := range tests {
+		key := ObjectKey(tt.prefix, id, tt.layout)
+		if key != tt.want {
+			t.Errorf("ObjectKey(%q, %s) = %q, want %q", tt.prefix, tt.layout, key, tt.want)
+		}
+		parsed, err := ParseObjectKey(tt.prefix, key, tt.layout)
+		if err != nil || parsed != id {
+			t.Errorf("ParseObjectKey(%q) = %s, %v", key, parsed.ToHex(), err)
+		}
+	}
+
+	for _, bad := range []string{
+		"blobs/2024/06/02/12/" + id.ToHex(),
+		"other/2024/06/01/12/" + id.ToHex(),
+		"blobs/2024/06/01/" + id.ToHex(),
+		"blobs/2024/06/01/12/not-hex",
+	} {
+		if _, err := ParseObjectKey("blobs", bad, KeyLayoutHour); err == nil {
+			t.Errorf("ParseObjectKey(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestObjectListPrefixes(t *testing.T) {
+	date := func(y int, m time.Month, d, h, min int) time.Time { return time.Date(y, m, d, h, min, 0, 0, time.UTC) }
+	tests := []struct {
+		name       string
+		start, end time.Time
+		layout     KeyLayout
+		want       []string
+	}{
+		{"partial hours", date(2024, 6, 1, 22, 30), date(2024, 6, 2, 1, 0), KeyLayoutHour,
+			[]string{"p/2024/06/01/22/", "p/2024/06/01/23/", "p/2024/06/02/00/"}},
+		{"whole day", date(2024, 6, 1, 23, 0), date(2024, 6, 3, 1, 0), KeyLayoutHour,
+			[]string{"p/2024/06/01/23/", "p/2024/06/02/", "p/2024/06/03/00/"}},
+		{"whole month and year", date(2023, 12, 31, 0, 0), date(2025, 2, 2, 0, 0), KeyLayoutDay,
+			[]string{"p/2023/12/31/", "p/2024/", "p/2025/01/", "p/2025/02/01/"}},
+		{"minutes", date(2024, 6, 1, 12, 58), date(2024, 6, 1, 13, 1), KeyLayoutMinute,
+			[]string{"p/2024/06/01/12/58/", "p/2024/06/01/12/59/", "p/2024/06/01/13/00/"}},
+		{"empty", date(2024, 6, 1, 12, 0), date(2024, 6, 1, 12, 0), KeyLayoutHour, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ObjectListPrefixes("p", tt.start, tt.end, tt.layout)
+			if err != nil {
+				t.Fatalf("ObjectListPrefixes() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ObjectListPrefixes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Every key in the span is under exactly one prefix.
+	start, end := date(2024, 2, 28, 17, 0), date(2024, 3, 2, 3, 0)
+	prefixes, _ := ObjectListPrefixes("p", start, end, KeyLayoutHour)
+	for ts := start; ts.Before(end); ts = ts.Add(37 * time.Minute) {
+		key := ObjectKey("p", mustGenerate(t, ts.UnixMilli(), 0), KeyLayoutHour)
+		n := 0
+		for _, p := range prefixes {
+			if strings.HasPrefix(key, p) {
+				n++
+			}
+		}
+		if n != 1 {
+			t.Errorf("key %s is under %d prefixes of %v", key, n, prefixes)
+		}
+	}
+
+	if _, err := ObjectListPrefixes("p", start, end, KeyLayout(9)); err == nil {
+		t.Error("ObjectListPrefixes() accepted an unknown layout")
+	}
+}
@@ -0,0 +1,137 @@
+package nano64
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// oneTimeMACLength is the length of the truncated HMAC-SHA256 tag of a token.
+	oneTimeMACLength = 16
+
+	// minOneTimeKeyLength is the minimum accepted HMAC key length.
+	minOneTimeKeyLength = 16
+)
+
+var (
+	// ErrInvalidToken is returned by OneTimeToken.Redeem for malformed or forged tokens.
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrTokenExpired is returned by OneTimeToken.Redeem for authentic tokens past their expiry.
+	ErrTokenExpired = errors.New("token expired")
+
+	// ErrTokenUsed is returned by a OneTimeTokenOptions.Consume callback for a
+	// token that was already redeemed or is unknown to the store.
+	ErrTokenUsed = errors.New("token already used")
+)
+
+// OneTimeTokenOptions configures a OneTimeToken.
+type OneTimeTokenOptions struct {
+	// Key signs the tokens; at least 16 bytes. Required.
+	Key []byte
+
+	// Purpose separates token kinds signed with the same key, e.g.
+	// "password-reset" and "email-verification", so one cannot stand in for the other.
+	Purpose string
+
+	// TTL is the lifetime of issued tokens. Required.
+	TTL time.Duration
+
+	// Consume atomically marks the token with ID id as used, returning ErrTokenUsed
+	// if it was used before or was never issued, e.g. with a DELETE checking the
+	// affected row count. It runs only for authentic, unexpired tokens. Required.
+	Consume func(ctx context.Context, id Nano64) error
+
+	// Clock defaults to DefaultClock.
+	Clock Clock
+}
+
+// OneTimeToken issues and redeems single-use tokens such as password-reset
+// links. The token ID is expiry-encoded (see GenerateExpiring), so expiry needs
+// no lookup; an HMAC makes tokens unforgeable, and the Consume callback makes
+// each redeemable once. Raw Nano64s are unsuitable for this on their own: their
+// random field is short and their timestamp is guessable.
+// A OneTimeToken is safe for concurrent use.
+type OneTimeToken struct {
+	key       []byte
+	purpose   string
+	consume   func(ctx context.Context, id Nano64) error
+	clock     Clock
+	generator *Generator
+}
+
+// NewOneTimeToken creates a OneTimeToken.
+func NewOneTimeToken(opts OneTimeTokenOptions) (*OneTimeToken, error) {
+	if len(opts.Key) < minOneTimeKeyLength {
+		return nil, fmt.Errorf("token key must be at least %d bytes, got %d", minOneTimeKeyLength, len(opts.Key))
+	}
+	if opts.TTL <= 0 {
+		return nil, fmt.Errorf("token ttl must be positive, got %v", opts.TTL)
+	}
+	if opts.Consume == nil {
+		return nil, fmt.Errorf("token Consume callback is required")
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	// A monotonic generator keeps the IDs issued by this process unique.
+	ttl := opts.TTL.Milliseconds()
+	generator, err := NewGenerator(WithClock(func() int64 { return clock() + ttl }))
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, len(opts.Key))
+	copy(key, opts.Key)
+	return &OneTimeToken{key: key, purpose: opts.Purpose, consume: opts.Consume, clock: clock, generator: generator}, nil
+}
+
+// mac computes the truncated tag of the token with ID id.
+func (t *OneTimeToken) mac(id Nano64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], id.value)
+	h := hmac.New(sha256.New, t.key)
+	h.Write([]byte(t.purpose))
+	h.Write([]byte{0})
+	h.Write(buf[:])
+	return h.Sum(nil)[:oneTimeMACLength]
+}
+
+// Issue creates a token. Store the returned ID with the subject it was issued
+// for (e.g. the user requesting a reset) so Consume can find it; the token
+// itself goes to the user.
+func (t *OneTimeToken) Issue() (string, Nano64, error) {
+	id, err := t.generator.GenerateMonotonic()
+	if err != nil {
+		return "", Nil, fmt.Errorf("failed to generate token ID: %w", err)
+	}
+	buf := make([]byte, 8, 8+oneTimeMACLength)
+	binary.BigEndian.PutUint64(buf, id.value)
+	buf = append(buf, t.mac(id)...)
+	return base64.RawURLEncoding.EncodeToString(buf), id, nil
+}
+
+// Redeem verifies a token, checks its expiry and consumes it, returning its ID.
+// Returns ErrInvalidToken for malformed or forged tokens, ErrTokenExpired for
+// expired ones, and the error of Consume otherwise.
+func (t *OneTimeToken) Redeem(ctx context.Context, token string) (Nano64, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) != 8+oneTimeMACLength {
+		return Nil, ErrInvalidToken
+	}
+	id := Nano64{value: binary.BigEndian.Uint64(buf[:8])}
+	if !hmac.Equal(buf[8:], t.mac(id)) {
+		return Nil, ErrInvalidToken
+	}
+	if ExpiredAt(id, t.clock()) {
+		return id, ErrTokenExpired
+	}
+	return id, t.consume(ctx, id)
+}
@@ -0,0 +1,81 @@
+package nano64
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOneTimeToken(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	var mu sync.Mutex
+	issued := make(map[Nano64]bool)
+	opts := OneTimeTokenOptions{
+		Key:     []byte("0123456789abcdef"),
+		Purpose: "password-reset",
+		TTL:     15 * time.Minute,
+		Clock:   func() int64 { return now },
+		Consume: func(_ context.Context, id Nano64) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if !issued[id] {
+				return ErrTokenUsed
+			}
+			delete(issued, id)
+			return nil
+		},
+	}
+	tokens, err := NewOneTimeToken(opts)
+	if err != nil {
+		t.Fatalf("NewOneTimeToken() error = %v", err)
+	}
+	ctx := context.Background()
+
+	token, id, err := tokens.Issue()
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	issued[id] = true
+	if id.GetTimestamp() != now+15*60*1000 {
+		t.Errorf("token ID expires at %d, want now + TTL", id.GetTimestamp())
+	}
+	if got, err := tokens.Redeem(ctx, token); err != nil || got != id {
+		t.Errorf("Redeem() = %s, %v", got.ToHex(), err)
+	}
+	if _, err := tokens.Redeem(ctx, token); !errors.Is(err, ErrTokenUsed) {
+		t.Errorf("second Redeem() error = %v, want ErrTokenUsed", err)
+	}
+
+	expiring, id, _ := tokens.Issue()
+	issued[id] = true
+	now += (15 * time.Minute).Milliseconds()
+	if _, err := tokens.Redeem(ctx, expiring); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Redeem() after TTL error = %v, want ErrTokenExpired", err)
+	}
+
+	fresh, id, _ := tokens.Issue()
+	issued[id] = true
+	tampered := []byte(fresh)
+	tampered[len(tampered)-1] ^= 1
+	opts.Purpose = "email-verification"
+	otherPurpose, _ := NewOneTimeToken(opts)
+	for name, redeem := range map[string]func() (Nano64, error){
+		"tampered":      func() (Nano64, error) { return tokens.Redeem(ctx, string(tampered)) },
+		"garbage":       func() (Nano64, error) { return tokens.Redeem(ctx, "not a token") },
+		"other purpose": func() (Nano64, error) { return otherPurpose.Redeem(ctx, fresh) },
+	} {
+		if _, err := redeem(); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: Redeem() error = %v, want ErrInvalidToken", name, err)
+		}
+	}
+	if !issued[id] {
+		t.Error("a rejected token was consumed")
+	}
+
+	opts.Key = []byte("short")
+	if _, err := NewOneTimeToken(opts); err == nil {
+		t.Error("NewOneTimeToken() accepted a short key")
+	}
+}
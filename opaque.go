@@ -0,0 +1,144 @@
+package nano64
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// cipherRounds is the number of Feistel rounds of Cipher. Four rounds of a
+// pseudorandom function already give a strong pseudorandom permutation; the rest
+// are margin.
+const cipherRounds = 8
+
+// ErrNilOpaqueID is returned by Cipher.Decrypt for the zero OpaqueNano64, which is
+// what an unset field or column holds.
+var ErrNilOpaqueID = errors.New("opaque ID is nil")
+
+// OpaqueNano64 is the external form of a Nano64 produced by Cipher.Encrypt. It has
+// the same size as the ID but reveals neither its creation time nor its order.
+// It encodes as 16 uppercase hex digits without a dash, so it cannot be mistaken
+// for a plain ID.
+type OpaqueNano64 struct {
+	value uint64
+}
+
+// Cipher maps IDs to OpaqueNano64 values with a keyed 64-bit block permutation:
+// an 8-round Feistel network whose round function is AES under the key. The
+// mapping is deterministic and one-to-one, so an ID always has the same opaque
+// form and can be stored in plain, time-sortable form while only the opaque form
+// crosses the API boundary.
+//
+// Unlike EncryptedIDConfig the output is not authenticated and carries no IV:
+// every 64-bit string decrypts to some ID. Treat decrypted IDs as untrusted lookup
+// keys. Use a key dedicated to this purpose.
+type Cipher struct {
+	block cipher.Block
+}
+
+// NewCipher creates a Cipher. The key must be 16, 24, or 32 bytes for AES-128,
+// AES-192, or AES-256 respectively.
+func NewCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+	return &Cipher{block: block}, nil
+}
+
+// round computes the Feistel round function of round i over half.
+func (c *Cipher) round(i int, half uint32) uint32 {
+	var buf [aes.BlockSize]byte
+	buf[0] = byte(i)
+	binary.BigEndian.PutUint32(buf[aes.BlockSize-4:], half)
+	c.block.Encrypt(buf[:], buf[:])
+	return binary.BigEndian.Uint32(buf[:4])
+}
+
+// Encrypt returns the opaque form of id.
+func (c *Cipher) Encrypt(id Nano64) OpaqueNano64 {
+	left, right := uint32(id.value>>32), uint32(id.value)
+	for i := range cipherRounds {
+		left, right = right, left^c.round(i, right)
+	}
+	return OpaqueNano64{value: uint64(left)<<32 | uint64(right)}
+}
+
+// Decrypt returns the ID behind an opaque value.
+// Returns ErrNilOpaqueID for the zero OpaqueNano64.
+func (c *Cipher) Decrypt(opaque OpaqueNano64) (Nano64, error) {
+	if opaque.IsNil() {
+		return Nil, ErrNilOpaqueID
+	}
+	left, right := uint32(opaque.value>>32), uint32(opaque.value)
+	for i := cipherRounds - 1; i >= 0; i-- {
+		left, right = right^c.round(i, left), left
+	}
+	return Nano64{value: uint64(left)<<32 | uint64(right)}, nil
+}
+
+// IsNil returns true for the zero OpaqueNano64.
+func (o OpaqueNano64) IsNil() bool {
+	return o.value == 0
+}
+
+// ToHex returns the 16-char uppercase hex encoding.
+func (o OpaqueNano64) ToHex() string {
+	return fmt.Sprintf("%016X", o.value)
+}
+
+// String returns the hex encoding.
+func (o OpaqueNano64) String() string {
+	return o.ToHex()
+}
+
+// ToBytes returns the 8-byte big-endian encoding.
+func (o OpaqueNano64) ToBytes() []byte {
+	return BigIntHelpers.ToBytesBE(o.value)
+}
+
+// OpaqueFromHex parses the 16-char hex form, in either case.
+func OpaqueFromHex(hexStr string) (OpaqueNano64, error) {
+	if len(hexStr) != 16 {
+		return OpaqueNano64{}, fmt.Errorf("opaque ID must be 16 hex chars, got %q", hexStr)
+	}
+	value, err := strconv.ParseUint(hexStr, 16, 64)
+	if err != nil {
+		return OpaqueNano64{}, fmt.Errorf("invalid opaque ID %q: %w", hexStr, err)
+	}
+	return OpaqueNano64{value: value}, nil
+}
+
+// OpaqueFromBytes parses the 8-byte big-endian form.
+func OpaqueFromBytes(bytes []byte) (OpaqueNano64, error) {
+	value, err := BigIntHelpers.FromBytesBE(bytes)
+	if err != nil {
+		return OpaqueNano64{}, fmt.Errorf("failed to parse bytes: %w", err)
+	}
+	return OpaqueNano64{value: value}, nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// Encodes the opaque ID as a hex string in JSON.
+func (o OpaqueNano64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.ToHex())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Accepts a hex string.
+func (o *OpaqueNano64) UnmarshalJSON(data []byte) error {
+	var hexStr string
+	if err := json.Unmarshal(data, &hexStr); err != nil {
+		return fmt.Errorf("failed to unmarshal OpaqueNano64: expected hex string")
+	}
+	parsed, err := OpaqueFromHex(hexStr)
+	if err != nil {
+		return err
+	}
+	*o = parsed
+	return nil
+}
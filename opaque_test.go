@@ -0,0 +1,77 @@
+package nano64
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCipher(t *testing.T) {
+	if _, err := NewCipher([]byte("short")); err == nil {
+		t.Error("NewCipher() accepted a 5-byte key")
+	}
+	c, err := NewCipher([]byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	other, _ := NewCipher([]byte("fedcba9876543210"))
+
+	// Consecutive IDs must not produce ordered or similar opaque values.
+	prev := c.Encrypt(mustGenerate(t, 1234567890123, 0))
+	ascending := 0
+	for random := uint32(1); random <= 64; random++ {
+		id := mustGenerate(t, 1234567890123, random)
+		opaque := c.Encrypt(id)
+		if opaque != c.Encrypt(id) {
+			t.Fatal("Encrypt() is not deterministic")
+		}
+		if opaque.value>>44 == id.value>>44 {
+			t.Errorf("opaque %s keeps the timestamp of %s", opaque, id.ToHex())
+		}
+		if opaque == other.Encrypt(id) {
+			t.Errorf("different keys map %s to the same opaque ID", id.ToHex())
+		}
+		decrypted, err := c.Decrypt(opaque)
+		if err != nil || decrypted != id {
+			t.Fatalf("Decrypt(Encrypt(%s)) = %s, %v", id.ToHex(), decrypted.ToHex(), err)
+		}
+		if opaque.value > prev.value {
+			ascending++
+		}
+		prev = opaque
+	}
+	if ascending < 16 || ascending > 48 {
+		t.Errorf("%d of 64 opaque IDs ascend, want about half", ascending)
+	}
+
+	if _, err := c.Decrypt(OpaqueNano64{}); !errors.Is(err, ErrNilOpaqueID) {
+		t.Errorf("Decrypt(zero) error = %v, want ErrNilOpaqueID", err)
+	}
+}
+
+func TestOpaqueNano64Encoding(t *testing.T) {
+	opaque := OpaqueNano64{value: 0x0123456789ABCDEF}
+	if got := opaque.ToHex(); got != "0123456789ABCDEF" {
+		t.Errorf("ToHex() = %s", got)
+	}
+	if parsed, err := OpaqueFromHex("0123456789abcdef"); err != nil || parsed != opaque {
+		t.Errorf("OpaqueFromHex() = %v, %v", parsed, err)
+	}
+	for _, bad := range []string{"0123456789ABCDE", "0123456789A-CDEF", "0x23456789ABCDEF", "0123456789ABCDEG"} {
+		if _, err := OpaqueFromHex(bad); err == nil {
+			t.Errorf("OpaqueFromHex(%q) succeeded, want error", bad)
+		}
+	}
+
+	data, err := json.Marshal(opaque)
+	if err != nil || string(data) != `"0123456789ABCDEF"` {
+		t.Fatalf("json.Marshal() = %s, %v", data, err)
+	}
+	var decoded OpaqueNano64
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != opaque {
+		t.Errorf("json.Unmarshal() = %v, %v", decoded, err)
+	}
+	if err := json.Unmarshal([]byte(`123`), &decoded); err == nil {
+		t.Error("json.Unmarshal(number) succeeded, want error")
+	}
+}
@@ -0,0 +1,45 @@
+package nano64
+
+const (
+	// OpenAPIFormat is the `format` name used for Nano64 string fields.
+	OpenAPIFormat = "nano64"
+
+	// OpenAPIPattern matches the strings accepted by FromHex and produced by ToHex.
+	OpenAPIPattern = `^(0[xX])?[0-9A-Fa-f]{11}-?[0-9A-Fa-f]{5}$`
+
+	// OpenAPIExample is a canonical ID used as the schema example.
+	OpenAPIExample = "0199C6A1F5E-3B2D4"
+
+	// SwaggoOverrides is the content of a swaggo `.swaggo` overrides file that
+	// documents Nano64 fields as strings; pass it to `swag init --overridesFile`.
+	// Tag individual fields with `format:"nano64"` to carry the format name as well.
+	SwaggoOverrides = "replace github.com/pisoj/go-nano64.Nano64 string\n" +
+		"replace github.com/pisoj/go-nano64.NullNano64 string\n"
+)
+
+// OpenAPISchema returns the JSON Schema / OpenAPI 3 fragment describing the JSON
+// form of a Nano64 (see MarshalJSON). The map is freshly allocated on every call,
+// so callers may extend it, e.g. with "nullable" for NullNano64 fields.
+func OpenAPISchema() map[string]any {
+	return map[string]any{
+		"type":        "string",
+		"format":      OpenAPIFormat,
+		"pattern":     OpenAPIPattern,
+		"minLength":   16,
+		"maxLength":   19,
+		"example":     OpenAPIExample,
+		"description": "Nano64 ID: 44-bit millisecond timestamp and 20-bit random field as dashed hex.",
+	}
+}
+
+// OapiCodegenSchema returns OpenAPISchema with the `x-go-type` extensions that make
+// oapi-codegen generate nano64.Nano64 fields instead of plain strings.
+func OapiCodegenSchema() map[string]any {
+	schema := OpenAPISchema()
+	schema["x-go-type"] = "nano64.Nano64"
+	schema["x-go-type-import"] = map[string]any{
+		"path": "github.com/pisoj/go-nano64",
+		"name": "nano64",
+	}
+	return schema
+}
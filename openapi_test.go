@@ -0,0 +1,58 @@
+package nano64
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+)
+
+func TestOpenAPIPattern(t *testing.T) {
+	pattern := regexp.MustCompile(OpenAPIPattern)
+
+	id, err := GenerateDefault()
+	if err != nil {
+		t.Fatalf("GenerateDefault() error = %v", err)
+	}
+	encoded, err := json.Marshal(id)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var emitted string
+	if err := json.Unmarshal(encoded, &emitted); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{emitted, true},
+		{OpenAPIExample, true},
+		{"0199c6a1f5e3b2d4", true},
+		{"0x0199C6A1F5E-3B2D4", true},
+		{"0199C6A1F5E-3B2D", false},
+		{"0199C6A1F5E-3B2DG", false},
+	}
+
+	for _, tt := range tests {
+		if got := pattern.MatchString(tt.input); got != tt.want {
+			t.Errorf("pattern.MatchString(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if _, err := FromHex(tt.input); (err == nil) != tt.want {
+			t.Errorf("FromHex(%q) error = %v, pattern match = %v", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestOapiCodegenSchema(t *testing.T) {
+	schema := OapiCodegenSchema()
+	if schema["x-go-type"] != "nano64.Nano64" {
+		t.Errorf("x-go-type = %v", schema["x-go-type"])
+	}
+	if _, ok := OpenAPISchema()["x-go-type"]; ok {
+		t.Error("OapiCodegenSchema() modified the shared OpenAPISchema()")
+	}
+	if _, err := json.Marshal(schema); err != nil {
+		t.Errorf("json.Marshal() error = %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// FindOrphans reports the distinct values of fkCol in childTable that lie within
+// timeRange and have no matching pkCol in parentTable, in ascending order.
+//
+// The range is scanned in chunks aligned to UTC hours (widened on long ranges to
+// at most 1024 chunks), each probing the parent's key with NOT EXISTS, so every
+// statement stays an index range scan however large the tables are. Both columns
+// must hold the 8-byte form; NULL references are never orphans.
+func FindOrphans(ctx context.Context, db Querier, dialect Dialect, childTable, fkCol, parentTable, pkCol string, timeRange IDRange) ([]Nano64, error) {
+	if err := dialect.validate(); err != nil {
+		return nil, err
+	}
+	if timeRange.Empty() {
+		return nil, nil
+	}
+
+	child, parent := dialect.QuoteIdent(childTable), dialect.QuoteIdent(parentTable)
+	fk, pk := dialect.QuoteIdent(fkCol), dialect.QuoteIdent(pkCol)
+	query := fmt.Sprintf(
+		"SELECT DISTINCT c.%s FROM %s c WHERE c.%s BETWEEN %s AND %s AND NOT EXISTS (SELECT 1 FROM %s p WHERE p.%s = c.%s)",
+		fk, child, fk, dialect.Placeholder(1), dialect.Placeholder(2), parent, pk, fk,
+	)
+
+	var orphans []Nano64
+	for _, chunk := range splitHourly(timeRange.First.value, timeRange.Last.value) {
+		rows, err := db.QueryContext(ctx, query, chunk.First, chunk.Last)
+		if err != nil {
+			return nil, fmt.Errorf("orphan scan of %s failed: %w", chunk, err)
+		}
+		found, err := ScanMissing(rows)
+		if err != nil {
+			return nil, err
+		}
+		for id := range found {
+			orphans = append(orphans, id)
+		}
+	}
+	slices.SortFunc(orphans, Compare)
+	return orphans, nil
+}
@@ -0,0 +1,54 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestFindOrphans(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	for _, stmt := range []string{
+		`CREATE TABLE users (id BLOB PRIMARY KEY)`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id BLOB)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	user := func(offset time.Duration, random uint32) Nano64 {
+		return mustGenerate(t, base.Add(offset).UnixMilli(), random)
+	}
+	kept := user(time.Minute, 1)
+	deleted := user(3*time.Hour, 2)
+	early := user(-time.Hour, 3)
+	if _, err := db.Exec(`INSERT INTO users (id) VALUES (?)`, kept); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	for _, ref := range []any{kept, deleted, deleted, early, nil} {
+		if _, err := db.Exec(`INSERT INTO orders (user_id) VALUES (?)`, ref); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+
+	orphans, err := FindOrphans(context.Background(), db, DialectSQLite, "orders", "user_id", "users", "id",
+		RangeForTime(base, base.Add(24*time.Hour)))
+	if err != nil {
+		t.Fatalf("FindOrphans() error = %v", err)
+	}
+	if len(orphans) != 1 || orphans[0] != deleted {
+		t.Errorf("FindOrphans() = %v, want [%v]", orphans, deleted)
+	}
+}
@@ -0,0 +1,216 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultOutboxSettleTime is the OutboxOptions.SettleTime used when it is zero.
+const defaultOutboxSettleTime = 5 * time.Second
+
+// OutboxOptions configures an Outbox.
+type OutboxOptions struct {
+	// Dialect selects the SQL flavor of the generated statements.
+	Dialect Dialect
+
+	// Table holds the outbox records. Required.
+	Table string
+
+	// IDColumn and PayloadColumn name the 8-byte ID column and the payload column;
+	// they default to "id" and "payload".
+	IDColumn      string
+	PayloadColumn string
+
+	// SettleTime is how old a record must be before it is claimed; zero means 5s.
+	// Transactions commit out of ID order, so a record with a lower ID may become
+	// visible after one with a higher ID. Claiming only settled records keeps the
+	// watermark from moving past a record that is still being committed; set it
+	// above the longest transaction that enqueues.
+	SettleTime time.Duration
+
+	// Watermark tracks the delivered records and is advanced by Ack. Nil creates
+	// an unpersisted one; pass a Watermark with Load and Save hooks to survive restarts.
+	Watermark *Watermark
+
+	// Clock stamps enqueued IDs and bounds claims; DefaultClock if nil.
+	Clock Clock
+}
+
+// OutboxRecord is a claimed outbox entry.
+type OutboxRecord struct {
+	ID      Nano64
+	Payload []byte
+}
+
+// Outbox implements the transactional outbox pattern on top of monotonic IDs.
+// Records are enqueued in the producer's transaction and delivered in ID order
+// by a relay that claims batches after a watermark and acknowledges them once
+// published. Delivery is at least once: records claimed but not acknowledged
+// before a restart are claimed again.
+//
+// An Outbox is meant for a single relay; it is safe for concurrent use by that
+// relay's goroutines.
+type Outbox struct {
+	dialect       Dialect
+	table         string
+	idColumn      string
+	payloadColumn string
+	settle        time.Duration
+	watermark     *Watermark
+	clock         Clock
+
+	mu      sync.Mutex
+	pending []Nano64
+	acked   map[Nano64]bool
+}
+
+// NewOutbox creates an Outbox.
+func NewOutbox(opts OutboxOptions) (*Outbox, error) {
+	if err := opts.Dialect.validate(); err != nil {
+		return nil, err
+	}
+	if opts.Table == "" {
+		return nil, fmt.Errorf("outbox table is required")
+	}
+	if opts.SettleTime < 0 {
+		return nil, fmt.Errorf("settle time must not be negative, got %v", opts.SettleTime)
+	}
+
+	o := &Outbox{
+		dialect:       opts.Dialect,
+		table:         opts.Table,
+		idColumn:      opts.IDColumn,
+		payloadColumn: opts.PayloadColumn,
+		settle:        opts.SettleTime,
+		watermark:     opts.Watermark,
+		clock:         opts.Clock,
+		acked:         make(map[Nano64]bool),
+	}
+	if o.idColumn == "" {
+		o.idColumn = "id"
+	}
+	if o.payloadColumn == "" {
+		o.payloadColumn = "payload"
+	}
+	if o.settle == 0 {
+		o.settle = defaultOutboxSettleTime
+	}
+	if o.watermark == nil {
+		o.watermark = NewWatermark(WatermarkOptions{Clock: opts.Clock})
+	}
+	if o.clock == nil {
+		o.clock = DefaultClock
+	}
+	return o, nil
+}
+
+// Watermark returns the watermark advanced by Ack.
+func (o *Outbox) Watermark() *Watermark {
+	return o.watermark
+}
+
+// Enqueue inserts payload with a fresh monotonic ID and returns the ID. Pass the
+// producer's *sql.Tx as db so the record commits atomically with the change it announces.
+func (o *Outbox) Enqueue(ctx context.Context, db Execer, payload []byte) (Nano64, error) {
+	id, err := GenerateMonotonic(o.clock(), DefaultRNG)
+	if err != nil {
+		return Nil, fmt.Errorf("failed to generate outbox ID: %w", err)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (%s, %s)",
+		o.dialect.QuoteIdent(o.table), o.dialect.QuoteIdent(o.idColumn), o.dialect.QuoteIdent(o.payloadColumn),
+		o.dialect.Placeholder(1), o.dialect.Placeholder(2))
+	if _, err := db.ExecContext(ctx, query, id, payload); err != nil {
+		return Nil, fmt.Errorf("failed to enqueue outbox record: %w", err)
+	}
+	return id, nil
+}
+
+// ClaimBatchAfter builds the query selecting up to limit settled records with IDs
+// above watermark, in ID order. Its rows hold the ID and the payload.
+func (o *Outbox) ClaimBatchAfter(watermark Nano64, limit int) (string, []any, error) {
+	if limit <= 0 {
+		return "", nil, fmt.Errorf("claim limit must be positive, got %d", limit)
+	}
+	settled := o.clock() - o.settle.Milliseconds()
+	if settled < 0 {
+		return "", nil, fmt.Errorf("clock %d is before the settle time", o.clock())
+	}
+	upper := Nano64{value: uint64(settled)<<timestampShift | randomMask}
+
+	id := o.dialect.QuoteIdent(o.idColumn)
+	query := fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s > %s AND %s <= %s ORDER BY %s LIMIT %d",
+		id, o.dialect.QuoteIdent(o.payloadColumn), o.dialect.QuoteIdent(o.table),
+		id, o.dialect.Placeholder(1), id, o.dialect.Placeholder(2), id, limit)
+	return query, []any{watermark, upper}, nil
+}
+
+// Claim fetches the next batch of up to limit records. Batches continue after the
+// last claimed record, so unacknowledged records are not claimed twice by one Outbox.
+func (o *Outbox) Claim(ctx context.Context, db Querier, limit int) ([]OutboxRecord, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	after := o.watermark.ID()
+	if n := len(o.pending); n > 0 && Compare(o.pending[n-1], after) > 0 {
+		after = o.pending[n-1]
+	}
+	query, args, err := o.ClaimBatchAfter(after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []OutboxRecord
+	for rows.Next() {
+		var r OutboxRecord
+		if err := rows.Scan(&r.ID, &r.Payload); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox record: %w", err)
+		}
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim outbox records: %w", err)
+	}
+
+	for _, r := range records {
+		o.pending = append(o.pending, r.ID)
+	}
+	return records, nil
+}
+
+// Ack marks a claimed record as delivered. Records may be acknowledged in any
+// order; the watermark only advances past a record once every record claimed
+// before it has been acknowledged too, so a crash never skips an undelivered one.
+// Returns whether the watermark moved.
+func (o *Outbox) Ack(id Nano64) (bool, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, found := slices.BinarySearchFunc(o.pending, id, Compare); !found {
+		return false, fmt.Errorf("outbox record %s was not claimed", id.ToHex())
+	}
+	o.acked[id] = true
+
+	done := 0
+	for done < len(o.pending) && o.acked[o.pending[done]] {
+		delete(o.acked, o.pending[done])
+		done++
+	}
+	if done == 0 {
+		return false, nil
+	}
+	last := o.pending[done-1]
+	o.pending = o.pending[done:]
+	return o.watermark.Advance(last), nil
+}
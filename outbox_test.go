@@ -0,0 +1,104 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestOutboxClaimBatchAfter(t *testing.T) {
+	o, err := NewOutbox(OutboxOptions{
+		Dialect: DialectPostgres,
+		Table:   "app.outbox",
+		Clock:   func() int64 { return 10_000 },
+	})
+	if err != nil {
+		t.Fatalf("NewOutbox() error = %v", err)
+	}
+
+	query, args, err := o.ClaimBatchAfter(Nil, 100)
+	if err != nil {
+		t.Fatalf("ClaimBatchAfter() error = %v", err)
+	}
+	want := `SELECT "id", "payload" FROM "app"."outbox" WHERE "id" > $1 AND "id" <= $2 ORDER BY "id" LIMIT 100`
+	if query != want {
+		t.Errorf("ClaimBatchAfter() =\n%s\nwant\n%s", query, want)
+	}
+	if upper := args[1].(Nano64); upper.GetTimestamp() != 5_000 || upper.GetRandom() != randomMask {
+		t.Errorf("upper bound = %s, want the last ID of the settled millisecond", upper.ToHex())
+	}
+
+	if _, _, err := o.ClaimBatchAfter(Nil, 0); err == nil {
+		t.Error("ClaimBatchAfter() accepted a zero limit")
+	}
+	if _, err := NewOutbox(OutboxOptions{}); err == nil {
+		t.Error("NewOutbox() accepted an empty table")
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE outbox (id BLOB PRIMARY KEY, payload BLOB)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	now := DefaultClock()
+	o, err := NewOutbox(OutboxOptions{
+		Dialect:    DialectSQLite,
+		Table:      "outbox",
+		SettleTime: time.Second,
+		Clock:      func() int64 { return now },
+	})
+	if err != nil {
+		t.Fatalf("NewOutbox() error = %v", err)
+	}
+
+	var ids []Nano64
+	for _, payload := range []string{"a", "b", "c"} {
+		id, err := o.Enqueue(ctx, db, []byte(payload))
+		if err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Fresh records are not settled yet.
+	if records, err := o.Claim(ctx, db, 10); err != nil || len(records) != 0 {
+		t.Fatalf("Claim() = %v, %v; want nothing before the settle time", records, err)
+	}
+
+	now += 2_000
+	batch, err := o.Claim(ctx, db, 2)
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	if len(batch) != 2 || batch[0].ID != ids[0] || string(batch[1].Payload) != "b" {
+		t.Fatalf("Claim() = %v, want the first two records", batch)
+	}
+
+	// Acknowledging out of order holds the watermark until the gap closes.
+	if moved, err := o.Ack(ids[1]); err != nil || moved {
+		t.Errorf("Ack(second) = %v, %v; want no move", moved, err)
+	}
+	if moved, err := o.Ack(ids[0]); err != nil || !moved || o.Watermark().ID() != ids[1] {
+		t.Errorf("Ack(first) = %v, %v; watermark %s, want %s", moved, err, o.Watermark().ID().ToHex(), ids[1].ToHex())
+	}
+	if _, err := o.Ack(ids[2]); err == nil {
+		t.Error("Ack() accepted an unclaimed record")
+	}
+
+	rest, err := o.Claim(ctx, db, 10)
+	if err != nil || len(rest) != 1 || rest[0].ID != ids[2] {
+		t.Fatalf("Claim() = %v, %v; want the third record", rest, err)
+	}
+}
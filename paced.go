@@ -0,0 +1,117 @@
+package nano64
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SafeRatePerMillisecond is the number of random IDs per millisecond at which the
+// probability of any collision within that millisecond stays around 1%.
+const SafeRatePerMillisecond = 145
+
+// PacedOptions configures a PacedGenerator.
+type PacedOptions struct {
+	// PerMillisecond is the number of IDs issued per millisecond before moving on
+	// to the next one. Zero means SafeRatePerMillisecond.
+	PerMillisecond int
+
+	// MaxSkew bounds how far ahead of the clock issuance may run. When a burst
+	// exhausts it, Generate sleeps until the clock catches up. Zero means 50ms.
+	MaxSkew time.Duration
+
+	// OverflowMargin makes Generate fail with ErrNearOverflow once the clock is
+	// within this margin of OverflowDate. Zero disables the check.
+	OverflowMargin time.Duration
+
+	// Clock and RNG default to DefaultClock and DefaultRNG.
+	Clock Clock
+	RNG   RNG
+}
+
+// PacedGenerator smooths bursts of random IDs over consecutive milliseconds.
+// Each millisecond hands out at most PerMillisecond IDs; a burst beyond that
+// borrows capacity from the following milliseconds, so IDs may carry timestamps
+// slightly in the future instead of racing for collisions within one millisecond.
+// It suits batch processes that tolerate a few milliseconds of skew.
+// A PacedGenerator is safe for concurrent use.
+type PacedGenerator struct {
+	perMs   int
+	maxSkew int64
+	margin  time.Duration
+	clock   Clock
+	rng     RNG
+
+	mu     sync.Mutex
+	ts     int64
+	issued int
+}
+
+// NewPacedGenerator creates a PacedGenerator.
+func NewPacedGenerator(opts PacedOptions) (*PacedGenerator, error) {
+	if opts.PerMillisecond < 0 {
+		return nil, fmt.Errorf("per-millisecond rate cannot be negative: %d", opts.PerMillisecond)
+	}
+	if opts.PerMillisecond == 0 {
+		opts.PerMillisecond = SafeRatePerMillisecond
+	}
+	if opts.MaxSkew < 0 {
+		return nil, fmt.Errorf("max skew cannot be negative: %v", opts.MaxSkew)
+	}
+	if opts.MaxSkew == 0 {
+		opts.MaxSkew = 50 * time.Millisecond
+	}
+	if opts.OverflowMargin < 0 {
+		return nil, fmt.Errorf("overflow margin cannot be negative: %v", opts.OverflowMargin)
+	}
+	if opts.Clock == nil {
+		opts.Clock = DefaultClock
+	}
+	if opts.RNG == nil {
+		opts.RNG = DefaultRNG
+	}
+
+	return &PacedGenerator{
+		perMs:   opts.PerMillisecond,
+		maxSkew: opts.MaxSkew.Milliseconds(),
+		margin:  opts.OverflowMargin,
+		clock:   opts.Clock,
+		rng:     opts.RNG,
+		ts:      -1,
+	}, nil
+}
+
+// Generate returns the next ID, possibly stamped a few milliseconds ahead of the clock.
+// While waiting for the clock to catch up it does not hold the generator's lock,
+// so concurrent callers are not serialized behind the sleep.
+func (g *PacedGenerator) Generate() (Nano64, error) {
+	g.mu.Lock()
+	for {
+		now := g.clock()
+		if err := CheckOverflow(now, g.margin); err != nil {
+			g.mu.Unlock()
+			return Nil, err
+		}
+		if now > g.ts {
+			g.ts = now
+			g.issued = 0
+		}
+		if g.issued >= g.perMs {
+			if ahead := g.ts + 1 - now; ahead > g.maxSkew {
+				// Wait for the clock to catch up, then take a fresh look:
+				// other callers may have moved on in the meantime.
+				g.mu.Unlock()
+				time.Sleep(time.Duration(ahead-g.maxSkew) * time.Millisecond)
+				g.mu.Lock()
+				continue
+			}
+			g.ts++
+			g.issued = 0
+		}
+
+		g.issued++
+		ts := g.ts
+		g.mu.Unlock()
+		return Generate(ts, g.rng)
+	}
+}
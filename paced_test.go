@@ -0,0 +1,98 @@
+package nano64
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPacedGenerator(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	gen, err := NewPacedGenerator(PacedOptions{
+		PerMillisecond: 10,
+		Clock:          func() int64 { return now },
+	})
+	if err != nil {
+		t.Fatalf("NewPacedGenerator() error = %v", err)
+	}
+
+	perMs := make(map[int64]int)
+	for i := 0; i < 35; i++ {
+		id, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		perMs[id.GetTimestamp()]++
+	}
+
+	for ts := now; ts < now+3; ts++ {
+		if perMs[ts] != 10 {
+			t.Errorf("ms %d issued %d IDs, want 10", ts-now, perMs[ts])
+		}
+	}
+	if perMs[now+3] != 5 {
+		t.Errorf("ms 3 issued %d IDs, want 5", perMs[now+3])
+	}
+
+	// Once the clock moves past the borrowed milliseconds, issuance follows it again.
+	now += 100
+	id, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if id.GetTimestamp() != now {
+		t.Errorf("timestamp = %d, want %d", id.GetTimestamp(), now)
+	}
+}
+
+func TestPacedGeneratorSleepsUnlocked(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	gen, err := NewPacedGenerator(PacedOptions{
+		PerMillisecond: 1,
+		MaxSkew:        time.Millisecond,
+		Clock:          now.Load,
+	})
+	if err != nil {
+		t.Fatalf("NewPacedGenerator() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := gen.Generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	// The skew is used up, so the next call waits for the frozen clock.
+	done := make(chan error, 1)
+	go func() {
+		_, err := gen.Generate()
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	unlocked := false
+	for deadline := time.Now().Add(time.Second); !unlocked && time.Now().Before(deadline); {
+		select {
+		case err := <-done:
+			t.Fatalf("Generate() returned %v before the clock advanced", err)
+		default:
+		}
+		if gen.mu.TryLock() {
+			gen.mu.Unlock()
+			unlocked = true
+		}
+	}
+	if !unlocked {
+		t.Error("Generate() holds the lock while waiting for the clock")
+	}
+
+	now.Add(10)
+	if err := <-done; err != nil {
+		t.Errorf("Generate() after the clock advanced error = %v", err)
+	}
+}
+
+func TestNewPacedGenerator_Errors(t *testing.T) {
+	if _, err := NewPacedGenerator(PacedOptions{PerMillisecond: -1}); err == nil {
+		t.Error("NewPacedGenerator() expected error for negative rate")
+	}
+}
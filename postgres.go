@@ -0,0 +1,102 @@
+package nano64
+
+import (
+	"strconv"
+	"strings"
+)
+
+// postgresFunctionsTemplate holds the PL/pgSQL definitions emitted by PostgresFunctions.
+// IDs are handled in their SignedNano64 form (value XOR 2^63) so they fit a BIGINT column.
+const postgresFunctionsTemplate = `CREATE OR REPLACE FUNCTION {{prefix}}nano64_from_parts(ts_ms bigint, random bigint)
+RETURNS bigint LANGUAGE plpgsql IMMUTABLE STRICT PARALLEL SAFE AS $$
+BEGIN
+	IF ts_ms < 0 OR ts_ms > {{timestamp_mask}} THEN
+		RAISE EXCEPTION 'nano64: timestamp exceeds {{timestamp_bits}}-bit range: %', ts_ms;
+	END IF;
+	IF random < 0 OR random > {{random_mask}} THEN
+		RAISE EXCEPTION 'nano64: random exceeds {{random_bits}}-bit range: %', random;
+	END IF;
+	RETURN ((ts_ms << {{random_bits}}) | random) # ({{sign_bit}})::bigint;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION {{prefix}}nano64_generate()
+RETURNS bigint LANGUAGE plpgsql VOLATILE PARALLEL SAFE AS $$
+DECLARE
+	b bytea := uuid_send(gen_random_uuid());
+BEGIN
+	RETURN {{prefix}}nano64_from_parts(
+		floor(extract(epoch FROM clock_timestamp()) * 1000)::bigint,
+		((get_byte(b, 0)::bigint << 16) | (get_byte(b, 1)::bigint << 8) | get_byte(b, 2)::bigint) & {{random_mask}}
+	);
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION {{prefix}}nano64_timestamp(id bigint)
+RETURNS bigint LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS $$
+	SELECT ((id # ({{sign_bit}})::bigint) >> {{random_bits}}) & {{timestamp_mask}};
+$$;
+
+CREATE OR REPLACE FUNCTION {{prefix}}nano64_random(id bigint)
+RETURNS bigint LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS $$
+	SELECT (id # ({{sign_bit}})::bigint) & {{random_mask}};
+$$;
+
+CREATE OR REPLACE FUNCTION {{prefix}}nano64_to_timestamptz(id bigint)
+RETURNS timestamptz LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS $$
+	SELECT to_timestamp({{prefix}}nano64_timestamp(id) / 1000.0);
+$$;
+
+CREATE OR REPLACE FUNCTION {{prefix}}nano64_range_start(ts timestamptz)
+RETURNS bigint LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS $$
+	SELECT {{prefix}}nano64_from_parts(floor(extract(epoch FROM ts) * 1000)::bigint, 0);
+$$;
+
+CREATE OR REPLACE FUNCTION {{prefix}}nano64_range_end(ts timestamptz)
+RETURNS bigint LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS $$
+	SELECT {{prefix}}nano64_from_parts(floor(extract(epoch FROM ts) * 1000)::bigint, {{random_mask}});
+$$;
+
+CREATE OR REPLACE FUNCTION {{prefix}}nano64_hex(id bigint)
+RETURNS text LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS $$
+	SELECT upper(substr(h, 1, {{hex_split}}) || '-' || substr(h, {{hex_split}} + 1))
+	FROM (SELECT lpad(to_hex(id # ({{sign_bit}})::bigint), 16, '0') AS h) AS hex;
+$$;
+`
+
+// postgresQuoteIdent quotes a PostgreSQL identifier.
+func postgresQuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// PostgresFunctions returns PL/pgSQL definitions that generate and decompose Nano64
+// IDs stored as BIGINT in their SignedNano64 form. The bit layout is taken from this
+// package's constants, so server-side defaults always agree with Go:
+//
+//	nano64_generate()                 new ID, usable as a column DEFAULT
+//	nano64_from_parts(ts_ms, random)  ID from its fields
+//	nano64_timestamp(id)              embedded Unix milliseconds
+//	nano64_random(id)                 random field
+//	nano64_to_timestamptz(id)         embedded time as timestamptz
+//	nano64_range_start(ts)            lowest ID of a millisecond
+//	nano64_range_end(ts)              highest ID of a millisecond
+//	nano64_hex(id)                    dashed hex form, as Nano64.ToHex
+//
+// The functions are created in schema, or unqualified if schema is empty.
+// nano64_generate relies on gen_random_uuid, built in since PostgreSQL 13.
+func PostgresFunctions(schema string) string {
+	prefix := ""
+	if schema != "" {
+		prefix = postgresQuoteIdent(schema) + "."
+	}
+
+	return strings.NewReplacer(
+		"{{prefix}}", prefix,
+		"{{timestamp_bits}}", strconv.Itoa(TimestampBits),
+		"{{timestamp_mask}}", strconv.FormatUint(timestampMask, 10),
+		"{{random_bits}}", strconv.Itoa(RandomBits),
+		"{{random_mask}}", strconv.FormatUint(randomMask, 10),
+		"{{sign_bit}}", strconv.FormatInt(-1<<63, 10),
+		"{{hex_split}}", strconv.Itoa((TimestampBits+3)/4),
+	).Replace(postgresFunctionsTemplate)
+}
@@ -0,0 +1,18 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"context"
+	"fmt"
+)
+
+// InstallPostgresFunctions executes PostgresFunctions(schema) against db.
+// The script contains several statements, so the driver must accept multi-statement
+// execution without arguments (lib/pq and pgx's database/sql adapter both do).
+func InstallPostgresFunctions(ctx context.Context, db Execer, schema string) error {
+	if _, err := db.ExecContext(ctx, PostgresFunctions(schema)); err != nil {
+		return fmt.Errorf("failed to install postgres functions: %w", err)
+	}
+	return nil
+}
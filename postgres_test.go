@@ -0,0 +1,30 @@
+package nano64
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPostgresFunctions(t *testing.T) {
+	script := PostgresFunctions(`ids"x`)
+
+	for _, want := range []string{
+		`CREATE OR REPLACE FUNCTION "ids""x".nano64_generate()`,
+		`"ids""x".nano64_from_parts(`,
+		"((ts_ms << 20) | random) # (-9223372036854775808)::bigint",
+		"ts_ms > 17592186044415",
+		"random > 1048575",
+		"substr(h, 1, 11)",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+	if strings.Contains(script, "{{") {
+		t.Error("script contains unreplaced placeholders")
+	}
+
+	if unqualified := PostgresFunctions(""); !strings.Contains(unqualified, "FUNCTION nano64_hex(id bigint)") {
+		t.Error("unqualified script should not carry a schema prefix")
+	}
+}
@@ -0,0 +1,62 @@
+package nano64
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestPriorityLayout(t *testing.T) {
+	layout, err := NewPriorityLayout(2)
+	if err != nil {
+		t.Fatalf("NewPriorityLayout() error = %v", err)
+	}
+
+	ts := int64(1_700_000_000_000)
+	var ids []Nano64
+	for _, p := range []int{0, 3, 1, 2} {
+		id, err := layout.Generate(ts, p, nil)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if got := layout.Priority(id); got != p {
+			t.Errorf("Priority() = %d, want %d", got, p)
+		}
+		if id.GetTimestamp() != ts {
+			t.Errorf("GetTimestamp() = %d, want %d", id.GetTimestamp(), ts)
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return Compare(ids[i], ids[j]) < 0 })
+	for i, id := range ids {
+		if got, want := layout.Priority(id), 3-i; got != want {
+			t.Errorf("sorted[%d] priority = %d, want %d", i, got, want)
+		}
+	}
+
+	changed, err := layout.WithPriority(ids[0], 0)
+	if err != nil {
+		t.Fatalf("WithPriority() error = %v", err)
+	}
+	if layout.Priority(changed) != 0 || changed.GetTimestamp() != ts {
+		t.Errorf("WithPriority() = %s", changed.ToHex())
+	}
+
+	start, end, err := layout.Range(ts, 1)
+	if err != nil {
+		t.Fatalf("Range() error = %v", err)
+	}
+	if layout.Priority(start) != 1 || layout.Priority(end) != 1 || Compare(start, end) >= 0 {
+		t.Errorf("Range() = %s..%s", start.ToHex(), end.ToHex())
+	}
+}
+
+func TestPriorityLayout_Errors(t *testing.T) {
+	if _, err := NewPriorityLayout(0); err == nil {
+		t.Error("NewPriorityLayout(0) expected error")
+	}
+	layout, _ := NewPriorityLayout(3)
+	if _, err := layout.Generate(0, 8, nil); err == nil {
+		t.Error("Generate() expected error for out-of-range priority")
+	}
+}
@@ -0,0 +1,67 @@
+package nano64
+
+import "testing"
+
+func TestProvenanceRegistry(t *testing.T) {
+	for _, bits := range []int{1, 5} {
+		if _, err := NewProvenanceRegistry(bits); err == nil {
+			t.Errorf("NewProvenanceRegistry(%d) succeeded, want error", bits)
+		}
+	}
+
+	r, err := NewProvenanceRegistry(3)
+	if err != nil {
+		t.Fatalf("NewProvenanceRegistry() error = %v", err)
+	}
+
+	for class, name := range standardSourceClasses {
+		id, err := r.Generate(1234567890123, class, nil)
+		if err != nil {
+			t.Fatalf("Generate(%s) error = %v", name, err)
+		}
+		if id.GetTimestamp() != 1234567890123 {
+			t.Errorf("Generate(%s) timestamp = %d", name, id.GetTimestamp())
+		}
+		if got, gotName := r.Extract(id); got != class || gotName != name {
+			t.Errorf("Extract() = %d, %q; want %d, %q", got, gotName, class, name)
+		}
+	}
+
+	if err := r.Register("backfill", 5); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if class, ok := r.Lookup("backfill"); !ok || class != 5 {
+		t.Errorf("Lookup(backfill) = %d, %v", class, ok)
+	}
+	tests := []struct {
+		name  string
+		class SourceClass
+	}{
+		{"replay", 5},   // class taken
+		{"backfill", 6}, // name taken
+		{"api", 7},      // standard name taken
+		{"overflow", 8}, // does not fit 3 bits
+		{"", 6},         // empty name
+	}
+	for _, tt := range tests {
+		if err := r.Register(tt.name, tt.class); err == nil {
+			t.Errorf("Register(%q, %d) succeeded, want error", tt.name, tt.class)
+		}
+	}
+
+	// Encoding keeps the timestamp and the other random bits.
+	id := mustGenerate(t, 1234567890123, 0xFFFFF)
+	tagged, err := r.Encode(id, SourceImport)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if got := tagged.GetRandom(); got != 0x3FFFF {
+		t.Errorf("Encode() random = %#x, want 0x3ffff", got)
+	}
+	if _, err := r.Encode(id, 6); err == nil {
+		t.Error("Encode() accepted an unregistered class")
+	}
+	if class, name := r.Extract(id); class != 7 || name != "" {
+		t.Errorf("Extract() = %d, %q; want 7, unnamed", class, name)
+	}
+}
@@ -0,0 +1,189 @@
+package nano64
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"sort"
+	"time"
+)
+
+// IDRange is the inclusive range of IDs from First through Last.
+type IDRange struct {
+	First Nano64 `json:"first"`
+	Last  Nano64 `json:"last"`
+}
+
+// RangeForTime returns the range of every ID created in the half-open interval
+// [start, end), converted to the inclusive IDRange. Times outside the representable
+// span, before 1970 or after OverflowDate, are clamped to it. When no millisecond
+// lies in the interval, as when end is not after start, the result is empty and
+// Empty reports true; use RangeForTime(t, t.Add(time.Millisecond)) for the IDs of
+// a single millisecond.
+func RangeForTime(start, end time.Time) IDRange {
+	from, to := clampRangeMillis(start), clampRangeMillis(end)
+	if to <= from {
+		return IDRange{First: Nano64{value: 1}, Last: Nil}
+	}
+	return IDRange{
+		First: Nano64{value: uint64(from) << timestampShift},
+		Last:  Nano64{value: uint64(to-1)<<timestampShift | randomMask},
+	}
+}
+
+// clampRangeMillis returns t in Unix milliseconds, clamped to [0, maxTimestamp+1]
+// so that it can bound a half-open range of timestamps.
+func clampRangeMillis(t time.Time) int64 {
+	return min(max(t.UnixMilli(), 0), int64(maxTimestamp)+1)
+}
+
+// Empty reports whether the range contains no IDs.
+func (r IDRange) Empty() bool {
+	return r.First.value > r.Last.value
+}
+
+// Contains reports whether id lies within the range.
+func (r IDRange) Contains(id Nano64) bool {
+	return r.First.value <= id.value && id.value <= r.Last.value
+}
+
+// String returns the range as "[FIRST, LAST]" in hex.
+func (r IDRange) String() string {
+	return "[" + r.First.ToHex() + ", " + r.Last.ToHex() + "]"
+}
+
+// RangeSet is a union of ID ranges, kept as sorted, disjoint, non-adjacent
+// ranges; e.g. the parts of an ID-keyed table that have been backfilled or verified.
+// The zero value is an empty set. A RangeSet is not safe for concurrent use.
+type RangeSet struct {
+	ranges []IDRange
+}
+
+// Add adds every ID in r to the set. Empty ranges are ignored.
+func (s *RangeSet) Add(r IDRange) {
+	if r.Empty() {
+		return
+	}
+
+	// Ranges entirely before r with a gap stay in front; ranges entirely after
+	// r with a gap stay behind; everything between merges into r.
+	lo := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].Last.value >= r.First.value || r.First.value-s.ranges[i].Last.value == 1
+	})
+	hi := lo
+	for hi < len(s.ranges) && (s.ranges[hi].First.value <= r.Last.value || s.ranges[hi].First.value-r.Last.value == 1) {
+		hi++
+	}
+	if lo < hi {
+		r.First.value = min(r.First.value, s.ranges[lo].First.value)
+		r.Last.value = max(r.Last.value, s.ranges[hi-1].Last.value)
+	}
+
+	merged := make([]IDRange, 0, len(s.ranges)-(hi-lo)+1)
+	merged = append(merged, s.ranges[:lo]...)
+	merged = append(merged, r)
+	merged = append(merged, s.ranges[hi:]...)
+	s.ranges = merged
+}
+
+// Subtract removes every ID in r from the set.
+func (s *RangeSet) Subtract(r IDRange) {
+	if r.Empty() {
+		return
+	}
+
+	result := make([]IDRange, 0, len(s.ranges)+1)
+	for _, e := range s.ranges {
+		if e.Last.value < r.First.value || e.First.value > r.Last.value {
+			result = append(result, e)
+			continue
+		}
+		if e.First.value < r.First.value {
+			result = append(result, IDRange{First: e.First, Last: Nano64{value: r.First.value - 1}})
+		}
+		if e.Last.value > r.Last.value {
+			result = append(result, IDRange{First: Nano64{value: r.Last.value + 1}, Last: e.Last})
+		}
+	}
+	s.ranges = result
+}
+
+// Contains reports whether id is in the set.
+func (s *RangeSet) Contains(id Nano64) bool {
+	i := sort.Search(len(s.ranges), func(i int) bool { return s.ranges[i].Last.value >= id.value })
+	return i < len(s.ranges) && s.ranges[i].Contains(id)
+}
+
+// Missing returns the parts of r not covered by the set, in ascending order.
+func (s *RangeSet) Missing(r IDRange) []IDRange {
+	var gaps RangeSet
+	gaps.Add(r)
+	for _, e := range s.ranges {
+		gaps.Subtract(e)
+	}
+	return gaps.ranges
+}
+
+// Ranges iterates over the set's ranges in ascending order.
+func (s *RangeSet) Ranges() iter.Seq[IDRange] {
+	return func(yield func(IDRange) bool) {
+		for _, r := range s.ranges {
+			if !yield(r) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of disjoint ranges in the set.
+func (s *RangeSet) Len() int {
+	return len(s.ranges)
+}
+
+// MarshalJSON encodes the set as an array of {"first", "last"} objects.
+func (s RangeSet) MarshalJSON() ([]byte, error) {
+	if s.ranges == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s.ranges)
+}
+
+// UnmarshalJSON decodes a set encoded by MarshalJSON. Ranges may overlap or be
+// out of order; they are normalized.
+func (s *RangeSet) UnmarshalJSON(data []byte) error {
+	var ranges []IDRange
+	if err := json.Unmarshal(data, &ranges); err != nil {
+		return fmt.Errorf("failed to unmarshal RangeSet: %w", err)
+	}
+	*s = RangeSet{}
+	for _, r := range ranges {
+		s.Add(r)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the set as consecutive 16-byte big-endian (first, last) pairs.
+func (s RangeSet) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 16*len(s.ranges))
+	for _, r := range s.ranges {
+		buf = binary.BigEndian.AppendUint64(buf, r.First.value)
+		buf = binary.BigEndian.AppendUint64(buf, r.Last.value)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a set encoded by MarshalBinary.
+func (s *RangeSet) UnmarshalBinary(data []byte) error {
+	if len(data)%16 != 0 {
+		return fmt.Errorf("invalid RangeSet length: %d", len(data))
+	}
+	*s = RangeSet{}
+	for i := 0; i < len(data); i += 16 {
+		s.Add(IDRange{
+			First: Nano64{value: binary.BigEndian.Uint64(data[i:])},
+			Last:  Nano64{value: binary.BigEndian.Uint64(data[i+8:])},
+		})
+	}
+	return nil
+}
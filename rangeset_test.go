@@ -0,0 +1,136 @@
+package nano64
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func idRange(first, last uint64) IDRange {
+	return IDRange{First: New(first), Last: New(last)}
+}
+
+func TestRangeSetAdd(t *testing.T) {
+	tests := []struct {
+		name string
+		add  []IDRange
+		want []IDRange
+	}{
+		{"disjoint", []IDRange{idRange(10, 20), idRange(30, 40)}, []IDRange{idRange(10, 20), idRange(30, 40)}},
+		{"out of order", []IDRange{idRange(30, 40), idRange(10, 20)}, []IDRange{idRange(10, 20), idRange(30, 40)}},
+		{"overlap", []IDRange{idRange(10, 20), idRange(15, 35)}, []IDRange{idRange(10, 35)}},
+		{"adjacent", []IDRange{idRange(10, 20), idRange(21, 30)}, []IDRange{idRange(10, 30)}},
+		{"bridge", []IDRange{idRange(10, 20), idRange(30, 40), idRange(50, 60), idRange(21, 49)}, []IDRange{idRange(10, 60)}},
+		{"contained", []IDRange{idRange(10, 60), idRange(20, 30)}, []IDRange{idRange(10, 60)}},
+		{"empty ignored", []IDRange{idRange(20, 10)}, nil},
+		{"extremes", []IDRange{idRange(0, 5), idRange(^uint64(0)-5, ^uint64(0))}, []IDRange{idRange(0, 5), idRange(^uint64(0)-5, ^uint64(0))}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s RangeSet
+			for _, r := range tt.add {
+				s.Add(r)
+			}
+			if got := slices.Collect(s.Ranges()); !slices.Equal(got, tt.want) {
+				t.Errorf("Ranges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRangeSetSubtractContainsMissing(t *testing.T) {
+	var s RangeSet
+	s.Add(idRange(10, 100))
+	s.Subtract(idRange(40, 60))
+	s.Subtract(idRange(0, 10))
+
+	if want := []IDRange{idRange(11, 39), idRange(61, 100)}; !slices.Equal(slices.Collect(s.Ranges()), want) {
+		t.Fatalf("Ranges() = %v, want %v", slices.Collect(s.Ranges()), want)
+	}
+
+	for id, want := range map[uint64]bool{10: false, 11: true, 39: true, 40: false, 60: false, 61: true, 100: true, 101: false} {
+		if got := s.Contains(New(id)); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", id, got, want)
+		}
+	}
+
+	if got, want := s.Missing(idRange(0, 120)), []IDRange{idRange(0, 10), idRange(40, 60), idRange(101, 120)}; !slices.Equal(got, want) {
+		t.Errorf("Missing() = %v, want %v", got, want)
+	}
+}
+
+func TestRangeSetSerialization(t *testing.T) {
+	var s RangeSet
+	s.Add(RangeForTime(time.UnixMilli(1000), time.UnixMilli(2000)))
+	s.Add(idRange(^uint64(0)-1, ^uint64(0)))
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var fromJSON RangeSet
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	bin, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+	var fromBinary RangeSet
+	if err := fromBinary.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+
+	want := slices.Collect(s.Ranges())
+	if got := slices.Collect(fromJSON.Ranges()); !slices.Equal(got, want) {
+		t.Errorf("JSON round trip = %v, want %v", got, want)
+	}
+	if got := slices.Collect(fromBinary.Ranges()); !slices.Equal(got, want) {
+		t.Errorf("binary round trip = %v, want %v", got, want)
+	}
+	if err := fromBinary.UnmarshalBinary(bin[:5]); err == nil {
+		t.Error("UnmarshalBinary() expected error for truncated input")
+	}
+}
+
+func TestRangeForTime(t *testing.T) {
+	r := RangeForTime(time.UnixMilli(1000), time.UnixMilli(1002))
+	if r.First.GetTimestamp() != 1000 || r.First.GetRandom() != 0 {
+		t.Errorf("First = %v", r.First)
+	}
+	if r.Last.GetTimestamp() != 1001 || r.Last.GetRandom() != randomMask {
+		t.Errorf("Last = %v", r.Last)
+	}
+}
+
+func TestRangeForTimeBounds(t *testing.T) {
+	overflow := time.UnixMilli(int64(maxTimestamp) + 1)
+	tests := []struct {
+		name        string
+		start, end  time.Time
+		empty       bool
+		first, last uint64
+	}{
+		{"same instant", time.UnixMilli(1000), time.UnixMilli(1000), true, 0, 0},
+		{"reversed", time.UnixMilli(2000), time.UnixMilli(1000), true, 0, 0},
+		{"ends at the epoch", time.UnixMilli(-5000), time.UnixMilli(0), true, 0, 0},
+		{"starts before 1970", time.UnixMilli(-5000), time.UnixMilli(2), false, 0, 1<<timestampShift | randomMask},
+		{"ends after overflow", time.UnixMilli(int64(maxTimestamp)), overflow.Add(time.Hour), false, maxTimestamp << timestampShift, math.MaxUint64},
+		{"starts after overflow", overflow, overflow.Add(time.Hour), true, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := RangeForTime(tt.start, tt.end)
+			if r.Empty() != tt.empty {
+				t.Fatalf("RangeForTime() = %v, Empty() = %v, want %v", r, r.Empty(), tt.empty)
+			}
+			if !tt.empty && (r.First.value != tt.first || r.Last.value != tt.last) {
+				t.Errorf("RangeForTime() = %v, want [%016X, %016X]", r, tt.first, tt.last)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package nano64
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ErrRefCycle is wrapped by the *RefCycleError TopologicalOrder returns.
+var ErrRefCycle = errors.New("reference cycle")
+
+// RefCycleError reports the cycles of a RefGraph that prevent a topological order.
+type RefCycleError struct {
+	// Cycles lists each cycle starting at its smallest ID, following parent
+	// references; cycles are ordered by that ID.
+	Cycles [][]Nano64
+}
+
+// Error lists the cycles as hex IDs.
+func (e *RefCycleError) Error() string {
+	parts := make([]string, len(e.Cycles))
+	for i, cycle := range e.Cycles {
+		ids := make([]string, len(cycle))
+		for j, id := range cycle {
+			ids[j] = id.ToHex()
+		}
+		parts[i] = strings.Join(ids, " -> ")
+	}
+	return fmt.Sprintf("%v: %s", ErrRefCycle, strings.Join(parts, "; "))
+}
+
+// Unwrap returns ErrRefCycle.
+func (e *RefCycleError) Unwrap() error {
+	return ErrRefCycle
+}
+
+// RefGraph is a hierarchy of IDs with optional parents, such as rows with a
+// nullable parent_id column: each node references at most one parent. It orders
+// the nodes parents-first, finds cycles and dangling references, and renders the
+// graph in Graphviz DOT. A RefGraph is not safe for concurrent use.
+type RefGraph struct {
+	parents  map[Nano64]NullNano64
+	children map[Nano64][]Nano64
+}
+
+// NewRefGraph creates an empty graph.
+func NewRefGraph() *RefGraph {
+	return &RefGraph{parents: make(map[Nano64]NullNano64), children: make(map[Nano64][]Nano64)}
+}
+
+// Add adds the edge of id to parent; an invalid parent makes id a root. Adding
+// the same edge again is a no-op; giving id a different parent is an error.
+func (g *RefGraph) Add(id Nano64, parent NullNano64) error {
+	if id.IsNil() {
+		return fmt.Errorf("cannot add the Nil ID to a reference graph")
+	}
+	if parent.Valid && parent.ID == id {
+		return fmt.Errorf("%w: %s references itself", ErrRefCycle, id.ToHex())
+	}
+	if existing, ok := g.parents[id]; ok {
+		if existing != parent {
+			return fmt.Errorf("%s already has a different parent", id.ToHex())
+		}
+		return nil
+	}
+	g.parents[id] = parent
+	if parent.Valid {
+		g.children[parent.ID] = append(g.children[parent.ID], id)
+	}
+	return nil
+}
+
+// Len returns the number of nodes added.
+func (g *RefGraph) Len() int {
+	return len(g.parents)
+}
+
+// Parent returns the parent of id; invalid for roots and unknown IDs.
+func (g *RefGraph) Parent(id Nano64) NullNano64 {
+	return g.parents[id]
+}
+
+// Children returns the nodes referencing id, in ID order.
+func (g *RefGraph) Children(id Nano64) []Nano64 {
+	children := slices.Clone(g.children[id])
+	slices.SortFunc(children, Compare)
+	return children
+}
+
+// Roots returns the nodes without a parent in the graph, in ID order: those with
+// no parent and those whose parent was never added.
+func (g *RefGraph) Roots() []Nano64 {
+	var roots []Nano64
+	for id, parent := range g.parents {
+		if _, known := g.parents[parent.ID]; !parent.Valid || !known {
+			roots = append(roots, id)
+		}
+	}
+	slices.SortFunc(roots, Compare)
+	return roots
+}
+
+// Dangling returns the parents referenced but never added, in ID order, such as
+// rows whose parent was deleted.
+func (g *RefGraph) Dangling() []Nano64 {
+	var dangling []Nano64
+	for parent := range g.children {
+		if _, ok := g.parents[parent]; !ok {
+			dangling = append(dangling, parent)
+		}
+	}
+	slices.SortFunc(dangling, Compare)
+	return dangling
+}
+
+// TopologicalOrder returns every node with each parent before its children:
+// depth-first from the roots, siblings in ID order. It returns a *RefCycleError
+// if some nodes are caught in, or descend from, a cycle.
+func (g *RefGraph) TopologicalOrder() ([]Nano64, error) {
+	order := make([]Nano64, 0, len(g.parents))
+	stack := slices.Clone(g.Roots())
+	slices.Reverse(stack)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		order = append(order, id)
+		children := g.Children(id)
+		slices.Reverse(children)
+		stack = append(stack, children...)
+	}
+	if len(order) < len(g.parents) {
+		return order, &RefCycleError{Cycles: g.Cycles()}
+	}
+	return order, nil
+}
+
+// Cycles returns every cycle, each starting at its smallest ID and following
+// parent references, ordered by that ID.
+func (g *RefGraph) Cycles() [][]Nano64 {
+	// Each node has one parent, so following parents from any node either ends
+	// at a root or enters exactly one cycle.
+	state := make(map[Nano64]int, len(g.parents)) // 0 unvisited, 1 on the current path, 2 done
+	var cycles [][]Nano64
+	for start := range g.parents {
+		var path []Nano64
+		id := start
+		for state[id] == 0 {
+			parent, ok := g.parents[id]
+			if !ok || !parent.Valid {
+				break
+			}
+			state[id] = 1
+			path = append(path, id)
+			id = parent.ID
+		}
+		if state[id] == 1 {
+			cycle := slices.Clone(path[slices.Index(path, id):])
+			smallest := slices.Index(cycle, slices.MinFunc(cycle, Compare))
+			cycles = append(cycles, append(cycle[smallest:], cycle[:smallest]...))
+		}
+		for _, visited := range path {
+			state[visited] = 2
+		}
+	}
+	slices.SortFunc(cycles, func(a, b []Nano64) int { return Compare(a[0], b[0]) })
+	return cycles
+}
+
+// WriteDOT writes the graph in Graphviz DOT with an edge from each parent to
+// its children. Nodes are labeled with their hex ID; dangling parents are drawn
+// dashed.
+func (g *RefGraph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph refs {\n")
+	ids := make([]Nano64, 0, len(g.parents))
+	for id := range g.parents {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, Compare)
+	for _, id := range ids {
+		fmt.Fprintf(bw, "\t%q;\n", id.ToHex())
+	}
+	for _, id := range g.Dangling() {
+		fmt.Fprintf(bw, "\t%q [style=dashed];\n", id.ToHex())
+	}
+	for _, id := range ids {
+		if parent := g.parents[id]; parent.Valid {
+			fmt.Fprintf(bw, "\t%q -> %q;\n", parent.ID.ToHex(), id.ToHex())
+		}
+	}
+	bw.WriteString("}\n")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write DOT: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package nano64
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRefGraph(t *testing.T) {
+	ids := make([]Nano64, 8)
+	for i := range ids {
+		ids[i] = mustGenerate(t, int64(1000+i), 0)
+	}
+	root := NullNano64{}
+	ref := func(i int) NullNano64 { return NullNano64{ID: ids[i], Valid: true} }
+	deleted := mustGenerate(t, 500, 0)
+
+	g := NewRefGraph()
+	edges := []struct {
+		id     int
+		parent NullNano64
+	}{
+		{3, ref(1)}, {1, root}, {2, ref(1)}, {4, ref(2)}, {0, root}, {5, NullNano64{ID: deleted, Valid: true}},
+	}
+	for _, e := range edges {
+		if err := g.Add(ids[e.id], e.parent); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if err := g.Add(ids[3], ref(1)); err != nil {
+		t.Errorf("re-adding an edge failed: %v", err)
+	}
+	if err := g.Add(ids[3], ref(2)); err == nil {
+		t.Error("Add() accepted a second parent")
+	}
+	if err := g.Add(ids[6], ref(6)); !errors.Is(err, ErrRefCycle) {
+		t.Errorf("self-reference error = %v, want ErrRefCycle", err)
+	}
+
+	order, err := g.TopologicalOrder()
+	if err != nil {
+		t.Fatalf("TopologicalOrder() error = %v", err)
+	}
+	want := []Nano64{ids[0], ids[1], ids[2], ids[4], ids[3], ids[5]}
+	if !slices.Equal(order, want) {
+		t.Errorf("TopologicalOrder() = %v, want %v", order, want)
+	}
+	if !slices.Equal(g.Roots(), []Nano64{ids[0], ids[1], ids[5]}) || !slices.Equal(g.Dangling(), []Nano64{deleted}) {
+		t.Errorf("Roots() = %v, Dangling() = %v", g.Roots(), g.Dangling())
+	}
+	if !slices.Equal(g.Children(ids[1]), []Nano64{ids[2], ids[3]}) || g.Parent(ids[4]) != ref(2) {
+		t.Errorf("Children() = %v, Parent() = %v", g.Children(ids[1]), g.Parent(ids[4]))
+	}
+
+	var dot strings.Builder
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+	for _, line := range []string{
+		`"` + ids[1].ToHex() + `" -> "` + ids[2].ToHex() + `";`,
+		`"` + deleted.ToHex() + `" [style=dashed];`,
+	} {
+		if !strings.Contains(dot.String(), line) {
+			t.Errorf("DOT output lacks %s:\n%s", line, dot.String())
+		}
+	}
+
+	// 6 -> 7 -> 6 is a cycle.
+	g.Add(ids[6], ref(7))
+	g.Add(ids[7], ref(6))
+	order, err = g.TopologicalOrder()
+	var cycleErr *RefCycleError
+	if !errors.As(err, &cycleErr) || !errors.Is(err, ErrRefCycle) || len(order) != 6 {
+		t.Fatalf("TopologicalOrder() = %v, %v, want a cycle error after 6 nodes", order, err)
+	}
+	if len(cycleErr.Cycles) != 1 || !slices.Equal(cycleErr.Cycles[0], []Nano64{ids[6], ids[7]}) {
+		t.Errorf("Cycles = %v, want [[6 7]]", cycleErr.Cycles)
+	}
+}
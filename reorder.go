@@ -0,0 +1,77 @@
+package nano64
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Reorderer restores ID order over slightly shuffled delivery. Incoming IDs are
+// held until the newest embedded timestamp seen has moved more than the lateness
+// window past them, then released in ascending order. Memory is bounded by the
+// number of IDs arriving within one window.
+// A Reorderer is safe for concurrent use.
+type Reorderer struct {
+	lateness int64
+
+	mu       sync.Mutex
+	pending  idHeap
+	newest   int64
+	released Nano64
+	started  bool
+}
+
+// NewReorderer creates a Reorderer that tolerates IDs arriving up to lateness
+// after newer ones.
+func NewReorderer(lateness time.Duration) *Reorderer {
+	return &Reorderer{lateness: max(lateness.Milliseconds(), 0)}
+}
+
+// Push adds id and returns the IDs that became ready, in ascending order.
+// ok is false if id is too late to be ordered, because a greater ID has already
+// been released; such IDs are not buffered and the caller decides what to do.
+func (r *Reorderer) Push(id Nano64) (ready []Nano64, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started && id.value < r.released.value {
+		return nil, false
+	}
+	heap.Push(&r.pending, id)
+	if ts := id.GetTimestamp(); ts > r.newest {
+		r.newest = ts
+	}
+
+	cutoff := r.newest - r.lateness
+	for len(r.pending) > 0 && r.pending[0].GetTimestamp() < cutoff {
+		ready = append(ready, r.pop())
+	}
+	return ready, true
+}
+
+// Flush releases every buffered ID in ascending order, e.g. at end of stream.
+func (r *Reorderer) Flush() []Nano64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ready := make([]Nano64, 0, len(r.pending))
+	for len(r.pending) > 0 {
+		ready = append(ready, r.pop())
+	}
+	return ready
+}
+
+// Len returns the number of buffered IDs.
+func (r *Reorderer) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending)
+}
+
+// pop releases the smallest buffered ID.
+func (r *Reorderer) pop() Nano64 {
+	id := heap.Pop(&r.pending).(Nano64)
+	r.released = id
+	r.started = true
+	return id
+}
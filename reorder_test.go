@@ -0,0 +1,49 @@
+package nano64
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReorderer(t *testing.T) {
+	r := NewReorderer(100 * time.Millisecond)
+	at := func(ts int64) Nano64 { return mustGenerate(t, ts, 0) }
+
+	var out []Nano64
+	push := func(id Nano64) {
+		t.Helper()
+		ready, ok := r.Push(id)
+		if !ok {
+			t.Fatalf("Push(%d) rejected", id.GetTimestamp())
+		}
+		out = append(out, ready...)
+	}
+
+	push(at(1000))
+	push(at(1050))
+	push(at(1020))
+	push(at(1090))
+	if len(out) != 0 {
+		t.Fatalf("released %d IDs inside the window, want 0", len(out))
+	}
+
+	push(at(1130)) // releases everything before 1030
+	if want := []Nano64{at(1000), at(1020)}; !slices.Equal(out, want) {
+		t.Fatalf("released %v, want %v", out, want)
+	}
+	if r.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", r.Len())
+	}
+
+	if _, ok := r.Push(at(1010)); ok {
+		t.Error("Push() accepted an ID older than one already released")
+	}
+
+	push(at(1040)) // late but still orderable
+	out = append(out, r.Flush()...)
+	want := []Nano64{at(1000), at(1020), at(1040), at(1050), at(1090), at(1130)}
+	if !slices.Equal(out, want) {
+		t.Errorf("output = %v, want %v", out, want)
+	}
+}
@@ -0,0 +1,84 @@
+package nano64
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplayMapperPreservesTiming(t *testing.T) {
+	const base = 1_600_000_000_000
+	originals := []Nano64{
+		mustGenerate(t, base, 900_000),
+		mustGenerate(t, base, 10),
+		mustGenerate(t, base+7, 5),
+		mustGenerate(t, base+250, 1),
+	}
+
+	shift := 24 * time.Hour
+	m := NewReplayMapper(shift, nil)
+
+	var mapped []Nano64
+	for _, original := range originals {
+		id, err := m.Map(original)
+		if err != nil {
+			t.Fatalf("Map() error = %v", err)
+		}
+		if got, want := id.GetTimestamp(), original.GetTimestamp()+shift.Milliseconds(); got != want {
+			t.Errorf("mapped timestamp = %d, want %d", got, want)
+		}
+		mapped = append(mapped, id)
+	}
+
+	for i := 1; i < len(mapped); i++ {
+		if Compare(mapped[i-1], mapped[i]) >= 0 {
+			t.Errorf("mapped IDs out of arrival order at %d: %s >= %s", i, mapped[i-1], mapped[i])
+		}
+	}
+
+	again, err := m.Map(originals[2])
+	if err != nil || again != mapped[2] {
+		t.Errorf("Map() of repeated ID = %s, %v; want %s", again, err, mapped[2])
+	}
+	if m.Len() != len(originals) {
+		t.Errorf("Len() = %d, want %d", m.Len(), len(originals))
+	}
+}
+
+func TestReplayMapperExportImport(t *testing.T) {
+	m := NewReplayMapper(time.Hour, nil)
+	a, b := mustGenerate(t, 1000, 1), mustGenerate(t, 1000, 2)
+	mappedA, _ := m.Map(a)
+	mappedB, _ := m.Map(b)
+
+	var buf bytes.Buffer
+	if err := m.Export(&buf); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Fatalf("Export() wrote %d lines, want 2", lines)
+	}
+
+	restored := NewReplayMapper(time.Hour, nil)
+	if err := restored.Import(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if got, ok := restored.Lookup(a); !ok || got != mappedA {
+		t.Errorf("Lookup(a) = %s, %v; want %s", got, ok, mappedA)
+	}
+
+	// New IDs in an imported millisecond must sort after the imported ones.
+	c, err := restored.Map(mustGenerate(t, 1000, 3))
+	if err != nil {
+		t.Fatalf("Map() error = %v", err)
+	}
+	if Compare(c, mappedA) <= 0 || Compare(c, mappedB) <= 0 {
+		t.Errorf("Map() after Import = %s, want above %s and %s", c, mappedA, mappedB)
+	}
+
+	conflict := a.ToHex() + "," + c.ToHex() + "\n"
+	if err := restored.Import(strings.NewReader(conflict)); err == nil {
+		t.Error("Import() expected error for conflicting mapping")
+	}
+}
@@ -0,0 +1,67 @@
+package nano64
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// ReservedBits records which bits of the random field are claimed by features that
+// store data there: PriorityLayout, ProvenanceRegistry and TempIDBit take the
+// top bits, TombstoneBit and EmbedShard the bottom ones. Features claiming the same bits
+// silently overwrite each other's data, so a keyspace combining several of them
+// should claim each one's mask through a single ReservedBits, which rejects
+// overlaps:
+//
+//	var reserved nano64.ReservedBits
+//	err := reserved.Reserve("priority", priority.Mask())
+//	err = reserved.Reserve("tombstone", nano64.TombstoneBit)
+//	err = reserved.Reserve("shard", nano64.ShardMask(4)) // overlaps the tombstone bit
+//
+// The zero value has nothing reserved. A ReservedBits is not safe for concurrent use.
+type ReservedBits struct {
+	claims []bitClaim
+}
+
+// bitClaim is one feature's reservation.
+type bitClaim struct {
+	name string
+	mask uint64
+}
+
+// Reserve claims mask for the feature name. It fails if mask is empty, reaches
+// outside the random field, or overlaps bits already claimed by another feature.
+func (r *ReservedBits) Reserve(name string, mask uint64) error {
+	if name == "" {
+		return fmt.Errorf("reservation name must not be empty")
+	}
+	if mask == 0 {
+		return fmt.Errorf("%s reserves no bits", name)
+	}
+	if mask&^randomMask != 0 {
+		return fmt.Errorf("%s reserves bits outside the random field: %#x", name, mask)
+	}
+	for _, c := range r.claims {
+		if c.name == name {
+			return fmt.Errorf("%s is already reserved", name)
+		}
+		if overlap := c.mask & mask; overlap != 0 {
+			return fmt.Errorf("%s overlaps %s in bits %#x", name, c.name, overlap)
+		}
+	}
+	r.claims = append(r.claims, bitClaim{name: name, mask: mask})
+	return nil
+}
+
+// Mask returns the union of every reserved mask.
+func (r *ReservedBits) Mask() uint64 {
+	var mask uint64
+	for _, c := range r.claims {
+		mask |= c.mask
+	}
+	return mask
+}
+
+// Free returns the number of random bits left unreserved.
+func (r *ReservedBits) Free() int {
+	return RandomBits - bits.OnesCount64(r.Mask())
+}
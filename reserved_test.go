@@ -0,0 +1,62 @@
+package nano64
+
+import "testing"
+
+func TestReservedBits(t *testing.T) {
+	priority, err := NewPriorityLayout(3)
+	if err != nil {
+		t.Fatalf("NewPriorityLayout() error = %v", err)
+	}
+	provenance, err := NewProvenanceRegistry(2)
+	if err != nil {
+		t.Fatalf("NewProvenanceRegistry() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		claims  map[string]uint64
+		order   []string
+		wantErr bool
+	}{
+		{"priority and tombstone", map[string]uint64{"priority": priority.Mask(), "tombstone": TombstoneBit}, []string{"priority", "tombstone"}, false},
+		{"provenance and shard", map[string]uint64{"provenance": provenance.Mask(), "shard": ShardMask(8)}, []string{"provenance", "shard"}, false},
+		{"priority and provenance", map[string]uint64{"priority": priority.Mask(), "provenance": provenance.Mask()}, []string{"priority", "provenance"}, true},
+		{"shard and tombstone", map[string]uint64{"shard": ShardMask(4), "tombstone": TombstoneBit}, []string{"shard", "tombstone"}, true},
+		{"invalid shard width", map[string]uint64{"shard": ShardMask(9)}, []string{"shard"}, true},
+		{"timestamp bits", map[string]uint64{"clock": 1 << timestampShift}, []string{"clock"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reserved ReservedBits
+			var err error
+			for _, name := range tt.order {
+				if err = reserved.Reserve(name, tt.claims[name]); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reserve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReservedBitsMask(t *testing.T) {
+	var reserved ReservedBits
+	if err := reserved.Reserve("tombstone", TombstoneBit); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if err := reserved.Reserve("tombstone", 1<<5); err == nil {
+		t.Error("Reserve() under a taken name succeeded")
+	}
+	layout, _ := NewPriorityLayout(2)
+	if err := reserved.Reserve("priority", layout.Mask()); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if got, want := reserved.Mask(), TombstoneBit|3<<(RandomBits-2); got != want {
+		t.Errorf("Mask() = %#x, want %#x", got, want)
+	}
+	if got := reserved.Free(); got != RandomBits-3 {
+		t.Errorf("Free() = %d, want %d", got, RandomBits-3)
+	}
+}
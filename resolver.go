@@ -0,0 +1,217 @@
+package nano64
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultResolverMaxBatch is the number of IDs fetched at once unless configured.
+const defaultResolverMaxBatch = 100
+
+// ErrNotFound is returned by Resolver.Get for IDs the fetch function did not return.
+var ErrNotFound = errors.New("entity not found")
+
+// ResolverCache caches resolved entities. *LRU[T] implements it.
+type ResolverCache[T any] interface {
+	Get(id Nano64) (T, bool)
+	Add(id Nano64, value T) (evicted Nano64, ok bool)
+}
+
+// ResolverOptions configures a Resolver.
+type ResolverOptions[T any] struct {
+	// Fetch loads the entities with the given IDs, e.g. with a single
+	// `WHERE id IN (...)` query. IDs missing from the result do not exist.
+	// Required.
+	Fetch func(ctx context.Context, ids []Nano64) (map[Nano64]T, error)
+
+	// MaxBatch is the most IDs passed to one Fetch; 100 if zero.
+	MaxBatch int
+
+	// Wait is how long a lookup waits for others to join its batch. Zero batches
+	// only the IDs of a single GetMany and lookups already waiting.
+	Wait time.Duration
+
+	// Cache, if set, is consulted before fetching and filled with fetched
+	// entities.
+	Cache ResolverCache[T]
+
+	// Absent, if set, reports IDs known not to exist, such as the Contains
+	// method of a RangeSet of purged ranges; they are not fetched.
+	Absent func(id Nano64) bool
+}
+
+// resolverCall is a pending or in-flight lookup of one ID, shared by every
+// caller asking for it meanwhile.
+type resolverCall[T any] struct {
+	done  chan struct{}
+	value T
+	found bool
+	err   error
+}
+
+// Resolver resolves IDs to entities through a batch fetch function. Concurrent
+// lookups of the same ID share one fetch, and lookups of different IDs are
+// grouped into batches, so a burst of requests resolving overlapping IDs costs
+// a handful of queries instead of one per request.
+// A Resolver is safe for concurrent use.
+type Resolver[T any] struct {
+	fetch    func(ctx context.Context, ids []Nano64) (map[Nano64]T, error)
+	maxBatch int
+	wait     time.Duration
+	cache    ResolverCache[T]
+	absent   func(id Nano64) bool
+
+	mu      sync.Mutex
+	calls   map[Nano64]*resolverCall[T]
+	pending []Nano64
+	timer   *time.Timer
+}
+
+// NewResolver creates a Resolver.
+func NewResolver[T any](opts ResolverOptions[T]) (*Resolver[T], error) {
+	if opts.Fetch == nil {
+		return nil, fmt.Errorf("resolver Fetch function is required")
+	}
+	if opts.MaxBatch < 0 {
+		return nil, fmt.Errorf("resolver batch size must not be negative, got %d", opts.MaxBatch)
+	}
+	if opts.Wait < 0 {
+		return nil, fmt.Errorf("resolver wait must not be negative, got %v", opts.Wait)
+	}
+	maxBatch := opts.MaxBatch
+	if maxBatch == 0 {
+		maxBatch = defaultResolverMaxBatch
+	}
+	return &Resolver[T]{
+		fetch:    opts.Fetch,
+		maxBatch: maxBatch,
+		wait:     opts.Wait,
+		cache:    opts.Cache,
+		absent:   opts.Absent,
+		calls:    make(map[Nano64]*resolverCall[T]),
+	}, nil
+}
+
+// Get resolves id. It returns ErrNotFound if the entity does not exist.
+func (r *Resolver[T]) Get(ctx context.Context, id Nano64) (T, error) {
+	found, err := r.GetMany(ctx, []Nano64{id})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	value, ok := found[id]
+	if !ok {
+		return value, fmt.Errorf("%w: %s", ErrNotFound, id.ToHex())
+	}
+	return value, nil
+}
+
+// GetMany resolves ids, returning the entities that exist. It fails if any
+// batch holding one of ids failed to fetch.
+//
+// Batches are fetched with a context that keeps the values but not the
+// cancellation of ctx, since other callers may share them; canceling ctx only
+// stops the wait.
+func (r *Resolver[T]) GetMany(ctx context.Context, ids []Nano64) (map[Nano64]T, error) {
+	found := make(map[Nano64]T, len(ids))
+	waiting := make(map[Nano64]*resolverCall[T])
+
+	r.mu.Lock()
+	for _, id := range ids {
+		if _, ok := found[id]; ok || waiting[id] != nil {
+			continue
+		}
+		if r.absent != nil && r.absent(id) {
+			continue
+		}
+		if r.cache != nil {
+			if value, ok := r.cache.Get(id); ok {
+				found[id] = value
+				continue
+			}
+		}
+		call, ok := r.calls[id]
+		if !ok {
+			call = &resolverCall[T]{done: make(chan struct{})}
+			r.calls[id] = call
+			r.pending = append(r.pending, id)
+			if len(r.pending) == r.maxBatch {
+				r.dispatchLocked(ctx)
+			}
+		}
+		waiting[id] = call
+	}
+	if len(r.pending) > 0 {
+		if r.wait == 0 {
+			r.dispatchLocked(ctx)
+		} else if r.timer == nil {
+			fetchCtx := context.WithoutCancel(ctx)
+			r.timer = time.AfterFunc(r.wait, func() {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+				r.dispatchLocked(fetchCtx)
+			})
+		}
+	}
+	r.mu.Unlock()
+
+	for id, call := range waiting {
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		if call.found {
+			found[id] = call.value
+		}
+	}
+	return found, nil
+}
+
+// dispatchLocked starts fetching the pending IDs; r.mu must be held.
+func (r *Resolver[T]) dispatchLocked(ctx context.Context) {
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	if len(r.pending) == 0 {
+		return
+	}
+	batch := r.pending
+	r.pending = nil
+	go r.fetchBatch(context.WithoutCancel(ctx), batch)
+}
+
+// fetchBatch fetches batch and completes its calls.
+func (r *Resolver[T]) fetchBatch(ctx context.Context, batch []Nano64) {
+	values, err := r.fetch(ctx, batch)
+	if err != nil {
+		err = fmt.Errorf("failed to resolve %d IDs: %w", len(batch), err)
+	}
+
+	r.mu.Lock()
+	calls := make([]*resolverCall[T], len(batch))
+	for i, id := range batch {
+		calls[i] = r.calls[id]
+		delete(r.calls, id)
+	}
+	r.mu.Unlock()
+
+	for i, id := range batch {
+		call := calls[i]
+		call.err = err
+		if err == nil {
+			call.value, call.found = values[id]
+			if call.found && r.cache != nil {
+				r.cache.Add(id, call.value)
+			}
+		}
+		close(call.done)
+	}
+}
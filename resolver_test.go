@@ -0,0 +1,152 @@
+package nano64
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fetchRecorder is a Fetch function returning the hex of even IDs and recording its batches.
+type fetchRecorder struct {
+	mu      sync.Mutex
+	batches [][]Nano64
+	err     error
+	block   chan struct{}
+}
+
+func (f *fetchRecorder) fetch(ctx context.Context, ids []Nano64) (map[Nano64]string, error) {
+	if f.block != nil {
+		<-f.block
+	}
+	f.mu.Lock()
+	f.batches = append(f.batches, append([]Nano64(nil), ids...))
+	f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	values := make(map[Nano64]string)
+	for _, id := range ids {
+		if id.GetRandom()%2 == 0 {
+			values[id] = id.ToHex()
+		}
+	}
+	return values, nil
+}
+
+func (f *fetchRecorder) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.batches)
+}
+
+func TestResolverGetMany(t *testing.T) {
+	f := &fetchRecorder{}
+	cache, err := NewLRU[string](LRUOptions{Capacity: 100})
+	if err != nil {
+		t.Fatalf("NewLRU() error = %v", err)
+	}
+	purged := mustGenerate(t, 1_700_000_000_000, 100)
+	r, err := NewResolver(ResolverOptions[string]{
+		Fetch:    f.fetch,
+		MaxBatch: 4,
+		Cache:    cache,
+		Absent:   func(id Nano64) bool { return id == purged },
+	})
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	ctx := context.Background()
+	var ids []Nano64
+	for i := range 10 {
+		ids = append(ids, mustGenerate(t, 1_700_000_000_000, uint32(i)))
+	}
+	found, err := r.GetMany(ctx, append(ids, ids[0], purged))
+	if err != nil {
+		t.Fatalf("GetMany() error = %v", err)
+	}
+	if len(found) != 5 {
+		t.Errorf("GetMany() found %d entities, want 5", len(found))
+	}
+	for id, value := range found {
+		if value != id.ToHex() {
+			t.Errorf("GetMany()[%s] = %s", id.ToHex(), value)
+		}
+	}
+	if f.calls() != 3 {
+		t.Errorf("fetched in %d batches, want 3 of at most 4", f.calls())
+	}
+
+	if value, err := r.Get(ctx, ids[2]); err != nil || value != ids[2].ToHex() {
+		t.Errorf("Get() = %q, %v", value, err)
+	}
+	if f.calls() != 3 {
+		t.Error("Get() fetched an ID that was cached")
+	}
+	if _, err := r.Get(ctx, purged); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of an absent ID error = %v, want ErrNotFound", err)
+	}
+	if _, err := r.Get(ctx, ids[1]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of a missing ID error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestResolverDedupe(t *testing.T) {
+	f := &fetchRecorder{}
+	r, err := NewResolver(ResolverOptions[string]{Fetch: f.fetch, Wait: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	id := mustGenerate(t, 1_700_000_000_000, 2)
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			target := id
+			if i%2 == 1 {
+				target = mustGenerate(t, 1_700_000_000_000, uint32(100+2*i))
+			}
+			if _, err := r.Get(context.Background(), target); err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if f.calls() != 1 {
+		t.Fatalf("fetched %d times, want 1", f.calls())
+	}
+	if n := len(f.batches[0]); n != 11 {
+		t.Errorf("batch holds %d IDs, want 11 distinct", n)
+	}
+}
+
+func TestResolverErrors(t *testing.T) {
+	if _, err := NewResolver(ResolverOptions[string]{}); err == nil {
+		t.Error("NewResolver() accepted a missing Fetch")
+	}
+
+	boom := errors.New("boom")
+	f := &fetchRecorder{err: boom}
+	r, _ := NewResolver(ResolverOptions[string]{Fetch: f.fetch})
+	id := mustGenerate(t, 1_700_000_000_000, 2)
+	if _, err := r.Get(context.Background(), id); !errors.Is(err, boom) {
+		t.Errorf("Get() error = %v, want boom", err)
+	}
+	f.err = nil
+	if _, err := r.Get(context.Background(), id); err != nil {
+		t.Errorf("Get() after a failed fetch error = %v, want a retry", err)
+	}
+
+	f = &fetchRecorder{block: make(chan struct{})}
+	r, _ = NewResolver(ResolverOptions[string]{Fetch: f.fetch})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.Get(ctx, id); !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() error = %v, want context.Canceled", err)
+	}
+	close(f.block)
+}
@@ -0,0 +1,103 @@
+package nano64
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRevisionConflict is returned by CheckRevision when an entity changed since
+// the revision a writer read.
+var ErrRevisionConflict = errors.New("revision conflict")
+
+// Revision is an optimistic-concurrency version of an entity, stored in a
+// revision column next to the Nano64 primary key. Each write stamps a new
+// revision from NextRevision; an update applies only if the row still has the
+// revision the writer read:
+//
+//	UPDATE orders SET ..., revision = $next WHERE id = $id AND revision = $read
+//
+// Revisions are time-ordered IDs, so they also tell when an entity last
+// changed. The zero Revision marks an entity never written. In SQL and JSON a
+// Revision is encoded like a Nano64.
+type Revision struct {
+	id Nano64
+}
+
+// RevisionOf returns the revision with the given ID.
+func RevisionOf(id Nano64) Revision {
+	return Revision{id: id}
+}
+
+// NextRevision returns a revision newer than prev, using the default generator.
+// The result is newer than prev even if prev was stamped by a host whose clock
+// runs ahead.
+func NextRevision(prev Revision) (Revision, error) {
+	return defaultGenerator.NextRevision(prev)
+}
+
+// NextRevision returns a revision newer than prev generated by g.
+func (g *Generator) NextRevision(prev Revision) (Revision, error) {
+	id, err := g.GenerateMonotonic()
+	if err != nil {
+		return Revision{}, fmt.Errorf("failed to generate revision: %w", err)
+	}
+	if id.value <= prev.id.value {
+		if prev.id.value == ^uint64(0) {
+			return Revision{}, fmt.Errorf("revision %s has no successor", prev)
+		}
+		id = Nano64{value: prev.id.value + 1}
+	}
+	return Revision{id: id}, nil
+}
+
+// ID returns the revision as an ID.
+func (r Revision) ID() Nano64 {
+	return r.id
+}
+
+// IsZero reports whether r is the zero Revision.
+func (r Revision) IsZero() bool {
+	return r.id.IsNil()
+}
+
+// IsNewerThan reports whether r was stamped after other.
+func (r Revision) IsNewerThan(other Revision) bool {
+	return r.id.value > other.id.value
+}
+
+// Time returns the time the revision was stamped.
+func (r Revision) Time() time.Time {
+	return r.id.ToDate()
+}
+
+// String returns the revision as dashed hex.
+func (r Revision) String() string {
+	return r.id.ToHex()
+}
+
+// CompareRevisions orders revisions by age.
+// Returns -1 if a < b, 0 if a == b, 1 if a > b.
+func CompareRevisions(a, b Revision) int {
+	return Compare(a.id, b.id)
+}
+
+// CheckRevision returns an error wrapping ErrRevisionConflict unless current,
+// the revision stored, equals expected, the revision the writer read. Use it
+// where the store cannot make the write conditional itself.
+func CheckRevision(expected, current Revision) error {
+	if expected != current {
+		return fmt.Errorf("%w: expected %s, found %s", ErrRevisionConflict, expected, current)
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r Revision) MarshalJSON() ([]byte, error) {
+	return r.id.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *Revision) UnmarshalJSON(data []byte) error {
+	return r.id.UnmarshalJSON(data)
+}
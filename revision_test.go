@@ -0,0 +1,63 @@
+package nano64
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNextRevision(t *testing.T) {
+	const now = 1_700_000_000_000
+	g, err := NewGenerator(WithClock(func() int64 { return now }))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	var r Revision
+	if !r.IsZero() {
+		t.Error("zero Revision is not IsZero")
+	}
+	for range 5 {
+		next, err := g.NextRevision(r)
+		if err != nil {
+			t.Fatalf("NextRevision() error = %v", err)
+		}
+		if !next.IsNewerThan(r) || r.IsNewerThan(next) || CompareRevisions(r, next) != -1 {
+			t.Errorf("NextRevision(%s) = %s is not newer", r, next)
+		}
+		r = next
+	}
+
+	// A revision stamped by a host whose clock runs ahead still gets a successor.
+	ahead := RevisionOf(mustGenerate(t, now+60_000, 5))
+	next, err := g.NextRevision(ahead)
+	if err != nil || !next.IsNewerThan(ahead) {
+		t.Errorf("NextRevision(%s) = %s, %v, want newer", ahead, next, err)
+	}
+	if _, err := g.NextRevision(RevisionOf(Nano64{value: ^uint64(0)})); err == nil {
+		t.Error("NextRevision() of the largest revision succeeded")
+	}
+}
+
+func TestRevisionEncoding(t *testing.T) {
+	r := RevisionOf(mustGenerate(t, 1_700_000_000_000, 42))
+
+	data, err := json.Marshal(struct{ Revision Revision }{r})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `{"Revision":"` + r.String() + `"}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+	var decoded struct{ Revision Revision }
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Revision != r {
+		t.Errorf("Unmarshal() = %s, %v, want %s", decoded.Revision, err, r)
+	}
+
+	if err := CheckRevision(r, r); err != nil {
+		t.Errorf("CheckRevision() of equal revisions error = %v", err)
+	}
+	if err := CheckRevision(r, Revision{}); !errors.Is(err, ErrRevisionConflict) {
+		t.Errorf("CheckRevision() error = %v, want ErrRevisionConflict", err)
+	}
+}
@@ -0,0 +1,173 @@
+package nano64
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// defaultRingReplicas is the number of virtual points per node when NewRing is given zero.
+const defaultRingReplicas = 128
+
+// Node identifies a member of a Ring.
+type Node string
+
+// ringPoint is one virtual node position on the hash circle.
+type ringPoint struct {
+	hash uint64
+	node Node
+}
+
+// Ring is an immutable consistent-hash ring that routes IDs to nodes. IDs are
+// scrambled before lookup, so time-clustered IDs still spread evenly. Adding or
+// removing a node returns a new Ring and only moves the IDs that node gains or loses.
+// A Ring is safe for concurrent use.
+type Ring struct {
+	replicas int
+	nodes    []Node
+	points   []ringPoint
+}
+
+// NewRing builds a ring with the given number of virtual points per node (128 if zero).
+func NewRing(replicas int, nodes ...Node) *Ring {
+	if replicas <= 0 {
+		replicas = defaultRingReplicas
+	}
+	r := &Ring{replicas: replicas}
+	r.build(nodes)
+	return r
+}
+
+// build places the virtual points of every distinct node on the circle.
+func (r *Ring) build(nodes []Node) {
+	seen := make(map[Node]bool, len(nodes))
+	for _, n := range nodes {
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		r.nodes = append(r.nodes, n)
+
+		h := fnv.New64a()
+		h.Write([]byte(n))
+		base := h.Sum64()
+		for i := 0; i < r.replicas; i++ {
+			r.points = append(r.points, ringPoint{hash: mix64(base ^ mix64(uint64(i))), node: n})
+		}
+	}
+	sort.Slice(r.nodes, func(i, j int) bool { return r.nodes[i] < r.nodes[j] })
+	sort.Slice(r.points, func(i, j int) bool {
+		if r.points[i].hash != r.points[j].hash {
+			return r.points[i].hash < r.points[j].hash
+		}
+		return r.points[i].node < r.points[j].node
+	})
+}
+
+// Nodes returns the ring members in sorted order.
+func (r *Ring) Nodes() []Node {
+	return append([]Node(nil), r.nodes...)
+}
+
+// With returns a new ring that additionally contains nodes.
+func (r *Ring) With(nodes ...Node) *Ring {
+	return NewRing(r.replicas, append(r.Nodes(), nodes...)...)
+}
+
+// Without returns a new ring without nodes.
+func (r *Ring) Without(nodes ...Node) *Ring {
+	drop := make(map[Node]bool, len(nodes))
+	for _, n := range nodes {
+		drop[n] = true
+	}
+	var kept []Node
+	for _, n := range r.nodes {
+		if !drop[n] {
+			kept = append(kept, n)
+		}
+	}
+	return NewRing(r.replicas, kept...)
+}
+
+// ringKey is the position of an ID on the hash circle.
+func ringKey(id Nano64) uint64 {
+	return mix64(id.value)
+}
+
+// ownerAt returns the node owning a position on the circle.
+func (r *Ring) ownerAt(key uint64) Node {
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= key })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].node
+}
+
+// Owner returns the node responsible for id, or "" if the ring is empty.
+func (r *Ring) Owner(id Nano64) Node {
+	if len(r.points) == 0 {
+		return ""
+	}
+	return r.ownerAt(ringKey(id))
+}
+
+// Transfer is an arc of the hash circle whose owner differs between two rings.
+// The arc covers positions in (After, Through], wrapping around zero when After >= Through.
+type Transfer struct {
+	After   uint64
+	Through uint64
+	From    Node
+	To      Node
+}
+
+// Contains reports whether id falls into the transferred arc.
+func (t Transfer) Contains(id Nano64) bool {
+	key := ringKey(id)
+	if t.After < t.Through {
+		return key > t.After && key <= t.Through
+	}
+	return key > t.After || key <= t.Through
+}
+
+// Transfers lists the arcs that change owner when moving from one ring to another,
+// so a rebalancer can stream exactly the affected IDs to their new nodes.
+func Transfers(from, to *Ring) []Transfer {
+	if len(from.points) == 0 || len(to.points) == 0 {
+		return nil
+	}
+
+	// Every boundary of either ring starts a segment with a single owner in both.
+	bounds := make([]uint64, 0, len(from.points)+len(to.points))
+	for _, p := range from.points {
+		bounds = append(bounds, p.hash)
+	}
+	for _, p := range to.points {
+		bounds = append(bounds, p.hash)
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	var transfers []Transfer
+	for i, through := range bounds {
+		after := bounds[(i+len(bounds)-1)%len(bounds)]
+		if after == through && len(bounds) > 1 {
+			continue
+		}
+		src, dst := from.ownerAt(through), to.ownerAt(through)
+		if src == dst {
+			continue
+		}
+		if n := len(transfers); n > 0 && transfers[n-1].Through == after &&
+			transfers[n-1].From == src && transfers[n-1].To == dst {
+			transfers[n-1].Through = through
+			continue
+		}
+		transfers = append(transfers, Transfer{After: after, Through: through, From: src, To: dst})
+	}
+	return transfers
+}
+
+// String returns a short description for debugging.
+func (t Transfer) String() string {
+	return string(t.From) + "->" + string(t.To) + " (" +
+		strconv.FormatUint(t.After, 16) + "," + strconv.FormatUint(t.Through, 16) + "]"
+}
@@ -0,0 +1,61 @@
+package nano64
+
+import "testing"
+
+func TestRing_Owner(t *testing.T) {
+	ring := NewRing(0, "a", "b", "c")
+
+	counts := make(map[Node]int)
+	for i := uint64(0); i < 30000; i++ {
+		// Sequential IDs from a handful of milliseconds must still spread out.
+		counts[ring.Owner(New(1_700_000_000_000<<RandomBits+i))]++
+	}
+	for _, n := range ring.Nodes() {
+		if counts[n] < 7000 {
+			t.Errorf("node %s owns only %d of 30000 IDs", n, counts[n])
+		}
+	}
+
+	if NewRing(0).Owner(New(1)) != "" {
+		t.Error("empty ring should have no owner")
+	}
+}
+
+func TestRing_Transfers(t *testing.T) {
+	before := NewRing(16, "a", "b", "c")
+	after := before.With("d")
+
+	transfers := Transfers(before, after)
+	if len(transfers) == 0 {
+		t.Fatal("Transfers() returned nothing after adding a node")
+	}
+
+	for i := uint64(0); i < 5000; i++ {
+		id := New(i * 0x9E3779B97F4A7C15)
+		src, dst := before.Owner(id), after.Owner(id)
+
+		var covering []Transfer
+		for _, tr := range transfers {
+			if tr.Contains(id) {
+				covering = append(covering, tr)
+			}
+		}
+
+		if src == dst {
+			if len(covering) != 0 {
+				t.Fatalf("ID %s stays on %s but is covered by %v", id.ToHex(), src, covering)
+			}
+			continue
+		}
+		if dst != "d" {
+			t.Fatalf("ID %s moved from %s to %s; only moves to d expected", id.ToHex(), src, dst)
+		}
+		if len(covering) != 1 || covering[0].From != src || covering[0].To != dst {
+			t.Fatalf("ID %s moved %s->%s but transfers cover it with %v", id.ToHex(), src, dst, covering)
+		}
+	}
+
+	if got := Transfers(after, after.Without("d").With("d")); len(got) != 0 {
+		t.Errorf("identical rings should not transfer anything: %v", got)
+	}
+}
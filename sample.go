@@ -0,0 +1,117 @@
+package nano64
+
+import (
+	"container/heap"
+	"fmt"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// SampleMode selects how Sample weighs IDs.
+type SampleMode int
+
+const (
+	// SampleUniform gives every ID the same chance of being kept.
+	SampleUniform SampleMode = iota
+	// SampleRecency favours IDs created recently; an ID's weight halves every HalfLife of age.
+	SampleRecency
+)
+
+// SamplePolicy configures Sample.
+type SamplePolicy struct {
+	Mode SampleMode
+
+	// Size is the number of IDs to keep.
+	Size int
+
+	// Since and Until restrict the sample to IDs created in [Since, Until).
+	// Zero values leave that side open.
+	Since time.Time
+	Until time.Time
+
+	// HalfLife is the age at which an ID's weight halves under SampleRecency.
+	HalfLife time.Duration
+
+	// Now is the reference instant for ages; DefaultClock if zero.
+	Now time.Time
+
+	// Seed makes the sample reproducible; zero draws a random seed.
+	Seed uint64
+}
+
+// Sample draws a weighted random sample without replacement from a stream of IDs
+// of unknown length in a single pass, keeping only Size IDs in memory
+// (Efraimidis–Spirakis reservoir sampling). The result is in no particular order.
+func Sample(ids iter.Seq[Nano64], policy SamplePolicy) ([]Nano64, error) {
+	if policy.Size <= 0 {
+		return nil, fmt.Errorf("sample size must be positive, got %d", policy.Size)
+	}
+	if policy.Mode == SampleRecency && policy.HalfLife <= 0 {
+		return nil, fmt.Errorf("recency sampling requires a positive half-life")
+	}
+	if policy.Mode != SampleUniform && policy.Mode != SampleRecency {
+		return nil, fmt.Errorf("unknown sample mode %d", int(policy.Mode))
+	}
+
+	now := policy.Now.UnixMilli()
+	if policy.Now.IsZero() {
+		now = DefaultClock()
+	}
+	seed := policy.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rnd := rand.New(rand.NewPCG(seed, seed^0x9E3779B97F4A7C15))
+
+	reservoir := make(sampleHeap, 0, policy.Size)
+	for id := range ids {
+		ts := id.GetTimestamp()
+		if (!policy.Since.IsZero() && ts < policy.Since.UnixMilli()) ||
+			(!policy.Until.IsZero() && ts >= policy.Until.UnixMilli()) {
+			continue
+		}
+
+		// Key u^(1/w) in log space: log(u)/w; the largest keys win.
+		key := math.Log(1 - rnd.Float64())
+		if policy.Mode == SampleRecency {
+			age := float64(max(now-ts, 0))
+			key *= math.Exp2(age / float64(policy.HalfLife.Milliseconds()))
+		}
+
+		if len(reservoir) < policy.Size {
+			heap.Push(&reservoir, sampleItem{id: id, key: key})
+		} else if key > reservoir[0].key {
+			reservoir[0] = sampleItem{id: id, key: key}
+			heap.Fix(&reservoir, 0)
+		}
+	}
+
+	result := make([]Nano64, len(reservoir))
+	for i, item := range reservoir {
+		result[i] = item.id
+	}
+	return result, nil
+}
+
+// sampleItem is a reservoir entry.
+type sampleItem struct {
+	id  Nano64
+	key float64
+}
+
+// sampleHeap is a min-heap of reservoir entries by key, so the entry to replace
+// is at the root.
+type sampleHeap []sampleItem
+
+func (h sampleHeap) Len() int           { return len(h) }
+func (h sampleHeap) Less(i, j int) bool { return h[i].key < h[j].key }
+func (h sampleHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *sampleHeap) Push(x any)        { *h = append(*h, x.(sampleItem)) }
+func (h *sampleHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,81 @@
+package nano64
+
+import (
+	"iter"
+	"slices"
+	"testing"
+	"time"
+)
+
+// sampleStream yields one ID per minute over the given number of minutes, ending at end.
+func sampleStream(end time.Time, minutes int) iter.Seq[Nano64] {
+	return func(yield func(Nano64) bool) {
+		for i := minutes - 1; i >= 0; i-- {
+			ts := end.Add(-time.Duration(i) * time.Minute).UnixMilli()
+			if !yield(New(uint64(ts) << RandomBits)) {
+				return
+			}
+		}
+	}
+}
+
+func TestSample_Uniform(t *testing.T) {
+	end := time.UnixMilli(1_700_000_000_000)
+	got, err := Sample(sampleStream(end, 1000), SamplePolicy{Size: 50, Seed: 1})
+	if err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if len(got) != 50 {
+		t.Fatalf("got %d IDs, want 50", len(got))
+	}
+
+	again, _ := Sample(sampleStream(end, 1000), SamplePolicy{Size: 50, Seed: 1})
+	slices.SortFunc(got, Compare)
+	slices.SortFunc(again, Compare)
+	if !slices.Equal(got, again) {
+		t.Error("same seed produced different samples")
+	}
+}
+
+func TestSample_RecencyAndWindow(t *testing.T) {
+	end := time.UnixMilli(1_700_000_000_000)
+	policy := SamplePolicy{
+		Mode:     SampleRecency,
+		Size:     100,
+		HalfLife: 10 * time.Minute,
+		Now:      end,
+		Seed:     7,
+	}
+
+	got, err := Sample(sampleStream(end, 2000), policy)
+	if err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	recent := 0
+	for _, id := range got {
+		if end.Sub(id.ToDate()) < 100*time.Minute {
+			recent++
+		}
+	}
+	if recent < 90 {
+		t.Errorf("only %d of %d sampled IDs are from the last 100 minutes", recent, len(got))
+	}
+
+	since := end.Add(-30 * time.Minute)
+	windowed, err := Sample(sampleStream(end, 2000), SamplePolicy{Size: 100, Since: since, Seed: 3})
+	if err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if len(windowed) != 31 {
+		t.Errorf("got %d IDs in window, want 31", len(windowed))
+	}
+}
+
+func TestSample_Errors(t *testing.T) {
+	if _, err := Sample(sampleStream(time.Now(), 1), SamplePolicy{}); err == nil {
+		t.Error("Sample() expected error for zero size")
+	}
+	if _, err := Sample(sampleStream(time.Now(), 1), SamplePolicy{Size: 1, Mode: SampleRecency}); err == nil {
+		t.Error("Sample() expected error for missing half-life")
+	}
+}
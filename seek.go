@@ -0,0 +1,58 @@
+package nano64
+
+import "fmt"
+
+// SeekDirection selects the query SeekSQL builds.
+type SeekDirection int
+
+const (
+	// SeekAtOrAfter finds the first ID >= the argument.
+	SeekAtOrAfter SeekDirection = iota
+	// SeekAtOrBefore finds the last ID <= the argument.
+	SeekAtOrBefore
+)
+
+// String returns a short name for the direction.
+func (s SeekDirection) String() string {
+	switch s {
+	case SeekAtOrAfter:
+		return "at-or-after"
+	case SeekAtOrBefore:
+		return "at-or-before"
+	default:
+		return fmt.Sprintf("SeekDirection(%d)", int(s))
+	}
+}
+
+// SeekSQL returns a query selecting the first ID at or after, or the last ID at
+// or before, its single bind parameter, e.g. for the first row created after a
+// point in time:
+//
+//	query, _ := nano64.DialectPostgres.SeekSQL("events", "id", nano64.RepresentationBytes, nano64.SeekAtOrAfter)
+//	start := nano64.RangeForTime(t, t.Add(time.Millisecond)).First
+//	row := db.QueryRowContext(ctx, query, nano64.ColumnOf(&start, nano64.RepresentationBytes))
+//
+// The column is compared bare against the bound value, in the same representation,
+// and sorted in the comparison's direction, so the query is a single index seek.
+// Wrapping the column in a function or cast instead, as in hex(id) >= '...' or
+// CAST(id AS ...), defeats the index and scans the whole table.
+func (d Dialect) SeekSQL(table, column string, repr Representation, dir SeekDirection) (string, error) {
+	if err := d.validate(); err != nil {
+		return "", err
+	}
+	if repr < RepresentationBytes || repr > RepresentationHex {
+		return "", fmt.Errorf("unsupported representation %v", repr)
+	}
+	col := d.QuoteIdent(column)
+	var op, order string
+	switch dir {
+	case SeekAtOrAfter:
+		op, order = ">=", "ASC"
+	case SeekAtOrBefore:
+		op, order = "<=", "DESC"
+	default:
+		return "", fmt.Errorf("unknown seek direction %v", dir)
+	}
+	return fmt.Sprintf("SELECT %s FROM %s WHERE %s %s %s ORDER BY %s %s LIMIT 1",
+		col, d.QuoteIdent(table), col, op, d.Placeholder(1), col, order), nil
+}
@@ -0,0 +1,94 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestSeekSQL(t *testing.T) {
+	golden := []struct {
+		dialect Dialect
+		dir     SeekDirection
+		want    string
+	}{
+		{DialectPostgres, SeekAtOrAfter, `SELECT "id" FROM "public"."events" WHERE "id" >= $1 ORDER BY "id" ASC LIMIT 1`},
+		{DialectMySQL, SeekAtOrBefore, "SELECT `id` FROM `public`.`events` WHERE `id` <= ? ORDER BY `id` DESC LIMIT 1"},
+	}
+	for _, tt := range golden {
+		got, err := tt.dialect.SeekSQL("public.events", "id", RepresentationBytes, tt.dir)
+		if err != nil || got != tt.want {
+			t.Errorf("%s SeekSQL(%s) = %q, %v, want %q", tt.dialect, tt.dir, got, err, tt.want)
+		}
+	}
+	if _, err := DialectSQLite.SeekSQL("events", "id", RepresentationBytes, SeekDirection(5)); err == nil {
+		t.Error("SeekSQL() accepted an unknown direction")
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	ids := []Nano64{mustGenerate(t, 1000, 1), mustGenerate(t, 2000, 2), mustGenerate(t, 3000, 3)}
+	for _, repr := range []Representation{RepresentationBytes, RepresentationSigned, RepresentationHex} {
+		table := "events_" + repr.String()
+		if _, err := db.Exec(fmt.Sprintf(`CREATE TABLE %s (id ANY PRIMARY KEY) WITHOUT ROWID`, table)); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+		for i := range ids {
+			if _, err := db.Exec("INSERT INTO "+table+" (id) VALUES (?)", ColumnOf(&ids[i], repr)); err != nil {
+				t.Fatalf("insert failed: %v", err)
+			}
+		}
+
+		tests := []struct {
+			dir   SeekDirection
+			probe Nano64
+			want  Nano64
+			found bool
+		}{
+			{SeekAtOrAfter, mustGenerate(t, 1500, 0), ids[1], true},
+			{SeekAtOrAfter, ids[1], ids[1], true},
+			{SeekAtOrAfter, mustGenerate(t, 3001, 0), Nil, false},
+			{SeekAtOrBefore, mustGenerate(t, 2500, 0), ids[1], true},
+			{SeekAtOrBefore, mustGenerate(t, 999, 0), Nil, false},
+		}
+		for _, tt := range tests {
+			query, err := DialectSQLite.SeekSQL(table, "id", repr, tt.dir)
+			if err != nil {
+				t.Fatalf("SeekSQL() error = %v", err)
+			}
+			var got Nano64
+			err = db.QueryRow(query, ColumnOf(&tt.probe, repr)).Scan(ColumnOf(&got, repr))
+			if found := err == nil; found != tt.found || got != tt.want {
+				t.Errorf("%s %s %s = %s, %v, want %s", repr, tt.dir, tt.probe.ToHex(), got.ToHex(), err, tt.want.ToHex())
+			}
+
+			// The plan is an index search, not a scan.
+			var plan strings.Builder
+			rows, err := db.Query("EXPLAIN QUERY PLAN "+query, ColumnOf(&tt.probe, repr))
+			if err != nil {
+				t.Fatalf("EXPLAIN failed: %v", err)
+			}
+			for rows.Next() {
+				var id, parent, unused int
+				var detail string
+				if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+					t.Fatalf("failed to read plan: %v", err)
+				}
+				plan.WriteString(detail)
+			}
+			rows.Close()
+			if !strings.Contains(plan.String(), "SEARCH") {
+				t.Errorf("%s %s plan = %q, want an index search", repr, tt.dir, plan.String())
+			}
+		}
+	}
+}
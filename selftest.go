@@ -0,0 +1,123 @@
+package nano64
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// selfTestSampleEvery is how often SelfTest times a call; timing every call
+	// would dominate the cost of generation itself.
+	selfTestSampleEvery = 64
+
+	// selfTestMaxRecorded bounds the IDs each worker keeps for collision counting.
+	selfTestMaxRecorded = 1 << 22
+)
+
+// SelfTestReport is the result of SelfTest.
+type SelfTestReport struct {
+	Duration    time.Duration
+	Concurrency int
+
+	// Generated is the number of IDs produced and Rate the throughput in IDs per second.
+	Generated int64
+	Rate      float64
+	// Errors counts failed generations, i.e. entropy failures.
+	Errors int64
+
+	// PeakPerMillisecond is the most IDs produced in one millisecond.
+	PeakPerMillisecond int
+	// Collisions counts IDs equal to an earlier one. Each worker records at most
+	// 4M IDs, so very long runs count collisions over that sample.
+	Collisions int
+
+	// MeanLatency, P99Latency and MaxLatency describe the time of a single
+	// GenerateDefault call, sampled on every 64th call; they grow with contention
+	// on the shared entropy source.
+	MeanLatency time.Duration
+	P99Latency  time.Duration
+	MaxLatency  time.Duration
+}
+
+// SelfTest generates IDs with GenerateDefault from concurrency goroutines for
+// duration and reports the throughput, collisions and call latency observed, for
+// deployment health checks that export the results to monitoring.
+func SelfTest(duration time.Duration, concurrency int) (SelfTestReport, error) {
+	if duration <= 0 {
+		return SelfTestReport{}, fmt.Errorf("duration must be positive, got %v", duration)
+	}
+	if concurrency < 1 {
+		return SelfTestReport{}, fmt.Errorf("concurrency must be positive, got %d", concurrency)
+	}
+
+	type result struct {
+		ids       []Nano64
+		generated int64
+		errors    int64
+		latencies []time.Duration
+	}
+	results := make([]result, concurrency)
+
+	start := time.Now()
+	deadline := start.Add(duration)
+	var wg sync.WaitGroup
+	for w := range results {
+		wg.Add(1)
+		go func(r *result) {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				sampled := i%selfTestSampleEvery == 0
+				var begin time.Time
+				if sampled {
+					begin = time.Now()
+					if begin.After(deadline) {
+						return
+					}
+				}
+				id, err := GenerateDefault()
+				if sampled {
+					r.latencies = append(r.latencies, time.Since(begin))
+				}
+				if err != nil {
+					r.errors++
+					continue
+				}
+				r.generated++
+				if len(r.ids) < selfTestMaxRecorded {
+					r.ids = append(r.ids, id)
+				}
+			}
+		}(&results[w])
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	report := SelfTestReport{Duration: elapsed, Concurrency: concurrency}
+	var ids []Nano64
+	var latencies []time.Duration
+	for _, r := range results {
+		report.Generated += r.generated
+		report.Errors += r.errors
+		ids = append(ids, r.ids...)
+		latencies = append(latencies, r.latencies...)
+	}
+	report.Rate = float64(report.Generated) / elapsed.Seconds()
+
+	stats := Stats(ids)
+	report.PeakPerMillisecond = stats.PeakPerMillisecond
+	report.Collisions = stats.Duplicates
+
+	if len(latencies) > 0 {
+		slices.Sort(latencies)
+		var total time.Duration
+		for _, l := range latencies {
+			total += l
+		}
+		report.MeanLatency = total / time.Duration(len(latencies))
+		report.P99Latency = latencies[percentileIndex(len(latencies), 0.99)]
+		report.MaxLatency = latencies[len(latencies)-1]
+	}
+	return report, nil
+}
@@ -0,0 +1,37 @@
+package nano64
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelfTest(t *testing.T) {
+	report, err := SelfTest(50*time.Millisecond, 4)
+	if err != nil {
+		t.Fatalf("SelfTest() error = %v", err)
+	}
+	if report.Generated == 0 || report.Rate <= 0 {
+		t.Errorf("SelfTest() generated %d IDs at %.0f/s, want some", report.Generated, report.Rate)
+	}
+	if report.Concurrency != 4 || report.Duration < 50*time.Millisecond {
+		t.Errorf("SelfTest() report = %+v", report)
+	}
+	if report.Errors != 0 {
+		t.Errorf("SelfTest() had %d errors", report.Errors)
+	}
+	if report.PeakPerMillisecond < 1 {
+		t.Errorf("PeakPerMillisecond = %d, want at least 1", report.PeakPerMillisecond)
+	}
+	if report.MaxLatency < report.P99Latency || report.MaxLatency < report.MeanLatency || report.MeanLatency <= 0 {
+		t.Errorf("inconsistent latencies: mean %v, p99 %v, max %v", report.MeanLatency, report.P99Latency, report.MaxLatency)
+	}
+}
+
+func TestSelfTestValidation(t *testing.T) {
+	if _, err := SelfTest(0, 1); err == nil {
+		t.Error("SelfTest() expected error for zero duration")
+	}
+	if _, err := SelfTest(time.Millisecond, 0); err == nil {
+		t.Error("SelfTest() expected error for zero concurrency")
+	}
+}
@@ -0,0 +1,163 @@
+package nano64
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrClockAnomaly is returned by GenerateMonotonic when the clock jumped by more
+// than GeneratorSettings.MaxClockJump and Degrade is off.
+var ErrClockAnomaly = errors.New("clock anomaly")
+
+// GeneratorEventKind classifies a GeneratorEvent.
+type GeneratorEventKind int
+
+const (
+	// EventGenerated reports a call to Generate or GenerateMonotonic; Err is set if it failed.
+	EventGenerated GeneratorEventKind = iota
+	// EventRollover reports a monotonic ID moved into the next millisecond
+	// because the random space of the current one was exhausted.
+	EventRollover
+	// EventDegraded reports a monotonic ID issued from the logical counter while
+	// the clock was untrusted. See GeneratorSettings.Degrade.
+	EventDegraded
+	// EventDualWriteAnomaly reports a problem found by a DualWriteVerifier; Err
+	// is the *DualWriteAnomaly and ID the new ID of the pair.
+	EventDualWriteAnomaly
+)
+
+// String returns a short name for the event kind, suitable as a metric label.
+func (k GeneratorEventKind) String() string {
+	switch k {
+	case EventGenerated:
+		return "generated"
+	case EventRollover:
+		return "rollover"
+	case EventDegraded:
+		return "degraded"
+	case EventDualWriteAnomaly:
+		return "dual-write-anomaly"
+	default:
+		return fmt.Sprintf("GeneratorEventKind(%d)", int(k))
+	}
+}
+
+// GeneratorEvent is passed to a MetricsSink.
+type GeneratorEvent struct {
+	Kind GeneratorEventKind
+	// ID is the generated ID; Nil if generation failed.
+	ID Nano64
+	// Monotonic is set for events of GenerateMonotonic.
+	Monotonic bool
+	// Err is the generation error, if any.
+	Err error
+}
+
+// MetricsSink receives generator events. It is called synchronously on the
+// generating goroutine, so it must be cheap (e.g. increment a counter).
+type MetricsSink func(GeneratorEvent)
+
+// GeneratorSettings are the parts of a Generator's configuration that can change
+// while it runs. Reload swaps them atomically; the clock, entropy source, epoch,
+// layout and monotonic state are fixed for the lifetime of a Generator.
+type GeneratorSettings struct {
+	// MaxPerMillisecond caps the IDs issued per millisecond; further calls fail
+	// with an error wrapping ErrRateLimited. Zero means no limit.
+	MaxPerMillisecond int
+
+	// Metrics receives an event for every generation. Nil disables metrics.
+	Metrics MetricsSink
+
+	// OverflowMargin makes generation fail with ErrNearOverflow once the clock
+	// is within this margin of the layout's last timestamp. Zero disables the check.
+	OverflowMargin time.Duration
+
+	// MaxClockJump enables clock anomaly detection in GenerateMonotonic. A clock
+	// reading that differs by more than MaxClockJump from the last trusted reading,
+	// advanced by the time elapsed since on the process's monotonic clock, is
+	// untrusted. Untrusted readings that keep agreeing with each other for ten
+	// seconds of monotonic time are taken as a step of the clock, e.g. by NTP,
+	// and become the new trusted reading. Zero disables detection. Injected
+	// clocks that advance faster than real time (e.g. SimClock) must stay within
+	// MaxClockJump per step.
+	MaxClockJump time.Duration
+
+	// Degrade selects what happens on an untrusted reading. When false,
+	// GenerateMonotonic fails with an error wrapping ErrClockAnomaly. When true,
+	// it keeps issuing IDs from a logical counter continuing from the last issued
+	// ID, moving to the next millisecond only when a millisecond's random space is
+	// exhausted, and reports each with an EventDegraded event; it returns to the
+	// clock once a reading agrees again or the clock is re-anchored. This keeps
+	// writes available and ordered at the cost of IDs whose timestamps lag real
+	// time.
+	Degrade bool
+}
+
+// validate checks the settings.
+func (s *GeneratorSettings) validate() error {
+	if s.MaxPerMillisecond < 0 {
+		return fmt.Errorf("max IDs per millisecond cannot be negative: %d", s.MaxPerMillisecond)
+	}
+	if s.OverflowMargin < 0 {
+		return fmt.Errorf("overflow margin cannot be negative: %v", s.OverflowMargin)
+	}
+	if s.MaxClockJump < 0 {
+		return fmt.Errorf("max clock jump cannot be negative: %v", s.MaxClockJump)
+	}
+	return nil
+}
+
+// emit passes e to the metrics sink, if any.
+func (s *GeneratorSettings) emit(e GeneratorEvent) {
+	if s.Metrics != nil {
+		s.Metrics(e)
+	}
+}
+
+// WithMaxPerMillisecond sets GeneratorSettings.MaxPerMillisecond.
+func WithMaxPerMillisecond(n int) Option {
+	return func(g *Generator) error {
+		g.modifySettings(func(s *GeneratorSettings) { s.MaxPerMillisecond = n })
+		return nil
+	}
+}
+
+// WithMetrics sets GeneratorSettings.Metrics.
+func WithMetrics(sink MetricsSink) Option {
+	return func(g *Generator) error {
+		g.modifySettings(func(s *GeneratorSettings) { s.Metrics = sink })
+		return nil
+	}
+}
+
+// WithClockAnomalyDetection sets GeneratorSettings.MaxClockJump and Degrade.
+func WithClockAnomalyDetection(maxJump time.Duration, degrade bool) Option {
+	return func(g *Generator) error {
+		g.modifySettings(func(s *GeneratorSettings) { s.MaxClockJump, s.Degrade = maxJump, degrade })
+		return nil
+	}
+}
+
+// modifySettings replaces the settings with a modified copy.
+func (g *Generator) modifySettings(modify func(s *GeneratorSettings)) {
+	s := *g.settings.Load()
+	modify(&s)
+	g.settings.Store(&s)
+}
+
+// Settings returns the current settings.
+func (g *Generator) Settings() GeneratorSettings {
+	return *g.settings.Load()
+}
+
+// Reload atomically replaces the settings of a running Generator. Calls already in
+// progress finish with the old settings; monotonic state is kept, so IDs keep
+// increasing across the swap. To change a single field, modify the result of Settings.
+func (g *Generator) Reload(s GeneratorSettings) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	g.settings.Store(&s)
+	return nil
+}
@@ -0,0 +1,241 @@
+package nano64
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGeneratorReload(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	// A 32-bit layout runs out about 50 days past its epoch, so the clock can
+	// near overflow without leaving the present.
+	epoch := now - 1<<31
+	var events []GeneratorEvent
+	g, err := NewGenerator(
+		WithClock(func() int64 { return now }),
+		WithLayout(Layout{TimestampBits: 32}),
+		WithEpoch(time.UnixMilli(epoch)),
+		WithMaxPerMillisecond(2),
+		WithMetrics(func(e GeneratorEvent) { events = append(events, e) }),
+	)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	a, _ := g.GenerateMonotonic()
+	b, _ := g.GenerateMonotonic()
+	if _, err := g.GenerateMonotonic(); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("third ID in one millisecond: error = %v, want ErrRateLimited", err)
+	}
+	if _, err := g.Generate(); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Generate() error = %v, want ErrRateLimited", err)
+	}
+	if len(events) != 4 || events[0].Kind != EventGenerated || !events[0].Monotonic || events[0].ID != a ||
+		events[2].Err == nil || events[3].Monotonic {
+		t.Errorf("events = %+v", events)
+	}
+
+	// Lift the limit and drop the sink; the monotonic sequence continues.
+	settings := g.Settings()
+	settings.MaxPerMillisecond = 0
+	settings.Metrics = nil
+	if err := g.Reload(settings); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	c, err := g.GenerateMonotonic()
+	if err != nil {
+		t.Fatalf("GenerateMonotonic() after Reload error = %v", err)
+	}
+	if Compare(a, b) >= 0 || Compare(b, c) >= 0 {
+		t.Error("monotonic order broke across Reload")
+	}
+	if len(events) != 4 {
+		t.Errorf("removed sink still received %d events", len(events)-4)
+	}
+
+	now = epoch + g.Layout().maxTimestamp() - time.Hour.Milliseconds()
+	if _, err := g.Generate(); err != nil {
+		t.Fatalf("Generate() near overflow without a margin: error = %v", err)
+	}
+	settings.OverflowMargin = 2 * time.Hour
+	if err := g.Reload(settings); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if _, err := g.Generate(); !errors.Is(err, ErrNearOverflow) {
+		t.Errorf("Generate() error = %v, want ErrNearOverflow after tightening the margin", err)
+	}
+
+	if err := g.Reload(GeneratorSettings{MaxPerMillisecond: -1}); err == nil {
+		t.Error("Reload() accepted a negative limit")
+	}
+	if g.Settings().OverflowMargin != settings.OverflowMargin {
+		t.Error("failed Reload() changed the settings")
+	}
+}
+
+func TestGeneratorRolloverEvent(t *testing.T) {
+	var rollovers int
+	g, err := NewGenerator(
+		WithClock(func() int64 { return 1_700_000_000_000 }),
+		WithRNG(func(int) (uint32, error) { return randomMask, nil }),
+		WithMetrics(func(e GeneratorEvent) {
+			if e.Kind == EventRollover {
+				rollovers++
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	for range 2 {
+		if _, err := g.GenerateMonotonic(); err != nil {
+			t.Fatalf("GenerateMonotonic() error = %v", err)
+		}
+	}
+	if rollovers != 1 {
+		t.Errorf("rollover events = %d, want 1", rollovers)
+	}
+}
+
+func TestGeneratorClockAnomaly(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	clock := func() int64 { return now }
+
+	strict, err := NewGenerator(WithClock(clock), WithClockAnomalyDetection(time.Minute, false))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	if _, err := strict.GenerateMonotonic(); err != nil {
+		t.Fatalf("GenerateMonotonic() error = %v", err)
+	}
+	now -= time.Hour.Milliseconds()
+	if _, err := strict.GenerateMonotonic(); !errors.Is(err, ErrClockAnomaly) {
+		t.Errorf("GenerateMonotonic() after a backward jump: error = %v, want ErrClockAnomaly", err)
+	}
+
+	now = 1_700_000_000_000
+	var degradedEvents int
+	g, err := NewGenerator(
+		WithClock(clock),
+		WithClockAnomalyDetection(time.Minute, true),
+		WithMetrics(func(e GeneratorEvent) {
+			if e.Kind == EventDegraded {
+				degradedEvents++
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	steps := []struct {
+		name         string
+		offset       time.Duration
+		wantDegraded bool
+	}{
+		{"trusted", 0, false},
+		{"small step", 10 * time.Millisecond, false},
+		{"backward jump", -time.Hour, true},
+		{"still behind", -time.Hour + time.Millisecond, true},
+		{"recovered", 20 * time.Millisecond, false},
+		{"forward jump", 24 * time.Hour, true},
+		{"jump undone", 30 * time.Millisecond, false},
+	}
+	var prev Nano64
+	for _, step := range steps {
+		now = 1_700_000_000_000 + step.offset.Milliseconds()
+		id, err := g.GenerateMonotonic()
+		if err != nil {
+			t.Fatalf("%s: GenerateMonotonic() error = %v", step.name, err)
+		}
+		if g.Degraded() != step.wantDegraded {
+			t.Errorf("%s: Degraded() = %v, want %v", step.name, g.Degraded(), step.wantDegraded)
+		}
+		if Compare(prev, id) >= 0 {
+			t.Errorf("%s: ID %s does not follow %s", step.name, id, prev)
+		}
+		if step.wantDegraded && g.Timestamp(id) != g.Timestamp(prev) {
+			t.Errorf("%s: degraded ID left the last good millisecond", step.name)
+		}
+		prev = id
+	}
+	if degradedEvents != 3 {
+		t.Errorf("degraded events = %d, want 3", degradedEvents)
+	}
+
+	if err := g.Reload(GeneratorSettings{MaxClockJump: -time.Second}); err == nil {
+		t.Error("Reload() accepted a negative clock jump")
+	}
+}
+
+func TestGeneratorClockReanchor(t *testing.T) {
+	for _, degrade := range []bool{false, true} {
+		now := int64(1_700_000_000_000)
+		g, err := NewGenerator(WithClock(func() int64 { return now }), WithClockAnomalyDetection(time.Minute, degrade))
+		if err != nil {
+			t.Fatalf("NewGenerator() error = %v", err)
+		}
+		g.reanchorAfter = 50 * time.Millisecond
+		if _, err := g.GenerateMonotonic(); err != nil {
+			t.Fatalf("GenerateMonotonic() error = %v", err)
+		}
+
+		// The clock steps an hour ahead and stays there, advancing in real
+		// time, so its readings keep agreeing with each other.
+		stepped := now + time.Hour.Milliseconds()
+		start := time.Now()
+		for i := 0; ; i++ {
+			now = stepped + time.Since(start).Milliseconds()
+			id, err := g.GenerateMonotonic()
+			trusted := err == nil && !g.Degraded()
+			if trusted {
+				if time.Since(start) < g.reanchorAfter {
+					t.Errorf("degrade=%v: step accepted after %v", degrade, time.Since(start))
+				}
+				if g.Timestamp(id) != now {
+					t.Errorf("after re-anchoring: timestamp %d, want %d", g.Timestamp(id), now)
+				}
+				break
+			}
+			if !degrade && !errors.Is(err, ErrClockAnomaly) {
+				t.Fatalf("strict reading %d: error = %v", i, err)
+			}
+			if degrade && err != nil {
+				t.Fatalf("degrading reading %d: error = %v", i, err)
+			}
+			if time.Since(start) > time.Second {
+				t.Fatalf("degrade=%v: the step was never accepted", degrade)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		now += 5
+		if id, err := g.GenerateMonotonic(); err != nil || g.Degraded() || g.Timestamp(id) != now {
+			t.Errorf("degrade=%v: after recovery GenerateMonotonic() = %d, %v; want timestamp %d", degrade, g.Timestamp(id), err, now)
+		}
+	}
+}
+
+func TestGeneratorClockJumpTightLoop(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	g, err := NewGenerator(WithClock(func() int64 { return now }), WithClockAnomalyDetection(time.Minute, true))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	if _, err := g.GenerateMonotonic(); err != nil {
+		t.Fatalf("GenerateMonotonic() error = %v", err)
+	}
+
+	// A bogus jump that persists must not be accepted however many IDs are
+	// generated while it lasts.
+	now += time.Hour.Milliseconds()
+	for i := range 10_000 {
+		id, err := g.GenerateMonotonic()
+		if err != nil || !g.Degraded() {
+			t.Fatalf("ID %d after the jump: Degraded() = %v, error = %v", i, g.Degraded(), err)
+		}
+		if g.Timestamp(id) >= now {
+			t.Fatalf("ID %d after the jump is stamped with the bogus clock", i)
+		}
+	}
+}
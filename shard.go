@@ -0,0 +1,60 @@
+package nano64
+
+import "fmt"
+
+// maxShardBits is the widest shard number EmbedShard can store.
+const maxShardBits = 8
+
+// validateShardBits checks the width of a shard field.
+func validateShardBits(bits int) error {
+	if bits < 1 || bits > maxShardBits {
+		return fmt.Errorf("shard bits must be 1-%d, got %d", maxShardBits, bits)
+	}
+	return nil
+}
+
+// ShardMask returns the bits of the random field EmbedShard uses for a shard number
+// of the given width, for claiming them in a ReservedBits. It returns 0 for an
+// invalid width.
+func ShardMask(bits int) uint64 {
+	if validateShardBits(bits) != nil {
+		return 0
+	}
+	return uint64(1)<<bits - 1
+}
+
+// EmbedShard stores shard in the lowest bits of the random field, so routers can
+// derive the owning shard from the ID alone without a lookup table. The timestamp
+// and the remaining random bits are untouched; every shard bit halves the random
+// space per millisecond and shard.
+func EmbedShard(id Nano64, shard uint8, bits int) (Nano64, error) {
+	if err := validateShardBits(bits); err != nil {
+		return Nano64{}, err
+	}
+	mask := ShardMask(bits)
+	if uint64(shard) > mask {
+		return Nano64{}, fmt.Errorf("shard %d does not fit in %d bits", shard, bits)
+	}
+	return Nano64{value: id.value&^mask | uint64(shard)}, nil
+}
+
+// ExtractShard returns the shard stored by EmbedShard with the same bit width.
+// It returns 0 for an invalid width.
+func ExtractShard(id Nano64, bits int) uint8 {
+	if validateShardBits(bits) != nil {
+		return 0
+	}
+	return uint8(id.value & ShardMask(bits))
+}
+
+// GenerateForShard creates an ID at timestamp with shard embedded in its lowest random bits.
+func GenerateForShard(timestamp int64, shard uint8, bits int, rng RNG) (Nano64, error) {
+	if err := validateShardBits(bits); err != nil {
+		return Nano64{}, err
+	}
+	id, err := Generate(timestamp, rng)
+	if err != nil {
+		return Nano64{}, err
+	}
+	return EmbedShard(id, shard, bits)
+}
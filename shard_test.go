@@ -0,0 +1,37 @@
+package nano64
+
+import "testing"
+
+func TestEmbedShard(t *testing.T) {
+	id := New(0x123456789ABCDEF0)
+
+	for shard := 0; shard < 16; shard++ {
+		embedded, err := EmbedShard(id, uint8(shard), 4)
+		if err != nil {
+			t.Fatalf("EmbedShard() error = %v", err)
+		}
+		if got := ExtractShard(embedded, 4); got != uint8(shard) {
+			t.Errorf("ExtractShard() = %d, want %d", got, shard)
+		}
+		if embedded.Uint64Value()>>4 != id.Uint64Value()>>4 {
+			t.Errorf("EmbedShard() changed bits above the shard field: %s", embedded.ToHex())
+		}
+	}
+
+	if _, err := EmbedShard(id, 16, 4); err == nil {
+		t.Error("EmbedShard() expected error for shard overflow")
+	}
+	if _, err := EmbedShard(id, 1, 9); err == nil {
+		t.Error("EmbedShard() expected error for invalid width")
+	}
+}
+
+func TestGenerateForShard(t *testing.T) {
+	id, err := GenerateForShard(1_700_000_000_000, 5, 3, nil)
+	if err != nil {
+		t.Fatalf("GenerateForShard() error = %v", err)
+	}
+	if ExtractShard(id, 3) != 5 || id.GetTimestamp() != 1_700_000_000_000 {
+		t.Errorf("GenerateForShard() = %s", id.ToHex())
+	}
+}
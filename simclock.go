@@ -0,0 +1,98 @@
+package nano64
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SimClock is a simulated clock for backtests and replays. Pass its Now method
+// wherever a Clock is accepted (PacedOptions, DriftOptions, NewKeyedLimiter,
+// NewEncryptedIDConfig, ...) or feed Now() to Generate and GenerateMonotonic, and
+// generated IDs carry simulated rather than wall-clock timestamps.
+//
+// Time moves either explicitly with Advance and Set, or on its own: readings queued
+// with Script or NewTraceClock are returned by successive Now calls, after which the
+// clock holds at the last reading. A SimClock is safe for concurrent use.
+type SimClock struct {
+	mu      sync.Mutex
+	now     int64
+	pending []int64
+}
+
+// NewSimClock creates a clock reading start (epoch ms) until moved.
+func NewSimClock(start int64) *SimClock {
+	return &SimClock{now: start}
+}
+
+// NewTraceClock creates a clock whose successive Now calls return the timestamps of
+// an event trace (epoch ms), e.g. the IDs' timestamps of a recorded stream.
+// The trace must be non-empty and non-decreasing.
+func NewTraceClock(trace []int64) (*SimClock, error) {
+	if len(trace) == 0 {
+		return nil, fmt.Errorf("trace must not be empty")
+	}
+	for i := 1; i < len(trace); i++ {
+		if trace[i] < trace[i-1] {
+			return nil, fmt.Errorf("trace goes backwards at index %d: %d < %d", i, trace[i], trace[i-1])
+		}
+	}
+
+	pending := make([]int64, len(trace))
+	copy(pending, trace)
+	return &SimClock{now: trace[0], pending: pending}, nil
+}
+
+// Now returns the current simulated time in epoch milliseconds, consuming the next
+// scripted reading if one is queued.
+func (c *SimClock) Now() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.pending) > 0 {
+		c.now = c.pending[0]
+		c.pending = c.pending[1:]
+	}
+	return c.now
+}
+
+// Script queues readings for the next Now calls: each step advances the clock by
+// the given duration from the previous reading. Steps must not be negative; if
+// one is, nothing is queued.
+func (c *SimClock) Script(steps ...time.Duration) error {
+	for i, step := range steps {
+		if step < 0 {
+			return fmt.Errorf("step %d is negative: %v", i, step)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := c.now
+	if n := len(c.pending); n > 0 {
+		last = c.pending[n-1]
+	}
+	for _, step := range steps {
+		last += step.Milliseconds()
+		c.pending = append(c.pending, last)
+	}
+	return nil
+}
+
+// Advance moves the clock forward by d and discards any queued readings.
+func (c *SimClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now += d.Milliseconds()
+	c.pending = nil
+}
+
+// Set moves the clock to ms (epoch ms), forwards or backwards, and discards any
+// queued readings.
+func (c *SimClock) Set(ms int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = ms
+	c.pending = nil
+}
@@ -0,0 +1,74 @@
+package nano64
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSimClockScript(t *testing.T) {
+	c := NewSimClock(1000)
+	if err := c.Script(10*time.Millisecond, 0, 5*time.Millisecond); err != nil {
+		t.Fatalf("Script() error = %v", err)
+	}
+
+	var got []int64
+	for i := 0; i < 5; i++ {
+		got = append(got, c.Now())
+	}
+	if want := []int64{1010, 1010, 1015, 1015, 1015}; !slices.Equal(got, want) {
+		t.Errorf("Now() readings = %v, want %v", got, want)
+	}
+
+	if err := c.Script(-time.Millisecond); err == nil {
+		t.Error("Script() expected error for negative step")
+	}
+	if err := c.Script(time.Millisecond, -time.Millisecond); err == nil {
+		t.Error("Script() expected error for a negative later step")
+	}
+	if got := c.Now(); got != 1015 {
+		t.Errorf("Now() after a rejected Script = %d, want 1015 with nothing queued", got)
+	}
+
+	c.Advance(time.Second)
+	if got := c.Now(); got != 2015 {
+		t.Errorf("Now() after Advance = %d, want 2015", got)
+	}
+	c.Set(500)
+	if got := c.Now(); got != 500 {
+		t.Errorf("Now() after Set = %d, want 500", got)
+	}
+}
+
+func TestTraceClock(t *testing.T) {
+	if _, err := NewTraceClock(nil); err == nil {
+		t.Error("NewTraceClock() expected error for empty trace")
+	}
+	if _, err := NewTraceClock([]int64{5, 4}); err == nil {
+		t.Error("NewTraceClock() expected error for decreasing trace")
+	}
+
+	trace := []int64{1_700_000_000_000, 1_700_000_000_000, 1_700_000_000_250}
+	c, err := NewTraceClock(trace)
+	if err != nil {
+		t.Fatalf("NewTraceClock() error = %v", err)
+	}
+
+	// Generators fed by the clock reproduce the trace's timing.
+	paced, err := NewPacedGenerator(PacedOptions{Clock: c.Now})
+	if err != nil {
+		t.Fatalf("NewPacedGenerator() error = %v", err)
+	}
+	for i, want := range trace {
+		id, err := paced.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if id.GetTimestamp() != want {
+			t.Errorf("ID %d timestamp = %d, want %d", i, id.GetTimestamp(), want)
+		}
+	}
+	if got := c.Now(); got != trace[len(trace)-1] {
+		t.Errorf("Now() after trace = %d, want %d", got, trace[len(trace)-1])
+	}
+}
@@ -0,0 +1,345 @@
+package nano64
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"iter"
+	"sort"
+	"time"
+)
+
+// Snapshot files hold large sorted ID collections. Layout, all integers big-endian:
+//
+//	header   "N64SNAP" version(1)
+//	blocks   count(u32) compressedLen(u32) crc32(u32) reserved(u32) data
+//	index    per block: first(u64) last(u64) offset(u64) count(u32)
+//	footer   indexOffset(u64) blocks(u32) indexCRC(u32) total(u64) "N64SNAPF"
+//
+// A block's data is the DEFLATE-compressed first ID followed by uvarint deltas
+// between consecutive IDs, which for time-sorted IDs are small. The index lets a
+// reader seek to a time or ID while decoding only the blocks it needs.
+
+const (
+	snapshotMagic       = "N64SNAP"
+	snapshotFooterMagic = "N64SNAPF"
+	snapshotVersion     = 1
+
+	snapshotHeaderLength      = 8
+	snapshotBlockHeaderLength = 16
+	snapshotIndexEntryLength  = 28
+	snapshotFooterLength      = 32
+
+	// DefaultSnapshotBlockSize is the number of IDs per block unless configured.
+	DefaultSnapshotBlockSize = 4096
+)
+
+// ErrSnapshotCorrupt is returned when a snapshot file fails validation.
+var ErrSnapshotCorrupt = errors.New("corrupt snapshot")
+
+// snapshotBlock is an index entry.
+type snapshotBlock struct {
+	first, last Nano64
+	offset      uint64
+	count       uint32
+}
+
+// SnapshotOptions configures a SnapshotWriter.
+type SnapshotOptions struct {
+	// BlockSize is the number of IDs per block; DefaultSnapshotBlockSize if zero.
+	// Smaller blocks make seeks cheaper and compression worse.
+	BlockSize int
+
+	// Level is the DEFLATE compression level; flate.DefaultCompression if zero.
+	Level int
+}
+
+// SnapshotWriter writes a snapshot file. IDs must be written in non-decreasing order.
+type SnapshotWriter struct {
+	w         *bufio.Writer
+	blockSize int
+	level     int
+
+	offset  uint64
+	pending []Nano64
+	index   []snapshotBlock
+	total   uint64
+	last    Nano64
+	closed  bool
+	scratch bytes.Buffer
+}
+
+// NewSnapshotWriter writes the file header to w and returns a writer.
+func NewSnapshotWriter(w io.Writer, opts SnapshotOptions) (*SnapshotWriter, error) {
+	blockSize := opts.BlockSize
+	if blockSize == 0 {
+		blockSize = DefaultSnapshotBlockSize
+	}
+	if blockSize < 1 {
+		return nil, fmt.Errorf("block size must be positive, got %d", blockSize)
+	}
+	level := opts.Level
+	if level == 0 {
+		level = flate.DefaultCompression
+	}
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return nil, fmt.Errorf("invalid compression level %d", level)
+	}
+
+	sw := &SnapshotWriter{w: bufio.NewWriter(w), blockSize: blockSize, level: level}
+	header := append([]byte(snapshotMagic), snapshotVersion)
+	if _, err := sw.w.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot header: %w", err)
+	}
+	sw.offset = snapshotHeaderLength
+	return sw, nil
+}
+
+// Write appends id. It fails if id is less than the previously written ID.
+func (sw *SnapshotWriter) Write(id Nano64) error {
+	if sw.closed {
+		return fmt.Errorf("snapshot writer is closed")
+	}
+	if sw.total > 0 && id.value < sw.last.value {
+		return fmt.Errorf("IDs must be written in order: %s after %s", id.ToHex(), sw.last.ToHex())
+	}
+	sw.pending = append(sw.pending, id)
+	sw.last = id
+	sw.total++
+	if len(sw.pending) == sw.blockSize {
+		return sw.flushBlock()
+	}
+	return nil
+}
+
+// flushBlock compresses and writes the pending IDs as one block.
+func (sw *SnapshotWriter) flushBlock() error {
+	if len(sw.pending) == 0 {
+		return nil
+	}
+
+	raw := make([]byte, 0, 8+len(sw.pending)*3)
+	raw = binary.BigEndian.AppendUint64(raw, sw.pending[0].value)
+	for i := 1; i < len(sw.pending); i++ {
+		raw = binary.AppendUvarint(raw, sw.pending[i].value-sw.pending[i-1].value)
+	}
+
+	sw.scratch.Reset()
+	fw, err := flate.NewWriter(&sw.scratch, sw.level)
+	if err != nil {
+		return fmt.Errorf("failed to create compressor: %w", err)
+	}
+	if _, err := fw.Write(raw); err != nil {
+		return fmt.Errorf("failed to compress block: %w", err)
+	}
+	if err := fw.Close(); err != nil {
+		return fmt.Errorf("failed to compress block: %w", err)
+	}
+	data := sw.scratch.Bytes()
+
+	header := make([]byte, 0, snapshotBlockHeaderLength)
+	header = binary.BigEndian.AppendUint32(header, uint32(len(sw.pending)))
+	header = binary.BigEndian.AppendUint32(header, uint32(len(data)))
+	header = binary.BigEndian.AppendUint32(header, crc32.ChecksumIEEE(data))
+	header = binary.BigEndian.AppendUint32(header, 0)
+	if _, err := sw.w.Write(header); err != nil {
+		return fmt.Errorf("failed to write block: %w", err)
+	}
+	if _, err := sw.w.Write(data); err != nil {
+		return fmt.Errorf("failed to write block: %w", err)
+	}
+
+	sw.index = append(sw.index, snapshotBlock{
+		first:  sw.pending[0],
+		last:   sw.pending[len(sw.pending)-1],
+		offset: sw.offset,
+		count:  uint32(len(sw.pending)),
+	})
+	sw.offset += uint64(snapshotBlockHeaderLength + len(data))
+	sw.pending = sw.pending[:0]
+	return nil
+}
+
+// Close writes the remaining IDs, the index and the footer. It does not close the
+// underlying writer.
+func (sw *SnapshotWriter) Close() error {
+	if sw.closed {
+		return nil
+	}
+	sw.closed = true
+	if err := sw.flushBlock(); err != nil {
+		return err
+	}
+
+	index := make([]byte, 0, len(sw.index)*snapshotIndexEntryLength)
+	for _, b := range sw.index {
+		index = binary.BigEndian.AppendUint64(index, b.first.value)
+		index = binary.BigEndian.AppendUint64(index, b.last.value)
+		index = binary.BigEndian.AppendUint64(index, b.offset)
+		index = binary.BigEndian.AppendUint32(index, b.count)
+	}
+
+	footer := make([]byte, 0, snapshotFooterLength)
+	footer = binary.BigEndian.AppendUint64(footer, sw.offset)
+	footer = binary.BigEndian.AppendUint32(footer, uint32(len(sw.index)))
+	footer = binary.BigEndian.AppendUint32(footer, crc32.ChecksumIEEE(index))
+	footer = binary.BigEndian.AppendUint64(footer, sw.total)
+	footer = append(footer, snapshotFooterMagic...)
+
+	if _, err := sw.w.Write(index); err != nil {
+		return fmt.Errorf("failed to write snapshot index: %w", err)
+	}
+	if _, err := sw.w.Write(footer); err != nil {
+		return fmt.Errorf("failed to write snapshot footer: %w", err)
+	}
+	if err := sw.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// SnapshotReader reads a snapshot file through random access.
+// It is safe for concurrent use if r is.
+type SnapshotReader struct {
+	r     io.ReaderAt
+	index []snapshotBlock
+	total uint64
+}
+
+// OpenSnapshot validates the header, footer and index of the size-byte snapshot in r.
+func OpenSnapshot(r io.ReaderAt, size int64) (*SnapshotReader, error) {
+	if size < snapshotHeaderLength+snapshotFooterLength {
+		return nil, fmt.Errorf("%w: file too small", ErrSnapshotCorrupt)
+	}
+
+	header := make([]byte, snapshotHeaderLength)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot header: %w", err)
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, fmt.Errorf("%w: bad magic", ErrSnapshotCorrupt)
+	}
+	if header[len(snapshotMagic)] != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", header[len(snapshotMagic)])
+	}
+
+	footer := make([]byte, snapshotFooterLength)
+	if _, err := r.ReadAt(footer, size-snapshotFooterLength); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot footer: %w", err)
+	}
+	if string(footer[24:]) != snapshotFooterMagic {
+		return nil, fmt.Errorf("%w: bad footer", ErrSnapshotCorrupt)
+	}
+	indexOffset := binary.BigEndian.Uint64(footer[0:8])
+	blocks := binary.BigEndian.Uint32(footer[8:12])
+	indexCRC := binary.BigEndian.Uint32(footer[12:16])
+	total := binary.BigEndian.Uint64(footer[16:24])
+
+	indexLength := uint64(blocks) * snapshotIndexEntryLength
+	if indexOffset+indexLength != uint64(size-snapshotFooterLength) {
+		return nil, fmt.Errorf("%w: index does not end at footer", ErrSnapshotCorrupt)
+	}
+	raw := make([]byte, indexLength)
+	if _, err := r.ReadAt(raw, int64(indexOffset)); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot index: %w", err)
+	}
+	if crc32.ChecksumIEEE(raw) != indexCRC {
+		return nil, fmt.Errorf("%w: index checksum mismatch", ErrSnapshotCorrupt)
+	}
+
+	index := make([]snapshotBlock, blocks)
+	for i := range index {
+		e := raw[i*snapshotIndexEntryLength:]
+		index[i] = snapshotBlock{
+			first:  Nano64{value: binary.BigEndian.Uint64(e[0:8])},
+			last:   Nano64{value: binary.BigEndian.Uint64(e[8:16])},
+			offset: binary.BigEndian.Uint64(e[16:24]),
+			count:  binary.BigEndian.Uint32(e[24:28]),
+		}
+	}
+	return &SnapshotReader{r: r, index: index, total: total}, nil
+}
+
+// Len returns the number of IDs in the snapshot.
+func (sr *SnapshotReader) Len() int {
+	return int(sr.total)
+}
+
+// All iterates over every ID in order. Iteration stops after yielding an error.
+func (sr *SnapshotReader) All() iter.Seq2[Nano64, error] {
+	return sr.From(Nil)
+}
+
+// SeekTime iterates over the IDs created at or after t, in order.
+func (sr *SnapshotReader) SeekTime(t time.Time) iter.Seq2[Nano64, error] {
+	ms := max(t.UnixMilli(), 0)
+	return sr.From(Nano64{value: uint64(ms) << timestampShift})
+}
+
+// From iterates over the IDs greater than or equal to start, in order, decoding
+// only the blocks that may contain them.
+func (sr *SnapshotReader) From(start Nano64) iter.Seq2[Nano64, error] {
+	return func(yield func(Nano64, error) bool) {
+		first := sort.Search(len(sr.index), func(i int) bool { return sr.index[i].last.value >= start.value })
+		for _, block := range sr.index[first:] {
+			ids, err := sr.readBlock(block)
+			if err != nil {
+				yield(Nil, err)
+				return
+			}
+			for _, id := range ids {
+				if id.value >= start.value && !yield(id, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// readBlock reads, verifies and decodes one block.
+func (sr *SnapshotReader) readBlock(b snapshotBlock) ([]Nano64, error) {
+	header := make([]byte, snapshotBlockHeaderLength)
+	if _, err := sr.r.ReadAt(header, int64(b.offset)); err != nil {
+		return nil, fmt.Errorf("failed to read block at %d: %w", b.offset, err)
+	}
+	count := binary.BigEndian.Uint32(header[0:4])
+	if count != b.count {
+		return nil, fmt.Errorf("%w: block at %d disagrees with index", ErrSnapshotCorrupt, b.offset)
+	}
+
+	data := make([]byte, binary.BigEndian.Uint32(header[4:8]))
+	if _, err := sr.r.ReadAt(data, int64(b.offset)+snapshotBlockHeaderLength); err != nil {
+		return nil, fmt.Errorf("failed to read block at %d: %w", b.offset, err)
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[8:12]) {
+		return nil, fmt.Errorf("%w: block at %d checksum mismatch", ErrSnapshotCorrupt, b.offset)
+	}
+
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: block at %d: %v", ErrSnapshotCorrupt, b.offset, err)
+	}
+	if len(raw) < 8 {
+		return nil, fmt.Errorf("%w: block at %d is truncated", ErrSnapshotCorrupt, b.offset)
+	}
+
+	ids := make([]Nano64, 0, count)
+	value := binary.BigEndian.Uint64(raw)
+	ids = append(ids, Nano64{value: value})
+	raw = raw[8:]
+	for uint32(len(ids)) < count {
+		delta, n := binary.Uvarint(raw)
+		if n <= 0 {
+			return nil, fmt.Errorf("%w: block at %d is truncated", ErrSnapshotCorrupt, b.offset)
+		}
+		raw = raw[n:]
+		value += delta
+		ids = append(ids, Nano64{value: value})
+	}
+	return ids, nil
+}
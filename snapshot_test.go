@@ -0,0 +1,121 @@
+package nano64
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func writeSnapshot(t *testing.T, ids []Nano64, opts SnapshotOptions) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewSnapshotWriter(&buf, opts)
+	if err != nil {
+		t.Fatalf("NewSnapshotWriter() error = %v", err)
+	}
+	for _, id := range ids {
+		if err := w.Write(id); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func snapshotIDs(t *testing.T, n int) []Nano64 {
+	t.Helper()
+	ids := make([]Nano64, n)
+	for i := range ids {
+		ids[i] = mustGenerate(t, 1_700_000_000_000+int64(i/4), uint32(i*7919)%randomMask)
+	}
+	slices.SortFunc(ids, Compare)
+	return ids
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	ids := snapshotIDs(t, 1000)
+	data := writeSnapshot(t, ids, SnapshotOptions{BlockSize: 64})
+
+	if len(data) >= len(ids)*8 {
+		t.Errorf("snapshot is %d bytes for %d IDs, want compression below 8 bytes per ID", len(data), len(ids))
+	}
+
+	r, err := OpenSnapshot(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenSnapshot() error = %v", err)
+	}
+	if r.Len() != len(ids) {
+		t.Errorf("Len() = %d, want %d", r.Len(), len(ids))
+	}
+
+	i := 0
+	for id, err := range r.All() {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		if id != ids[i] {
+			t.Fatalf("ID %d = %v, want %v", i, id, ids[i])
+		}
+		i++
+	}
+	if i != len(ids) {
+		t.Errorf("All() yielded %d IDs, want %d", i, len(ids))
+	}
+}
+
+func TestSnapshotSeekTime(t *testing.T) {
+	ids := snapshotIDs(t, 1000)
+	data := writeSnapshot(t, ids, SnapshotOptions{BlockSize: 64})
+	r, err := OpenSnapshot(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenSnapshot() error = %v", err)
+	}
+
+	// Timestamps advance every 4 IDs, so time +100ms starts at index 400.
+	var got []Nano64
+	for id, err := range r.SeekTime(time.UnixMilli(1_700_000_000_100)) {
+		if err != nil {
+			t.Fatalf("SeekTime() error = %v", err)
+		}
+		got = append(got, id)
+		if len(got) == 3 {
+			break
+		}
+	}
+	for i, id := range got {
+		if id != ids[400+i] {
+			t.Errorf("SeekTime() ID %d = %v, want %v", i, id, ids[400+i])
+		}
+	}
+}
+
+func TestSnapshotValidation(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewSnapshotWriter(&buf, SnapshotOptions{})
+	w.Write(New(10))
+	if err := w.Write(New(5)); err == nil {
+		t.Error("Write() expected error for out-of-order ID")
+	}
+
+	data := writeSnapshot(t, snapshotIDs(t, 100), SnapshotOptions{BlockSize: 16})
+	corrupt := bytes.Clone(data)
+	corrupt[snapshotHeaderLength+snapshotBlockHeaderLength+2] ^= 0xFF
+
+	r, err := OpenSnapshot(bytes.NewReader(corrupt), int64(len(corrupt)))
+	if err != nil {
+		t.Fatalf("OpenSnapshot() error = %v", err)
+	}
+	for _, err := range r.All() {
+		if err != nil {
+			if !errors.Is(err, ErrSnapshotCorrupt) {
+				t.Errorf("All() error = %v, want ErrSnapshotCorrupt", err)
+			}
+			return
+		}
+	}
+	t.Error("All() did not report the corrupted block")
+}
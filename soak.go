@@ -0,0 +1,303 @@
+package nano64
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// soakShards is the number of independently locked partitions of the exact set.
+	soakShards = 64
+
+	// soakBatch is how many IDs a worker generates between checks for the end of
+	// the run and flushes of its counters.
+	soakBatch = 1024
+
+	// soakFilterHashes is the number of bits set per ID in the filter.
+	soakFilterHashes = 4
+)
+
+// SoakConfig configures Soak.
+type SoakConfig struct {
+	// Duration ends the run; zero runs until the context is done.
+	Duration time.Duration
+
+	// Concurrency is the number of generating goroutines; GOMAXPROCS if zero.
+	Concurrency int
+
+	// Generate produces the IDs under test; GenerateDefault if nil. Point it at a
+	// Generator with a candidate RNG or clock to validate a change before release.
+	Generate func() (Nano64, error)
+
+	// Window is the span of embedded time, trailing the newest timestamp seen,
+	// within which duplicates are detected exactly; 5s if zero. Memory use of the
+	// exact set grows with the generation rate times Window.
+	Window time.Duration
+
+	// FilterBytes is the size of the Bloom filter remembering every ID, which
+	// checks IDs older than Window; 64 MiB if zero.
+	FilterBytes int
+
+	// Interval is how often Snapshot is called; one minute if zero.
+	Interval time.Duration
+
+	// Snapshot, if set, receives the running stats every Interval, e.g. to log
+	// progress of a nightly run.
+	Snapshot func(SoakStats)
+}
+
+// SoakStats are the running totals of a Soak.
+type SoakStats struct {
+	Elapsed   time.Duration
+	Generated int64
+	// Rate is Generated per second of Elapsed.
+	Rate float64
+	// Errors counts failed generations.
+	Errors int64
+
+	// Duplicates counts IDs equal to an earlier ID within Window. These are
+	// certain.
+	Duplicates int64
+	// Late counts IDs older than Window when generated, e.g. after the clock
+	// stepped back, which only the filter checks.
+	Late int64
+	// Suspected counts late IDs the filter reports as seen before. The filter has
+	// false positives, at the rate FilterFalsePositive estimates.
+	Suspected           int64
+	FilterFalsePositive float64
+
+	// Tracked is the number of IDs in the exact set and HeapAlloc the bytes of
+	// allocated heap, to confirm memory stays bounded over a long run.
+	Tracked   int64
+	HeapAlloc uint64
+}
+
+// Soak generates IDs from Concurrency goroutines until ctx is done or Duration
+// elapses, checking every ID for duplicates in bounded memory, and returns the
+// final stats. It returns an error only for an invalid config; callers decide
+// whether Duplicates or Suspected fail the run.
+//
+// IDs within Window of the newest timestamp are checked exactly against a set
+// partitioned by timestamp, so old partitions are dropped as time advances. A
+// duplicate needs an equal timestamp, so this finds every duplicate unless the
+// clock steps back by more than Window; IDs that late are checked against a
+// Bloom filter of every ID generated instead.
+func Soak(ctx context.Context, cfg SoakConfig) (SoakStats, error) {
+	if cfg.Duration < 0 || cfg.Concurrency < 0 || cfg.Window < 0 || cfg.FilterBytes < 0 || cfg.Interval < 0 {
+		return SoakStats{}, errors.New("soak config values must not be negative")
+	}
+	if cfg.Concurrency == 0 {
+		cfg.Concurrency = runtime.GOMAXPROCS(0)
+	}
+	if cfg.Generate == nil {
+		cfg.Generate = GenerateDefault
+	}
+	if cfg.Window == 0 {
+		cfg.Window = 5 * time.Second
+	}
+	if cfg.FilterBytes == 0 {
+		cfg.FilterBytes = 64 << 20
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = time.Minute
+	}
+
+	s := newSoakState(cfg)
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	var wg sync.WaitGroup
+	for range cfg.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(cfg.Generate)
+		}()
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for running := true; running; {
+		select {
+		case <-ctx.Done():
+			running = false
+		case <-ticker.C:
+			if cfg.Snapshot != nil {
+				cfg.Snapshot(s.stats())
+			}
+		}
+	}
+	s.stop.Store(true)
+	wg.Wait()
+	return s.stats(), nil
+}
+
+// soakState is the state shared by the workers of a Soak.
+type soakState struct {
+	start  time.Time
+	window int64
+	stop   atomic.Bool
+
+	newest atomic.Int64
+	shards [soakShards]soakShard
+	filter []atomic.Uint64
+
+	generated, errors, duplicates, late, suspected, tracked atomic.Int64
+}
+
+func newSoakState(cfg SoakConfig) *soakState {
+	s := &soakState{
+		start:  time.Now(),
+		window: cfg.Window.Milliseconds(),
+		filter: make([]atomic.Uint64, max(cfg.FilterBytes/8, 1)),
+	}
+	s.newest.Store(math.MinInt64)
+	for i := range s.shards {
+		s.shards[i].buckets = make(map[int64]map[Nano64]struct{})
+	}
+	return s
+}
+
+// work generates and checks IDs until stop is set.
+func (s *soakState) work(generate func() (Nano64, error)) {
+	for !s.stop.Load() {
+		var generated, errs, duplicates, late, suspected int64
+		for range soakBatch {
+			id, err := generate()
+			if err != nil {
+				errs++
+				continue
+			}
+			generated++
+			seen := s.filterAdd(id)
+			ts := id.GetTimestamp()
+			cutoff := s.advance(ts) - s.window
+			isLate := ts < cutoff
+			if !isLate {
+				var duplicate bool
+				duplicate, isLate = s.shards[id.Hash64(0)%soakShards].add(s, id, ts, cutoff)
+				if duplicate {
+					duplicates++
+				}
+			}
+			if isLate {
+				late++
+				if seen {
+					suspected++
+				}
+			}
+		}
+		s.generated.Add(generated)
+		s.errors.Add(errs)
+		s.duplicates.Add(duplicates)
+		s.late.Add(late)
+		s.suspected.Add(suspected)
+	}
+}
+
+// advance raises the newest timestamp to ts and returns the newest timestamp.
+func (s *soakState) advance(ts int64) int64 {
+	for {
+		newest := s.newest.Load()
+		if ts <= newest || s.newest.CompareAndSwap(newest, ts) {
+			return max(ts, newest)
+		}
+	}
+}
+
+// filterAdd adds id to the Bloom filter and reports whether it may have been
+// added before.
+func (s *soakState) filterAdd(id Nano64) bool {
+	bits := uint64(len(s.filter)) * 64
+	h1, h2 := id.Hash64(1), id.Hash64(2)
+	seen := true
+	for i := range uint64(soakFilterHashes) {
+		bit := (h1 + i*h2) % bits
+		mask := uint64(1) << (bit % 64)
+		if s.filter[bit/64].Or(mask)&mask == 0 {
+			seen = false
+		}
+	}
+	return seen
+}
+
+// stats returns the current totals.
+func (s *soakState) stats() SoakStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	st := SoakStats{
+		Elapsed:    time.Since(s.start),
+		Generated:  s.generated.Load(),
+		Errors:     s.errors.Load(),
+		Duplicates: s.duplicates.Load(),
+		Late:       s.late.Load(),
+		Suspected:  s.suspected.Load(),
+		Tracked:    s.tracked.Load(),
+		HeapAlloc:  mem.HeapAlloc,
+	}
+	st.Rate = float64(st.Generated) / st.Elapsed.Seconds()
+	fill := float64(soakFilterHashes) * float64(st.Generated) / (float64(len(s.filter)) * 64)
+	st.FilterFalsePositive = math.Pow(1-math.Exp(-fill), soakFilterHashes)
+	return st
+}
+
+// soakShard is one partition of the exact set, holding the IDs of each
+// timestamp not older than the cutoff.
+type soakShard struct {
+	mu      sync.Mutex
+	buckets map[int64]map[Nano64]struct{}
+	// oldest is a lower bound of the timestamps in buckets.
+	oldest int64
+}
+
+// add records id and reports whether it was already present, first dropping the
+// timestamps older than cutoff. Another worker may have moved the shard past ts
+// since the caller computed cutoff; such an id is reported late instead, so
+// evicted buckets are never recreated and only the filter checks it.
+func (sh *soakShard) add(s *soakState, id Nano64, ts, cutoff int64) (duplicate, late bool) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if cutoff > sh.oldest {
+		evicted := 0
+		if cutoff-sh.oldest > int64(len(sh.buckets)) {
+			// After a jump in time, scanning the buckets beats stepping through it.
+			for t, bucket := range sh.buckets {
+				if t < cutoff {
+					evicted += len(bucket)
+					delete(sh.buckets, t)
+				}
+			}
+		} else {
+			for t := sh.oldest; t < cutoff; t++ {
+				evicted += len(sh.buckets[t])
+				delete(sh.buckets, t)
+			}
+		}
+		sh.oldest = cutoff
+		s.tracked.Add(-int64(evicted))
+	}
+	if ts < sh.oldest {
+		return false, true
+	}
+
+	bucket := sh.buckets[ts]
+	if bucket == nil {
+		bucket = make(map[Nano64]struct{})
+		sh.buckets[ts] = bucket
+	}
+	if _, ok := bucket[id]; ok {
+		return true, false
+	}
+	bucket[id] = struct{}{}
+	s.tracked.Add(1)
+	return false, false
+}
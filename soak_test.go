@@ -0,0 +1,114 @@
+package nano64
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSoak(t *testing.T) {
+	var mu sync.Mutex
+	var snapshots []SoakStats
+	stats, err := Soak(context.Background(), SoakConfig{
+		Duration:    200 * time.Millisecond,
+		Concurrency: 4,
+		// Random IDs collide at this rate, as the birthday bound predicts.
+		Generate:    GenerateMonotonicDefault,
+		Window:      100 * time.Millisecond,
+		FilterBytes: 1 << 20,
+		Interval:    50 * time.Millisecond,
+		Snapshot: func(s SoakStats) {
+			mu.Lock()
+			defer mu.Unlock()
+			snapshots = append(snapshots, s)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Soak() error = %v", err)
+	}
+	if stats.Generated == 0 || stats.Errors != 0 || stats.Duplicates != 0 {
+		t.Errorf("Soak() = %+v", stats)
+	}
+	if stats.Elapsed < 200*time.Millisecond || stats.Elapsed > 2*time.Second {
+		t.Errorf("Soak() ran for %v, want 200ms", stats.Elapsed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(snapshots) < 2 {
+		t.Fatalf("got %d snapshots, want at least 2", len(snapshots))
+	}
+	if snapshots[1].Generated < snapshots[0].Generated {
+		t.Errorf("snapshots went backwards: %d then %d", snapshots[0].Generated, snapshots[1].Generated)
+	}
+}
+
+func TestSoakDetectsDuplicates(t *testing.T) {
+	const base = 1_700_000_000_000
+	at := func(ms int64, random uint64) Nano64 {
+		return Nano64{value: uint64(ms)<<timestampShift | random}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 100 IDs per millisecond, with an exact duplicate at i=500 and, after 50ms,
+	// a repeat of an ID from the first millisecond and a fresh ID that old.
+	i := 0
+	generate := func() (Nano64, error) {
+		defer func() { i++ }()
+		switch i {
+		case 500:
+			return at(base+4, 99), nil
+		case 5000:
+			return at(base, 10), nil
+		case 5001:
+			return at(base, 99_999), nil
+		case 6000:
+			cancel()
+		}
+		return at(base+int64(i/100), uint64(i%100)), nil
+	}
+	stats, err := Soak(ctx, SoakConfig{
+		Concurrency: 1,
+		Generate:    generate,
+		Window:      10 * time.Millisecond,
+		FilterBytes: 1 << 20,
+	})
+	if err != nil {
+		t.Fatalf("Soak() error = %v", err)
+	}
+	if stats.Duplicates != 1 || stats.Late != 2 || stats.Suspected != 1 {
+		t.Errorf("Soak() = %+v, want 1 duplicate, 2 late and 1 suspected", stats)
+	}
+	if stats.Tracked > 2000 {
+		t.Errorf("Soak() tracked %d IDs, want old partitions dropped", stats.Tracked)
+	}
+	if stats.FilterFalsePositive <= 0 || stats.FilterFalsePositive > 0.01 {
+		t.Errorf("FilterFalsePositive = %v", stats.FilterFalsePositive)
+	}
+
+	if _, err := Soak(context.Background(), SoakConfig{Window: -time.Second}); err == nil {
+		t.Error("Soak() with a negative window error = nil")
+	}
+}
+
+func TestSoakShardLateAfterEviction(t *testing.T) {
+	s := newSoakState(SoakConfig{Window: 10 * time.Millisecond})
+	sh := &s.shards[0]
+	id := func(ms int64) Nano64 { return Nano64{value: uint64(ms) << timestampShift} }
+
+	if dup, late := sh.add(s, id(100), 100, 90); dup || late {
+		t.Fatalf("add(100) = %v, %v", dup, late)
+	}
+	// A worker that read the clock before the shard moved to 90 still passes
+	// its older cutoff; the ID must count as late, not refill an evicted bucket.
+	if dup, late := sh.add(s, id(85), 85, 80); dup || !late {
+		t.Errorf("add(85) with a stale cutoff = %v, %v; want late", dup, late)
+	}
+	if _, ok := sh.buckets[85]; ok || s.tracked.Load() != 1 {
+		t.Errorf("buckets = %v, tracked = %d", sh.buckets, s.tracked.Load())
+	}
+	if dup, late := sh.add(s, id(100), 100, 90); !dup || late {
+		t.Errorf("add(100) again = %v, %v; want duplicate", dup, late)
+	}
+}
@@ -0,0 +1,231 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// The driver.Valuer and sql.Scanner implementations of the ID types. Tiny
+// builds leave them out, together with the database helpers, so firmware does
+// not link database/sql.
+
+// Value implements the driver.Valuer interface for SQL database support.
+// Returns the ID as a byte slice for storage in SQL databases as BYTEA.
+func (n Nano64) Value() (driver.Value, error) {
+	return n.ToBytes(), nil
+}
+
+// Scan implements the sql.Scanner interface for SQL database support.
+// Accepts int64 or uint64 values from SQL databases.
+func (n *Nano64) Scan(value interface{}) error {
+	if value == nil {
+		n.value = 0
+		return nil
+	}
+
+	switch v := value.(type) {
+	case int64:
+		n.value = uint64(v)
+		return nil
+	case uint64:
+		n.value = v
+		return nil
+	case []byte:
+		if len(v) != 8 {
+			return fmt.Errorf("invalid byte length for Nano64: %d", len(v))
+		}
+		parsed, err := BigIntHelpers.FromBytesBE(v)
+		if err != nil {
+			return fmt.Errorf("failed to scan bytes: %w", err)
+		}
+		n.value = parsed
+		return nil
+	default:
+		return fmt.Errorf("cannot scan type %T into Nano64", value)
+	}
+}
+
+// Value implements the driver.Valuer interface for NullNano64.
+func (n NullNano64) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.ID.Value()
+}
+
+// Scan implements the sql.Scanner interface for NullNano64.
+func (n *NullNano64) Scan(value interface{}) error {
+	if value == nil {
+		n.ID = Nil
+		n.Valid = false
+		return nil
+	}
+	n.Valid = true
+	return n.ID.Scan(value)
+}
+
+// Value implements the driver.Valuer interface for SQL database support.
+// Returns the ID as a 16-byte slice for storage as BYTEA/BINARY(16).
+func (n Nano128) Value() (driver.Value, error) {
+	return n.ToBytes(), nil
+}
+
+// Scan implements the sql.Scanner interface for SQL database support.
+// Accepts 16-byte slices or hex strings.
+func (n *Nano128) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*n = Nil128
+		return nil
+	case []byte:
+		parsed, err := FromBytes128(v)
+		if err != nil {
+			return fmt.Errorf("failed to scan bytes: %w", err)
+		}
+		*n = parsed
+		return nil
+	case string:
+		parsed, err := FromHex128(v)
+		if err != nil {
+			return fmt.Errorf("failed to scan string: %w", err)
+		}
+		*n = parsed
+		return nil
+	default:
+		return fmt.Errorf("cannot scan type %T into Nano128", value)
+	}
+}
+
+// Value implements the driver.Valuer interface for NullNano128.
+func (n NullNano128) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.ID.Value()
+}
+
+// Scan implements the sql.Scanner interface for NullNano128.
+func (n *NullNano128) Scan(value interface{}) error {
+	if value == nil {
+		n.ID = Nil128
+		n.Valid = false
+		return nil
+	}
+	n.Valid = true
+	return n.ID.Scan(value)
+}
+
+// Value implements the driver.Valuer interface for SQL database support.
+// Returns the byte encoding for storage as BYTEA/BINARY, or NULL for the zero Composite.
+func (c Composite) Value() (driver.Value, error) {
+	if c.n == 0 {
+		return nil, nil
+	}
+	return c.ToBytes(), nil
+}
+
+// Scan implements the sql.Scanner interface for SQL database support.
+// Accepts the byte encoding or the "/"-joined string; NULL scans as the zero Composite.
+func (c *Composite) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = Composite{}
+		return nil
+	case []byte:
+		parsed, err := CompositeFromBytes(v)
+		if err != nil {
+			return fmt.Errorf("failed to scan bytes: %w", err)
+		}
+		*c = parsed
+		return nil
+	case string:
+		parsed, err := ParseComposite(v)
+		if err != nil {
+			return fmt.Errorf("failed to scan string: %w", err)
+		}
+		*c = parsed
+		return nil
+	default:
+		return fmt.Errorf("cannot scan type %T into Composite", value)
+	}
+}
+
+// Value implements the driver.Valuer interface for SQL database support.
+// Returns the opaque ID as an 8-byte slice for storage as BYTEA.
+func (o OpaqueNano64) Value() (driver.Value, error) {
+	return o.ToBytes(), nil
+}
+
+// Scan implements the sql.Scanner interface for SQL database support.
+// Accepts 8-byte slices, hex strings or int64 values.
+func (o *OpaqueNano64) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		o.value = 0
+		return nil
+	case int64:
+		o.value = uint64(v)
+		return nil
+	case []byte:
+		parsed, err := OpaqueFromBytes(v)
+		if err != nil {
+			return fmt.Errorf("failed to scan bytes: %w", err)
+		}
+		*o = parsed
+		return nil
+	case string:
+		parsed, err := OpaqueFromHex(v)
+		if err != nil {
+			return fmt.Errorf("failed to scan string: %w", err)
+		}
+		*o = parsed
+		return nil
+	default:
+		return fmt.Errorf("cannot scan type %T into OpaqueNano64", value)
+	}
+}
+
+// Value implements the driver.Valuer interface for SQL database support.
+// Returns the 16-byte encoding for storage as BYTEA/BINARY(16).
+func (b BiTemporal) Value() (driver.Value, error) {
+	return b.ToBytes(), nil
+}
+
+// Scan implements the sql.Scanner interface for SQL database support.
+// Accepts 16-byte slices or "valid/system" strings.
+func (b *BiTemporal) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*b = BiTemporal{}
+		return nil
+	case []byte:
+		parsed, err := BiTemporalFromBytes(v)
+		if err != nil {
+			return fmt.Errorf("failed to scan bytes: %w", err)
+		}
+		*b = parsed
+		return nil
+	case string:
+		parsed, err := ParseBiTemporal(v)
+		if err != nil {
+			return fmt.Errorf("failed to scan string: %w", err)
+		}
+		*b = parsed
+		return nil
+	default:
+		return fmt.Errorf("cannot scan type %T into BiTemporal", value)
+	}
+}
+
+// Value implements the driver.Valuer interface for SQL database support.
+func (r Revision) Value() (driver.Value, error) {
+	return r.id.Value()
+}
+
+// Scan implements the sql.Scanner interface for SQL database support.
+// NULL scans as the zero Revision.
+func (r *Revision) Scan(value interface{}) error {
+	return r.id.Scan(value)
+}
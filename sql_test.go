@@ -0,0 +1,277 @@
+//go:build !tinygo && !nano64_tiny
+
+package nano64
+
+import (
+	"testing"
+)
+
+func TestNano64_Value(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   uint64
+		want    []byte
+		wantErr bool
+	}{
+		{"zero", 0, []byte{0, 0, 0, 0, 0, 0, 0, 0}, false},
+		{"positive", 12345, []byte{0, 0, 0, 0, 0, 0, 0x30, 0x39}, false},
+		{"large value", 0x123456789ABCDEF0, []byte{0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0xDE, 0xF0}, false},
+		{"max", ^uint64(0), []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := New(tt.value)
+			got, err := id.Value()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Value() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				gotBytes, ok := got.([]byte)
+				if !ok {
+					t.Errorf("Value() returned type %T, want []byte", got)
+					return
+				}
+				if len(gotBytes) != len(tt.want) {
+					t.Errorf("Value() returned %d bytes, want %d", len(gotBytes), len(tt.want))
+					return
+				}
+				for i := range gotBytes {
+					if gotBytes[i] != tt.want[i] {
+						t.Errorf("Value() = %v, want %v", gotBytes, tt.want)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNano64_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   interface{}
+		want    uint64
+		wantErr bool
+	}{
+		{"nil", nil, 0, false},
+		{"int64 zero", int64(0), 0, false},
+		{"int64 positive", int64(12345), 12345, false},
+		{"int64 large", int64(0x123456789ABCDEF0), 0x123456789ABCDEF0, false},
+		{"uint64 zero", uint64(0), 0, false},
+		{"uint64 positive", uint64(12345), 12345, false},
+		{"uint64 max", ^uint64(0), ^uint64(0), false},
+		{"bytes 8 bytes", []byte{0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0xDE, 0xF0}, 0x123456789ABCDEF0, false},
+		{"bytes zero", []byte{0, 0, 0, 0, 0, 0, 0, 0}, 0, false},
+		{"bytes wrong length", []byte{1, 2, 3}, 0, true},
+		{"string invalid type", "invalid", 0, true},
+		{"float invalid type", 3.14, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id Nano64
+			err := id.Scan(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && id.Uint64Value() != tt.want {
+				t.Errorf("Scan() resulted in value %d, want %d", id.Uint64Value(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNano64_ValueScan_Roundtrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value uint64
+	}{
+		{"zero", 0},
+		{"small", 12345},
+		{"large", 0x123456789ABCDEF0},
+		{"max", ^uint64(0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := New(tt.value)
+
+			// Convert to driver.Value
+			driverValue, err := original.Value()
+			if err != nil {
+				t.Fatalf("Value() error = %v", err)
+			}
+
+			// Scan back
+			var scanned Nano64
+			err = scanned.Scan(driverValue)
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			// Compare
+			if scanned.Uint64Value() != original.Uint64Value() {
+				t.Errorf("roundtrip failed: %d != %d", scanned.Uint64Value(), original.Uint64Value())
+			}
+		})
+	}
+}
+
+func TestNano64_Scan_BytesRoundtrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value uint64
+	}{
+		{"zero", 0},
+		{"small", 12345},
+		{"large", 0x123456789ABCDEF0},
+		{"max", ^uint64(0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := New(tt.value)
+
+			// Convert to bytes
+			bytes := original.ToBytes()
+
+			// Scan from bytes
+			var scanned Nano64
+			err := scanned.Scan(bytes)
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			// Compare
+			if scanned.Uint64Value() != original.Uint64Value() {
+				t.Errorf("bytes roundtrip failed: %d != %d", scanned.Uint64Value(), original.Uint64Value())
+			}
+		})
+	}
+}
+
+func TestNullNano64_Scan(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     interface{}
+		wantValid bool
+		wantError bool
+	}{
+		{"nil value", nil, false, false},
+		{"uint64 value", uint64(12345), true, false},
+		{"int64 value", int64(12345), true, false},
+		{"bytes value", []byte{0, 0, 0, 0, 0, 0, 0x30, 0x39}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n NullNano64
+			err := n.Scan(tt.input)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("Scan() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+
+			if n.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", n.Valid, tt.wantValid)
+			}
+
+			if !tt.wantValid && !n.ID.IsNil() {
+				t.Error("Invalid NullNano64 should have Nil ID")
+			}
+		})
+	}
+}
+
+func TestNullNano64_Value(t *testing.T) {
+	// Test valid NullNano64
+	id, err := GenerateDefault()
+	if err != nil {
+		t.Fatalf("GenerateDefault() error = %v", err)
+	}
+
+	validNull := NullNano64{ID: id, Valid: true}
+	val, err := validNull.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if val == nil {
+		t.Error("Valid NullNano64.Value() should not be nil")
+	}
+
+	// Test invalid NullNano64
+	invalidNull := NullNano64{Valid: false}
+	val, err = invalidNull.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if val != nil {
+		t.Errorf("Invalid NullNano64.Value() = %v, want nil", val)
+	}
+}
+
+func TestBiTemporalSQL(t *testing.T) {
+	original := BiTemporal{Valid: mustGenerate(t, 1_000_000, 7), System: mustGenerate(t, 2_000_000, 1)}
+
+	value, err := original.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	for _, src := range []any{value, original.String()} {
+		var scanned BiTemporal
+		if err := scanned.Scan(src); err != nil {
+			t.Fatalf("Scan(%v) error = %v", src, err)
+		}
+		if scanned != original {
+			t.Errorf("Scan(%v) = %v, want %v", src, scanned, original)
+		}
+	}
+	var scanned BiTemporal
+	if err := scanned.Scan(nil); err != nil || !scanned.IsNil() {
+		t.Errorf("Scan(nil) = %v, %v", scanned, err)
+	}
+	for _, src := range []any{[]byte{1, 2, 3}, "no-separator", int64(1)} {
+		if err := scanned.Scan(src); err == nil {
+			t.Errorf("Scan(%v) succeeded, want error", src)
+		}
+	}
+}
+
+func TestOpaqueNano64SQL(t *testing.T) {
+	opaque := OpaqueNano64{value: 0x0123456789ABCDEF}
+
+	value, err := opaque.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	for _, src := range []any{value, opaque.ToHex(), int64(opaque.value)} {
+		var scanned OpaqueNano64
+		if err := scanned.Scan(src); err != nil || scanned != opaque {
+			t.Errorf("Scan(%v) = %v, %v", src, scanned, err)
+		}
+	}
+	var scanned OpaqueNano64
+	if err := scanned.Scan(3.5); err == nil {
+		t.Error("Scan(float64) succeeded, want error")
+	}
+}
+
+func TestRevisionSQL(t *testing.T) {
+	r := RevisionOf(mustGenerate(t, 1_700_000_000_000, 42))
+
+	value, err := r.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	var scanned Revision
+	if err := scanned.Scan(value); err != nil || scanned != r {
+		t.Errorf("Scan() = %s, %v, want %s", scanned, err, r)
+	}
+	if err := scanned.Scan(nil); err != nil || !scanned.IsZero() {
+		t.Errorf("Scan(nil) = %s, %v, want zero", scanned, err)
+	}
+}
@@ -0,0 +1,148 @@
+package nano64
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseRepresentation returns the representation named s, as printed by
+// Representation.String; "int64" and "bigint" are accepted for signed.
+func ParseRepresentation(s string) (Representation, error) {
+	switch strings.ToLower(s) {
+	case "bytes", "bytea", "blob":
+		return RepresentationBytes, nil
+	case "signed", "int64", "bigint":
+		return RepresentationSigned, nil
+	case "hex":
+		return RepresentationHex, nil
+	default:
+		return 0, fmt.Errorf("unknown representation %q", s)
+	}
+}
+
+// Literal returns id as an SQL literal of the given representation.
+func (d Dialect) Literal(id Nano64, repr Representation) (string, error) {
+	if err := d.validate(); err != nil {
+		return "", err
+	}
+	switch repr {
+	case RepresentationBytes:
+		if d == DialectPostgres {
+			return `'\x` + hex.EncodeToString(id.ToBytes()) + `'::bytea`, nil
+		}
+		return "X'" + strings.ToUpper(hex.EncodeToString(id.ToBytes())) + "'", nil
+	case RepresentationSigned:
+		return strconv.FormatInt(SignedNano64.FromId(id), 10), nil
+	case RepresentationHex:
+		return "'" + id.ToHex() + "'", nil
+	default:
+		return "", fmt.Errorf("unsupported representation %v", repr)
+	}
+}
+
+// RangeCondition returns a predicate selecting the IDs of r in column, such as
+// `"id" BETWEEN X'...' AND X'...'`. The column is compared bare, so the query can
+// use an index on it; every representation sorts like the IDs themselves.
+func (d Dialect) RangeCondition(column string, r IDRange, repr Representation) (string, error) {
+	first, err := d.Literal(r.First, repr)
+	if err != nil {
+		return "", err
+	}
+	last, err := d.Literal(r.Last, repr)
+	if err != nil {
+		return "", err
+	}
+	return d.QuoteIdent(column) + " BETWEEN " + first + " AND " + last, nil
+}
+
+// TimestampExpr returns an SQL expression computing the embedded Unix milliseconds
+// of the IDs in column. It needs no installed functions.
+func (d Dialect) TimestampExpr(column string, repr Representation) (string, error) {
+	if err := d.validate(); err != nil {
+		return "", err
+	}
+	col := d.QuoteIdent(column)
+	shift := strconv.Itoa(RandomBits)
+	mask := strconv.FormatUint(timestampMask, 10)
+	// The signed form is value - 2^63; shifting it arithmetically and adding the
+	// shifted offset back recovers the timestamp without 64-bit unsigned math.
+	signOffset := strconv.FormatUint(1<<(63-RandomBits), 10)
+
+	switch repr {
+	case RepresentationSigned:
+		if d == DialectMySQL {
+			// MySQL shifts as unsigned, so flip the sign bit instead.
+			return "((" + col + " ^ 9223372036854775808) >> " + shift + ")", nil
+		}
+		return "((" + col + " >> " + shift + ") + " + signOffset + ")", nil
+	case RepresentationBytes:
+		switch d {
+		case DialectPostgres:
+			return "((('x' || encode(" + col + ", 'hex'))::bit(64)::bigint >> " + shift + ") & " + mask + ")", nil
+		case DialectMySQL:
+			return "(CAST(CONV(HEX(" + col + "), 16, 10) AS UNSIGNED) >> " + shift + ")", nil
+		default:
+			return sqliteHexDigitsExpr("hex("+col+")", timestampHexDigits), nil
+		}
+	case RepresentationHex:
+		switch d {
+		case DialectPostgres:
+			return "('x' || substr(" + col + ", 1, " + strconv.Itoa(timestampHexDigits) + "))::bit(" + strconv.Itoa(TimestampBits) + ")::bigint", nil
+		case DialectMySQL:
+			return "CAST(CONV(SUBSTRING(" + col + ", 1, " + strconv.Itoa(timestampHexDigits) + "), 16, 10) AS UNSIGNED)", nil
+		default:
+			return sqliteHexDigitsExpr("upper("+col+")", timestampHexDigits), nil
+		}
+	default:
+		return "", fmt.Errorf("unsupported representation %v", repr)
+	}
+}
+
+// sqliteHexDigitsExpr returns an expression decoding the first n hex digits of the
+// text expression s, as SQLite has no built-in hex-to-integer conversion.
+func sqliteHexDigitsExpr(s string, n int) string {
+	terms := make([]string, n)
+	for i := range terms {
+		terms[i] = fmt.Sprintf("((instr('0123456789ABCDEF', substr(%s, %d, 1)) - 1) << %d)", s, i+1, 4*(n-1-i))
+	}
+	return "(" + strings.Join(terms, " | ") + ")"
+}
+
+// ColumnType returns the column type storing the representation, including a
+// CHECK constraint on its shape where the type alone does not enforce it.
+func (d Dialect) ColumnType(column string, repr Representation) (string, error) {
+	if err := d.validate(); err != nil {
+		return "", err
+	}
+	col := d.QuoteIdent(column)
+	switch repr {
+	case RepresentationBytes:
+		switch d {
+		case DialectPostgres:
+			return "bytea CHECK (octet_length(" + col + ") = 8)", nil
+		case DialectMySQL:
+			return "BINARY(8)", nil
+		default:
+			return "BLOB CHECK (length(" + col + ") = 8)", nil
+		}
+	case RepresentationSigned:
+		if d == DialectSQLite {
+			return "INTEGER", nil
+		}
+		return "BIGINT", nil
+	case RepresentationHex:
+		length := strconv.Itoa(hexLength)
+		switch d {
+		case DialectPostgres:
+			return "char(" + length + ") CHECK (" + col + " ~ '" + CanonicalIDRegexp.String() + "')", nil
+		case DialectMySQL:
+			return "CHAR(" + length + ") CHECK (" + col + " REGEXP '" + CanonicalIDRegexp.String() + "')", nil
+		default:
+			return "TEXT CHECK (length(" + col + ") = " + length + " AND substr(" + col + ", 12, 1) = '-')", nil
+		}
+	default:
+		return "", fmt.Errorf("unsupported representation %v", repr)
+	}
+}
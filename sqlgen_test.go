@@ -0,0 +1,93 @@
+package nano64
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestDialectLiteral(t *testing.T) {
+	id, _ := FromHex("0199C6A1F5E-3B2D4")
+	tests := []struct {
+		dialect Dialect
+		repr    Representation
+		want    string
+	}{
+		{DialectPostgres, RepresentationBytes, `'\x0199c6a1f5e3b2d4'::bytea`},
+		{DialectMySQL, RepresentationBytes, "X'0199C6A1F5E3B2D4'"},
+		{DialectSQLite, RepresentationSigned, "-9108030372462742828"},
+		{DialectPostgres, RepresentationHex, "'0199C6A1F5E-3B2D4'"},
+	}
+	for _, tt := range tests {
+		got, err := tt.dialect.Literal(id, tt.repr)
+		if err != nil || got != tt.want {
+			t.Errorf("%v.Literal(%v) = %s, %v; want %s", tt.dialect, tt.repr, got, err, tt.want)
+		}
+	}
+}
+
+// TestSQLiteGeneratedSQL runs the generated DDL, range predicates and timestamp
+// expressions against SQLite for every representation.
+func TestSQLiteGeneratedSQL(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ids := []Nano64{
+		mustGenerate(t, 1, 0),
+		mustGenerate(t, base.UnixMilli(), randomMask),
+		mustGenerate(t, base.Add(time.Minute).UnixMilli(), 12345),
+		mustGenerate(t, maxTimestamp, 0),
+	}
+
+	for _, repr := range []Representation{RepresentationBytes, RepresentationSigned, RepresentationHex} {
+		t.Run(repr.String(), func(t *testing.T) {
+			colType, err := DialectSQLite.ColumnType("id", repr)
+			if err != nil {
+				t.Fatalf("ColumnType() error = %v", err)
+			}
+			table := "ids_" + repr.String()
+			if _, err := db.Exec("CREATE TABLE " + table + " (id " + colType + " PRIMARY KEY)"); err != nil {
+				t.Fatalf("create table failed: %v", err)
+			}
+			for _, id := range ids {
+				lit, _ := DialectSQLite.Literal(id, repr)
+				if _, err := db.Exec("INSERT INTO " + table + " VALUES (" + lit + ")"); err != nil {
+					t.Fatalf("insert failed: %v", err)
+				}
+			}
+
+			expr, err := DialectSQLite.TimestampExpr("id", repr)
+			if err != nil {
+				t.Fatalf("TimestampExpr() error = %v", err)
+			}
+			for _, id := range ids {
+				lit, _ := DialectSQLite.Literal(id, repr)
+				var ts int64
+				if err := db.QueryRow("SELECT " + expr + " FROM " + table + " WHERE id = " + lit).Scan(&ts); err != nil {
+					t.Fatalf("timestamp query failed: %v", err)
+				}
+				if ts != id.GetTimestamp() {
+					t.Errorf("timestamp of %s = %d, want %d", id.ToHex(), ts, id.GetTimestamp())
+				}
+			}
+
+			cond, err := DialectSQLite.RangeCondition("id", RangeForTime(base, base.Add(time.Hour)), repr)
+			if err != nil {
+				t.Fatalf("RangeCondition() error = %v", err)
+			}
+			var n int
+			if err := db.QueryRow("SELECT count(*) FROM " + table + " WHERE " + cond).Scan(&n); err != nil {
+				t.Fatalf("range query failed: %v", err)
+			}
+			if n != 2 {
+				t.Errorf("range matched %d rows, want 2", n)
+			}
+		})
+	}
+}
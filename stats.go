@@ -0,0 +1,107 @@
+package nano64
+
+import (
+	"slices"
+	"time"
+)
+
+// IDStats summarizes the timing of a collection of IDs, as used for capacity reports.
+type IDStats struct {
+	// Count is the number of IDs analysed; Unique excludes repeated values.
+	Count  int
+	Unique int
+	// Duplicates is Count - Unique: IDs that collided with an earlier one.
+	Duplicates int
+
+	// First and Last are the earliest and latest embedded timestamps.
+	First time.Time
+	Last  time.Time
+
+	// MinGap, MaxGap and MeanGap describe inter-arrival times between consecutive
+	// IDs in sorted order; IDs sharing a millisecond have a gap of zero.
+	MinGap  time.Duration
+	MaxGap  time.Duration
+	MeanGap time.Duration
+	// P50Gap, P90Gap and P99Gap are percentiles of the inter-arrival times.
+	P50Gap time.Duration
+	P90Gap time.Duration
+	P99Gap time.Duration
+
+	// Milliseconds is the number of distinct milliseconds holding at least one ID.
+	Milliseconds int
+	// PerMillisecond maps "IDs in a millisecond" to the number of milliseconds with that many.
+	PerMillisecond map[int]int
+	// PeakPerMillisecond is the most IDs seen in one millisecond, at PeakMillisecond.
+	PeakPerMillisecond int
+	PeakMillisecond    time.Time
+	// P50PerMillisecond and P99PerMillisecond are percentiles of the per-millisecond counts.
+	P50PerMillisecond int
+	P99PerMillisecond int
+}
+
+// Stats computes inter-arrival, per-millisecond and duplicate statistics for ids.
+// The input slice is not modified.
+func Stats(ids []Nano64) IDStats {
+	stats := IDStats{Count: len(ids), PerMillisecond: make(map[int]int)}
+	if len(ids) == 0 {
+		return stats
+	}
+
+	sorted := slices.Clone(ids)
+	slices.SortFunc(sorted, Compare)
+
+	stats.First = sorted[0].ToDate()
+	stats.Last = sorted[len(sorted)-1].ToDate()
+
+	gaps := make([]int64, 0, len(sorted)-1)
+	var perMs []int
+	run := 0
+	for i, id := range sorted {
+		if i == 0 || !id.Equals(sorted[i-1]) {
+			stats.Unique++
+		}
+		if i > 0 {
+			gaps = append(gaps, id.GetTimestamp()-sorted[i-1].GetTimestamp())
+		}
+
+		run++
+		if i == len(sorted)-1 || sorted[i+1].GetTimestamp() != id.GetTimestamp() {
+			perMs = append(perMs, run)
+			stats.PerMillisecond[run]++
+			if run > stats.PeakPerMillisecond {
+				stats.PeakPerMillisecond = run
+				stats.PeakMillisecond = id.ToDate()
+			}
+			run = 0
+		}
+	}
+	stats.Duplicates = stats.Count - stats.Unique
+	stats.Milliseconds = len(perMs)
+
+	slices.Sort(perMs)
+	stats.P50PerMillisecond = perMs[percentileIndex(len(perMs), 0.50)]
+	stats.P99PerMillisecond = perMs[percentileIndex(len(perMs), 0.99)]
+
+	if len(gaps) > 0 {
+		slices.Sort(gaps)
+		var total int64
+		for _, g := range gaps {
+			total += g
+		}
+		ms := func(v int64) time.Duration { return time.Duration(v) * time.Millisecond }
+		stats.MinGap = ms(gaps[0])
+		stats.MaxGap = ms(gaps[len(gaps)-1])
+		stats.MeanGap = time.Duration(float64(total) / float64(len(gaps)) * float64(time.Millisecond))
+		stats.P50Gap = ms(gaps[percentileIndex(len(gaps), 0.50)])
+		stats.P90Gap = ms(gaps[percentileIndex(len(gaps), 0.90)])
+		stats.P99Gap = ms(gaps[percentileIndex(len(gaps), 0.99)])
+	}
+
+	return stats
+}
+
+// percentileIndex returns the nearest-rank index of percentile p in a sorted slice of length n.
+func percentileIndex(n int, p float64) int {
+	i := int(p*float64(n)+0.999999) - 1
+	return min(max(i, 0), n-1)
+}
@@ -0,0 +1,47 @@
+package nano64
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	at := func(ts, random uint64) Nano64 { return New(ts<<RandomBits | random) }
+	ids := []Nano64{
+		at(1010, 1),
+		at(1000, 1),
+		at(1000, 2),
+		at(1000, 2), // duplicate
+		at(1002, 9),
+	}
+
+	stats := Stats(ids)
+
+	if stats.Count != 5 || stats.Unique != 4 || stats.Duplicates != 1 {
+		t.Errorf("counts = %d/%d/%d, want 5/4/1", stats.Count, stats.Unique, stats.Duplicates)
+	}
+	if stats.First.UnixMilli() != 1000 || stats.Last.UnixMilli() != 1010 {
+		t.Errorf("range = %v..%v", stats.First, stats.Last)
+	}
+	if stats.MinGap != 0 || stats.MaxGap != 8*time.Millisecond || stats.MeanGap != 2500*time.Microsecond {
+		t.Errorf("gaps = %v/%v/%v", stats.MinGap, stats.MaxGap, stats.MeanGap)
+	}
+	if stats.Milliseconds != 3 || stats.PeakPerMillisecond != 3 || stats.PeakMillisecond.UnixMilli() != 1000 {
+		t.Errorf("per-ms = %d ms, peak %d at %v", stats.Milliseconds, stats.PeakPerMillisecond, stats.PeakMillisecond)
+	}
+	if stats.PerMillisecond[1] != 2 || stats.PerMillisecond[3] != 1 {
+		t.Errorf("PerMillisecond = %v", stats.PerMillisecond)
+	}
+	if stats.P50PerMillisecond != 1 || stats.P99PerMillisecond != 3 {
+		t.Errorf("per-ms percentiles = %d/%d", stats.P50PerMillisecond, stats.P99PerMillisecond)
+	}
+	if !ids[0].Equals(at(1010, 1)) {
+		t.Error("Stats() modified its input")
+	}
+}
+
+func TestStats_Empty(t *testing.T) {
+	if stats := Stats(nil); stats.Count != 0 || stats.Milliseconds != 0 {
+		t.Errorf("Stats(nil) = %+v", stats)
+	}
+}
@@ -0,0 +1,99 @@
+package nano64
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"time"
+)
+
+// StreamOptions configures Stream.
+type StreamOptions struct {
+	// Generator produces the IDs. Nil means the package-level generator.
+	Generator *Generator
+
+	// Monotonic selects GenerateMonotonic over Generate.
+	Monotonic bool
+
+	// Count bounds the number of IDs. Zero means the stream is infinite.
+	Count int
+
+	// Rate caps the IDs produced per second. Zero means no cap.
+	Rate float64
+}
+
+// Stream returns a sequence of IDs for use with range:
+//
+//	for id, err := range nano64.Stream(ctx, nano64.StreamOptions{Count: 100}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// The sequence ends after Count IDs, when the loop breaks, or after yielding an
+// error: the context's error once it is done, or the first generation error.
+// Rather than failing, it waits for the next millisecond when the generator's
+// MaxPerMillisecond is reached, and paces itself to Rate.
+func Stream(ctx context.Context, opts StreamOptions) iter.Seq2[Nano64, error] {
+	return func(yield func(Nano64, error) bool) {
+		g := opts.Generator
+		if g == nil {
+			g = defaultGenerator
+		}
+		generate := g.Generate
+		if opts.Monotonic {
+			generate = g.GenerateMonotonic
+		}
+		var interval time.Duration
+		if opts.Rate > 0 {
+			interval = time.Duration(float64(time.Second) / opts.Rate)
+		}
+
+		next := time.Now()
+		for n := 0; opts.Count <= 0 || n < opts.Count; n++ {
+			if interval > 0 {
+				if err := sleepContext(ctx, time.Until(next)); err != nil {
+					yield(Nil, err)
+					return
+				}
+				next = next.Add(interval)
+			}
+			if err := ctx.Err(); err != nil {
+				yield(Nil, err)
+				return
+			}
+
+			id, err := generate()
+			for errors.Is(err, ErrRateLimited) {
+				if err = sleepContext(ctx, time.Millisecond); err != nil {
+					break
+				}
+				id, err = generate()
+			}
+			if err != nil {
+				yield(Nil, err)
+				return
+			}
+			if !yield(id, nil) {
+				return
+			}
+		}
+	}
+}
+
+// sleepContext waits for d or until ctx is done, returning ctx's error in the
+// latter case.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
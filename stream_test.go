@@ -0,0 +1,83 @@
+package nano64
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStream(t *testing.T) {
+	g, err := NewGenerator(WithMaxPerMillisecond(2))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	var ids []Nano64
+	for id, err := range Stream(context.Background(), StreamOptions{Generator: g, Monotonic: true, Count: 10}) {
+		if err != nil {
+			t.Fatalf("Stream() error = %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 10 {
+		t.Fatalf("Stream() yielded %d IDs, want 10", len(ids))
+	}
+	for i := 1; i < len(ids); i++ {
+		if Compare(ids[i-1], ids[i]) >= 0 {
+			t.Errorf("ID %d (%s) is not after ID %d (%s)", i, ids[i].ToHex(), i-1, ids[i-1].ToHex())
+		}
+	}
+
+	// Breaking out of an infinite stream stops it.
+	n := 0
+	for range Stream(context.Background(), StreamOptions{}) {
+		if n++; n == 5 {
+			break
+		}
+	}
+
+	start := time.Now()
+	n = 0
+	for _, err := range Stream(context.Background(), StreamOptions{Count: 11, Rate: 500}) {
+		if err != nil {
+			t.Fatalf("Stream() error = %v", err)
+		}
+		n++
+	}
+	if elapsed := time.Since(start); n != 11 || elapsed < 20*time.Millisecond {
+		t.Errorf("rate-limited stream yielded %d IDs in %v, want 11 in at least 20ms", n, elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n = 0
+	var last error
+	for _, err := range Stream(ctx, StreamOptions{Rate: 1000}) {
+		if err != nil {
+			last = err
+			continue
+		}
+		if n++; n == 3 {
+			cancel()
+		}
+	}
+	if n != 3 || !errors.Is(last, context.Canceled) {
+		t.Errorf("canceled stream yielded %d IDs and error %v, want 3 and context.Canceled", n, last)
+	}
+
+	failing, err := NewGenerator(WithRNG(func(bits int) (uint32, error) { return 0, errors.New("no entropy") }))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	n = 0
+	for _, err := range Stream(context.Background(), StreamOptions{Generator: failing}) {
+		if err == nil {
+			t.Fatal("Stream() yielded an ID without entropy")
+		}
+		n++
+	}
+	if n != 1 {
+		t.Errorf("failing stream yielded %d errors, want 1", n)
+	}
+}
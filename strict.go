@@ -0,0 +1,85 @@
+package nano64
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrImplausibleTimestamp is returned by strict parsing for IDs whose embedded
+// timestamp lies outside the configured sanity bounds.
+var ErrImplausibleTimestamp = errors.New("implausible ID timestamp")
+
+// DefaultStrictParser accepts IDs created from 2000 onwards and at most a day
+// ahead of DefaultClock.
+var DefaultStrictParser = StrictParser{
+	NotBefore: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	MaxAhead:  24 * time.Hour,
+}
+
+// StrictParser parses IDs at trust boundaries, rejecting well-formed values whose
+// timestamp cannot belong to a real ID, such as corrupted or forged input, before
+// it travels any further. The zero value accepts every timestamp up to the present.
+type StrictParser struct {
+	// NotBefore rejects IDs created before it. Zero disables the bound.
+	NotBefore time.Time
+
+	// MaxAhead is how far ahead of the clock an ID's timestamp may be, to allow
+	// for clock skew between hosts.
+	MaxAhead time.Duration
+
+	// Clock defaults to DefaultClock.
+	Clock Clock
+}
+
+// Check returns an error wrapping ErrImplausibleTimestamp if id's timestamp is
+// outside the bounds.
+func (p StrictParser) Check(id Nano64) error {
+	ts := id.GetTimestamp()
+	if !p.NotBefore.IsZero() && ts < p.NotBefore.UnixMilli() {
+		return fmt.Errorf("%w: %s is before %s", ErrImplausibleTimestamp, id.ToDate().UTC().Format(time.RFC3339Nano), p.NotBefore.Format(time.RFC3339))
+	}
+
+	clock := p.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	if limit := clock() + p.MaxAhead.Milliseconds(); ts > limit {
+		return fmt.Errorf("%w: %s is more than %v in the future", ErrImplausibleTimestamp, id.ToDate().UTC().Format(time.RFC3339Nano), p.MaxAhead)
+	}
+	return nil
+}
+
+// FromHex parses hex like FromHex and checks the bounds.
+func (p StrictParser) FromHex(hex string) (Nano64, error) {
+	id, err := FromHex(hex)
+	if err != nil {
+		return Nil, err
+	}
+	if err := p.Check(id); err != nil {
+		return Nil, err
+	}
+	return id, nil
+}
+
+// FromBytes parses bytes like FromBytes and checks the bounds.
+func (p StrictParser) FromBytes(bytes []byte) (Nano64, error) {
+	id, err := FromBytes(bytes)
+	if err != nil {
+		return Nil, err
+	}
+	if err := p.Check(id); err != nil {
+		return Nil, err
+	}
+	return id, nil
+}
+
+// ParseStrict parses hex with DefaultStrictParser.
+func ParseStrict(hex string) (Nano64, error) {
+	return DefaultStrictParser.FromHex(hex)
+}
+
+// ParseStrictBytes parses 8 big-endian bytes with DefaultStrictParser.
+func ParseStrictBytes(bytes []byte) (Nano64, error) {
+	return DefaultStrictParser.FromBytes(bytes)
+}
@@ -0,0 +1,61 @@
+package nano64
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStrictParser(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	p := StrictParser{
+		NotBefore: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		MaxAhead:  time.Minute,
+		Clock:     func() int64 { return now.UnixMilli() },
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		ok   bool
+	}{
+		{"recent", now.Add(-time.Hour), true},
+		{"within skew", now.Add(30 * time.Second), true},
+		{"far future", now.Add(time.Hour), false},
+		{"before bound", time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := mustGenerate(t, tt.at.UnixMilli(), 7)
+			for name, parse := range map[string]func() (Nano64, error){
+				"hex":   func() (Nano64, error) { return p.FromHex(id.ToHex()) },
+				"bytes": func() (Nano64, error) { return p.FromBytes(id.ToBytes()) },
+			} {
+				got, err := parse()
+				if tt.ok && (err != nil || got != id) {
+					t.Errorf("%s: got %v, %v; want %v", name, got, err, id)
+				}
+				if !tt.ok && !errors.Is(err, ErrImplausibleTimestamp) {
+					t.Errorf("%s: error = %v, want ErrImplausibleTimestamp", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	if _, err := ParseStrict(Nil.ToHex()); !errors.Is(err, ErrImplausibleTimestamp) {
+		t.Errorf("ParseStrict(Nil) error = %v, want ErrImplausibleTimestamp", err)
+	}
+	if _, err := ParseStrict("not-hex"); err == nil || errors.Is(err, ErrImplausibleTimestamp) {
+		t.Errorf("ParseStrict(malformed) error = %v, want a parse error", err)
+	}
+
+	id, err := GenerateDefault()
+	if err != nil {
+		t.Fatalf("GenerateDefault() error = %v", err)
+	}
+	if got, err := ParseStrictBytes(id.ToBytes()); err != nil || got != id {
+		t.Errorf("ParseStrictBytes() = %v, %v; want %v", got, err, id)
+	}
+}
@@ -0,0 +1,172 @@
+package nano64
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// LegacyFormat is the ID format a service migrates away from with a Switchover.
+type LegacyFormat struct {
+	// Generate creates an ID in the legacy format. Required.
+	Generate func() (string, error)
+
+	// Validate returns an error if s is not a legacy ID. Required.
+	Validate func(s string) error
+}
+
+// SwitchoverID is an ID in either format of a Switchover.
+type SwitchoverID struct {
+	// ID is the Nano64; Nil for a legacy ID.
+	ID Nano64
+	// Legacy is the legacy ID; empty for a Nano64.
+	Legacy string
+}
+
+// IsLegacy reports whether the ID is in the legacy format.
+func (id SwitchoverID) IsLegacy() bool {
+	return id.Legacy != ""
+}
+
+// String returns the legacy ID, or the Nano64 as dashed hex.
+func (id SwitchoverID) String() string {
+	if id.IsLegacy() {
+		return id.Legacy
+	}
+	return id.ID.ToHex()
+}
+
+// SwitchoverOptions configures a Switchover.
+type SwitchoverOptions struct {
+	// Legacy is the format being replaced. Required.
+	Legacy LegacyFormat
+
+	// Percent is the share of new IDs issued as Nano64, from 0 to 100, before
+	// Cutover. SetPercent changes it at runtime, e.g. from a feature flag.
+	Percent float64
+
+	// Cutover is when every new ID becomes a Nano64 regardless of Percent. The
+	// zero Time leaves the decision to Percent alone.
+	Cutover time.Time
+
+	// Generator issues the Nano64s; nil means GenerateMonotonicDefault.
+	Generator *Generator
+
+	// RNG draws the percentage decisions; DefaultRNG if nil.
+	RNG RNG
+
+	// Clock is checked against Cutover; DefaultClock if nil.
+	Clock Clock
+}
+
+// switchoverResolution is the number of random bits of a percentage decision.
+const switchoverResolution = 20
+
+// Switchover moves a service from a legacy ID format to Nano64 without a
+// big-bang deploy: it reads both formats and issues a configurable share of new
+// IDs as Nano64 (read-both/write-new), rising to all of them at the cutover time.
+// A Switchover is safe for concurrent use.
+type Switchover struct {
+	legacy    LegacyFormat
+	cutover   int64
+	generate  func() (Nano64, error)
+	rng       RNG
+	clock     Clock
+	threshold atomic.Uint64 // Percent scaled to 1<<switchoverResolution
+}
+
+// NewSwitchover creates a Switchover.
+func NewSwitchover(opts SwitchoverOptions) (*Switchover, error) {
+	if opts.Legacy.Generate == nil || opts.Legacy.Validate == nil {
+		return nil, fmt.Errorf("legacy format needs Generate and Validate")
+	}
+	s := &Switchover{
+		legacy:   opts.Legacy,
+		cutover:  math.MaxInt64,
+		generate: GenerateMonotonicDefault,
+		rng:      opts.RNG,
+		clock:    opts.Clock,
+	}
+	if !opts.Cutover.IsZero() {
+		s.cutover = opts.Cutover.UnixMilli()
+	}
+	if opts.Generator != nil {
+		s.generate = opts.Generator.GenerateMonotonic
+	}
+	if s.rng == nil {
+		s.rng = DefaultRNG
+	}
+	if s.clock == nil {
+		s.clock = DefaultClock
+	}
+	if err := s.SetPercent(opts.Percent); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetPercent changes the share of new IDs issued as Nano64.
+func (s *Switchover) SetPercent(percent float64) error {
+	if !(percent >= 0 && percent <= 100) {
+		return fmt.Errorf("percent must be 0-100, got %v", percent)
+	}
+	s.threshold.Store(uint64(math.Round(percent / 100 * (1 << switchoverResolution))))
+	return nil
+}
+
+// New issues an ID, as a Nano64 or in the legacy format depending on the gate.
+func (s *Switchover) New() (SwitchoverID, error) {
+	useNano, err := s.useNano64()
+	if err != nil {
+		return SwitchoverID{}, err
+	}
+	if useNano {
+		id, err := s.generate()
+		if err != nil {
+			return SwitchoverID{}, err
+		}
+		return SwitchoverID{ID: id}, nil
+	}
+	legacy, err := s.legacy.Generate()
+	if err != nil {
+		return SwitchoverID{}, fmt.Errorf("failed to generate legacy ID: %w", err)
+	}
+	if legacy == "" {
+		return SwitchoverID{}, fmt.Errorf("legacy generator returned an empty ID")
+	}
+	return SwitchoverID{Legacy: legacy}, nil
+}
+
+// useNano64 decides the format of the next ID.
+func (s *Switchover) useNano64() (bool, error) {
+	if s.clock() >= s.cutover {
+		return true, nil
+	}
+	threshold := s.threshold.Load()
+	switch threshold {
+	case 0:
+		return false, nil
+	case 1 << switchoverResolution:
+		return true, nil
+	}
+	r, err := s.rng(switchoverResolution)
+	if err != nil {
+		return false, fmt.Errorf("failed to draw switchover decision: %w", err)
+	}
+	return uint64(r) < threshold, nil
+}
+
+// Parse reads an ID in either format. Nano64s must be in the dashed hex form
+// that New issues, so legacy IDs of 16 hex digits are not misread.
+func (s *Switchover) Parse(str string) (SwitchoverID, error) {
+	if len(str) == hexLength {
+		if value, ok := decodeCanonicalHex(str); ok {
+			return SwitchoverID{ID: Nano64{value: value}}, nil
+		}
+	}
+	if err := s.legacy.Validate(str); err != nil {
+		return SwitchoverID{}, fmt.Errorf("%q is neither a Nano64 nor a legacy ID: %w", str, err)
+	}
+	return SwitchoverID{Legacy: str}, nil
+}
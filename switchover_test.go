@@ -0,0 +1,96 @@
+package nano64
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSwitchover(t *testing.T) {
+	var seq int
+	legacy := LegacyFormat{
+		Generate: func() (string, error) {
+			seq++
+			return strconv.Itoa(seq), nil
+		},
+		Validate: func(s string) error {
+			if _, err := strconv.ParseUint(s, 10, 64); err != nil {
+				return fmt.Errorf("not a numeric ID")
+			}
+			return nil
+		},
+	}
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	var draws uint32
+	s, err := NewSwitchover(SwitchoverOptions{
+		Legacy:  legacy,
+		Percent: 25,
+		Cutover: now.Add(time.Hour),
+		RNG: func(bits int) (uint32, error) {
+			draws = (draws + 1<<16) % (1 << bits)
+			return draws, nil
+		},
+		Clock: func() int64 { return now.UnixMilli() },
+	})
+	if err != nil {
+		t.Fatalf("NewSwitchover() error = %v", err)
+	}
+
+	countNano := func(n int) int {
+		var count int
+		for range n {
+			id, err := s.New()
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			parsed, err := s.Parse(id.String())
+			if err != nil || parsed != id {
+				t.Fatalf("Parse(%q) = %+v, %v, want %+v", id, parsed, err, id)
+			}
+			if !id.IsLegacy() {
+				count++
+			}
+		}
+		return count
+	}
+
+	// The stub RNG cycles evenly through 16 values.
+	if got := countNano(16); got != 4 {
+		t.Errorf("at 25%%, %d of 16 IDs were Nano64, want 4", got)
+	}
+	if err := s.SetPercent(0); err != nil {
+		t.Fatalf("SetPercent() error = %v", err)
+	}
+	if got := countNano(8); got != 0 {
+		t.Errorf("at 0%%, %d IDs were Nano64", got)
+	}
+	now = now.Add(time.Hour)
+	if got := countNano(8); got != 8 {
+		t.Errorf("after cutover, %d of 8 IDs were Nano64", got)
+	}
+
+	tests := []struct {
+		in      string
+		legacy  bool
+		wantErr bool
+	}{
+		{"1234567890123456", true, false}, // 16 digits: legacy, not undashed hex
+		{"0000000000A-BCDEF", false, false},
+		{"0000000000a-bcdef", false, false},
+		{"xyz", false, true},
+	}
+	for _, tt := range tests {
+		got, err := s.Parse(tt.in)
+		if (err != nil) != tt.wantErr || (err == nil && got.IsLegacy() != tt.legacy) {
+			t.Errorf("Parse(%q) = %+v, %v", tt.in, got, err)
+		}
+	}
+
+	if err := s.SetPercent(101); err == nil {
+		t.Error("SetPercent() accepted 101")
+	}
+	if _, err := NewSwitchover(SwitchoverOptions{}); err == nil {
+		t.Error("NewSwitchover() accepted a missing legacy format")
+	}
+}
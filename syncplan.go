@@ -0,0 +1,72 @@
+package nano64
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SyncPlanner works out which ID ranges two replicas must exchange to reconcile,
+// from the digests of the local MerkleTree and digests received from the remote
+// replica. Both trees must have the same depth.
+//
+// Replicas that can ship all leaf digests at once call Plan. Otherwise they
+// negotiate top-down: start by sending the root, and on each round send the
+// digests of the nodes Compare returned, until no nodes are left.
+type SyncPlanner struct {
+	local *MerkleTree
+}
+
+// NewSyncPlanner creates a planner for the local tree.
+func NewSyncPlanner(local *MerkleTree) *SyncPlanner {
+	return &SyncPlanner{local: local}
+}
+
+// Plan compares every leaf digest of the remote tree with the local tree and
+// returns the differing buckets as the fewest ranges covering them, in ascending
+// order. Leaves missing from remote are empty there.
+func (p *SyncPlanner) Plan(remoteLeaves map[MerkleNode]MerkleDigest) ([]IDRange, error) {
+	var diff RangeSet
+	for node, digest := range remoteLeaves {
+		if node.Level != p.local.depth {
+			return nil, fmt.Errorf("remote leaf at level %d, want %d", node.Level, p.local.depth)
+		}
+		if p.local.Digest(node) != digest {
+			diff.Add(node.Range())
+		}
+	}
+	for node, digest := range p.local.Leaves() {
+		if _, ok := remoteLeaves[node]; !ok && digest != (MerkleDigest{}) {
+			diff.Add(node.Range())
+		}
+	}
+	return diff.ranges, nil
+}
+
+// Compare runs one round of top-down negotiation. For every remote node digest
+// that differs from the local one it either descends, returning the node's
+// children for the next round, or, at leaf level or when a side is empty there,
+// settles on exchanging the node's whole range. Ranges are merged and sorted.
+func (p *SyncPlanner) Compare(remote map[MerkleNode]MerkleDigest) (next []MerkleNode, ranges []IDRange, err error) {
+	var diff RangeSet
+	for node, digest := range remote {
+		if node.Level > p.local.depth {
+			return nil, nil, fmt.Errorf("remote node at level %d below leaf level %d", node.Level, p.local.depth)
+		}
+		local := p.local.Digest(node)
+		if local == digest {
+			continue
+		}
+		// Nothing to narrow down when one side holds no entries: ship the range.
+		if node.Level == p.local.depth || local == (MerkleDigest{}) || digest == (MerkleDigest{}) {
+			diff.Add(node.Range())
+			continue
+		}
+		left, right := node.Children()
+		next = append(next, left, right)
+	}
+
+	slices.SortFunc(next, func(a, b MerkleNode) int {
+		return Compare(a.Range().First, b.Range().First)
+	})
+	return next, diff.ranges, nil
+}
@@ -0,0 +1,92 @@
+package nano64
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func syncTrees(t *testing.T) (*MerkleTree, *MerkleTree, []Nano64) {
+	t.Helper()
+	local, _ := NewMerkleTree(12)
+	remote, _ := NewMerkleTree(12)
+
+	for ts := int64(0); ts < 64; ts++ {
+		id := mustGenerate(t, ts<<32, 1)
+		local.Add(id, nil)
+		remote.Add(id, nil)
+	}
+
+	// Differences: one ID only local, one with a different version, one only remote.
+	onlyLocal := mustGenerate(t, 10<<32, 2)
+	changed := mustGenerate(t, 11<<32, 1)
+	onlyRemote := mustGenerate(t, 40<<32, 2)
+	local.Add(onlyLocal, nil)
+	remote.Remove(changed, nil)
+	remote.Add(changed, []byte("v2"))
+	remote.Add(onlyRemote, nil)
+
+	return local, remote, []Nano64{onlyLocal, changed, onlyRemote}
+}
+
+func TestSyncPlannerPlan(t *testing.T) {
+	local, remote, diffs := syncTrees(t)
+
+	ranges, err := NewSyncPlanner(local).Plan(maps.Collect(remote.Leaves()))
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	assertCovers(t, ranges, diffs)
+
+	// Buckets 10 and 11 are adjacent and merge into one range.
+	if len(ranges) != 2 {
+		t.Errorf("Plan() = %v, want 2 ranges", ranges)
+	}
+
+	if _, err := NewSyncPlanner(local).Plan(map[MerkleNode]MerkleDigest{{Level: 3}: {}}); err == nil {
+		t.Error("Plan() expected error for mismatched depth")
+	}
+}
+
+func TestSyncPlannerNegotiation(t *testing.T) {
+	local, remote, diffs := syncTrees(t)
+	planner := NewSyncPlanner(local)
+
+	var all RangeSet
+	nodes := []MerkleNode{{}}
+	rounds := 0
+	for len(nodes) > 0 {
+		rounds++
+		digests := make(map[MerkleNode]MerkleDigest, len(nodes))
+		for _, n := range nodes {
+			digests[n] = remote.Digest(n)
+		}
+
+		next, ranges, err := planner.Compare(digests)
+		if err != nil {
+			t.Fatalf("Compare() error = %v", err)
+		}
+		for _, r := range ranges {
+			all.Add(r)
+		}
+		nodes = next
+	}
+
+	if rounds != 13 {
+		t.Errorf("negotiation took %d rounds, want depth+1 = 13", rounds)
+	}
+	ranges := slices.Collect(all.Ranges())
+	assertCovers(t, ranges, diffs)
+	if len(ranges) != 2 {
+		t.Errorf("negotiated %v, want 2 ranges", ranges)
+	}
+}
+
+func assertCovers(t *testing.T, ranges []IDRange, ids []Nano64) {
+	t.Helper()
+	for _, id := range ids {
+		if !slices.ContainsFunc(ranges, func(r IDRange) bool { return r.Contains(id) }) {
+			t.Errorf("ranges %v miss differing ID %v", ranges, id)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package nano64
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// TempIDBit is the random-field bit reserved to mark provisional IDs created by
+// clients, e.g. offline-first apps that create entities before the server
+// assigns their final IDs. It is the highest random bit, so it does not collide
+// with TombstoneBit or EmbedShard, but it does collide with the top bits
+// PriorityLayout and ProvenanceRegistry take; claim it in the keyspace's
+// ReservedBits to have such overlaps rejected.
+//
+// The bit only means something in a keyspace that reserves it: every final ID
+// must be generated with FinalIDRNG, or about half of them read as provisional.
+// IDs generated before the keyspace adopted the scheme cannot be told apart.
+const TempIDBit uint64 = 1 << (RandomBits - 1)
+
+// GenerateTempID generates a provisional ID stamped with DefaultClock.
+func GenerateTempID() (Nano64, error) {
+	return Generate(DefaultClock(), TempIDRNG(nil))
+}
+
+// IsTempID reports whether id has TempIDBit set.
+func IsTempID(id Nano64) bool {
+	return id.value&TempIDBit != 0
+}
+
+// TempIDRNG wraps rng (DefaultRNG if nil) so that generated IDs have TempIDBit
+// set, for clients generating provisional IDs with their own Generator.
+func TempIDRNG(rng RNG) RNG {
+	if rng == nil {
+		rng = DefaultRNG
+	}
+	return func(bits int) (uint32, error) {
+		v, err := rng(bits)
+		return v | uint32(TempIDBit), err
+	}
+}
+
+// FinalIDRNG wraps rng (DefaultRNG if nil) so that generated IDs keep TempIDBit
+// clear, for servers assigning final IDs. This halves the random space per
+// millisecond. Monotonic generation increments the random field and is
+// therefore not compatible with the reservation.
+func FinalIDRNG(rng RNG) RNG {
+	if rng == nil {
+		rng = DefaultRNG
+	}
+	return func(bits int) (uint32, error) {
+		v, err := rng(bits)
+		return v &^ uint32(TempIDBit), err
+	}
+}
+
+// TempIDMapping pairs a provisional ID with the final ID the server assigned.
+// Servers return these to clients, which rewrite references they hold.
+type TempIDMapping struct {
+	Temp Nano64 `json:"temp"`
+	ID   Nano64 `json:"id"`
+}
+
+// TempIDMap records the final IDs of provisional ones while a batch of client
+// changes is applied, so references between the new entities can be rewritten.
+// In JSON it is an array of TempIDMapping ordered by provisional ID.
+// The zero value is empty. A TempIDMap is safe for concurrent use.
+type TempIDMap struct {
+	mu sync.Mutex
+	m  map[Nano64]Nano64
+}
+
+// Reconcile records that the provisional ID temp became server. It fails if
+// temp is not provisional, server is provisional or nil, or temp was already
+// mapped elsewhere.
+func (t *TempIDMap) Reconcile(temp, server Nano64) error {
+	if !IsTempID(temp) {
+		return fmt.Errorf("%s is not a provisional ID", temp.ToHex())
+	}
+	if server.IsNil() {
+		return fmt.Errorf("cannot reconcile %s to the nil ID", temp.ToHex())
+	}
+	if IsTempID(server) {
+		return fmt.Errorf("final ID %s has the provisional bit set", server.ToHex())
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if existing, ok := t.m[temp]; ok && existing != server {
+		return fmt.Errorf("provisional ID %s is already mapped to %s", temp.ToHex(), existing.ToHex())
+	}
+	if t.m == nil {
+		t.m = make(map[Nano64]Nano64)
+	}
+	t.m[temp] = server
+	return nil
+}
+
+// Resolve returns the final ID of id if it is a reconciled provisional ID, and
+// id otherwise. The second result is false for provisional IDs not reconciled.
+func (t *TempIDMap) Resolve(id Nano64) (Nano64, bool) {
+	if !IsTempID(id) {
+		return id, true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	server, ok := t.m[id]
+	if !ok {
+		return id, false
+	}
+	return server, true
+}
+
+// Rewrite replaces every reconciled provisional ID in ids with its final ID and
+// returns the number of provisional IDs left unresolved.
+func (t *TempIDMap) Rewrite(ids []Nano64) int {
+	unresolved := 0
+	for i, id := range ids {
+		resolved, ok := t.Resolve(id)
+		if !ok {
+			unresolved++
+		}
+		ids[i] = resolved
+	}
+	return unresolved
+}
+
+// Mappings returns the recorded mappings ordered by provisional ID.
+func (t *TempIDMap) Mappings() []TempIDMapping {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	mappings := make([]TempIDMapping, 0, len(t.m))
+	for temp, server := range t.m {
+		mappings = append(mappings, TempIDMapping{Temp: temp, ID: server})
+	}
+	slices.SortFunc(mappings, func(a, b TempIDMapping) int { return Compare(a.Temp, b.Temp) })
+	return mappings
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t *TempIDMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Mappings())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, validating each mapping as
+// Reconcile does.
+func (t *TempIDMap) UnmarshalJSON(data []byte) error {
+	var mappings []TempIDMapping
+	if err := json.Unmarshal(data, &mappings); err != nil {
+		return err
+	}
+	var parsed TempIDMap
+	for _, m := range mappings {
+		if err := parsed.Reconcile(m.Temp, m.ID); err != nil {
+			return err
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.m = parsed.m
+	return nil
+}
@@ -0,0 +1,85 @@
+package nano64
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTempID(t *testing.T) {
+	temp, err := GenerateTempID()
+	if err != nil {
+		t.Fatalf("GenerateTempID() error = %v", err)
+	}
+	if !IsTempID(temp) {
+		t.Errorf("GenerateTempID() = %s is not provisional", temp.ToHex())
+	}
+	ones := func(int) (uint32, error) { return uint32(randomMask), nil }
+	final, err := Generate(1_700_000_000_000, FinalIDRNG(ones))
+	if err != nil || IsTempID(final) {
+		t.Errorf("FinalIDRNG() generated %s, %v, want the bit clear", final.ToHex(), err)
+	}
+	var reserved ReservedBits
+	if err := reserved.Reserve("temp", TempIDBit); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if err := reserved.Reserve("tombstone", TombstoneBit); err != nil {
+		t.Errorf("Reserve() of TombstoneBit error = %v", err)
+	}
+	priority, err := NewPriorityLayout(1)
+	if err != nil {
+		t.Fatalf("NewPriorityLayout() error = %v", err)
+	}
+	if err := reserved.Reserve("priority", priority.Mask()); err == nil {
+		t.Error("Reserve() accepted a priority layout overlapping TempIDBit")
+	}
+
+	var m TempIDMap
+	other, _ := GenerateTempID()
+	if err := m.Reconcile(temp, final); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := m.Reconcile(temp, final); err != nil {
+		t.Errorf("repeated Reconcile() error = %v", err)
+	}
+	tests := []struct {
+		name         string
+		temp, server Nano64
+	}{
+		{"final as temp", final, final},
+		{"temp as final", other, temp},
+		{"nil final", other, Nil},
+		{"remapped", temp, Nano64{value: final.value + 2}},
+	}
+	for _, tt := range tests {
+		if err := m.Reconcile(tt.temp, tt.server); err == nil {
+			t.Errorf("Reconcile() %s error = nil", tt.name)
+		}
+	}
+
+	ids := []Nano64{temp, final, other}
+	if n := m.Rewrite(ids); n != 1 {
+		t.Errorf("Rewrite() = %d unresolved, want 1", n)
+	}
+	if ids[0] != final || ids[1] != final || ids[2] != other {
+		t.Errorf("Rewrite() = %v", ids)
+	}
+
+	data, err := json.Marshal(&m)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `[{"temp":"` + temp.ToHex() + `","id":"` + final.ToHex() + `"}]`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+	var decoded TempIDMap
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if id, ok := decoded.Resolve(temp); !ok || id != final {
+		t.Errorf("Resolve() after a round trip = %s, %v", id.ToHex(), ok)
+	}
+	bad := `[{"temp":"` + final.ToHex() + `","id":"` + final.ToHex() + `"}]`
+	if err := json.Unmarshal([]byte(bad), &decoded); err == nil {
+		t.Error("Unmarshal() accepted a mapping from a final ID")
+	}
+}
@@ -0,0 +1,93 @@
+package nano64
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"sync/atomic"
+)
+
+// TenantKeySize is the length of the keys accepted by TenantRNG.
+const TenantKeySize = 16
+
+// TenantRNG returns an RNG that derives random fields from a per-tenant key: each
+// call returns SipHash-2-4 of an incrementing 64-bit counter under key, truncated to
+// the requested bits. The counter starts at a value read from the entropy source,
+// so restarted processes do not replay earlier sequences.
+//
+// Give every tenant its own generator and key. IDs of different tenants are then
+// unlinkable even if one tenant's key and counter leak, because nothing about the
+// outputs under one key reveals the outputs under another. The RNG costs a single
+// entropy read at creation, which makes it a cheap replacement for DefaultRNG on
+// hot paths. It is safe for concurrent use.
+func TenantRNG(key []byte) (RNG, error) {
+	if len(key) != TenantKeySize {
+		return nil, fmt.Errorf("tenant key must be %d bytes, got %d", TenantKeySize, len(key))
+	}
+
+	var seed [8]byte
+	if err := readEntropy(seed[:]); err != nil {
+		return nil, fmt.Errorf("failed to seed tenant RNG: %w", err)
+	}
+	return newTenantRNG(key, binary.LittleEndian.Uint64(seed[:])), nil
+}
+
+// newTenantRNG returns the keyed RNG with its counter starting at start.
+func newTenantRNG(key []byte, start uint64) RNG {
+	k0 := binary.LittleEndian.Uint64(key[:8])
+	k1 := binary.LittleEndian.Uint64(key[8:])
+
+	var counter atomic.Uint64
+	counter.Store(start - 1)
+	return func(n int) (uint32, error) {
+		if n <= 0 || n > 32 {
+			return 0, fmt.Errorf("bits must be 1-32, got %d", n)
+		}
+		v := sipHash64(k0, k1, counter.Add(1))
+		return uint32(v >> (64 - n)), nil
+	}
+}
+
+// sipHash64 computes SipHash-2-4 of the 8-byte little-endian encoding of m.
+func sipHash64(k0, k1, m uint64) uint64 {
+	v0 := k0 ^ 0x736f6d6570736575
+	v1 := k1 ^ 0x646f72616e646f6d
+	v2 := k0 ^ 0x6c7967656e657261
+	v3 := k1 ^ 0x7465646279746573
+
+	round := func() {
+		v0 += v1
+		v1 = bits.RotateLeft64(v1, 13)
+		v1 ^= v0
+		v0 = bits.RotateLeft64(v0, 32)
+		v2 += v3
+		v3 = bits.RotateLeft64(v3, 16)
+		v3 ^= v2
+		v0 += v3
+		v3 = bits.RotateLeft64(v3, 21)
+		v3 ^= v0
+		v2 += v1
+		v1 = bits.RotateLeft64(v1, 17)
+		v1 ^= v2
+		v2 = bits.RotateLeft64(v2, 32)
+	}
+
+	v3 ^= m
+	round()
+	round()
+	v0 ^= m
+
+	// Final block: no remaining bytes, message length 8 in the top byte.
+	b := uint64(8) << 56
+	v3 ^= b
+	round()
+	round()
+	v0 ^= b
+
+	v2 ^= 0xff
+	round()
+	round()
+	round()
+	round()
+	return v0 ^ v1 ^ v2 ^ v3
+}
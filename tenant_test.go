@@ -0,0 +1,66 @@
+package nano64
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSipHash64(t *testing.T) {
+	// Reference vector from the SipHash paper: key 00..0f, message 00..07.
+	got := sipHash64(0x0706050403020100, 0x0f0e0d0c0b0a0908, 0x0706050403020100)
+	if want := uint64(0x93f5f5799a932462); got != want {
+		t.Errorf("sipHash64() = %#x, want %#x", got, want)
+	}
+}
+
+func TestTenantRNG(t *testing.T) {
+	keyA := []byte("tenant-a-key-16b")
+	keyB := []byte("tenant-b-key-16b")
+
+	// Same key and counter give the same sequence; another key gives another.
+	a1, a2, b := newTenantRNG(keyA, 42), newTenantRNG(keyA, 42), newTenantRNG(keyB, 42)
+	same, differ := 0, 0
+	for range 100 {
+		x, _ := a1(RandomBits)
+		y, _ := a2(RandomBits)
+		z, err := b(RandomBits)
+		if err != nil {
+			t.Fatalf("rng() error = %v", err)
+		}
+		if x >= 1<<RandomBits {
+			t.Fatalf("rng(%d) = %d, exceeds the requested bits", RandomBits, x)
+		}
+		if x == y {
+			same++
+		}
+		if x != z {
+			differ++
+		}
+	}
+	if same != 100 {
+		t.Errorf("equal keys and counters agreed on %d of 100 values", same)
+	}
+	if differ < 99 {
+		t.Errorf("different keys agreed on %d of 100 values", 100-differ)
+	}
+
+	rng, err := TenantRNG(keyA)
+	if err != nil {
+		t.Fatalf("TenantRNG() error = %v", err)
+	}
+	if _, err := Generate(1234567890123, rng); err != nil {
+		t.Errorf("Generate() error = %v", err)
+	}
+	if _, err := rng(0); err == nil {
+		t.Error("rng(0) succeeded, want error")
+	}
+	if _, err := TenantRNG(keyA[:8]); err == nil {
+		t.Error("TenantRNG() accepted a short key")
+	}
+
+	SetEntropySource(func([]byte) error { return errors.New("offline") })
+	defer SetEntropySource(testEntropy)
+	if _, err := TenantRNG(keyA); err == nil {
+		t.Error("TenantRNG() succeeded without entropy")
+	}
+}
@@ -0,0 +1,61 @@
+package nano64
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// tenantKeyLength is the length of a TenantKey: the tenant ID then the entity ID.
+const tenantKeyLength = 16
+
+// TenantKey returns the key-value store key of id within tenant: the 8 big-endian
+// bytes of tenant followed by those of id. Ordered stores such as FoundationDB,
+// Badger or Pebble then keep each tenant's keys together, in creation order, so
+// a tenant's data is scanned with TenantPrefix or TenantRange.
+//
+// The key is 16 bytes. Append a suffix to store several values per entity; they
+// stay within the entity's key range.
+func TenantKey(tenant, id Nano64) []byte {
+	key := make([]byte, tenantKeyLength)
+	binary.BigEndian.PutUint64(key, tenant.value)
+	binary.BigEndian.PutUint64(key[8:], id.value)
+	return key
+}
+
+// ParseTenantKey splits a key produced by TenantKey, optionally followed by a
+// suffix, into the tenant, the ID and the suffix.
+func ParseTenantKey(key []byte) (tenant, id Nano64, suffix []byte, err error) {
+	if len(key) < tenantKeyLength {
+		return Nil, Nil, nil, fmt.Errorf("tenant key must be at least %d bytes, got %d", tenantKeyLength, len(key))
+	}
+	tenant = Nano64{value: binary.BigEndian.Uint64(key)}
+	id = Nano64{value: binary.BigEndian.Uint64(key[8:])}
+	return tenant, id, key[tenantKeyLength:], nil
+}
+
+// TenantPrefix returns the prefix shared by every key of tenant, for prefix
+// iteration (Badger's IteratorOptions.Prefix, FoundationDB's PrefixRange).
+func TenantPrefix(tenant Nano64) []byte {
+	prefix := make([]byte, 8)
+	binary.BigEndian.PutUint64(prefix, tenant.value)
+	return prefix
+}
+
+// TenantRange returns the half-open key range [start, end) of tenant's keys whose
+// ID lies in r, including any suffixed keys, e.g. a tenant's entities created in
+// a time window (see RangeForTime). end is nil when the range extends to the end
+// of the keyspace.
+func TenantRange(tenant Nano64, r IDRange) (start, end []byte, err error) {
+	if Compare(r.First, r.Last) > 0 {
+		return nil, nil, fmt.Errorf("range first %s is after last %s", r.First.ToHex(), r.Last.ToHex())
+	}
+	start = TenantKey(tenant, r.First)
+	switch {
+	case r.Last.value < math.MaxUint64:
+		end = TenantKey(tenant, Nano64{value: r.Last.value + 1})
+	case tenant.value < math.MaxUint64:
+		end = TenantPrefix(Nano64{value: tenant.value + 1})
+	}
+	return start, end, nil
+}
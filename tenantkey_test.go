@@ -0,0 +1,75 @@
+package nano64
+
+import (
+	"bytes"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTenantKey(t *testing.T) {
+	tenantA, tenantB := mustGenerate(t, 1000, 1), mustGenerate(t, 1000, 2)
+	var keys [][]byte
+	for _, tenant := range []Nano64{tenantB, tenantA} {
+		for _, ts := range []int64{5000, 3000, 4000} {
+			id := mustGenerate(t, ts, 7)
+			keys = append(keys, TenantKey(tenant, id), append(TenantKey(tenant, id), "name"...))
+		}
+	}
+	slices.SortFunc(keys, bytes.Compare)
+
+	// Sorted keys group by tenant, then by creation time.
+	var prevTenant, prevID Nano64
+	for i, key := range keys {
+		tenant, id, suffix, err := ParseTenantKey(key)
+		if err != nil {
+			t.Fatalf("ParseTenantKey() error = %v", err)
+		}
+		if i%2 == 1 && string(suffix) != "name" {
+			t.Errorf("key %d suffix = %q, want \"name\"", i, suffix)
+		}
+		if i > 0 && (Compare(tenant, prevTenant) < 0 || (tenant == prevTenant && id.GetTimestamp() < prevID.GetTimestamp())) {
+			t.Errorf("key %d (%s, %s) sorts after (%s, %s)", i, tenant.ToHex(), id.ToHex(), prevTenant.ToHex(), prevID.ToHex())
+		}
+		prevTenant, prevID = tenant, id
+	}
+
+	if _, _, _, err := ParseTenantKey(TenantPrefix(tenantA)); err == nil {
+		t.Error("ParseTenantKey() accepted a prefix")
+	}
+
+	tests := []struct {
+		name  string
+		r     IDRange
+		count int
+	}{
+		{"one millisecond", RangeForTime(time.UnixMilli(4000), time.UnixMilli(4001)), 2},
+		{"window", RangeForTime(time.UnixMilli(4000), time.UnixMilli(6000)), 4},
+		{"everything", IDRange{First: Nil, Last: Nano64{value: math.MaxUint64}}, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := TenantRange(tenantA, tt.r)
+			if err != nil {
+				t.Fatalf("TenantRange() error = %v", err)
+			}
+			count := 0
+			for _, key := range keys {
+				if bytes.Compare(key, start) >= 0 && (end == nil || bytes.Compare(key, end) < 0) {
+					if !bytes.HasPrefix(key, TenantPrefix(tenantA)) {
+						t.Errorf("range includes key of another tenant: %x", key)
+					}
+					count++
+				}
+			}
+			if count != tt.count {
+				t.Errorf("range holds %d keys, want %d", count, tt.count)
+			}
+		})
+	}
+
+	if _, end, _ := TenantRange(Nano64{value: math.MaxUint64}, IDRange{First: Nil, Last: Nano64{value: math.MaxUint64}}); end != nil {
+		t.Errorf("TenantRange(max tenant, all IDs) end = %x, want nil", end)
+	}
+}
@@ -0,0 +1,39 @@
+package nano64
+
+// TombstoneBit is the random-field bit reserved to mark tombstones. It is the lowest
+// bit, so a tombstone sorts immediately after the ID it deletes.
+const TombstoneBit uint64 = 1
+
+// TombstoneFor returns the deterministic tombstone companion of id: the same ID with
+// TombstoneBit set. Append-only stores record a deletion by inserting this ID, which
+// lands right next to the original row in ID order.
+//
+// The scheme requires live IDs to keep TombstoneBit clear; generate them with an RNG
+// wrapped by TombstoneRNG. Monotonic generation increments the random field and is
+// therefore not compatible with the reservation. The bit is also the lowest shard
+// bit of EmbedShard; claim TombstoneBit in a ReservedBits to catch such overlaps.
+func TombstoneFor(id Nano64) Nano64 {
+	return Nano64{value: id.value | TombstoneBit}
+}
+
+// IsTombstone reports whether id has TombstoneBit set.
+func IsTombstone(id Nano64) bool {
+	return id.value&TombstoneBit != 0
+}
+
+// OriginalOf returns the live ID a tombstone refers to. For a live ID it returns the ID itself.
+func OriginalOf(id Nano64) Nano64 {
+	return Nano64{value: id.value &^ TombstoneBit}
+}
+
+// TombstoneRNG wraps rng (DefaultRNG if nil) so that generated IDs keep TombstoneBit
+// clear. This halves the random space per millisecond.
+func TombstoneRNG(rng RNG) RNG {
+	if rng == nil {
+		rng = DefaultRNG
+	}
+	return func(bits int) (uint32, error) {
+		v, err := rng(bits)
+		return v &^ uint32(TombstoneBit), err
+	}
+}
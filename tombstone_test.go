@@ -0,0 +1,27 @@
+package nano64
+
+import "testing"
+
+func TestTombstone(t *testing.T) {
+	id, err := Generate(1234567890123, TombstoneRNG(nil))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if IsTombstone(id) {
+		t.Fatalf("generated ID %s has the tombstone bit set", id.ToHex())
+	}
+
+	tomb := TombstoneFor(id)
+	if !IsTombstone(tomb) {
+		t.Errorf("IsTombstone(%s) = false", tomb.ToHex())
+	}
+	if !OriginalOf(tomb).Equals(id) {
+		t.Errorf("OriginalOf() = %s, want %s", OriginalOf(tomb).ToHex(), id.ToHex())
+	}
+	if !TombstoneFor(tomb).Equals(tomb) {
+		t.Error("TombstoneFor() is not idempotent")
+	}
+	if tomb.Uint64Value() != id.Uint64Value()+1 {
+		t.Errorf("tombstone %s does not sort directly after %s", tomb.ToHex(), id.ToHex())
+	}
+}
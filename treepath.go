@@ -0,0 +1,111 @@
+package nano64
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// pathSegmentLength is the width of one ID in a materialized path: the
+	// canonical hex form and a slash.
+	pathSegmentLength = hexLength + 1
+
+	// DefaultMaxPathDepth is the depth limit of materialized paths when none is given.
+	DefaultMaxPathDepth = 32
+)
+
+// ErrPathTooDeep is returned when a materialized path would exceed its depth limit.
+var ErrPathTooDeep = errors.New("materialized path too deep")
+
+// EncodePath returns the materialized path of a node given the IDs from the
+// root down to the node itself: each ID in canonical hex followed by a slash,
+// e.g. "018F1C2B3D4-5E6F7/018F1C2B3D5-00A1B/". Every segment has the same width,
+// so a node's path is a prefix of the paths of all its descendants and subtrees
+// are selected with a prefix match (see Dialect.SubtreeCondition). maxDepth
+// limits the number of IDs; zero means DefaultMaxPathDepth.
+func EncodePath(ids []Nano64, maxDepth int) (string, error) {
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxPathDepth
+	}
+	if len(ids) == 0 {
+		return "", fmt.Errorf("materialized path needs at least one ID")
+	}
+	if len(ids) > maxDepth {
+		return "", fmt.Errorf("%w: %d IDs, limit %d", ErrPathTooDeep, len(ids), maxDepth)
+	}
+	var b strings.Builder
+	b.Grow(len(ids) * pathSegmentLength)
+	for i, id := range ids {
+		if id.IsNil() {
+			return "", fmt.Errorf("materialized path ID %d is Nil", i)
+		}
+		b.WriteString(id.ToHex())
+		b.WriteByte('/')
+	}
+	return b.String(), nil
+}
+
+// ChildPath returns the path of id as a child of the node at parentPath, or a
+// root path if parentPath is empty.
+func ChildPath(parentPath string, id Nano64, maxDepth int) (string, error) {
+	ids, err := DecodePath(parentPath)
+	if err != nil && parentPath != "" {
+		return "", err
+	}
+	return EncodePath(append(ids, id), maxDepth)
+}
+
+// DecodePath returns the IDs of a path produced by EncodePath, root first.
+func DecodePath(path string) ([]Nano64, error) {
+	if path == "" || len(path)%pathSegmentLength != 0 {
+		return nil, fmt.Errorf("materialized path length must be a positive multiple of %d, got %d", pathSegmentLength, len(path))
+	}
+	ids := make([]Nano64, 0, len(path)/pathSegmentLength)
+	for i := 0; i < len(path); i += pathSegmentLength {
+		segment := path[i : i+hexLength]
+		id, err := FromHex(segment)
+		if err != nil || path[i+hexLength] != '/' || id.ToHex() != segment || id.IsNil() {
+			return nil, fmt.Errorf("invalid materialized path segment %d: %q", len(ids), path[i:i+pathSegmentLength])
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// PathDepth returns the number of IDs in a well-formed path.
+func PathDepth(path string) int {
+	return len(path) / pathSegmentLength
+}
+
+// AncestorPaths returns the paths of the ancestors of the node at path, root
+// first, for an IN (...) query fetching them all at once.
+func AncestorPaths(path string) ([]string, error) {
+	if _, err := DecodePath(path); err != nil {
+		return nil, err
+	}
+	ancestors := make([]string, 0, PathDepth(path)-1)
+	for end := pathSegmentLength; end < len(path); end += pathSegmentLength {
+		ancestors = append(ancestors, path[:end])
+	}
+	return ancestors, nil
+}
+
+// SubtreeCondition returns a predicate selecting the rows whose path column
+// lies in the subtree of the node at path, such as `"path" LIKE 'A/B/%'`, with
+// or without the node itself. Paths hold no LIKE wildcards, so the pattern is a
+// plain prefix an index on the column can serve, collation permitting; in
+// PostgreSQL, build the index with text_pattern_ops.
+func (d Dialect) SubtreeCondition(column, path string, includeSelf bool) (string, error) {
+	if err := d.validate(); err != nil {
+		return "", err
+	}
+	if _, err := DecodePath(path); err != nil {
+		return "", err
+	}
+	pattern := path + "%"
+	if !includeSelf {
+		pattern = path + "_%"
+	}
+	return d.QuoteIdent(column) + " LIKE '" + pattern + "'", nil
+}
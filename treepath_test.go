@@ -0,0 +1,102 @@
+package nano64
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestTreePath(t *testing.T) {
+	a, b, c := mustGenerate(t, 1000, 1), mustGenerate(t, 2000, 2), mustGenerate(t, 3000, 3)
+
+	path, err := EncodePath([]Nano64{a, b, c}, 0)
+	if err != nil {
+		t.Fatalf("EncodePath() error = %v", err)
+	}
+	if want := a.ToHex() + "/" + b.ToHex() + "/" + c.ToHex() + "/"; path != want || PathDepth(path) != 3 {
+		t.Errorf("EncodePath() = %q, want %q", path, want)
+	}
+	ids, err := DecodePath(path)
+	if err != nil || !slices.Equal(ids, []Nano64{a, b, c}) {
+		t.Errorf("DecodePath() = %v, %v", ids, err)
+	}
+	ancestors, err := AncestorPaths(path)
+	if err != nil || !slices.Equal(ancestors, []string{a.ToHex() + "/", a.ToHex() + "/" + b.ToHex() + "/"}) {
+		t.Errorf("AncestorPaths() = %v, %v", ancestors, err)
+	}
+	if child, err := ChildPath(ancestors[1], c, 0); err != nil || child != path {
+		t.Errorf("ChildPath() = %q, %v, want %q", child, err, path)
+	}
+	if root, err := ChildPath("", a, 0); err != nil || root != ancestors[0] {
+		t.Errorf("ChildPath(root) = %q, %v", root, err)
+	}
+
+	if _, err := ChildPath(path, a, 3); !errors.Is(err, ErrPathTooDeep) {
+		t.Errorf("ChildPath() past the limit error = %v, want ErrPathTooDeep", err)
+	}
+	for _, bad := range []string{"", path[:20], strings.ToLower(path), strings.Replace(path, "/", "|", 1), Nil.ToHex() + "/"} {
+		if _, err := DecodePath(bad); err == nil {
+			t.Errorf("DecodePath(%q) succeeded", bad)
+		}
+	}
+	if _, err := EncodePath([]Nano64{a, Nil}, 0); err == nil {
+		t.Error("EncodePath() accepted a Nil ID")
+	}
+}
+
+func TestSubtreeCondition(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE nodes (path TEXT PRIMARY KEY)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	// root -> (left -> leaf, right)
+	root, left, right, leaf := mustGenerate(t, 1000, 1), mustGenerate(t, 2000, 2), mustGenerate(t, 3000, 3), mustGenerate(t, 4000, 4)
+	paths := map[Nano64]string{}
+	for _, n := range []struct{ id, parent Nano64 }{{root, Nil}, {left, root}, {right, root}, {leaf, left}} {
+		path, err := ChildPath(paths[n.parent], n.id, 0)
+		if err != nil {
+			t.Fatalf("ChildPath() error = %v", err)
+		}
+		paths[n.id] = path
+		if _, err := db.Exec("INSERT INTO nodes (path) VALUES (?)", path); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		node        Nano64
+		includeSelf bool
+		want        int
+	}{
+		{root, true, 4},
+		{root, false, 3},
+		{left, true, 2},
+		{left, false, 1},
+		{right, false, 0},
+	}
+	for _, tt := range tests {
+		cond, err := DialectSQLite.SubtreeCondition("path", paths[tt.node], tt.includeSelf)
+		if err != nil {
+			t.Fatalf("SubtreeCondition() error = %v", err)
+		}
+		var n int
+		if err := db.QueryRow("SELECT count(*) FROM nodes WHERE " + cond).Scan(&n); err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if n != tt.want {
+			t.Errorf("%s selects %d rows, want %d", cond, n, tt.want)
+		}
+	}
+	if _, err := DialectPostgres.SubtreeCondition("path", "x'; DROP TABLE nodes; --", true); err == nil {
+		t.Error("SubtreeCondition() accepted an invalid path")
+	}
+}
@@ -0,0 +1,83 @@
+package nano64
+
+import (
+	"math"
+	"time"
+)
+
+// Strategy describes how To32 reduces a 64-bit ID to 32 bits. The result holds
+// (32 - RandomBits) bits of timestamp counted in Resolution steps since Epoch,
+// followed by the top RandomBits bits of the random field.
+//
+// Reduced values are far from unique. Within one Resolution step only 2^RandomBits
+// values exist, so two IDs created in the same step collide with probability
+// 1/2^RandomBits, and n IDs in one step collide with the probability returned by
+// CollisionProbability. For example, with 8 random bits the chance of any collision
+// already reaches 1% at 3 IDs per step and 50% at 20 IDs per step. Use the reduced
+// form only for legacy 32-bit key columns where rough time ordering is enough and
+// collisions are detected by the storage layer.
+type Strategy struct {
+	// Resolution is the width of one timestamp step. Zero or negative means one millisecond.
+	Resolution time.Duration
+
+	// Epoch is the start of the representable window in Unix milliseconds.
+	Epoch int64
+
+	// RandomBits is the number of random bits kept, clamped to 0..20.
+	RandomBits int
+}
+
+// stepMillis returns the resolution in whole milliseconds (at least 1).
+func (s Strategy) stepMillis() int64 {
+	step := s.Resolution.Milliseconds()
+	if step < 1 {
+		return 1
+	}
+	return step
+}
+
+// randomBits returns RandomBits clamped to the range of the random field.
+func (s Strategy) randomBits() int {
+	return min(max(s.RandomBits, 0), RandomBits)
+}
+
+// TimestampBits returns the number of bits left for the timestamp.
+func (s Strategy) TimestampBits() int {
+	return 32 - s.randomBits()
+}
+
+// Horizon returns the last instant representable without saturation.
+func (s Strategy) Horizon() time.Time {
+	steps := int64(1)<<s.TimestampBits() - 1
+	return time.UnixMilli(s.Epoch + steps*s.stepMillis())
+}
+
+// CollisionProbability returns the probability that at least two of n IDs created
+// within the same Resolution step reduce to the same value (birthday bound).
+func (s Strategy) CollisionProbability(n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	space := math.Ldexp(1, s.randomBits())
+	pairs := float64(n) * float64(n-1) / 2
+	return 1 - math.Exp(-pairs/space)
+}
+
+// To32 reduces the ID to 32 bits according to the strategy. The mapping preserves
+// ordering between IDs from different steps inside the window [Epoch, Horizon];
+// timestamps outside the window saturate to the first or last step.
+func (n Nano64) To32(reduction Strategy) uint32 {
+	randomBits := reduction.randomBits()
+	timestampBits := 32 - randomBits
+
+	step := (n.GetTimestamp() - reduction.Epoch) / reduction.stepMillis()
+	if step < 0 {
+		step = 0
+	}
+	if maxStep := int64(1)<<timestampBits - 1; step > maxStep {
+		step = maxStep
+	}
+
+	random := uint64(n.GetRandom()) >> (RandomBits - randomBits)
+	return uint32(uint64(step)<<randomBits | random)
+}
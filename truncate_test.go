@@ -0,0 +1,47 @@
+package nano64
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNano64_To32(t *testing.T) {
+	strategy := Strategy{Resolution: time.Second, Epoch: 1_700_000_000_000, RandomBits: 8}
+
+	early := New(uint64(1_700_000_001_500)<<RandomBits | 0xFFFFF)
+	late := New(uint64(1_700_000_002_000) << RandomBits)
+
+	if got, want := early.To32(strategy), uint32(1<<8|0xFF); got != want {
+		t.Errorf("To32() = %#x, want %#x", got, want)
+	}
+	if early.To32(strategy) >= late.To32(strategy) {
+		t.Errorf("order not preserved: %#x >= %#x", early.To32(strategy), late.To32(strategy))
+	}
+
+	beforeEpoch := New(uint64(1_600_000_000_000) << RandomBits)
+	if got := beforeEpoch.To32(strategy); got != 0 {
+		t.Errorf("To32() before epoch = %#x, want 0", got)
+	}
+
+	beyond := New(uint64(strategy.Horizon().UnixMilli()+10_000) << RandomBits)
+	if got, want := beyond.To32(strategy), uint32(0xFFFFFF00); got != want {
+		t.Errorf("To32() beyond horizon = %#x, want %#x", got, want)
+	}
+}
+
+func TestStrategy_CollisionProbability(t *testing.T) {
+	strategy := Strategy{RandomBits: 8}
+
+	if got := strategy.CollisionProbability(1); got != 0 {
+		t.Errorf("CollisionProbability(1) = %f, want 0", got)
+	}
+	if got := strategy.CollisionProbability(3); got < 0.01 || got > 0.02 {
+		t.Errorf("CollisionProbability(3) = %f, want ~0.0117", got)
+	}
+	if got := strategy.CollisionProbability(20); got < 0.5 {
+		t.Errorf("CollisionProbability(20) = %f, want >= 0.5", got)
+	}
+	if got := strategy.TimestampBits(); got != 24 {
+		t.Errorf("TimestampBits() = %d, want 24", got)
+	}
+}
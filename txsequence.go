@@ -0,0 +1,148 @@
+package nano64
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"sync/atomic"
+)
+
+// ErrSequenceExhausted is returned by TxSequence.Next when the next sub-ID would
+// pass the largest representable ID.
+var ErrSequenceExhausted = errors.New("transaction sequence exhausted")
+
+// TxSequence derives the IDs of the rows a transaction creates from one base ID,
+// so their creation order is recoverable from the IDs alone: the k-th row gets
+// base + k, counting up in the random field. Past the end of the random field
+// the count carries into the timestamp, like the rollover of GenerateMonotonic,
+// so a sequence never runs out within a realistic transaction.
+//
+// Sub-IDs are not drawn from a generator. Draw each base with Generate rather
+// than GenerateMonotonic, whose next ID would be base + 1, and keep transactions
+// short: a sequence of k rows collides with a random ID generated in the same
+// millisecond with probability about k / 2^20.
+//
+// Counting up changes the lowest bits of every sub-ID, which features such as
+// TombstoneBit and EmbedShard reserve. In keyspaces that claim such bits in a
+// ReservedBits, create sequences with NewReservedTxSequence: the count then
+// skips the reserved bits, and every sub-ID keeps the base's values there.
+// Once a sequence carries into the timestamp, its sub-IDs are stamped later
+// than the base. A TxSequence is safe for concurrent use.
+type TxSequence struct {
+	base Nano64
+	// free holds the bits the count runs through: all but the reserved ones.
+	free uint64
+	next atomic.Uint64
+}
+
+// NewTxSequence creates the sequence of a transaction with the given base ID.
+func NewTxSequence(base Nano64) *TxSequence {
+	return &TxSequence{base: base, free: math.MaxUint64}
+}
+
+// NewReservedTxSequence creates the sequence of a transaction in a keyspace that
+// claims the bits of reserved. Sub-IDs count up in the remaining bits and copy
+// the reserved ones from base, so a live base on shard 3 yields live sub-IDs on
+// shard 3. Each reserved bit halves the sub-IDs per millisecond.
+func NewReservedTxSequence(base Nano64, reserved *ReservedBits) *TxSequence {
+	return &TxSequence{base: base, free: ^reserved.Mask()}
+}
+
+// Base returns the base ID, which is also the first sub-ID.
+func (s *TxSequence) Base() Nano64 {
+	return s.base
+}
+
+// Next returns the next sub-ID.
+func (s *TxSequence) Next() (Nano64, error) {
+	return s.At(s.next.Add(1) - 1)
+}
+
+// Len returns the number of sub-IDs issued.
+func (s *TxSequence) Len() uint64 {
+	n := s.next.Load()
+	if limit := s.limit(); n > limit {
+		// Calls past the end failed without issuing a sub-ID.
+		return limit + 1
+	}
+	return n
+}
+
+// At returns the k-th sub-ID, as Next would, without issuing it.
+func (s *TxSequence) At(k uint64) (Nano64, error) {
+	if k > s.limit() {
+		return Nil, ErrSequenceExhausted
+	}
+	return Nano64{value: depositBits(s.count(s.base)+k, s.free) | s.base.value&^s.free}, nil
+}
+
+// Index returns the position of id in the sequence: the order in which the
+// transaction created it. It reports false for IDs below the base and IDs whose
+// reserved bits differ from the base's, which cannot belong to the sequence.
+func (s *TxSequence) Index(id Nano64) (uint64, bool) {
+	if id.value&^s.free != s.base.value&^s.free {
+		return 0, false
+	}
+	c, base := s.count(id), s.count(s.base)
+	if c < base {
+		return 0, false
+	}
+	return c - base, true
+}
+
+// count returns the counter held in the free bits of id.
+func (s *TxSequence) count(id Nano64) uint64 {
+	return extractBits(id.value, s.free)
+}
+
+// limit returns the largest k with a sub-ID.
+func (s *TxSequence) limit() uint64 {
+	return math.MaxUint64>>(64-bits.OnesCount64(s.free)) - s.count(s.base)
+}
+
+// TxSequenceAt returns the k-th sub-ID of the sequence with the given base, as
+// Next would, without issuing it.
+func TxSequenceAt(base Nano64, k uint64) (Nano64, error) {
+	return NewTxSequence(base).At(k)
+}
+
+// TxSequenceIndex returns the position of id in the sequence with the given base:
+// the order in which the transaction created it. It reports false for IDs below
+// the base, which cannot belong to the sequence.
+func TxSequenceIndex(base, id Nano64) (uint64, bool) {
+	return NewTxSequence(base).Index(id)
+}
+
+// extractBits gathers the bits of v selected by mask into the low bits of the
+// result, keeping their order.
+func extractBits(v, mask uint64) uint64 {
+	if mask == math.MaxUint64 {
+		return v
+	}
+	var out uint64
+	for i := 0; mask != 0; i++ {
+		low := mask & -mask
+		if v&low != 0 {
+			out |= 1 << i
+		}
+		mask &^= low
+	}
+	return out
+}
+
+// depositBits spreads the low bits of v over the bits selected by mask, the
+// inverse of extractBits.
+func depositBits(v, mask uint64) uint64 {
+	if mask == math.MaxUint64 {
+		return v
+	}
+	var out uint64
+	for i := 0; mask != 0; i++ {
+		low := mask & -mask
+		if v&(1<<i) != 0 {
+			out |= low
+		}
+		mask &^= low
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package nano64
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestTxSequence(t *testing.T) {
+	base := mustGenerate(t, 5000, uint32(randomMask-2))
+	seq := NewTxSequence(base)
+
+	var ids []Nano64
+	for range 5 {
+		id, err := seq.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if ids[0] != base || seq.Len() != 5 {
+		t.Errorf("first sub-ID %s, Len() %d, want the base and 5", ids[0].ToHex(), seq.Len())
+	}
+	// The count carries from the random field into the timestamp.
+	if ids[2].GetTimestamp() != 5000 || ids[3].GetTimestamp() != 5001 || ids[3].GetRandom() != 0 || ids[4].GetRandom() != 1 {
+		t.Errorf("unexpected carry: %s, %s, %s", ids[2].ToHex(), ids[3].ToHex(), ids[4].ToHex())
+	}
+	for k, id := range ids {
+		if i, ok := TxSequenceIndex(base, id); !ok || i != uint64(k) {
+			t.Errorf("TxSequenceIndex(%s) = %d, %v, want %d", id.ToHex(), i, ok, k)
+		}
+		if at, err := TxSequenceAt(base, uint64(k)); err != nil || at != id {
+			t.Errorf("TxSequenceAt(%d) = %s, %v, want %s", k, at.ToHex(), err, id.ToHex())
+		}
+		if k > 0 && Compare(ids[k-1], id) >= 0 {
+			t.Errorf("sub-ID %d does not sort after its predecessor", k)
+		}
+	}
+	if _, ok := TxSequenceIndex(base, mustGenerate(t, 4999, 0)); ok {
+		t.Error("TxSequenceIndex() accepted an ID below the base")
+	}
+
+	top := NewTxSequence(Nano64{value: math.MaxUint64 - 1})
+	for i := range 3 {
+		_, err := top.Next()
+		if exhausted := errors.Is(err, ErrSequenceExhausted); exhausted != (i == 2) {
+			t.Errorf("Next() %d at the top error = %v", i, err)
+		}
+	}
+	if top.Len() != 2 {
+		t.Errorf("Len() at the top = %d, want 2", top.Len())
+	}
+}
+
+func TestReservedTxSequence(t *testing.T) {
+	var reserved ReservedBits
+	if err := reserved.Reserve("shard", ShardMask(2)); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	live, err := EmbedShard(mustGenerate(t, 5000, uint32(randomMask-5)), 2, 2)
+	if err != nil {
+		t.Fatalf("EmbedShard() error = %v", err)
+	}
+	seq := NewReservedTxSequence(live, &reserved)
+
+	prev := Nil
+	for k := range 5 {
+		id, err := seq.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if ExtractShard(id, 2) != 2 {
+			t.Errorf("sub-ID %d is on shard %d, want 2", k, ExtractShard(id, 2))
+		}
+		if k > 0 && Compare(prev, id) >= 0 {
+			t.Errorf("sub-ID %d does not sort after its predecessor", k)
+		}
+		if i, ok := seq.Index(id); !ok || i != uint64(k) {
+			t.Errorf("Index(%s) = %d, %v, want %d", id.ToHex(), i, ok, k)
+		}
+		prev = id
+	}
+	// The count skips the two shard bits: the third sub-ID carries into the
+	// timestamp.
+	if at, _ := seq.At(2); at.GetTimestamp() != 5001 {
+		t.Errorf("At(2) = %s, want a carry into the timestamp", at.ToHex())
+	}
+	other, _ := EmbedShard(prev, 1, 2)
+	if _, ok := seq.Index(other); ok {
+		t.Error("Index() accepted an ID on another shard")
+	}
+
+	var tombstone ReservedBits
+	if err := tombstone.Reserve("tombstone", TombstoneBit); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	top := NewReservedTxSequence(Nano64{value: math.MaxUint64 - 3}, &tombstone)
+	for i := range 3 {
+		id, err := top.Next()
+		if exhausted := errors.Is(err, ErrSequenceExhausted); exhausted != (i == 2) {
+			t.Errorf("Next() %d at the top error = %v", i, err)
+		}
+		if err == nil && IsTombstone(id) {
+			t.Errorf("Next() %d at the top = %s, a tombstone", i, id.ToHex())
+		}
+	}
+	if top.Len() != 2 {
+		t.Errorf("Len() at the top = %d, want 2", top.Len())
+	}
+}
@@ -0,0 +1,127 @@
+package nano64
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WatermarkOptions configures a Watermark.
+type WatermarkOptions struct {
+	// Load reads the last persisted watermark; Restore calls it. Optional.
+	Load func(ctx context.Context) (Nano64, error)
+
+	// Save persists the watermark; Checkpoint calls it whenever the watermark moved
+	// since the last successful save. Optional.
+	Save func(ctx context.Context, id Nano64) error
+
+	// Clock is used for Lag; DefaultClock if nil.
+	Clock Clock
+}
+
+// Watermark tracks the highest ID an ID-ordered stream consumer has processed.
+// It only moves forward, can be advanced from many goroutines at once, and
+// checkpoints itself through the Load and Save hooks.
+type Watermark struct {
+	current AtomicNano64
+	load    func(ctx context.Context) (Nano64, error)
+	save    func(ctx context.Context, id Nano64) error
+	clock   Clock
+
+	saveMu sync.Mutex
+	saved  Nano64
+}
+
+// NewWatermark creates a Watermark starting at Nil.
+func NewWatermark(opts WatermarkOptions) *Watermark {
+	clock := opts.Clock
+	if clock == nil {
+		clock = DefaultClock
+	}
+	return &Watermark{load: opts.Load, save: opts.Save, clock: clock}
+}
+
+// Restore advances the watermark to the persisted value returned by Load.
+func (w *Watermark) Restore(ctx context.Context) error {
+	if w.load == nil {
+		return nil
+	}
+	id, err := w.load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load watermark: %w", err)
+	}
+
+	w.saveMu.Lock()
+	if Compare(id, w.saved) > 0 {
+		w.saved = id
+	}
+	w.saveMu.Unlock()
+
+	w.current.Max(id)
+	return nil
+}
+
+// Advance moves the watermark to id if id is greater and reports whether it moved.
+func (w *Watermark) Advance(id Nano64) bool {
+	return w.current.Max(id)
+}
+
+// ID returns the current watermark.
+func (w *Watermark) ID() Nano64 {
+	return w.current.Load()
+}
+
+// Time returns the time embedded in the current watermark.
+func (w *Watermark) Time() time.Time {
+	return w.current.Load().ToDate()
+}
+
+// Lag returns how far the watermark trails the clock, i.e. time.Since(w.Time())
+// measured with the configured clock.
+func (w *Watermark) Lag() time.Duration {
+	return time.Duration(w.clock()-w.current.Load().GetTimestamp()) * time.Millisecond
+}
+
+// Checkpoint persists the current watermark with Save unless it is unchanged since
+// the last successful save.
+func (w *Watermark) Checkpoint(ctx context.Context) error {
+	if w.save == nil {
+		return nil
+	}
+
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+
+	id := w.current.Load()
+	if id == w.saved {
+		return nil
+	}
+	if err := w.save(ctx, id); err != nil {
+		return fmt.Errorf("failed to save watermark: %w", err)
+	}
+	w.saved = id
+	return nil
+}
+
+// Run checkpoints every interval until ctx is done, then checkpoints a final
+// time with a fresh context so progress made before shutdown is not lost.
+// It returns the first checkpoint error.
+func (w *Watermark) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("checkpoint interval must be positive, got %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.Checkpoint(ctx); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return w.Checkpoint(context.WithoutCancel(ctx))
+		}
+	}
+}
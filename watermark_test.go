@@ -0,0 +1,107 @@
+package nano64
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWatermarkAdvance(t *testing.T) {
+	const now = 1_700_000_000_000
+	w := NewWatermark(WatermarkOptions{Clock: func() int64 { return now }})
+
+	later := mustGenerate(t, now-1000, 0)
+	earlier := mustGenerate(t, now-5000, 0)
+
+	if !w.Advance(later) {
+		t.Error("Advance() did not move from Nil")
+	}
+	if w.Advance(earlier) {
+		t.Error("Advance() moved backwards")
+	}
+	if w.ID() != later {
+		t.Errorf("ID() = %v, want %v", w.ID(), later)
+	}
+	if got := w.Time(); !got.Equal(time.UnixMilli(now - 1000)) {
+		t.Errorf("Time() = %v", got)
+	}
+	if got := w.Lag(); got != time.Second {
+		t.Errorf("Lag() = %v, want 1s", got)
+	}
+}
+
+func TestWatermarkCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	stored := mustGenerate(t, 1000, 0)
+	var saves []Nano64
+	saveErr := error(nil)
+
+	w := NewWatermark(WatermarkOptions{
+		Load: func(context.Context) (Nano64, error) { return stored, nil },
+		Save: func(_ context.Context, id Nano64) error {
+			if saveErr != nil {
+				return saveErr
+			}
+			saves = append(saves, id)
+			return nil
+		},
+	})
+
+	if err := w.Restore(ctx); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if w.ID() != stored {
+		t.Fatalf("ID() after Restore = %v, want %v", w.ID(), stored)
+	}
+
+	// A restored watermark is already persisted.
+	if err := w.Checkpoint(ctx); err != nil || len(saves) != 0 {
+		t.Fatalf("Checkpoint() = %v with %d saves, want no save", err, len(saves))
+	}
+
+	next := mustGenerate(t, 2000, 0)
+	w.Advance(next)
+	saveErr = errors.New("disk full")
+	if err := w.Checkpoint(ctx); !errors.Is(err, saveErr) {
+		t.Fatalf("Checkpoint() error = %v, want %v", err, saveErr)
+	}
+
+	saveErr = nil
+	if err := w.Checkpoint(ctx); err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+	if err := w.Checkpoint(ctx); err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+	if len(saves) != 1 || saves[0] != next {
+		t.Errorf("saves = %v, want [%v]", saves, next)
+	}
+}
+
+func TestWatermarkRunFinalCheckpoint(t *testing.T) {
+	var saved Nano64
+	w := NewWatermark(WatermarkOptions{
+		Save: func(ctx context.Context, id Nano64) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			saved = id
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx, time.Hour) }()
+
+	id := mustGenerate(t, 1000, 1)
+	w.Advance(id)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if saved != id {
+		t.Errorf("final checkpoint saved %v, want %v", saved, id)
+	}
+}
@@ -0,0 +1,108 @@
+package nano64
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// minWebhookKeyLength is the minimum accepted signing key length.
+const minWebhookKeyLength = 16
+
+// webhookScheme names the signature scheme in the header value and the MAC input.
+const webhookScheme = "v1"
+
+var (
+	// ErrInvalidSignature is returned by VerifyPayload for malformed headers and
+	// signatures that do not match the payload.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrSignatureExpired is returned by VerifyPayload for authentic deliveries
+	// whose ID lies outside the replay window.
+	ErrSignatureExpired = errors.New("webhook signature outside replay window")
+)
+
+// SignPayload returns a signature header value binding body to the delivery ID
+// id, of the form "id=<hex>,v1=<base64url HMAC-SHA256>". Since the ID embeds the
+// send time, the receiver can bound replays without a separate timestamp. The
+// key must be at least 16 bytes.
+func SignPayload(key []byte, id Nano64, body []byte) (string, error) {
+	if len(key) < minWebhookKeyLength {
+		return "", fmt.Errorf("webhook key must be at least %d bytes, got %d", minWebhookKeyLength, len(key))
+	}
+	return "id=" + id.ToHex() + "," + webhookScheme + "=" + base64.RawURLEncoding.EncodeToString(webhookMAC(key, id, body)), nil
+}
+
+// webhookMAC computes the signature of body for id.
+func webhookMAC(key []byte, id Nano64, body []byte) []byte {
+	var prefix [len(webhookScheme) + 8]byte
+	copy(prefix[:], webhookScheme)
+	binary.BigEndian.PutUint64(prefix[len(webhookScheme):], id.value)
+
+	h := hmac.New(sha256.New, key)
+	h.Write(prefix[:])
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// VerifyPayload checks a header produced by SignPayload against body and returns
+// the delivery ID. The ID's timestamp must be within window of DefaultClock in
+// either direction. Several v1 entries may be present, e.g. while the sender
+// rotates keys; one matching is enough.
+//
+// The window bounds how long a captured delivery can be replayed; to reject
+// replays within it, also deduplicate on the returned ID.
+func VerifyPayload(key []byte, header string, body []byte, window time.Duration) (Nano64, error) {
+	return VerifyPayloadAt(key, header, body, window, DefaultClock())
+}
+
+// VerifyPayloadAt is VerifyPayload evaluated at now (epoch ms).
+func VerifyPayloadAt(key []byte, header string, body []byte, window time.Duration, now int64) (Nano64, error) {
+	var id Nano64
+	var hasID bool
+	var signatures [][]byte
+	for _, field := range strings.Split(header, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return Nil, ErrInvalidSignature
+		}
+		switch name {
+		case "id":
+			parsed, err := FromHex(value)
+			if err != nil || hasID {
+				return Nil, ErrInvalidSignature
+			}
+			id, hasID = parsed, true
+		case webhookScheme:
+			sig, err := base64.RawURLEncoding.DecodeString(value)
+			if err != nil {
+				return Nil, ErrInvalidSignature
+			}
+			signatures = append(signatures, sig)
+		}
+	}
+	if !hasID || len(signatures) == 0 {
+		return Nil, ErrInvalidSignature
+	}
+
+	expected := webhookMAC(key, id, body)
+	valid := false
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			valid = true
+		}
+	}
+	if !valid {
+		return Nil, ErrInvalidSignature
+	}
+
+	if skew := now - id.GetTimestamp(); skew > window.Milliseconds() || -skew > window.Milliseconds() {
+		return id, fmt.Errorf("%w: sent %v from now", ErrSignatureExpired, time.Duration(skew)*time.Millisecond)
+	}
+	return id, nil
+}
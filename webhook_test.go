@@ -0,0 +1,61 @@
+package nano64
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignPayload(t *testing.T) {
+	const now = int64(1_700_000_000_000)
+	key := []byte("0123456789abcdef")
+	id := mustGenerate(t, now-2000, 42)
+	body := []byte(`{"event":"order.paid"}`)
+
+	header, err := SignPayload(key, id, body)
+	if err != nil {
+		t.Fatalf("SignPayload() error = %v", err)
+	}
+	if !strings.HasPrefix(header, "id="+id.ToHex()+",v1=") {
+		t.Errorf("header = %q", header)
+	}
+	if got, err := VerifyPayloadAt(key, header, body, 5*time.Minute, now); err != nil || got != id {
+		t.Errorf("VerifyPayloadAt() = %s, %v", got.ToHex(), err)
+	}
+
+	other, _ := SignPayload([]byte("fedcba9876543210"), id, body)
+	rotated := header + "," + strings.SplitN(other, ",", 2)[1]
+	if _, err := VerifyPayloadAt(key, rotated, body, 5*time.Minute, now); err != nil {
+		t.Errorf("VerifyPayloadAt() with two signatures error = %v", err)
+	}
+
+	signature := strings.SplitN(header, ",", 2)[1]
+	swapped := "id=" + mustGenerate(t, now-2000, 43).ToHex() + "," + signature
+	tests := []struct {
+		name    string
+		header  string
+		body    string
+		now     int64
+		wantErr error
+	}{
+		{"tampered body", header, `{"event":"order.refunded"}`, now, ErrInvalidSignature},
+		{"other ID", swapped, string(body), now, ErrInvalidSignature},
+		{"wrong key", other, string(body), now, ErrInvalidSignature},
+		{"missing ID", signature, string(body), now, ErrInvalidSignature},
+		{"garbage", "nonsense", string(body), now, ErrInvalidSignature},
+		{"replayed later", header, string(body), now + time.Hour.Milliseconds(), ErrSignatureExpired},
+		{"from the future", header, string(body), now - time.Hour.Milliseconds(), ErrSignatureExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyPayloadAt(key, tt.header, []byte(tt.body), 5*time.Minute, tt.now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyPayloadAt() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := SignPayload([]byte("short"), id, body); err == nil {
+		t.Error("SignPayload() accepted a short key")
+	}
+}
@@ -0,0 +1,206 @@
+package nano64
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+)
+
+// Arrival selects the inter-arrival distribution of a Workload.
+type Arrival int
+
+const (
+	// ArrivalConstant spaces IDs evenly at Rate.
+	ArrivalConstant Arrival = iota
+	// ArrivalPoisson draws exponential inter-arrival times with mean 1/Rate.
+	ArrivalPoisson
+	// ArrivalBursty is Poisson arrival whose rate rises to Rate*BurstFactor for the
+	// first BurstLength of every BurstPeriod.
+	ArrivalBursty
+)
+
+// WorkloadOptions configures a Workload.
+type WorkloadOptions struct {
+	// Start is the timestamp of the first arrival; DefaultClock if zero.
+	Start time.Time
+
+	// Rate is the mean number of IDs per second. Required.
+	Rate float64
+
+	// Arrival is the inter-arrival distribution.
+	Arrival Arrival
+
+	// BurstFactor, BurstPeriod and BurstLength shape ArrivalBursty. BurstPeriod
+	// must be at least a millisecond, the resolution of ID timestamps.
+	BurstFactor float64
+	BurstPeriod time.Duration
+	BurstLength time.Duration
+
+	// Count and Duration end the stream after this many IDs or this much simulated
+	// time, whichever comes first. At least one is required.
+	Count    int
+	Duration time.Duration
+
+	// Keys is the number of distinct keys (tenants, partitions, ...) events are
+	// spread over; zero means every event has key 0.
+	Keys int
+
+	// Skew is the Zipf exponent of the key distribution. Zero spreads keys
+	// uniformly; values above 1 concentrate events on low keys.
+	Skew float64
+
+	// Seed makes the stream reproducible; zero draws a random seed.
+	Seed uint64
+}
+
+// WorkloadEvent is one arrival of a Workload.
+type WorkloadEvent struct {
+	ID  Nano64
+	Key int
+}
+
+// Workload produces synthetic ID streams with realistic arrival patterns and key
+// skew for benchmarking Nano64-keyed indexes. Timestamps are simulated, so a
+// stream spanning days is produced as fast as it is consumed.
+type Workload struct {
+	opts  WorkloadOptions
+	start int64
+	seed  uint64
+}
+
+// NewWorkload validates opts and creates a Workload.
+func NewWorkload(opts WorkloadOptions) (*Workload, error) {
+	if opts.Rate <= 0 {
+		return nil, fmt.Errorf("rate must be positive, got %v", opts.Rate)
+	}
+	if opts.Count <= 0 && opts.Duration <= 0 {
+		return nil, fmt.Errorf("workload needs a positive count or duration")
+	}
+	switch opts.Arrival {
+	case ArrivalConstant, ArrivalPoisson:
+	case ArrivalBursty:
+		if opts.BurstFactor < 1 {
+			return nil, fmt.Errorf("burst factor must be at least 1, got %v", opts.BurstFactor)
+		}
+		if opts.BurstPeriod < time.Millisecond {
+			return nil, fmt.Errorf("burst period must be at least 1ms, got %v", opts.BurstPeriod)
+		}
+		if opts.BurstLength <= 0 || opts.BurstLength > opts.BurstPeriod {
+			return nil, fmt.Errorf("burst length must be within a positive burst period")
+		}
+	default:
+		return nil, fmt.Errorf("unknown arrival distribution %d", int(opts.Arrival))
+	}
+	if opts.Keys < 0 {
+		return nil, fmt.Errorf("keys must not be negative, got %d", opts.Keys)
+	}
+	if opts.Skew != 0 && opts.Skew <= 1 {
+		return nil, fmt.Errorf("skew must be 0 or greater than 1, got %v", opts.Skew)
+	}
+
+	start := opts.Start.UnixMilli()
+	if opts.Start.IsZero() {
+		start = DefaultClock()
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return &Workload{opts: opts, start: start, seed: seed}, nil
+}
+
+// Events returns the stream of arrivals in time order. Every call replays the same
+// stream.
+func (w *Workload) Events() iter.Seq[WorkloadEvent] {
+	return func(yield func(WorkloadEvent) bool) {
+		rnd := rand.New(rand.NewPCG(w.seed, w.seed^0x9E3779B97F4A7C15))
+		var zipf *rand.Zipf
+		if w.opts.Skew > 0 && w.opts.Keys > 1 {
+			zipf = rand.NewZipf(rnd, w.opts.Skew, 1, uint64(w.opts.Keys-1))
+		}
+
+		end := w.start + w.opts.Duration.Milliseconds()
+		elapsed := 0.0 // ms since start, kept fractional so high rates do not round away
+		for n := 0; w.opts.Count <= 0 || n < w.opts.Count; n++ {
+			ts := w.start + int64(elapsed)
+			if w.opts.Duration > 0 && ts >= end {
+				return
+			}
+
+			id, err := Generate(ts, func(bits int) (uint32, error) {
+				return rnd.Uint32() & (1<<bits - 1), nil
+			})
+			if err != nil {
+				return
+			}
+			event := WorkloadEvent{ID: id}
+			switch {
+			case zipf != nil:
+				event.Key = int(zipf.Uint64())
+			case w.opts.Keys > 1:
+				event.Key = rnd.IntN(w.opts.Keys)
+			}
+			if !yield(event) {
+				return
+			}
+
+			elapsed += w.gap(rnd, elapsed)
+		}
+	}
+}
+
+// IDs returns the IDs of Events.
+func (w *Workload) IDs() iter.Seq[Nano64] {
+	return func(yield func(Nano64) bool) {
+		for event := range w.Events() {
+			if !yield(event.ID) {
+				return
+			}
+		}
+	}
+}
+
+// gap returns the milliseconds until the arrival after the one at elapsed.
+func (w *Workload) gap(rnd *rand.Rand, elapsed float64) float64 {
+	mean := 1000 / w.opts.Rate
+	switch w.opts.Arrival {
+	case ArrivalPoisson:
+		return rnd.ExpFloat64() * mean
+	case ArrivalBursty:
+		period := float64(w.opts.BurstPeriod) / float64(time.Millisecond)
+		if math.Mod(elapsed, period) < float64(w.opts.BurstLength)/float64(time.Millisecond) {
+			mean /= w.opts.BurstFactor
+		}
+		return rnd.ExpFloat64() * mean
+	default:
+		return mean
+	}
+}
+
+// WriteTo writes the stream to wr as "id,key" lines of dashed hex and decimal,
+// implementing io.WriterTo for dumping workloads to files.
+func (w *Workload) WriteTo(wr io.Writer) (int64, error) {
+	bw := bufio.NewWriter(wr)
+	var written int64
+	buf := make([]byte, 0, 32)
+	for event := range w.Events() {
+		buf = appendHex(buf[:0], event.ID)
+		buf = append(buf, ',')
+		buf = strconv.AppendInt(buf, int64(event.Key), 10)
+		buf = append(buf, '\n')
+		n, err := bw.Write(buf)
+		written += int64(n)
+		if err != nil {
+			return written, fmt.Errorf("failed to write workload: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return written, fmt.Errorf("failed to write workload: %w", err)
+	}
+	return written, nil
+}
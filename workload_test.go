@@ -0,0 +1,138 @@
+package nano64
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWorkloadValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts WorkloadOptions
+	}{
+		{"no rate", WorkloadOptions{Count: 10}},
+		{"no bound", WorkloadOptions{Rate: 10}},
+		{"bad skew", WorkloadOptions{Rate: 10, Count: 10, Skew: 0.5}},
+		{"bad burst", WorkloadOptions{Rate: 10, Count: 10, Arrival: ArrivalBursty, BurstFactor: 5}},
+		{"sub-millisecond burst period", WorkloadOptions{Rate: 10, Count: 10, Arrival: ArrivalBursty, BurstFactor: 5,
+			BurstPeriod: 500 * time.Microsecond, BurstLength: 100 * time.Microsecond}},
+		{"unknown arrival", WorkloadOptions{Rate: 10, Count: 10, Arrival: Arrival(9)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewWorkload(tt.opts); err == nil {
+				t.Error("NewWorkload() expected error")
+			}
+		})
+	}
+}
+
+func TestWorkloadConstant(t *testing.T) {
+	start := time.UnixMilli(1_700_000_000_000)
+	w, err := NewWorkload(WorkloadOptions{Start: start, Rate: 100, Duration: time.Second, Seed: 1})
+	if err != nil {
+		t.Fatalf("NewWorkload() error = %v", err)
+	}
+
+	ids := slices.Collect(w.IDs())
+	if len(ids) != 100 {
+		t.Fatalf("got %d IDs, want 100", len(ids))
+	}
+	for i, id := range ids {
+		if want := start.UnixMilli() + int64(i)*10; id.GetTimestamp() != want {
+			t.Fatalf("ID %d timestamp = %d, want %d", i, id.GetTimestamp(), want)
+		}
+	}
+
+	if again := slices.Collect(w.IDs()); !slices.Equal(ids, again) {
+		t.Error("IDs() is not reproducible")
+	}
+}
+
+func TestWorkloadPoissonRate(t *testing.T) {
+	w, err := NewWorkload(WorkloadOptions{Rate: 10_000, Arrival: ArrivalPoisson, Duration: 10 * time.Second, Seed: 2})
+	if err != nil {
+		t.Fatalf("NewWorkload() error = %v", err)
+	}
+
+	n := 0
+	for range w.IDs() {
+		n++
+	}
+	if n < 98_000 || n > 102_000 {
+		t.Errorf("got %d IDs in 10s at 10k/s, want about 100000", n)
+	}
+}
+
+func TestWorkloadBursty(t *testing.T) {
+	start := time.UnixMilli(1_700_000_000_000)
+	w, err := NewWorkload(WorkloadOptions{
+		Start:       start,
+		Rate:        1000,
+		Arrival:     ArrivalBursty,
+		BurstFactor: 10,
+		BurstPeriod: time.Second,
+		BurstLength: 100 * time.Millisecond,
+		Duration:    10 * time.Second,
+		Seed:        3,
+	})
+	if err != nil {
+		t.Fatalf("NewWorkload() error = %v", err)
+	}
+
+	inBurst, outside := 0, 0
+	for id := range w.IDs() {
+		if (id.GetTimestamp()-start.UnixMilli())%1000 < 100 {
+			inBurst++
+		} else {
+			outside++
+		}
+	}
+	// Bursts are a tenth of the time at ten times the rate: about as many IDs as the rest.
+	if ratio := float64(inBurst) / float64(outside); ratio < 0.9 || ratio > 1.35 {
+		t.Errorf("burst/outside ratio = %.2f (%d/%d), want about 1.1", ratio, inBurst, outside)
+	}
+}
+
+func TestWorkloadKeySkew(t *testing.T) {
+	w, err := NewWorkload(WorkloadOptions{Rate: 1000, Count: 10_000, Keys: 100, Skew: 1.5, Seed: 4})
+	if err != nil {
+		t.Fatalf("NewWorkload() error = %v", err)
+	}
+
+	counts := make([]int, 100)
+	for event := range w.Events() {
+		counts[event.Key]++
+	}
+	if counts[0] < counts[50]*10 {
+		t.Errorf("key 0 got %d events, key 50 got %d; want heavy skew", counts[0], counts[50])
+	}
+}
+
+func TestWorkloadWriteTo(t *testing.T) {
+	w, err := NewWorkload(WorkloadOptions{Rate: 1000, Count: 3, Keys: 4, Seed: 5})
+	if err != nil {
+		t.Fatalf("NewWorkload() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d, wrote %d bytes", n, buf.Len())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("WriteTo() wrote %d lines, want 3", len(lines))
+	}
+	if _, err := FromHex(strings.Split(lines[0], ",")[0]); err != nil {
+		t.Errorf("first line %q does not start with an ID: %v", lines[0], err)
+	}
+}